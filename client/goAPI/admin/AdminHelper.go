@@ -23,10 +23,12 @@ package admin
 
 import (
 	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/iostream"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/utils"
+	"strings"
 	"time"
 )
 
@@ -690,6 +692,140 @@ func extractTransactionsInfoFromInputStream(is types.TGInputStream) (*Transactio
 	return transactionsInfo, nil
 }
 
+// extractTypeListFromInputStream reads the definitions of the node and edge types returned for ShowTypes and
+// Describe: the type count, followed by each type as its system type, id, name, parent name and the attribute
+// descriptors - then the primary key attribute names and the indices of a node type, or the direction and the
+// from and to node type names of an edge type - and the number of entities of the type
+func extractTypeListFromInputStream(is types.TGInputStream) ([]TGTypeInfo, types.TGError) {
+	typeList := make([]TGTypeInfo, 0)
+	typeCount, err := is.(*iostream.ProtocolDataInputStream).ReadInt() // type count
+	if err != nil {
+		logger.Error(fmt.Sprint("ERROR: Returning AdminResponseMessage:ReadPayload w/ Error in reading typeCount from message buffer"))
+		return nil, err
+	}
+	logger.Debug(fmt.Sprintf("Inside AdminResponseMessage:ReadPayload read typeCount as '%+v'", typeCount))
+
+	for i := 0; i < typeCount; i++ {
+		sysType, err := is.(*iostream.ProtocolDataInputStream).ReadByte() // system type
+		if err != nil {
+			logger.Error(fmt.Sprint("ERROR: Returning AdminResponseMessage:ReadPayload w/ Error in reading sysType from message buffer"))
+			return nil, err
+		}
+
+		typeId, err := is.(*iostream.ProtocolDataInputStream).ReadInt() // type id
+		if err != nil {
+			logger.Error(fmt.Sprint("ERROR: Returning AdminResponseMessage:ReadPayload w/ Error in reading typeId from message buffer"))
+			return nil, err
+		}
+
+		typeName, err := is.(*iostream.ProtocolDataInputStream).ReadUTF() // type name
+		if err != nil {
+			logger.Error(fmt.Sprint("ERROR: Returning AdminResponseMessage:ReadPayload w/ Error in reading typeName from message buffer"))
+			return nil, err
+		}
+
+		parentName, err := is.(*iostream.ProtocolDataInputStream).ReadUTF() // parent type name
+		if err != nil {
+			logger.Error(fmt.Sprint("ERROR: Returning AdminResponseMessage:ReadPayload w/ Error in reading parentName from message buffer"))
+			return nil, err
+		}
+		logger.Debug(fmt.Sprintf("Inside AdminResponseMessage:ReadPayload read type '%s' w/ parent '%s'", typeName, parentName))
+
+		attrCount, err := is.(*iostream.ProtocolDataInputStream).ReadShort() // attribute count
+		if err != nil {
+			logger.Error(fmt.Sprint("ERROR: Returning AdminResponseMessage:ReadPayload w/ Error in reading attrCount from message buffer"))
+			return nil, err
+		}
+
+		attributes := make([]types.TGAttributeDescriptor, 0, attrCount)
+		for j := 0; j < int(attrCount); j++ {
+			attrDesc := model.NewAttributeDescriptorWithType("temp", types.AttributeTypeString)
+			err := attrDesc.ReadExternal(is)
+			if err != nil {
+				logger.Error(fmt.Sprint("ERROR: Returning AdminResponseMessage:ReadPayload w/ Error in reading attrDesc from message buffer"))
+				return nil, err
+			}
+			attributes = append(attributes, attrDesc)
+		}
+
+		var typeInfo TGTypeInfo
+		switch types.TGSystemType(sysType) {
+		case types.SystemTypeNode:
+			pKeyCount, err := is.(*iostream.ProtocolDataInputStream).ReadShort() // primary key count
+			if err != nil {
+				logger.Error(fmt.Sprint("ERROR: Returning AdminResponseMessage:ReadPayload w/ Error in reading pKeyCount from message buffer"))
+				return nil, err
+			}
+
+			pKeys := make([]string, 0, pKeyCount)
+			for j := 0; j < int(pKeyCount); j++ {
+				pKeyName, err := is.(*iostream.ProtocolDataInputStream).ReadUTF() // primary key attribute name
+				if err != nil {
+					logger.Error(fmt.Sprint("ERROR: Returning AdminResponseMessage:ReadPayload w/ Error in reading pKeyName from message buffer"))
+					return nil, err
+				}
+				pKeys = append(pKeys, pKeyName)
+			}
+
+			// The indices are listed as for ShowIndices
+			indices, err := extractIndexListFromInputStream(is)
+			if err != nil {
+				return nil, err
+			}
+
+			numEntries, err := is.(*iostream.ProtocolDataInputStream).ReadLong() // num of Entries
+			if err != nil {
+				logger.Error(fmt.Sprint("ERROR: Returning AdminResponseMessage:ReadPayload w/ Error in reading numEntries from message buffer"))
+				return nil, err
+			}
+			typeInfo = NewNodeTypeInfoImpl(typeId, typeName, parentName, attributes, pKeys, indices, numEntries)
+		case types.SystemTypeEdge:
+			direction, err := is.(*iostream.ProtocolDataInputStream).ReadByte() // direction
+			if err != nil {
+				logger.Error(fmt.Sprint("ERROR: Returning AdminResponseMessage:ReadPayload w/ Error in reading direction from message buffer"))
+				return nil, err
+			}
+
+			fromType, err := is.(*iostream.ProtocolDataInputStream).ReadUTF() // from node type name
+			if err != nil {
+				logger.Error(fmt.Sprint("ERROR: Returning AdminResponseMessage:ReadPayload w/ Error in reading fromType from message buffer"))
+				return nil, err
+			}
+
+			toType, err := is.(*iostream.ProtocolDataInputStream).ReadUTF() // to node type name
+			if err != nil {
+				logger.Error(fmt.Sprint("ERROR: Returning AdminResponseMessage:ReadPayload w/ Error in reading toType from message buffer"))
+				return nil, err
+			}
+
+			numEntries, err := is.(*iostream.ProtocolDataInputStream).ReadLong() // num of Entries
+			if err != nil {
+				logger.Error(fmt.Sprint("ERROR: Returning AdminResponseMessage:ReadPayload w/ Error in reading numEntries from message buffer"))
+				return nil, err
+			}
+			typeInfo = NewEdgeTypeInfoImpl(typeId, typeName, parentName, attributes, types.TGDirectionType(direction), fromType, toType, numEntries)
+		default:
+			errMsg := fmt.Sprintf("Type '%s' has invalid system type '%d'", typeName, sysType)
+			logger.Error(fmt.Sprintf("ERROR: Returning AdminResponseMessage:ReadPayload - %s", errMsg))
+			return nil, exception.GetErrorByType(types.TGErrorIOException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+		}
+		typeList = append(typeList, typeInfo)
+	}
+	return typeList, nil
+}
+
+// splitRoleNames splits the comma separated role names of a principal
+func splitRoleNames(roleNames string) []string {
+	roles := make([]string, 0)
+	for _, role := range strings.Split(roleNames, ",") {
+		role = strings.TrimSpace(role)
+		if role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
 func extractUserListFromInputStream(is types.TGInputStream) ([]TGUserInfo, types.TGError) {
 	userList := make([]TGUserInfo, 0)
 	userCount, err := is.(*iostream.ProtocolDataInputStream).ReadInt() // user count
@@ -721,6 +857,8 @@ func extractUserListFromInputStream(is types.TGInputStream) ([]TGUserInfo, types
 		}
 		logger.Debug(fmt.Sprintf("Inside AdminResponseMessage:ReadPayload read userName as '%+v'", userName))
 
+		roles := make([]string, 0)
+		permissions := make([]string, 0)
 		switch types.TGSystemType(userType) {
 		case types.SystemTypePrincipal:
 			bufLen, err := is.(*iostream.ProtocolDataInputStream).ReadInt() // buffer length
@@ -744,9 +882,25 @@ func extractUserListFromInputStream(is types.TGInputStream) ([]TGUserInfo, types
 				return nil, err
 			}
 			logger.Debug(fmt.Sprintf("Inside AdminResponseMessage:ReadPayload read userRole as '%+v'", userRole))
+			roles = splitRoleNames(userRole)
+
+			permCount, err := is.(*iostream.ProtocolDataInputStream).ReadShort() // permission count
+			if err != nil {
+				logger.Error(fmt.Sprint("ERROR: Returning AdminResponseMessage:ReadPayload w/ Error in reading permCount from message buffer"))
+				return nil, err
+			}
+			for j := 0; j < int(permCount); j++ {
+				permission, err := is.(*iostream.ProtocolDataInputStream).ReadUTF() // permission
+				if err != nil {
+					logger.Error(fmt.Sprint("ERROR: Returning AdminResponseMessage:ReadPayload w/ Error in reading permission from message buffer"))
+					return nil, err
+				}
+				permissions = append(permissions, permission)
+			}
+			logger.Debug(fmt.Sprintf("Inside AdminResponseMessage:ReadPayload read permissions as '%+v'", permissions))
 		default:
 		}
-		userInfo := NewUserInfoImpl(userId, userName, userType)
+		userInfo := NewPrincipalInfoImpl(userId, userName, userType, roles, permissions)
 		userList = append(userList, userInfo)
 	}
 	return userList, nil
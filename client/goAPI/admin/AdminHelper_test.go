@@ -0,0 +1,98 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: AdminHelper_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package admin
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/iostream"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"testing"
+)
+
+func writeTestTypeHeader(t *testing.T, os *iostream.ProtocolDataOutputStream, sysType types.TGSystemType, id int, name, parent string, attrs ...*model.AttributeDescriptor) {
+	os.WriteByte(int(sysType))
+	os.WriteInt(id)
+	_ = os.WriteUTF(name)
+	_ = os.WriteUTF(parent)
+	os.WriteShort(len(attrs))
+	for _, attr := range attrs {
+		err := attr.WriteExternal(os)
+		if err != nil {
+			t.Fatalf("AttributeDescriptor WriteExternal failed w/ error: '%+v'", err)
+		}
+	}
+}
+
+func TestExtractTypeListFromInputStream(t *testing.T) {
+	id := model.NewAttributeDescriptorWithType("id", types.AttributeTypeLong)
+	amount := model.NewAttributeDescriptorWithType("amount", types.AttributeTypeNumber)
+	os := iostream.DefaultProtocolDataOutputStream()
+	os.WriteInt(2)
+
+	writeTestTypeHeader(t, os, types.SystemTypeNode, 12, "Savings", "Account", id)
+	os.WriteShort(1)
+	_ = os.WriteUTF("id")
+	os.WriteInt(1)
+	os.WriteByte(1)
+	os.WriteInt(40)
+	_ = os.WriteUTF("idIdx")
+	os.WriteBoolean(true)
+	os.WriteInt(1)
+	_ = os.WriteUTF("id")
+	os.WriteInt(1)
+	_ = os.WriteUTF("Savings")
+	os.WriteInt(512)
+	os.WriteLong(7)
+	_ = os.WriteBytes([]byte("Ready"))
+	os.WriteLong(7)
+
+	writeTestTypeHeader(t, os, types.SystemTypeEdge, 21, "Transfer", "", amount)
+	os.WriteByte(int(types.DirectionTypeDirected))
+	_ = os.WriteUTF("Savings")
+	_ = os.WriteUTF("Account")
+	os.WriteLong(3)
+
+	typeList, err := extractTypeListFromInputStream(iostream.NewProtocolDataInputStream(os.GetBuffer()[:os.GetLength()]))
+	if err != nil {
+		t.Fatalf("extractTypeListFromInputStream failed w/ error: '%+v'", err)
+	}
+	if len(typeList) != 2 {
+		t.Fatalf("extractTypeListFromInputStream returned '%d' types instead of 2", len(typeList))
+	}
+
+	node := typeList[0]
+	if node.GetSystemType() != types.SystemTypeNode || node.GetName() != "Savings" || node.GetParentName() != "Account" || node.GetNumEntries() != 7 {
+		t.Errorf("extractTypeListFromInputStream returned node type '%+v'", node)
+	}
+	if len(node.GetAttributeDescriptors()) != 1 || node.GetAttributeDescriptors()[0].GetAttrType() != types.AttributeTypeLong {
+		t.Errorf("extractTypeListFromInputStream returned attributes '%+v'", node.GetAttributeDescriptors())
+	}
+	if len(node.GetPKeyAttributeNames()) != 1 || len(node.GetIndices()) != 1 || !node.GetIndices()[0].IsUnique() || node.GetIndices()[0].GetName() != "idIdx" {
+		t.Errorf("extractTypeListFromInputStream returned node type '%+v'", node)
+	}
+
+	edge := typeList[1]
+	if edge.GetSystemType() != types.SystemTypeEdge || edge.GetDirectionType() != types.DirectionTypeDirected || edge.GetFromNodeType() != "Savings" || edge.GetToNodeType() != "Account" {
+		t.Errorf("extractTypeListFromInputStream returned edge type '%+v'", edge)
+	}
+	if edge.GetParentName() != "" || edge.GetNumEntries() != 3 || edge.GetAttributeDescriptors()[0].GetName() != "amount" {
+		t.Errorf("extractTypeListFromInputStream returned edge type '%+v'", edge)
+	}
+}
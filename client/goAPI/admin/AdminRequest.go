@@ -37,6 +37,11 @@ type AdminRequestMessage struct {
 	*pdu.AbstractProtocolMessage
 	command    AdminCommand
 	logDetails *ServerLogDetails
+	attrDesc   types.TGAttributeDescriptor
+	entityType types.TGEntityType
+	indexDef   *IndexDefinition
+	userDef    *UserDefinition
+	typeName   string
 }
 
 func DefaultAdminRequestMessage() *AdminRequestMessage {
@@ -68,22 +73,62 @@ func NewAdminRequestMessage(authToken, sessionId int64) *AdminRequestMessage {
 // Helper functions for AdminRequestMessage
 /////////////////////////////////////////////////////////////////
 
+func (msg *AdminRequestMessage) GetAttributeDescriptor() types.TGAttributeDescriptor {
+	return msg.attrDesc
+}
+
 func (msg *AdminRequestMessage) GetCommand() AdminCommand {
 	return msg.command
 }
 
+func (msg *AdminRequestMessage) GetEntityType() types.TGEntityType {
+	return msg.entityType
+}
+
+func (msg *AdminRequestMessage) GetIndexDefinition() *IndexDefinition {
+	return msg.indexDef
+}
+
 func (msg *AdminRequestMessage) GetLogLevel() *ServerLogDetails {
 	return msg.logDetails
 }
 
+func (msg *AdminRequestMessage) GetTypeName() string {
+	return msg.typeName
+}
+
+func (msg *AdminRequestMessage) GetUserDefinition() *UserDefinition {
+	return msg.userDef
+}
+
+func (msg *AdminRequestMessage) SetAttributeDescriptor(attrDesc types.TGAttributeDescriptor) {
+	msg.attrDesc = attrDesc
+}
+
 func (msg *AdminRequestMessage) SetCommand(cmd AdminCommand) {
 	msg.command = cmd
 }
 
+func (msg *AdminRequestMessage) SetEntityType(entityType types.TGEntityType) {
+	msg.entityType = entityType
+}
+
+func (msg *AdminRequestMessage) SetIndexDefinition(indexDef *IndexDefinition) {
+	msg.indexDef = indexDef
+}
+
 func (msg *AdminRequestMessage) SetLogLevel(connId *ServerLogDetails) {
 	msg.logDetails = connId
 }
 
+func (msg *AdminRequestMessage) SetTypeName(typeName string) {
+	msg.typeName = typeName
+}
+
+func (msg *AdminRequestMessage) SetUserDefinition(userDef *UserDefinition) {
+	msg.userDef = userDef
+}
+
 /////////////////////////////////////////////////////////////////
 // Implement functions from Interface ==> TGMessage
 /////////////////////////////////////////////////////////////////
@@ -252,6 +297,11 @@ func (msg *AdminRequestMessage) String() string {
 	buffer.WriteString("AdminRequest:{")
 	buffer.WriteString(fmt.Sprintf("Command: %d", msg.command))
 	buffer.WriteString(fmt.Sprintf(", ServerLogDetails: %+v", msg.logDetails))
+	buffer.WriteString(fmt.Sprintf(", AttributeDescriptor: %+v", msg.attrDesc))
+	buffer.WriteString(fmt.Sprintf(", EntityType: %+v", msg.entityType))
+	buffer.WriteString(fmt.Sprintf(", IndexDefinition: %+v", msg.indexDef))
+	buffer.WriteString(fmt.Sprintf(", UserDefinition: %+v", msg.userDef))
+	buffer.WriteString(fmt.Sprintf(", TypeName: %s", msg.typeName))
 	strArray := []string{buffer.String(), msg.APMMessageToString() + "}"}
 	msgStr := strings.Join(strArray, ", ")
 	return msgStr
@@ -292,10 +342,54 @@ func (msg *AdminRequestMessage) WritePayload(os types.TGOutputStream) types.TGEr
 
 	switch msg.command {
 	case AdminCommandCreateUser:
+		fallthrough
+	case AdminCommandChangePassword:
+		fallthrough
+	case AdminCommandSetUserRoles:
+		fallthrough
+	case AdminCommandDropUser:
+		os.(*iostream.ProtocolDataOutputStream).WriteInt(dataLen)
+		os.(*iostream.ProtocolDataOutputStream).WriteInt(checkSum)
+		os.(*iostream.ProtocolDataOutputStream).WriteInt(int(msg.command))
+		err := writeUserDefinition(msg.command, msg.userDef, os)
+		if err != nil {
+			return err
+		}
 	case AdminCommandCreateAttrDesc:
+		os.(*iostream.ProtocolDataOutputStream).WriteInt(dataLen)
+		os.(*iostream.ProtocolDataOutputStream).WriteInt(checkSum)
+		os.(*iostream.ProtocolDataOutputStream).WriteInt(int(msg.command))
+		err := msg.attrDesc.WriteExternal(os)
+		if err != nil {
+			return err
+		}
 	case AdminCommandCreateIndex:
+		os.(*iostream.ProtocolDataOutputStream).WriteInt(dataLen)
+		os.(*iostream.ProtocolDataOutputStream).WriteInt(checkSum)
+		os.(*iostream.ProtocolDataOutputStream).WriteInt(int(msg.command))
+		err := writeIndexDefinition(msg.indexDef, os)
+		if err != nil {
+			return err
+		}
 	case AdminCommandCreateNodeType:
+		fallthrough
 	case AdminCommandCreateEdgeType:
+		os.(*iostream.ProtocolDataOutputStream).WriteInt(dataLen)
+		os.(*iostream.ProtocolDataOutputStream).WriteInt(checkSum)
+		os.(*iostream.ProtocolDataOutputStream).WriteInt(int(msg.command))
+		err := msg.entityType.WriteExternal(os)
+		if err != nil {
+			return err
+		}
+		// The type definition does not include the parent type, which is sent by name
+		parentName := ""
+		if parent := msg.entityType.DerivedFrom(); parent != nil && !reflect.ValueOf(parent).IsNil() {
+			parentName = parent.GetName()
+		}
+		err = os.(*iostream.ProtocolDataOutputStream).WriteUTF(parentName)
+		if err != nil {
+			return err
+		}
 	case AdminCommandShowUsers:
 		fallthrough
 	case AdminCommandShowAttrDescs:
@@ -311,6 +405,13 @@ func (msg *AdminRequestMessage) WritePayload(os types.TGOutputStream) types.TGEr
 		os.(*iostream.ProtocolDataOutputStream).WriteInt(checkSum)
 		os.(*iostream.ProtocolDataOutputStream).WriteInt(int(msg.command))
 	case AdminCommandDescribe:
+		os.(*iostream.ProtocolDataOutputStream).WriteInt(dataLen)
+		os.(*iostream.ProtocolDataOutputStream).WriteInt(checkSum)
+		os.(*iostream.ProtocolDataOutputStream).WriteInt(int(msg.command))
+		err := os.(*iostream.ProtocolDataOutputStream).WriteUTF(msg.typeName)
+		if err != nil {
+			return err
+		}
 	case AdminCommandSetLogLevel:
 		os.(*iostream.ProtocolDataOutputStream).WriteInt(dataLen)
 		os.(*iostream.ProtocolDataOutputStream).WriteInt(checkSum)
@@ -343,6 +444,67 @@ func (msg *AdminRequestMessage) WritePayload(os types.TGOutputStream) types.TGEr
 	return nil
 }
 
+/////////////////////////////////////////////////////////////////
+// Private functions for AdminRequestMessage
+/////////////////////////////////////////////////////////////////
+
+// writeIndexDefinition writes the name and uniqueness of the index, followed by the names of its attributes and
+// of the node types it is restricted to
+func writeIndexDefinition(indexDef *IndexDefinition, os types.TGOutputStream) types.TGError {
+	if indexDef == nil {
+		errMsg := "Index definition is required to create an index"
+		return exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	err := os.(*iostream.ProtocolDataOutputStream).WriteUTF(indexDef.Name)
+	if err != nil {
+		return err
+	}
+	os.(*iostream.ProtocolDataOutputStream).WriteBoolean(indexDef.IsUnique)
+	os.(*iostream.ProtocolDataOutputStream).WriteShort(len(indexDef.Attributes))
+	for _, attrName := range indexDef.Attributes {
+		err = os.(*iostream.ProtocolDataOutputStream).WriteUTF(attrName)
+		if err != nil {
+			return err
+		}
+	}
+	os.(*iostream.ProtocolDataOutputStream).WriteShort(len(indexDef.NodeTypes))
+	for _, typeName := range indexDef.NodeTypes {
+		err = os.(*iostream.ProtocolDataOutputStream).WriteUTF(typeName)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeUserDefinition writes the name of the user, followed by the password and/or the roles as per the command
+func writeUserDefinition(command AdminCommand, userDef *UserDefinition, os types.TGOutputStream) types.TGError {
+	if userDef == nil {
+		errMsg := "User definition is required to manage a user"
+		return exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	err := os.(*iostream.ProtocolDataOutputStream).WriteUTF(userDef.Name)
+	if err != nil {
+		return err
+	}
+	if command == AdminCommandCreateUser || command == AdminCommandChangePassword {
+		err = os.(*iostream.ProtocolDataOutputStream).WriteBytes(userDef.Password)
+		if err != nil {
+			return err
+		}
+	}
+	if command == AdminCommandCreateUser || command == AdminCommandSetUserRoles {
+		os.(*iostream.ProtocolDataOutputStream).WriteShort(len(userDef.Roles))
+		for _, role := range userDef.Roles {
+			err = os.(*iostream.ProtocolDataOutputStream).WriteUTF(role)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 /////////////////////////////////////////////////////////////////
 // Implement functions from Interface ==> encoding/BinaryMarshaller
 /////////////////////////////////////////////////////////////////
@@ -39,6 +39,7 @@ type AdminResponseMessage struct {
 	connections     []TGConnectionInfo
 	indices         []TGIndexInfo
 	serverInfo      *ServerInfoImpl
+	typeInfos       []TGTypeInfo
 	users           []TGUserInfo
 }
 
@@ -85,6 +86,10 @@ func (msg *AdminResponseMessage) GetServerInfo() *ServerInfoImpl {
 	return msg.serverInfo
 }
 
+func (msg *AdminResponseMessage) GetTypeList() []TGTypeInfo {
+	return msg.typeInfos
+}
+
 func (msg *AdminResponseMessage) GetUserList() []TGUserInfo {
 	return msg.users
 }
@@ -105,6 +110,10 @@ func (msg *AdminResponseMessage) SetServerInfo(sInfo *ServerInfoImpl) {
 	msg.serverInfo = sInfo
 }
 
+func (msg *AdminResponseMessage) SetTypeList(list []TGTypeInfo) {
+	msg.typeInfos = list
+}
+
 func (msg *AdminResponseMessage) SetUserList(list []TGUserInfo) {
 	msg.users = list
 }
@@ -279,6 +288,7 @@ func (msg *AdminResponseMessage) String() string {
 	buffer.WriteString(fmt.Sprintf(", Connections: %+v", msg.connections))
 	buffer.WriteString(fmt.Sprintf(", Indices: %+v", msg.indices))
 	buffer.WriteString(fmt.Sprintf(", ServerInfo: %+v", msg.serverInfo))
+	buffer.WriteString(fmt.Sprintf(", Types: %+v", msg.typeInfos))
 	buffer.WriteString(fmt.Sprintf(", Users: %+v", msg.users))
 	strArray := []string{buffer.String(), msg.APMMessageToString() + "}"}
 	msgStr := strings.Join(strArray, ", ")
@@ -361,6 +371,14 @@ func (msg *AdminResponseMessage) ReadPayload(is types.TGInputStream) types.TGErr
 		}
 		msg.SetIndexList(indexList)
 	case AdminCommandShowTypes:
+		fallthrough
+	case AdminCommandDescribe:
+		typeList, err := extractTypeListFromInputStream(is)
+		if err != nil {
+			logger.Error(fmt.Sprint("ERROR: Returning AdminResponseMessage:ReadPayload w/ Error in reading typeList from message buffer"))
+			return err
+		}
+		msg.SetTypeList(typeList)
 	case AdminCommandShowInfo:
 		serverInfo, err := extractServerInfoFromInputStream(is)
 		if err != nil {
@@ -375,12 +393,14 @@ func (msg *AdminResponseMessage) ReadPayload(is types.TGInputStream) types.TGErr
 			return err
 		}
 		msg.SetConnectionList(connList)
-	case AdminCommandDescribe:
 	case AdminCommandSetLogLevel:
 	case AdminCommandStopServer:
 	case AdminCommandCheckpointServer:
 	case AdminCommandDisconnectClient:
 	case AdminCommandKillConnection:
+	case AdminCommandChangePassword:
+	case AdminCommandSetUserRoles:
+	case AdminCommandDropUser:
 	default:
 	}
 	logger.Log(fmt.Sprint("Returning AdminResponseMessage:ReadPayload"))
@@ -403,7 +423,7 @@ func (msg *AdminResponseMessage) MarshalBinary() ([]byte, error) {
 	_, err := fmt.Fprintln(&b, msg.HeaderGetMessageByteBufLength(), msg.HeaderGetVerbId(),
 		msg.HeaderGetSequenceNo(), msg.HeaderGetTimestamp(), msg.HeaderGetRequestId(),
 		msg.HeaderGetDataOffset(), msg.HeaderGetAuthToken(), msg.HeaderGetSessionId(),
-		msg.GetIsUpdatable(), msg.attrDescriptors, msg.connections, msg.indices, msg.serverInfo, msg.users)
+		msg.GetIsUpdatable(), msg.attrDescriptors, msg.connections, msg.indices, msg.serverInfo, msg.typeInfos, msg.users)
 	if err != nil {
 		logger.Error(fmt.Sprintf("ERROR: Returning AdminResponse:MarshalBinary w/ Error: '%+v'", err.Error()))
 		return nil, err
@@ -424,7 +444,7 @@ func (msg *AdminResponseMessage) UnmarshalBinary(data []byte) error {
 	var offset int16
 	var uFlag bool
 	_, err := fmt.Fscanln(b, &bLen, &vId, &seq, &tStamp, &reqId, &offset, &token, &sId, &uFlag,
-		&msg.attrDescriptors, &msg.connections, &msg.indices, &msg.serverInfo, &msg.users)
+		&msg.attrDescriptors, &msg.connections, &msg.indices, &msg.serverInfo, &msg.typeInfos, &msg.users)
 	if err != nil {
 		logger.Error(fmt.Sprintf("ERROR: Returning AdminResponse:UnmarshalBinary w/ Error: '%+v'", err.Error()))
 		return err
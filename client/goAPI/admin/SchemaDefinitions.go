@@ -0,0 +1,60 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: SchemaDefinitions.go
+ * SVN id: $id: $
+ *
+ */
+
+package admin
+
+import "github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+
+// AttributeDefinition describes an attribute descriptor to create on the server
+type AttributeDefinition struct {
+	Name        string
+	AttrType    int // One of the types.AttributeType constants
+	IsArray     bool
+	IsEncrypted bool
+	Precision   int16 // Only for attributes of type types.AttributeTypeNumber
+	Scale       int16 // Only for attributes of type types.AttributeTypeNumber
+}
+
+// NodeTypeDefinition describes a node type to create on the server. The attributes, including those of the primary
+// key, refer to existing attribute descriptors, and the parent - if any - to an existing node type.
+type NodeTypeDefinition struct {
+	Name           string
+	Parent         string
+	Attributes     []string
+	PKeyAttributes []string
+}
+
+// EdgeTypeDefinition describes an edge type to create on the server, between nodes of two existing node types
+type EdgeTypeDefinition struct {
+	Name         string
+	Parent       string
+	Direction    types.TGDirectionType
+	FromNodeType string
+	ToNodeType   string
+	Attributes   []string
+}
+
+// IndexDefinition describes an index to create on the server, on existing attribute descriptors - and
+// restricted to the nodes of existing node types, if any are listed
+type IndexDefinition struct {
+	Name       string
+	IsUnique   bool
+	Attributes []string
+	NodeTypes  []string
+}
@@ -0,0 +1,195 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: StatisticsSampler.go
+ * SVN id: $id: $
+ *
+ */
+
+package admin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// StatisticsSample is a time-stamped snapshot of the server statistics, along w/ the rates and ratios derived
+// from the difference w/ the previous sample of the same sampler. The derived values of the first sample - and
+// of a sample taken after the server counters were reset - are computed from the cumulative counters instead.
+type StatisticsSample struct {
+	Timestamp    time.Time
+	Interval     time.Duration // Time elapsed since the previous sample, 0 for the first one
+	Info         TGServerInfo
+	Cache        TGCacheStatistics
+	Database     TGDatabaseStatistics
+	Transactions TGTransactionStatistics
+
+	TransactionsPerSecond           float64
+	SuccessfulTransactionsPerSecond float64
+	DataCacheHitRatio               float64 // Hits over hits and misses, 0 if the cache was not accessed
+	IndexCacheHitRatio              float64 // Hits over hits and misses, 0 if the cache was not accessed
+	DataUsedDelta                   int64   // Growth of the data used, in bytes
+	IndexUsedDelta                  int64   // Growth of the index used, in bytes
+}
+
+// StatisticsSampler takes periodic samples of the server statistics through an admin connection
+type StatisticsSampler struct {
+	conn     TGAdminConnection
+	clock    func() time.Time
+	mutex    sync.Mutex
+	previous *StatisticsSample
+}
+
+func NewStatisticsSampler(conn TGAdminConnection) *StatisticsSampler {
+	return &StatisticsSampler{conn: conn, clock: time.Now}
+}
+
+/////////////////////////////////////////////////////////////////
+// Helper functions for StatisticsSampler
+/////////////////////////////////////////////////////////////////
+
+// Sample takes a snapshot of the server statistics, and derives its rates and ratios from the previous snapshot
+func (obj *StatisticsSampler) Sample() (*StatisticsSample, types.TGError) {
+	info, err := obj.conn.GetInfo()
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning StatisticsSampler:Sample - unable to GetInfo() w/ error: '%s'", err.Error()))
+		return nil, err
+	}
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	sample := newStatisticsSample(obj.clock(), info, obj.previous)
+	obj.previous = sample
+	logger.Debug(fmt.Sprintf("Inside StatisticsSampler:Sample took sample '%+v'", sample))
+	return sample, nil
+}
+
+// Run samples the server statistics every interval until the context is done, passing each sample - or the
+// error of the failed attempt - to the handler. The first sample is taken right away.
+func (obj *StatisticsSampler) Run(ctx context.Context, interval time.Duration, handler func(*StatisticsSample, types.TGError)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		handler(obj.Sample())
+		if ctx.Err() != nil {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (obj *StatisticsSample) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString("StatisticsSample:{")
+	buffer.WriteString(fmt.Sprintf("Timestamp: '%s'", obj.Timestamp.Format(time.RFC3339)))
+	buffer.WriteString(fmt.Sprintf(", Interval: '%s'", obj.Interval))
+	buffer.WriteString(fmt.Sprintf(", TransactionsPerSecond: '%.2f'", obj.TransactionsPerSecond))
+	buffer.WriteString(fmt.Sprintf(", SuccessfulTransactionsPerSecond: '%.2f'", obj.SuccessfulTransactionsPerSecond))
+	buffer.WriteString(fmt.Sprintf(", DataCacheHitRatio: '%.4f'", obj.DataCacheHitRatio))
+	buffer.WriteString(fmt.Sprintf(", IndexCacheHitRatio: '%.4f'", obj.IndexCacheHitRatio))
+	buffer.WriteString(fmt.Sprintf(", DataUsedDelta: '%d'", obj.DataUsedDelta))
+	buffer.WriteString(fmt.Sprintf(", IndexUsedDelta: '%d'", obj.IndexUsedDelta))
+	buffer.WriteString("}")
+	return buffer.String()
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for StatisticsSampler
+/////////////////////////////////////////////////////////////////
+
+func isNilStatistics(stats interface{}) bool {
+	return stats == nil || reflect.ValueOf(stats).IsNil()
+}
+
+// counterDelta returns the increase of a counter since the previous sample, or its cumulative value if there is
+// no previous sample or the counter went down - i.e. the server was restarted
+func counterDelta(current, previous int64, hasPrevious bool) int64 {
+	if !hasPrevious || current < previous {
+		return current
+	}
+	return current - previous
+}
+
+func hitRatio(hits, misses int64) float64 {
+	if hits+misses <= 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+func newStatisticsSample(now time.Time, info TGServerInfo, previous *StatisticsSample) *StatisticsSample {
+	sample := &StatisticsSample{Timestamp: now, Info: info}
+	if isNilStatistics(info) {
+		return sample
+	}
+	if !isNilStatistics(info.GetCacheInfo()) {
+		sample.Cache = info.GetCacheInfo()
+	}
+	if !isNilStatistics(info.GetDatabaseInfo()) {
+		sample.Database = info.GetDatabaseInfo()
+	}
+	if !isNilStatistics(info.GetTransactionsInfo()) {
+		sample.Transactions = info.GetTransactionsInfo()
+	}
+	if previous != nil {
+		sample.Interval = now.Sub(previous.Timestamp)
+	}
+
+	if sample.Transactions != nil {
+		hasPrevious := previous != nil && previous.Transactions != nil && sample.Interval > 0
+		var prevProcessed, prevSuccessful int64
+		if hasPrevious {
+			prevProcessed = previous.Transactions.GetTransactionProcessedCount()
+			prevSuccessful = previous.Transactions.GetTransactionSuccessfulCount()
+		}
+		if hasPrevious && sample.Transactions.GetTransactionProcessedCount() >= prevProcessed {
+			seconds := sample.Interval.Seconds()
+			sample.TransactionsPerSecond = float64(sample.Transactions.GetTransactionProcessedCount()-prevProcessed) / seconds
+			sample.SuccessfulTransactionsPerSecond = float64(counterDelta(sample.Transactions.GetTransactionSuccessfulCount(), prevSuccessful, true)) / seconds
+		} else if status := info.GetServerStatus(); !isNilStatistics(status) && status.GetUptime() > 0 {
+			// Without a previous sample, the rates are averaged over the uptime of the server
+			seconds := status.GetUptime().Seconds()
+			sample.TransactionsPerSecond = float64(sample.Transactions.GetTransactionProcessedCount()) / seconds
+			sample.SuccessfulTransactionsPerSecond = float64(sample.Transactions.GetTransactionSuccessfulCount()) / seconds
+		}
+	}
+
+	if sample.Cache != nil {
+		hasPrevious := previous != nil && previous.Cache != nil
+		var prev TGCacheStatistics = DefaultCacheStatisticsImpl()
+		if hasPrevious {
+			prev = previous.Cache
+		}
+		dataHits := counterDelta(sample.Cache.GetDataCacheHits(), prev.GetDataCacheHits(), hasPrevious)
+		dataMisses := counterDelta(sample.Cache.GetDataCacheMisses(), prev.GetDataCacheMisses(), hasPrevious)
+		indexHits := counterDelta(sample.Cache.GetIndexCacheHits(), prev.GetIndexCacheHits(), hasPrevious)
+		indexMisses := counterDelta(sample.Cache.GetIndexCacheMisses(), prev.GetIndexCacheMisses(), hasPrevious)
+		sample.DataCacheHitRatio = hitRatio(dataHits, dataMisses)
+		sample.IndexCacheHitRatio = hitRatio(indexHits, indexMisses)
+	}
+
+	if sample.Database != nil && previous != nil && previous.Database != nil {
+		sample.DataUsedDelta = sample.Database.GetDataUsed() - previous.Database.GetDataUsed()
+		sample.IndexUsedDelta = sample.Database.GetIndexUsed() - previous.Database.GetIndexUsed()
+	}
+	return sample
+}
@@ -0,0 +1,103 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: StatisticsSampler_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package admin
+
+import (
+	"context"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"math"
+	"testing"
+	"time"
+)
+
+// infoConnection returns the server information scripted for each call to GetInfo
+type infoConnection struct {
+	TGAdminConnection
+	infos []TGServerInfo
+	calls int
+}
+
+func (obj *infoConnection) GetInfo() (TGServerInfo, types.TGError) {
+	info := obj.infos[obj.calls%len(obj.infos)]
+	obj.calls++
+	return info, nil
+}
+
+func newTestServerInfo(processed, successful, dataHits, dataMisses, dataUsed int64, uptime time.Duration) TGServerInfo {
+	cache := NewCacheStatisticsImpl(100, 10, dataHits, dataMisses, 1024, 100, 10, 0, 0, 1024)
+	database := NewDatabaseStatisticsImpl(4096, 1, 2048, dataUsed, 2048-dataUsed, 512, 1, 2048, 0, 2048, 512)
+	transactions := NewTransactionStatisticsImpl(1.5, 0, 0, 4, processed, successful)
+	status := NewServerStatusImpl("tgdb", nil, "42", ServerStateStarted, uptime)
+	return NewServerInfoImpl(cache, database, nil, nil, status, transactions)
+}
+
+func TestStatisticsSamplerDeltas(t *testing.T) {
+	conn := &infoConnection{infos: []TGServerInfo{
+		newTestServerInfo(100, 90, 75, 25, 1000, 100*time.Second),
+		newTestServerInfo(150, 130, 165, 35, 1500, 110*time.Second),
+		newTestServerInfo(10, 10, 5, 5, 1600, time.Second),
+	}}
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	sampler := NewStatisticsSampler(conn)
+	sampler.clock = func() time.Time { return now }
+
+	first, err := sampler.Sample()
+	if err != nil {
+		t.Fatalf("StatisticsSampler Sample failed w/ error: '%+v'", err)
+	}
+	if first.Interval != 0 || first.TransactionsPerSecond != 1 || first.DataCacheHitRatio != 0.75 || first.DataUsedDelta != 0 {
+		t.Errorf("StatisticsSampler returned first sample '%+v'", first)
+	}
+
+	now = now.Add(10 * time.Second)
+	second, _ := sampler.Sample()
+	if second.Interval != 10*time.Second || second.TransactionsPerSecond != 5 || second.SuccessfulTransactionsPerSecond != 4 {
+		t.Errorf("StatisticsSampler returned second sample '%+v'", second)
+	}
+	if math.Abs(second.DataCacheHitRatio-0.9) > 1e-9 || second.IndexCacheHitRatio != 0 || second.DataUsedDelta != 500 {
+		t.Errorf("StatisticsSampler returned second sample '%+v'", second)
+	}
+
+	// The counters went down as the server restarted, so the rates are taken over its uptime again
+	now = now.Add(10 * time.Second)
+	third, _ := sampler.Sample()
+	if third.TransactionsPerSecond != 10 || third.DataCacheHitRatio != 0.5 {
+		t.Errorf("StatisticsSampler returned third sample '%+v'", third)
+	}
+}
+
+func TestStatisticsSamplerRun(t *testing.T) {
+	conn := &infoConnection{infos: []TGServerInfo{newTestServerInfo(1, 1, 1, 1, 1, time.Second)}}
+	sampler := NewStatisticsSampler(conn)
+	ctx, cancel := context.WithCancel(context.Background())
+	samples := 0
+	sampler.Run(ctx, time.Millisecond, func(sample *StatisticsSample, err types.TGError) {
+		if err != nil {
+			t.Errorf("StatisticsSampler Run failed w/ error: '%+v'", err)
+		}
+		samples++
+		if samples == 3 {
+			cancel()
+		}
+	})
+	if samples != 3 {
+		t.Errorf("StatisticsSampler Run took '%d' samples instead of 3", samples)
+	}
+}
@@ -47,6 +47,9 @@ const (
 	AdminCommandCheckpointServer
 	AdminCommandDisconnectClient
 	AdminCommandKillConnection
+	AdminCommandChangePassword
+	AdminCommandSetUserRoles
+	AdminCommandDropUser
 )
 
 func (command AdminCommand) String() string {
@@ -90,6 +93,12 @@ func (command AdminCommand) String() string {
 		buffer.WriteString("Admin Command Disconnect Client")
 	} else if command&AdminCommandKillConnection == AdminCommandKillConnection {
 		buffer.WriteString("Admin Command Kill Connection")
+	} else if command&AdminCommandChangePassword == AdminCommandChangePassword {
+		buffer.WriteString("Admin Command Change Password")
+	} else if command&AdminCommandSetUserRoles == AdminCommandSetUserRoles {
+		buffer.WriteString("Admin Command Set User Roles")
+	} else if command&AdminCommandDropUser == AdminCommandDropUser {
+		buffer.WriteString("Admin Command Drop User")
 	}
 	return buffer.String()
 }
@@ -21,22 +21,62 @@
 
 package admin
 
-import "github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+import (
+	"context"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"io"
+)
 
 type TGAdminConnection interface {
 	types.TGConnection
+	// ChangeUserPassword changes the password of an existing user
+	ChangeUserPassword(userName string, password []byte) types.TGError
+
 	// CheckpointServer allows the programmatic control to do a checkpoint on server
 	CheckpointServer() types.TGError
 
+	// CreateAttributeDescriptor creates an attribute descriptor w/ its type, array, encryption and - for numbers -
+	// precision and scale settings
+	CreateAttributeDescriptor(def AttributeDefinition) types.TGError
+
+	// CreateEdgeType creates an edge type w/ its direction, between two existing node types
+	CreateEdgeType(def EdgeTypeDefinition) types.TGError
+
+	// CreateIndex creates an index, unique or not, on existing attribute descriptors
+	CreateIndex(def IndexDefinition) types.TGError
+
+	// CreateNodeType creates a node type w/ its attributes, primary key attributes and parent type
+	CreateNodeType(def NodeTypeDefinition) types.TGError
+
+	// CreateUser creates a user w/ its password and roles
+	CreateUser(def UserDefinition) types.TGError
+
+	// Describe gets the definition of a node or edge type, w/ its attributes, primary key, indices and parent type
+	Describe(typeName string) (TGTypeInfo, types.TGError)
+
+	// DropUser removes an existing user
+	DropUser(userName string) types.TGError
+
 	// DumpServerStackTrace allows the programmatic control to dump the stack trace on the server console
 	DumpServerStackTrace() types.TGError
 
+	// ExportDatabase streams a full export of the database into the writer, cancelling it on the server if the
+	// context is done before the export completes. Experimental: the export messages follow an unpublished server
+	// protocol, and the archive may not be readable by other versions of the client.
+	ExportDatabase(ctx context.Context, w io.Writer) types.TGError
+
 	// GetAttributeDescriptors gets the list of attribute descriptors
 	GetAttributeDescriptors() ([]types.TGAttributeDescriptor, types.TGError)
 
+	// GetCacheStatistics gets the statistics of the data and index caches of the server
+	GetCacheStatistics() (TGCacheStatistics, types.TGError)
+
 	// GetConnections gets the list of all socket connections using this connection type
 	GetConnections() ([]TGConnectionInfo, types.TGError)
 
+	// GetDatabaseStatistics gets the statistics of the data and index segments of the database
+	GetDatabaseStatistics() (TGDatabaseStatistics, types.TGError)
+
 	// GetIndices gets the list of all indices
 	GetIndices() ([]TGIndexInfo, types.TGError)
 
@@ -44,15 +84,36 @@ type TGAdminConnection interface {
 	// memory information, transaction statistics, cache statistics, database statistics)
 	GetInfo() (TGServerInfo, types.TGError)
 
-	// GetUsers gets the list of users
+	// GetMemoryInfo gets the process or shared memory information of the server
+	GetMemoryInfo(memType MemType) (TGMemoryInfo, types.TGError)
+
+	// GetNetListeners gets the information of the net listeners of the server
+	GetNetListeners() ([]TGNetListenerInfo, types.TGError)
+
+	// GetServerStatus gets the name, process id, state and uptime of the server
+	GetServerStatus() (TGServerStatus, types.TGError)
+
+	// GetTransactionStatistics gets the statistics of the transactions processed by the server
+	GetTransactionStatistics() (TGTransactionStatistics, types.TGError)
+
+	// GetTypes gets the definitions of all node and edge types, w/ their attributes, primary keys, indices and parent types
+	GetTypes() ([]TGTypeInfo, types.TGError)
+
+	// GetUsers gets the list of users, w/ their roles and permissions
 	GetUsers() ([]TGUserInfo, types.TGError)
 
+	// ImportDatabase imports an export written by ExportDatabase from the reader, and returns the number of entities imported
+	ImportDatabase(ctx context.Context, r io.Reader) (int64, types.TGError)
+
 	// KillConnection allows the programmatic control to stop a particular connection instance
 	KillConnection(sessionId int64) types.TGError
 
 	// SetServerLogLevel sets the appropriate log level on server
 	SetServerLogLevel(logLevel int, logComponent int64) types.TGError
 
+	// SetUserRoles replaces the roles of an existing user
+	SetUserRoles(userName string, roles []string) types.TGError
+
 	// StopServer allows the programmatic-stop of the server execution
 	StopServer() types.TGError
 }
@@ -0,0 +1,48 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: TGTypeInfo.go
+ * SVN id: $id: $
+ *
+ */
+
+package admin
+
+import "github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+
+// TGTypeInfo allows users to inspect the definition of a node or edge type on the server
+type TGTypeInfo interface {
+	// GetAttributeDescriptors returns the descriptors of the attributes of the type
+	GetAttributeDescriptors() []types.TGAttributeDescriptor
+	// GetDirectionType returns the direction of an edge type
+	GetDirectionType() types.TGDirectionType
+	// GetFromNodeType returns the name of the node type edges of an edge type start from
+	GetFromNodeType() string
+	// GetIndices returns the indices on a node type
+	GetIndices() []TGIndexInfo
+	// GetName returns the type name
+	GetName() string
+	// GetNumEntries returns the number of entities of the type
+	GetNumEntries() int64
+	// GetParentName returns the name of the type the type derives from, if any
+	GetParentName() string
+	// GetPKeyAttributeNames returns the names of the primary key attributes of a node type
+	GetPKeyAttributeNames() []string
+	// GetSystemId returns the system ID
+	GetSystemId() int
+	// GetSystemType returns whether the type is a node or an edge type
+	GetSystemType() types.TGSystemType
+	// GetToNodeType returns the name of the node type edges of an edge type end at
+	GetToNodeType() string
+}
@@ -25,6 +25,10 @@ package admin
 type TGUserInfo interface {
 	// GetName returns the user name
 	GetName() string
+	// GetPermissions returns the permissions granted to the user through its roles
+	GetPermissions() []string
+	// GetRoles returns the names of the roles of the user
+	GetRoles() []string
 	// GetSystemId returns the system ID for this user
 	GetSystemId() int
 	// GetType returns the user type
@@ -0,0 +1,163 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: TypeInfoImpl.go
+ * SVN id: $id: $
+ *
+ */
+
+package admin
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+)
+
+type TypeInfoImpl struct {
+	sysId      int
+	sysType    types.TGSystemType
+	name       string
+	parentName string
+	attributes []types.TGAttributeDescriptor
+	pKeys      []string
+	indices    []TGIndexInfo
+	direction  types.TGDirectionType
+	fromType   string
+	toType     string
+	numEntries int64
+}
+
+// Make sure that the TypeInfoImpl implements the TGTypeInfo interface
+var _ TGTypeInfo = (*TypeInfoImpl)(nil)
+
+func DefaultTypeInfoImpl() *TypeInfoImpl {
+	// We must register the concrete type for the encoder and decoder (which would
+	// normally be on a separate machine from the encoder). On each end, this tells the
+	// engine which concrete type is being sent that implements the interface.
+	gob.Register(TypeInfoImpl{})
+
+	return &TypeInfoImpl{sysType: types.SystemTypeInvalid}
+}
+
+func NewNodeTypeInfoImpl(sysId int, name, parentName string, attributes []types.TGAttributeDescriptor,
+	pKeys []string, indices []TGIndexInfo, entries int64) *TypeInfoImpl {
+	newTypeInfo := DefaultTypeInfoImpl()
+	newTypeInfo.sysId = sysId
+	newTypeInfo.sysType = types.SystemTypeNode
+	newTypeInfo.name = name
+	newTypeInfo.parentName = parentName
+	newTypeInfo.attributes = attributes
+	newTypeInfo.pKeys = pKeys
+	newTypeInfo.indices = indices
+	newTypeInfo.numEntries = entries
+	return newTypeInfo
+}
+
+func NewEdgeTypeInfoImpl(sysId int, name, parentName string, attributes []types.TGAttributeDescriptor,
+	direction types.TGDirectionType, fromType, toType string, entries int64) *TypeInfoImpl {
+	newTypeInfo := DefaultTypeInfoImpl()
+	newTypeInfo.sysId = sysId
+	newTypeInfo.sysType = types.SystemTypeEdge
+	newTypeInfo.name = name
+	newTypeInfo.parentName = parentName
+	newTypeInfo.attributes = attributes
+	newTypeInfo.direction = direction
+	newTypeInfo.fromType = fromType
+	newTypeInfo.toType = toType
+	newTypeInfo.numEntries = entries
+	return newTypeInfo
+}
+
+/////////////////////////////////////////////////////////////////
+// Helper functions from Interface ==> TGTypeInfoImpl
+/////////////////////////////////////////////////////////////////
+
+func (obj *TypeInfoImpl) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString("TypeInfoImpl:{")
+	buffer.WriteString(fmt.Sprintf("SysId: '%d'", obj.sysId))
+	buffer.WriteString(fmt.Sprintf(", SysType: '%d'", obj.sysType))
+	buffer.WriteString(fmt.Sprintf(", Name: '%s'", obj.name))
+	buffer.WriteString(fmt.Sprintf(", ParentName: '%s'", obj.parentName))
+	buffer.WriteString(fmt.Sprintf(", Attributes: '%+v'", obj.attributes))
+	buffer.WriteString(fmt.Sprintf(", PKeys: '%+v'", obj.pKeys))
+	buffer.WriteString(fmt.Sprintf(", Indices: '%+v'", obj.indices))
+	buffer.WriteString(fmt.Sprintf(", Direction: '%d'", obj.direction))
+	buffer.WriteString(fmt.Sprintf(", FromType: '%s'", obj.fromType))
+	buffer.WriteString(fmt.Sprintf(", ToType: '%s'", obj.toType))
+	buffer.WriteString(fmt.Sprintf(", NumEntries: '%+v'", obj.numEntries))
+	buffer.WriteString("}")
+	return buffer.String()
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> TGTypeInfo
+/////////////////////////////////////////////////////////////////
+
+// GetAttributeDescriptors returns the descriptors of the attributes of the type
+func (obj *TypeInfoImpl) GetAttributeDescriptors() []types.TGAttributeDescriptor {
+	return obj.attributes
+}
+
+// GetDirectionType returns the direction of an edge type
+func (obj *TypeInfoImpl) GetDirectionType() types.TGDirectionType {
+	return obj.direction
+}
+
+// GetFromNodeType returns the name of the node type edges of an edge type start from
+func (obj *TypeInfoImpl) GetFromNodeType() string {
+	return obj.fromType
+}
+
+// GetIndices returns the indices on a node type
+func (obj *TypeInfoImpl) GetIndices() []TGIndexInfo {
+	return obj.indices
+}
+
+// GetName returns the type name
+func (obj *TypeInfoImpl) GetName() string {
+	return obj.name
+}
+
+// GetNumEntries returns the number of entities of the type
+func (obj *TypeInfoImpl) GetNumEntries() int64 {
+	return obj.numEntries
+}
+
+// GetParentName returns the name of the type the type derives from, if any
+func (obj *TypeInfoImpl) GetParentName() string {
+	return obj.parentName
+}
+
+// GetPKeyAttributeNames returns the names of the primary key attributes of a node type
+func (obj *TypeInfoImpl) GetPKeyAttributeNames() []string {
+	return obj.pKeys
+}
+
+// GetSystemId returns the system ID
+func (obj *TypeInfoImpl) GetSystemId() int {
+	return obj.sysId
+}
+
+// GetSystemType returns whether the type is a node or an edge type
+func (obj *TypeInfoImpl) GetSystemType() types.TGSystemType {
+	return obj.sysType
+}
+
+// GetToNodeType returns the name of the node type edges of an edge type end at
+func (obj *TypeInfoImpl) GetToNodeType() string {
+	return obj.toType
+}
@@ -0,0 +1,43 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: UserDefinition.go
+ * SVN id: $id: $
+ *
+ */
+
+package admin
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// UserDefinition describes a database user to create, or whose password or roles to change, on the server
+type UserDefinition struct {
+	Name     string
+	Password []byte
+	Roles    []string
+}
+
+// String leaves out the password, so that a definition can be logged
+func (def *UserDefinition) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString("UserDefinition:{")
+	buffer.WriteString(fmt.Sprintf("Name: '%s'", def.Name))
+	buffer.WriteString(fmt.Sprintf(", HasPassword: '%+v'", len(def.Password) > 0))
+	buffer.WriteString(fmt.Sprintf(", Roles: '%+v'", def.Roles))
+	buffer.WriteString("}")
+	return buffer.String()
+}
@@ -28,9 +28,11 @@ import (
 )
 
 type UserInfoImpl struct {
-	userId   int
-	userType byte
-	userName string
+	userId      int
+	userType    byte
+	userName    string
+	roles       []string
+	permissions []string
 }
 
 // Make sure that the UserInfoImpl implements the TGUserInfo interface
@@ -53,6 +55,13 @@ func NewUserInfoImpl(_userId int, _userName string, _userType byte) *UserInfoImp
 	return newConnectionInfo
 }
 
+func NewPrincipalInfoImpl(_userId int, _userName string, _userType byte, _roles, _permissions []string) *UserInfoImpl {
+	newConnectionInfo := NewUserInfoImpl(_userId, _userName, _userType)
+	newConnectionInfo.roles = _roles
+	newConnectionInfo.permissions = _permissions
+	return newConnectionInfo
+}
+
 /////////////////////////////////////////////////////////////////
 // Helper functions from Interface ==> TGUserInfoImpl
 /////////////////////////////////////////////////////////////////
@@ -63,6 +72,8 @@ func (obj *UserInfoImpl) String() string {
 	buffer.WriteString(fmt.Sprintf("UserId: '%d'", obj.userId))
 	buffer.WriteString(fmt.Sprintf(", UserType: '%+v'", obj.userType))
 	buffer.WriteString(fmt.Sprintf(", UserName: '%s'", obj.userName))
+	buffer.WriteString(fmt.Sprintf(", Roles: '%+v'", obj.roles))
+	buffer.WriteString(fmt.Sprintf(", Permissions: '%+v'", obj.permissions))
 	buffer.WriteString("}")
 	return buffer.String()
 }
@@ -76,6 +87,16 @@ func (obj *UserInfoImpl) GetName() string {
 	return obj.userName
 }
 
+// GetPermissions returns the permissions granted to the user through its roles
+func (obj *UserInfoImpl) GetPermissions() []string {
+	return obj.permissions
+}
+
+// GetRoles returns the names of the roles of the user
+func (obj *UserInfoImpl) GetRoles() []string {
+	return obj.roles
+}
+
 // GetSystemId returns the system ID for this user
 func (obj *UserInfoImpl) GetSystemId() int {
 	return obj.userId
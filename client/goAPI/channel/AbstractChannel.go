@@ -29,6 +29,7 @@ import (
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/pdu"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/utils"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -126,19 +127,44 @@ func NewAbstractChannel(linkUrl *LinkUrl, props *utils.SortedProperties) *Abstra
 	newChannel.channelUrl = linkUrl
 	newChannel.primaryUrl = linkUrl
 	newChannel.channelProperties = props
-	// TODO: Uncomment the following two lines to test Trace functionality
-	//enableTraceFlag := newChannel.channelProperties.GetProperty(utils.GetConfigFromKey(utils.EnableConnectionTrace), "true")
-	//if enableTraceFlag == "true" {
 	enableTraceFlag := newChannel.channelProperties.GetPropertyAsBoolean(utils.GetConfigFromKey(utils.EnableConnectionTrace))
 	if enableTraceFlag {
-		traceDir := newChannel.channelProperties.GetProperty(utils.GetConfigFromKey(utils.ConnectionTraceDir), ".")
-		clientId := newChannel.channelProperties.GetProperty(utils.GetConfigFromKey(utils.ChannelClientId), "")
-		newChannel.tracer = NewChannelTracer(clientId, traceDir)
-		//newChannel.tracer.Start()
+		newChannel.tracer = newChannelTracerFromProperties(props)
 	}
 	return newChannel
 }
 
+// newChannelTracerFromProperties creates the wire tracer of the channel, in the trace directory and w/ the rotation
+// of the properties
+func newChannelTracerFromProperties(props *utils.SortedProperties) *ChannelTracer {
+	cn := utils.GetConfigFromKey(utils.ConnectionTraceDir)
+	traceDir := props.GetProperty(cn, cn.GetDefaultValue())
+	cn = utils.GetConfigFromKey(utils.ChannelClientId)
+	clientId := props.GetProperty(cn, cn.GetDefaultValue())
+	tracer := NewChannelTracer(clientId, traceDir)
+
+	cn = utils.GetConfigFromKey(utils.ConnectionTraceMaxFileSize)
+	maxFileSize, err := strconv.ParseInt(props.GetProperty(cn, cn.GetDefaultValue()), 10, 64)
+	if err != nil || maxFileSize <= 0 {
+		logger.Warning(fmt.Sprintf("WARNING: AbstractChannel:newChannelTracerFromProperties - invalid '%s', using default '%s'", cn.GetName(), cn.GetDefaultValue()))
+		maxFileSize = MaxFileSize
+	}
+	cn = utils.GetConfigFromKey(utils.ConnectionTraceMaxFileAge)
+	maxFileAge, err := strconv.Atoi(props.GetProperty(cn, cn.GetDefaultValue()))
+	if err != nil || maxFileAge < 0 {
+		logger.Warning(fmt.Sprintf("WARNING: AbstractChannel:newChannelTracerFromProperties - invalid '%s', using default '%s'", cn.GetName(), cn.GetDefaultValue()))
+		maxFileAge = int(MaxFileAge / time.Second)
+	}
+	cn = utils.GetConfigFromKey(utils.ConnectionTraceMaxFiles)
+	maxFiles, err := strconv.Atoi(props.GetProperty(cn, cn.GetDefaultValue()))
+	if err != nil || maxFiles < 0 {
+		logger.Warning(fmt.Sprintf("WARNING: AbstractChannel:newChannelTracerFromProperties - invalid '%s', using default '%s'", cn.GetName(), cn.GetDefaultValue()))
+		maxFiles = MaxTraceFiles
+	}
+	tracer.SetRotation(maxFileSize, time.Duration(maxFileAge)*time.Second, maxFiles)
+	return tracer
+}
+
 /////////////////////////////////////////////////////////////////
 // Private functions for TGChannel / Derived Channels
 /////////////////////////////////////////////////////////////////
@@ -391,10 +417,12 @@ func channelReconnect(obj types.TGChannel) bool {
 	if err != nil {
 		obj.SetChannelURL(oldUrl.(*LinkUrl))
 		obj.SetChannelLinkState(types.LinkClosed)
+		notifyReconnected(oldUrl.GetUrlAsString(), false)
 		logger.Error(fmt.Sprintf("ERROR: Returning AbstractChannel:channelReconnect - failed to reconnect w/ error: /%+v'", err.Error()))
 		return false
 	}
 	obj.SetChannelLinkState(types.LinkConnected)
+	notifyReconnected(obj.GetChannelURL().GetUrlAsString(), true)
 
 	logger.Log(fmt.Sprint("Returning AbstractChannel:channelReconnect w/ NO Errors"))
 	return true
@@ -541,7 +569,15 @@ func channelSendMessage(obj types.TGChannel, msg types.TGMessage, resendFlag boo
 	return error
 }
 
+// channelSendRequest sends the request and waits for its response, through the request interceptor
 func channelSendRequest(obj types.TGChannel, msg types.TGMessage, channelResponse types.TGChannelResponse, resendFlag bool) (types.TGMessage, types.TGError) {
+	msg.SetRequestId(channelResponse.GetRequestId())
+	return interceptRequest(msg, func() (types.TGMessage, types.TGError) {
+		return channelSendRequestWithRetry(obj, msg, channelResponse, resendFlag)
+	})
+}
+
+func channelSendRequestWithRetry(obj types.TGChannel, msg types.TGMessage, channelResponse types.TGChannelResponse, resendFlag bool) (types.TGMessage, types.TGError) {
 	logger.Log(fmt.Sprintf("Entering AbstractChannel:channelSendRequest w/ Message type: '%+v' ChannelResponse: '%+v'", msg.GetVerbId(), channelResponse))
 	reqId := channelResponse.GetRequestId()
 	msg.SetRequestId(reqId)
@@ -566,10 +602,6 @@ func channelSendRequest(obj types.TGChannel, msg types.TGMessage, channelRespons
 				logger.Error(fmt.Sprintf("ERROR: Returning %s", errMsg))
 				return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.TGDB_CHANNEL_ERROR, errMsg, "")
 			}
-			// TODO: Uncomment once Trace functionality is implemented and tested
-			//if obj.GetTracer() != nil {
-			//	obj.GetTracer().Trace(msg)
-			//}
 			//obj.ChannelLock()
 			logger.Debug(fmt.Sprintf("Inside AbstractChannel:channelSendRequest about to set channel response '%+v' in map '%+v'", channelResponse, obj.GetResponses()))
 			obj.SetResponse(reqId, channelResponse)
@@ -652,9 +684,10 @@ func channelStart(obj types.TGChannel) types.TGError {
 	obj.EnablePing()
 	logger.Debug(fmt.Sprint("Inside AbstractChannel:channelStart about to start channel Reader"))
 	go obj.GetReader().Start()
-	// TODO: Uncomment once Trace functionality is implemented and tested
-	//logger.Debug(fmt.Sprint("Inside AbstractChannel:channelStart about to start channel Tracer"))
-	//go obj.GetTracer().Start()
+	if obj.GetTracer() != nil {
+		logger.Debug(fmt.Sprint("Inside AbstractChannel:channelStart about to start channel Tracer"))
+		obj.GetTracer().Start()
+	}
 	logger.Log(fmt.Sprint("Returning AbstractChannel:channelStart"))
 	return nil
 }
@@ -680,9 +713,10 @@ func channelStop(obj types.TGChannel, bForcefully bool) {
 		obj.DisablePing()
 		logger.Debug(fmt.Sprint("Inside AbstractChannel:channelStop about to stop channel Reader"))
 		obj.GetReader().Stop()
-		// TODO: Uncomment once Trace functionality is implemented and tested
-		//logger.Debug(fmt.Sprint("Inside AbstractChannel:channelStop about to stop channel Tracer"))
-		//obj.GetTracer().Stop()
+		if obj.GetTracer() != nil {
+			logger.Debug(fmt.Sprint("Inside AbstractChannel:channelStop about to stop channel Tracer"))
+			obj.GetTracer().Stop()
+		}
 
 		logger.Debug(fmt.Sprint("Inside AbstractChannel:channelStop about to CreateMessageForVerb()"))
 		// Send the disconnect request. sendRequest will not receive a channel response since the channel will be disconnected.
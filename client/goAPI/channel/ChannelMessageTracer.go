@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"encoding/gob"
 	"fmt"
-	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/utils"
 	"os"
 	"path/filepath"
-	"syscall"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -33,15 +36,36 @@ import (
  *
  */
 
+// ChannelMessageTracer writes the messages queued by the channel tracer to trace files - see TraceRecord for their
+// format - on its own goroutine, so that tracing does not slow down the wire. The file is rolled over to the next
+// suffix once it would exceed the maximum size, or is older than the maximum age, and only the most recent files
+// are kept.
 type ChannelMessageTracer struct {
+	mutex         sync.Mutex
 	currentSuffix int
 	traceFile     *os.File
+	fileSize      int64
+	fileOpenedAt  time.Time
 	isRunning     bool
 	msgQueue      *utils.SimpleQueue
 	traceFileName string
+	maxFileSize   int64
+	maxFileAge    time.Duration
+	maxFiles      int
+	stopCh        chan struct{}
+	doneCh        chan struct{}
 }
 
-const MaxFileSize int64 = 1 << 20
+const (
+	MaxFileSize   int64 = 1 << 20
+	MaxFileAge          = time.Hour
+	MaxTraceFiles       = 10
+)
+
+// tracePollInterval is how long the tracer waits for new messages once the queue is empty
+const tracePollInterval = 100 * time.Millisecond
+
+var traceFileCount int32
 
 func DefaultChannelMessageTracer() *ChannelMessageTracer {
 	// We must register the concrete type for the encoder and decoder (which would
@@ -54,152 +78,166 @@ func DefaultChannelMessageTracer() *ChannelMessageTracer {
 		isRunning:     false,
 		msgQueue:      utils.NewSimpleQueue(),
 		traceFileName: "",
+		maxFileSize:   MaxFileSize,
+		maxFileAge:    MaxFileAge,
+		maxFiles:      MaxTraceFiles,
 	}
 
 	return &newChannelMessageTracer
 }
 
+// NewChannelMessageTracer traces the messages of the queue in the files '<client>-<pid>-<n>.trace.<suffix>' of
+// the trace directory, n telling apart the channels of the process
 func NewChannelMessageTracer(queue *utils.SimpleQueue, client, traceDir string) *ChannelMessageTracer {
 	newChannelMessageTracer := DefaultChannelMessageTracer()
 	newChannelMessageTracer.msgQueue = queue
-	newChannelMessageTracer.traceFileName = filepath.FromSlash(fmt.Sprint(traceDir, "/", client, ".trace"))
-	newChannelMessageTracer.createTraceFile(0, newChannelMessageTracer.currentSuffix)
+	fileName := fmt.Sprintf("%s-%d-%d.trace", client, os.Getpid(), atomic.AddInt32(&traceFileCount, 1))
+	newChannelMessageTracer.traceFileName = filepath.Join(traceDir, fileName)
 	return newChannelMessageTracer
 }
 
 /////////////////////////////////////////////////////////////////
-// Private functions for ChannelMessageTracer
+// Helper functions for ChannelMessageTracer
 /////////////////////////////////////////////////////////////////
 
-// exists returns whether the given file or directory exists, and whether it is a directory or not
-func exists(path string) (bool, bool, error) {
-	info, err := os.Stat(path)
-	if err != nil {
-		return false, false, err
-	}
-	if os.IsNotExist(err) {
-		return false, false, nil
-	}
-	if info.IsDir() {
-		return true, true, nil
-	}
-	return true, false, err
+// GetTraceFileName returns the name of the trace files, w/o their suffix
+func (obj *ChannelMessageTracer) GetTraceFileName() string {
+	return obj.traceFileName
 }
 
-// isFileReadyForRollover checks if the file needs to be rolled over with incremented suffix
-func (obj *ChannelMessageTracer) isFileReadyForRollover(suffix, msgBufLen int) bool {
-	if obj == nil {
-		return false
-	}
+// IsRunning tells whether the tracer writes the queued messages in a trace file
+func (obj *ChannelMessageTracer) IsRunning() bool {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	return obj.isRunning
+}
 
-	fileWithSuffix := fmt.Sprintf("%s%d", obj.traceFileName, suffix)
-	fileInfo, err := os.Stat(fileWithSuffix)
-	if err != nil {
-		return false
+// SetRotation sets the size and the age after which the trace file is rolled over, and the number of files kept.
+// An age or a number of files of 0 disables the corresponding limit.
+func (obj *ChannelMessageTracer) SetRotation(maxFileSize int64, maxFileAge time.Duration, maxFiles int) {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	if maxFileSize > 0 {
+		obj.maxFileSize = maxFileSize
 	}
+	obj.maxFileAge = maxFileAge
+	obj.maxFiles = maxFiles
+}
 
-	if fileInfo.Size()+int64(msgBufLen) < MaxFileSize {
-		return false
+/////////////////////////////////////////////////////////////////
+// Private functions for ChannelMessageTracer
+/////////////////////////////////////////////////////////////////
+
+// traceFileSuffixes returns the suffixes of the existing trace files, in increasing order
+func (obj *ChannelMessageTracer) traceFileSuffixes() []int {
+	matches, _ := filepath.Glob(obj.traceFileName + ".*")
+	suffixes := make([]int, 0, len(matches))
+	for _, match := range matches {
+		suffix, err := strconv.Atoi(strings.TrimPrefix(match, obj.traceFileName+"."))
+		if err == nil {
+			suffixes = append(suffixes, suffix)
+		}
 	}
-	return true
+	sort.Ints(suffixes)
+	return suffixes
 }
 
-// createTraceFile creates a new trace file with new suffix
-func (obj *ChannelMessageTracer) createTraceFile(oldSuffix, newSuffix int) bool {
-	if obj == nil {
-		return false
+// isFileReadyForRollover checks if the file needs to be rolled over with incremented suffix
+func (obj *ChannelMessageTracer) isFileReadyForRollover(now time.Time, msgBufLen int) bool {
+	if obj.fileSize > int64(len(TraceFileMagic)) && obj.fileSize+int64(msgBufLen) > obj.maxFileSize {
+		return true
 	}
+	return obj.maxFileAge > 0 && now.Sub(obj.fileOpenedAt) >= obj.maxFileAge
+}
 
-	// First check the existence of file with old suffix and close it for writing
-	traceFileWithOldSuffix := fmt.Sprintf("%s.%d", obj.traceFileName, oldSuffix)
-	oFlag, _, err := exists(traceFileWithOldSuffix)
-	if err != nil {
-		return false
+// closeTraceFile flushes and closes the current trace file, if any
+func (obj *ChannelMessageTracer) closeTraceFile() {
+	if obj.traceFile == nil {
+		return
 	}
+	_ = obj.traceFile.Sync()  // Flush
+	_ = obj.traceFile.Close() // Close FD
+	obj.traceFile = nil
+}
 
-	if !oFlag {
-		return false
-	} else {
-		_ = obj.traceFile.Sync()  // Flush
-		_ = obj.traceFile.Close() // Close FD
-		obj.traceFile = nil
+// createTraceFile closes the current trace file, and creates a new one w/ the suffix - removing the oldest files
+// beyond the number of files kept
+func (obj *ChannelMessageTracer) createTraceFile(newSuffix int, now time.Time) error {
+	obj.closeTraceFile()
+	if err := os.MkdirAll(filepath.Dir(obj.traceFileName), 0755); err != nil {
+		return err
 	}
-
-	// Next check the existence of file with new suffix and open it for writing
 	traceFileWithNewSuffix := fmt.Sprintf("%s.%d", obj.traceFileName, newSuffix)
-	nFlag, _, err := exists(traceFileWithNewSuffix)
+	fp, err := os.OpenFile(traceFileWithNewSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 	if err != nil {
-		return false
-	}
-
-	if nFlag {
-		return false
+		return err
 	}
-
-	fp, err := os.OpenFile(traceFileWithNewSuffix, syscall.O_RDWR, 0644)
+	n, err := fp.Write([]byte(TraceFileMagic))
 	if err != nil {
-		return false
+		_ = fp.Close()
+		return err
 	}
 	obj.traceFile = fp
+	obj.fileSize = int64(n)
+	obj.fileOpenedAt = now
 	obj.currentSuffix = newSuffix
-	return true
-}
 
-// extractAndTraceMessage reads a message from the message queue and traces it in the trace file
-func (obj *ChannelMessageTracer) extractAndTraceMessage() {
-	if obj == nil {
-		return
+	if obj.maxFiles > 0 {
+		for _, suffix := range obj.traceFileSuffixes() {
+			if suffix <= newSuffix-obj.maxFiles {
+				_ = os.Remove(fmt.Sprintf("%s.%d", obj.traceFileName, suffix))
+			}
+		}
 	}
+	return nil
+}
 
-	//logger.Log(fmt.Sprintf("Entering ChannelMessageTracer:extractAndTraceMessage w/ Message Tracer object: '%+v'", obj.String()))
-	for {
-		if !obj.isRunning {
-			logger.Log(fmt.Sprintf("Breaking ChannelMessageTracer:extractAndTraceMessage loop since message tracer is not running '%+v'", obj.isRunning))
-			break
+// traceRecord writes the record in the current trace file, rolling it over first if needed
+func (obj *ChannelMessageTracer) traceRecord(record *TraceRecord) {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	now := time.Now()
+	if obj.traceFile == nil || obj.isFileReadyForRollover(now, 13+len(record.Bytes)) {
+		if err := obj.createTraceFile(obj.currentSuffix+1, now); err != nil {
+			logger.Error(fmt.Sprintf("ERROR: Inside ChannelMessageTracer:traceRecord unable to create trace file w/ '%+v'", err.Error()))
+			return
 		}
+	}
+	n, err := writeTraceRecord(obj.traceFile, record)
+	obj.fileSize += int64(n)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Inside ChannelMessageTracer:traceRecord Error in obj.traceFile.Write() w/ '%+v'", err.Error()))
+	}
+}
 
-		// At this point, the trace file with suffix is expected to be ready for writing contents in it
-		msg := obj.msgQueue.Dequeue()
-		if msg != nil {
-			msgBuf, msgLen, err := msg.(types.TGMessage).ToBytes()
-			if err != nil {
-				logger.Error(fmt.Sprintf("ERROR: Inside ChannelMessageTracer:extractAndTraceMessage Error in msg.ToBytes() w/ '%+v'", err.Error()))
-				time.Sleep(1000 * time.Millisecond)
-				continue
-			}
-
-			// Check if the file is about to exceed the limit after appending current message buffer
-			if obj.isFileReadyForRollover(obj.currentSuffix, msgLen) {
-				// Create a new file with incremented suffix
-				cFlag := obj.createTraceFile(obj.currentSuffix, obj.currentSuffix+1)
-				if !cFlag {
-					logger.Error(fmt.Sprint("ERROR: Inside ChannelMessageTracer:extractAndTraceMessage Error in obj.createTraceFile()"))
-					time.Sleep(1000 * time.Millisecond)
-					continue
-				}
-			}
+// drainQueue traces all the records queued so far
+func (obj *ChannelMessageTracer) drainQueue() {
+	for record := obj.msgQueue.Dequeue(); record != nil; record = obj.msgQueue.Dequeue() {
+		obj.traceRecord(record.(*TraceRecord))
+	}
+}
 
-			_, err1 := obj.traceFile.Write(msgBuf)
-			if err1 != nil {
-				logger.Error(fmt.Sprintf("ERROR: Inside ChannelMessageTracer:extractAndTraceMessage Error in obj.traceFile.Write() w/ '%+v'", err1.Error()))
-				time.Sleep(1000 * time.Millisecond)
-				continue
-			}
-		} else {
-			logger.Debug(fmt.Sprint("Inside ChannelMessageTracer:extractAndTraceMessage - No pending messages in the queue"))
-			time.Sleep(1000 * time.Millisecond)
-			continue
+// extractAndTraceMessage traces the records of the message queue until the tracer is stopped
+func (obj *ChannelMessageTracer) extractAndTraceMessage(stopCh, doneCh chan struct{}) {
+	defer close(doneCh)
+	for {
+		obj.drainQueue()
+		select {
+		case <-stopCh:
+			// Finish any remaining processing
+			obj.drainQueue()
+			return
+		case <-time.After(tracePollInterval):
 		}
-	} // End of Infinite Loop
-	obj.isRunning = false
-	//logger.Log(fmt.Sprintf("Returning ChannelMessageTracer:extractAndTraceMessage w/ Message Tracer object: '%+v'", obj.String()))
+	}
 }
 
 func (obj *ChannelMessageTracer) String() string {
 	var buffer bytes.Buffer
 	buffer.WriteString("ChannelMessageTracer:{")
 	buffer.WriteString(fmt.Sprintf("TraceFileName: %+v", obj.traceFileName))
-	buffer.WriteString(fmt.Sprintf(", MsgQueue: %d", obj.msgQueue))
+	buffer.WriteString(fmt.Sprintf(", CurrentSuffix: %d", obj.currentSuffix))
+	buffer.WriteString(fmt.Sprintf(", PendingMessages: %d", obj.msgQueue.Len()))
 	buffer.WriteString("}")
 	return buffer.String()
 }
@@ -208,30 +246,40 @@ func (obj *ChannelMessageTracer) String() string {
 // Implement functions for TGTracer
 /////////////////////////////////////////////////////////////////
 
-// Start starts the channel message tracer
+// Start starts tracing the queued messages in a new trace file, following any left by a previous run
 func (obj *ChannelMessageTracer) Start() {
-	//logger.Log(fmt.Sprint("Entering ChannelMessageTracer:Start ..."))
-	if !obj.isRunning {
-		obj.isRunning = true
-		// Start reading and processing messages from the wire
-		obj.extractAndTraceMessage()
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	if obj.isRunning {
+		return
+	}
+	if suffixes := obj.traceFileSuffixes(); len(suffixes) > 0 {
+		obj.currentSuffix = suffixes[len(suffixes)-1]
+	}
+	if err := obj.createTraceFile(obj.currentSuffix+1, time.Now()); err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning ChannelMessageTracer:Start unable to create trace file w/ '%+v'", err.Error()))
+		return
 	}
-	//logger.Log(fmt.Sprint("Returning ChannelMessageTracer:Start ..."))
+	obj.isRunning = true
+	obj.stopCh = make(chan struct{})
+	obj.doneCh = make(chan struct{})
+	go obj.extractAndTraceMessage(obj.stopCh, obj.doneCh)
 }
 
-// Stop stops the channel message tracer
+// Stop traces the messages still queued, and closes the trace file
 func (obj *ChannelMessageTracer) Stop() {
-	//logger.Log(fmt.Sprint("Entering ChannelMessageTracer:Stop ..."))
-	if obj.isRunning {
-		// Finish / Flush any remaining processing
-		traceFileWithSuffix := fmt.Sprintf("%s.%d", obj.traceFileName, obj.currentSuffix)
-		flag, _, _ := exists(traceFileWithSuffix)
-		if flag {
-			_ = obj.traceFile.Sync()  // Flush
-			_ = obj.traceFile.Close() // Close FD
-			obj.traceFile = nil
-		}
-		obj.isRunning = false
+	obj.mutex.Lock()
+	if !obj.isRunning {
+		obj.mutex.Unlock()
+		return
 	}
-	//logger.Log(fmt.Sprint("Returning ChannelMessageTracer:Stop ..."))
+	obj.isRunning = false
+	stopCh, doneCh := obj.stopCh, obj.doneCh
+	obj.mutex.Unlock()
+
+	close(stopCh)
+	<-doneCh
+	obj.mutex.Lock()
+	obj.closeTraceFile()
+	obj.mutex.Unlock()
 }
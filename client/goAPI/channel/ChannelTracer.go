@@ -23,13 +23,23 @@ package channel
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/gob"
 	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/admin"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/pdu"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/utils"
+	"sync"
+	"time"
 )
 
+// TraceHeaderLength is the length of the message header - bufLength, magic, protocol version, verb, sequence no,
+// timestamp, request id, auth token, session id and data offset - which is all that is traced of a redacted message
+const TraceHeaderLength = 54
+
 type ChannelTracer struct {
+	mutex     sync.RWMutex // Guards isRunning, as messages are traced by the goroutines of the channel
 	msgQueue  *utils.SimpleQueue
 	msgTracer *ChannelMessageTracer
 	clientId  string
@@ -59,16 +69,70 @@ func NewChannelTracer(client, traceDir string) *ChannelTracer {
 	return newChannelTracer
 }
 
+/////////////////////////////////////////////////////////////////
+// Helper functions for ChannelTracer
+/////////////////////////////////////////////////////////////////
+
+// GetTraceFileName returns the name of the trace files, w/o their suffix
+func (obj *ChannelTracer) GetTraceFileName() string {
+	return obj.msgTracer.GetTraceFileName()
+}
+
+// SetRotation sets the size and the age after which the trace file is rolled over, and the number of files kept
+func (obj *ChannelTracer) SetRotation(maxFileSize int64, maxFileAge time.Duration, maxFiles int) {
+	obj.msgTracer.SetRotation(maxFileSize, maxFileAge, maxFiles)
+}
+
 /////////////////////////////////////////////////////////////////
 // Private functions for ChannelTracer
 /////////////////////////////////////////////////////////////////
 
+// enqueue queues a copy of the bytes, as the buffers of the wire are reused
+func (obj *ChannelTracer) enqueue(direction TraceDirection, buf []byte) {
+	obj.mutex.RLock()
+	defer obj.mutex.RUnlock()
+	if !obj.isRunning {
+		return
+	}
+	record := &TraceRecord{Timestamp: time.Now(), Direction: direction, Bytes: redactTraceBytes(buf)}
+	obj.msgQueue.Enqueue(record)
+}
+
+// isSensitiveMessage checks whether the message carries credentials: authentication, and the admin commands that
+// create a user or change its password
+func isSensitiveMessage(buf []byte) bool {
+	switch int(binary.BigEndian.Uint16(buf[10:12])) {
+	case pdu.VerbAuthenticateRequest, pdu.VerbAuthenticateResponse:
+		return true
+	case pdu.VerbAdminRequest:
+		// The admin command follows the data length and the checksum at the start of the payload
+		cmdPos := int(binary.BigEndian.Uint16(buf[TraceHeaderLength-2:TraceHeaderLength])) + 8
+		if cmdPos+4 > len(buf) {
+			return false
+		}
+		command := admin.AdminCommand(binary.BigEndian.Uint32(buf[cmdPos : cmdPos+4]))
+		return command == admin.AdminCommandCreateUser || command == admin.AdminCommandChangePassword
+	}
+	return false
+}
+
+// redactTraceBytes returns a copy of the message bytes, truncated to the message header for a message that carries
+// credentials, so that they never reach the trace files
+func redactTraceBytes(buf []byte) []byte {
+	if len(buf) <= TraceHeaderLength || !isSensitiveMessage(buf) {
+		return append([]byte(nil), buf...)
+	}
+	redacted := append([]byte(nil), buf[:TraceHeaderLength]...)
+	binary.BigEndian.PutUint32(redacted[0:4], TraceHeaderLength)
+	return redacted
+}
+
 func (obj *ChannelTracer) String() string {
 	var buffer bytes.Buffer
 	buffer.WriteString("ChannelTracer:{")
 	buffer.WriteString(fmt.Sprintf("ClientId: %+v", obj.clientId))
-	buffer.WriteString(fmt.Sprintf(", MsgQueue: %d", obj.msgQueue))
-	buffer.WriteString(fmt.Sprintf(", MsgTracer: %d", obj.msgTracer))
+	buffer.WriteString(fmt.Sprintf(", MsgQueue: %d", obj.msgQueue.Len()))
+	buffer.WriteString(fmt.Sprintf(", MsgTracer: %s", obj.msgTracer.String()))
 	buffer.WriteString("}")
 	return buffer.String()
 }
@@ -79,28 +143,46 @@ func (obj *ChannelTracer) String() string {
 
 // Start starts the channel tracer
 func (obj *ChannelTracer) Start() {
-	//logger.Log(fmt.Sprint("Entering ChannelTracer:Start ..."))
+	logger.Log(fmt.Sprint("Entering ChannelTracer:Start ..."))
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
 	if !obj.isRunning {
 		obj.msgTracer.Start()
-		obj.isRunning = true
+		// Nothing is queued if the trace file could not be created
+		obj.isRunning = obj.msgTracer.IsRunning()
 	}
-	//logger.Log(fmt.Sprint("Returning ChannelTracer:Start ..."))
+	logger.Log(fmt.Sprint("Returning ChannelTracer:Start ..."))
 }
 
 // Stop stops the channel tracer
 func (obj *ChannelTracer) Stop() {
 	logger.Log(fmt.Sprint("Entering ChannelTracer:Stop ..."))
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
 	if obj.isRunning {
+		obj.isRunning = false
 		// Finish / Flush any remaining processing
 		obj.msgTracer.Stop()
-		obj.isRunning = false
 	}
 	logger.Log(fmt.Sprint("Returning ChannelTracer:Stop ..."))
 }
 
-// Trace traces the path the message has taken
+// Trace traces the path the message has taken, as it would be sent on the wire
 func (obj *ChannelTracer) Trace(msg types.TGMessage) {
-	//logger.Log(fmt.Sprint("Entering ChannelTracer:Trace"))
-	obj.msgQueue.Enqueue(msg)
-	//logger.Log(fmt.Sprintf("Returning ChannelTracer:Trace ..."))
+	msgBytes, bufLen, err := msg.ToBytes()
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning ChannelTracer:Trace Error in msg.ToBytes() w/ '%+v'", err.Error()))
+		return
+	}
+	obj.enqueue(TraceSent, msgBytes[0:bufLen])
+}
+
+// TraceReceived traces the bytes of a message as read from the wire
+func (obj *ChannelTracer) TraceReceived(buf []byte) {
+	obj.enqueue(TraceReceived, buf)
+}
+
+// TraceSent traces the bytes of a message as written on the wire
+func (obj *ChannelTracer) TraceSent(buf []byte) {
+	obj.enqueue(TraceSent, buf)
 }
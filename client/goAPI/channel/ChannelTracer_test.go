@@ -0,0 +1,170 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: ChannelTracer_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package channel
+
+import (
+	"bytes"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/admin"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/pdu"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"io"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func readTraceFile(t *testing.T, fileName string) []*TraceRecord {
+	file, err := os.Open(fileName)
+	if err != nil {
+		t.Fatalf("Unable to open trace file '%s' w/ error: '%+v'", fileName, err)
+	}
+	defer file.Close()
+	reader, err := NewTraceReader(file)
+	if err != nil {
+		t.Fatalf("NewTraceReader failed w/ error: '%+v'", err)
+	}
+	records := make([]*TraceRecord, 0)
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("TraceReader Next failed w/ error: '%+v'", err)
+		}
+		records = append(records, record)
+	}
+}
+
+func TestChannelTracerWritesRecords(t *testing.T) {
+	tracer := NewChannelTracer("client", t.TempDir())
+	tracer.TraceSent([]byte("dropped"))
+	tracer.Start()
+	sent := []byte("request")
+	tracer.TraceSent(sent)
+	// The tracer keeps a copy, as the buffers of the wire are reused
+	sent[0] = 'X'
+	tracer.TraceReceived([]byte("response"))
+	tracer.Stop()
+
+	suffixes := tracer.msgTracer.traceFileSuffixes()
+	if len(suffixes) != 1 {
+		t.Fatalf("ChannelTracer created trace files w/ suffixes '%v'", suffixes)
+	}
+	records := readTraceFile(t, tracer.GetTraceFileName()+".1")
+	if len(records) != 2 {
+		t.Fatalf("ChannelTracer traced '%d' records", len(records))
+	}
+	if records[0].Direction != TraceSent || !bytes.Equal(records[0].Bytes, []byte("request")) || records[0].Timestamp.IsZero() {
+		t.Errorf("ChannelTracer traced first record '%s' w/ bytes '%s'", records[0], records[0].Bytes)
+	}
+	if records[1].Direction != TraceReceived || !bytes.Equal(records[1].Bytes, []byte("response")) {
+		t.Errorf("ChannelTracer traced second record '%s' w/ bytes '%s'", records[1], records[1].Bytes)
+	}
+
+	// A restarted tracer follows the files of the previous run
+	tracer.Start()
+	tracer.TraceSent([]byte("again"))
+	tracer.Stop()
+	if records = readTraceFile(t, tracer.GetTraceFileName()+".2"); len(records) != 1 {
+		t.Errorf("ChannelTracer traced '%d' records after a restart", len(records))
+	}
+}
+
+func TestChannelTracerRedactsCredentials(t *testing.T) {
+	auth := pdu.NewAuthenticateRequestMessage(0, 0)
+	auth.SetUserName("scott")
+	auth.SetPassword([]byte("tiger-secret"))
+	createUser := admin.NewAdminRequestMessage(0, 0)
+	createUser.SetCommand(admin.AdminCommandCreateUser)
+	createUser.SetUserDefinition(&admin.UserDefinition{Name: "scott", Password: []byte("tiger-secret"), Roles: []string{"user"}})
+	showUsers := admin.NewAdminRequestMessage(0, 0)
+	showUsers.SetCommand(admin.AdminCommandShowUsers)
+
+	tracer := NewChannelTracer("client", t.TempDir())
+	tracer.Start()
+	for _, msg := range []types.TGMessage{auth, createUser, showUsers} {
+		tracer.Trace(msg)
+	}
+	tracer.Stop()
+
+	records := readTraceFile(t, tracer.GetTraceFileName()+".1")
+	if len(records) != 3 {
+		t.Fatalf("ChannelTracer traced '%d' records", len(records))
+	}
+	for i, record := range records[:2] {
+		verb, err := pdu.VerbIdFromBytes(record.Bytes)
+		if len(record.Bytes) != TraceHeaderLength || bytes.Contains(record.Bytes, []byte("tiger-secret")) || err != nil {
+			t.Errorf("ChannelTracer did not redact record %d w/ bytes '%+v'", i, record.Bytes)
+		} else if i == 0 && verb.GetID() != pdu.VerbAuthenticateRequest {
+			t.Errorf("ChannelTracer traced redacted record %d w/ verb '%+v'", i, verb)
+		}
+	}
+	if len(records[2].Bytes) <= TraceHeaderLength {
+		t.Errorf("ChannelTracer redacted an admin request w/o credentials")
+	}
+}
+
+func TestChannelMessageTracerRotation(t *testing.T) {
+	tracer := NewChannelTracer("client", t.TempDir())
+	// Each file holds the magic and a single record of 13 + 10 bytes
+	tracer.SetRotation(int64(len(TraceFileMagic)+30), 0, 3)
+	tracer.Start()
+	for i := 0; i < 5; i++ {
+		tracer.TraceSent([]byte("0123456789"))
+	}
+	tracer.Stop()
+
+	suffixes := tracer.msgTracer.traceFileSuffixes()
+	if len(suffixes) != 3 || suffixes[0] != 3 || suffixes[2] != 5 {
+		t.Fatalf("ChannelMessageTracer kept trace files w/ suffixes '%v'", suffixes)
+	}
+	for _, suffix := range suffixes {
+		fileName := tracer.GetTraceFileName() + "." + strconv.Itoa(suffix)
+		if records := readTraceFile(t, fileName); len(records) != 1 {
+			t.Errorf("Trace file '%s' has '%d' records", fileName, len(records))
+		}
+	}
+
+	// Files older than the maximum age are rolled over too
+	aged := NewChannelTracer("aged", t.TempDir())
+	aged.SetRotation(MaxFileSize, time.Nanosecond, 0)
+	aged.Start()
+	aged.TraceSent([]byte("first"))
+	aged.TraceSent([]byte("second"))
+	aged.Stop()
+	if suffixes = aged.msgTracer.traceFileSuffixes(); len(suffixes) < 2 {
+		t.Errorf("ChannelMessageTracer did not roll over aged trace files - suffixes '%v'", suffixes)
+	}
+}
+
+func TestTraceReaderRejectsOtherFiles(t *testing.T) {
+	if _, err := NewTraceReader(bytes.NewReader([]byte("not a trace file"))); err == nil {
+		t.Error("NewTraceReader accepted a file w/o the trace magic")
+	}
+	var buf bytes.Buffer
+	buf.WriteString(TraceFileMagic)
+	_, _ = writeTraceRecord(&buf, &TraceRecord{Timestamp: time.Now(), Bytes: []byte("cut short")})
+	reader, _ := NewTraceReader(bytes.NewReader(buf.Bytes()[:buf.Len()-3]))
+	if _, err := reader.Next(); err != io.ErrUnexpectedEOF {
+		t.Errorf("TraceReader returned '%v' for a truncated record", err)
+	}
+}
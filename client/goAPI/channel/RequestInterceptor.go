@@ -0,0 +1,152 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: RequestInterceptor.go
+ * SVN id: $id: $
+ *
+ */
+
+package channel
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"sync"
+	"time"
+)
+
+// RequestTrace describes a request sent by a channel and, once answered, its outcome. The same trace is passed to
+// BeforeRequest and AfterRequest, so that an interceptor can keep its own state - e.g. a span - in the Attachment.
+type RequestTrace struct {
+	VerbId     int
+	VerbName   string // Name of the verb w/o its 'Verb' prefix, e.g. 'QueryRequest'
+	RequestId  int64
+	Size       int // Bytes of the request on the wire, 0 if it could not be sent
+	Start      time.Time
+	Duration   time.Duration // Time until the response, including the retries after a reconnect
+	Error      types.TGError
+	Attachment interface{}
+}
+
+// RequestInterceptor is invoked around every request sent by the channels, e.g. to trace them as spans. It is
+// called on the goroutine of the request, and must not block.
+type RequestInterceptor interface {
+	// BeforeRequest is called right before the request is sent - only the verb, request id and start are known
+	BeforeRequest(trace *RequestTrace)
+	// AfterRequest is called once the request has been answered, or has failed
+	AfterRequest(trace *RequestTrace)
+}
+
+// NoopRequestInterceptor is the default interceptor, which does nothing
+type NoopRequestInterceptor struct{}
+
+func (obj NoopRequestInterceptor) BeforeRequest(trace *RequestTrace) {}
+
+func (obj NoopRequestInterceptor) AfterRequest(trace *RequestTrace) {}
+
+// chainedInterceptor invokes the interceptors in order before the request, and in reverse order after it
+type chainedInterceptor []RequestInterceptor
+
+// ChainRequestInterceptors combines the interceptors, the first one being the outermost
+func ChainRequestInterceptors(interceptors ...RequestInterceptor) RequestInterceptor {
+	return chainedInterceptor(interceptors)
+}
+
+func (obj chainedInterceptor) BeforeRequest(trace *RequestTrace) {
+	for _, interceptor := range obj {
+		interceptor.BeforeRequest(trace)
+	}
+}
+
+func (obj chainedInterceptor) AfterRequest(trace *RequestTrace) {
+	for i := len(obj) - 1; i >= 0; i-- {
+		obj[i].AfterRequest(trace)
+	}
+}
+
+// RequestRecorder keeps the traces of the completed requests, e.g. to verify in tests the requests made
+type RequestRecorder struct {
+	mutex  sync.Mutex
+	traces []RequestTrace
+}
+
+func NewRequestRecorder() *RequestRecorder {
+	return &RequestRecorder{traces: make([]RequestTrace, 0)}
+}
+
+func (obj *RequestRecorder) BeforeRequest(trace *RequestTrace) {}
+
+func (obj *RequestRecorder) AfterRequest(trace *RequestTrace) {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	obj.traces = append(obj.traces, *trace)
+}
+
+// GetTraces returns a copy of the traces recorded so far, in the order the requests completed
+func (obj *RequestRecorder) GetTraces() []RequestTrace {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	return append([]RequestTrace(nil), obj.traces...)
+}
+
+// Reset discards the traces recorded so far
+func (obj *RequestRecorder) Reset() {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	obj.traces = obj.traces[:0]
+}
+
+var interceptorLock sync.RWMutex
+var requestInterceptor RequestInterceptor = NoopRequestInterceptor{}
+
+// SetRequestInterceptor installs the interceptor of the requests of all the channels, replacing the previous
+// one - nil restores the no-op default
+func SetRequestInterceptor(interceptor RequestInterceptor) {
+	interceptorLock.Lock()
+	defer interceptorLock.Unlock()
+	if interceptor == nil {
+		interceptor = NoopRequestInterceptor{}
+	}
+	requestInterceptor = interceptor
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for RequestInterceptor
+/////////////////////////////////////////////////////////////////
+
+func currentInterceptor() RequestInterceptor {
+	interceptorLock.RLock()
+	defer interceptorLock.RUnlock()
+	return requestInterceptor
+}
+
+// interceptRequest sends the request through the interceptor, and notifies the request observer of the outcome
+func interceptRequest(msg types.TGMessage, send func() (types.TGMessage, types.TGError)) (types.TGMessage, types.TGError) {
+	interceptor := currentInterceptor()
+	trace := &RequestTrace{
+		VerbId:    msg.GetVerbId(),
+		VerbName:  GetVerbName(msg.GetVerbId()),
+		RequestId: msg.GetRequestId(),
+		Start:     time.Now(),
+	}
+	interceptor.BeforeRequest(trace)
+	// The length is set to that of the bytes on the wire once the request is written
+	msg.SetMessageByteBufLength(0)
+	resp, err := send()
+	trace.Duration = time.Since(trace.Start)
+	trace.Size = msg.GetMessageByteBufLength()
+	trace.Error = err
+	interceptor.AfterRequest(trace)
+	notifyRequestCompleted(trace.VerbId, trace.Duration, err)
+	return resp, err
+}
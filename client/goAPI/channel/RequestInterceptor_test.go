@@ -0,0 +1,122 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: RequestInterceptor_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package channel
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/pdu"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"testing"
+	"time"
+)
+
+// orderInterceptor appends its name to the calls, before and after each request
+type orderInterceptor struct {
+	name  string
+	calls *[]string
+}
+
+func (obj *orderInterceptor) BeforeRequest(trace *RequestTrace) {
+	*obj.calls = append(*obj.calls, "before "+obj.name)
+	trace.Attachment = obj.name
+}
+
+func (obj *orderInterceptor) AfterRequest(trace *RequestTrace) {
+	*obj.calls = append(*obj.calls, "after "+obj.name)
+}
+
+// countingObserver counts the requests completed
+type countingObserver struct {
+	completed int
+}
+
+func (obj *countingObserver) RequestCompleted(verbId int, duration time.Duration, err types.TGError) {
+	obj.completed++
+}
+
+func (obj *countingObserver) Reconnected(url string, succeeded bool) {}
+
+func TestInterceptRequestRecordsTraces(t *testing.T) {
+	recorder := NewRequestRecorder()
+	observer := &countingObserver{}
+	SetRequestInterceptor(recorder)
+	SetRequestObserver(observer)
+	defer SetRequestInterceptor(nil)
+	defer SetRequestObserver(nil)
+
+	msg := pdu.NewQueryRequestMessage(0, 0)
+	msg.SetRequestId(42)
+	_, err := interceptRequest(msg, func() (types.TGMessage, types.TGError) {
+		// Stands for the channel writing the request on the wire
+		msg.SetMessageByteBufLength(128)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("interceptRequest failed w/ error: '%+v'", err)
+	}
+	failed := pdu.NewQueryRequestMessage(0, 0)
+	_, err = interceptRequest(failed, func() (types.TGMessage, types.TGError) {
+		return nil, exception.NewTGGeneralExceptionWithMsg("channel is closed")
+	})
+	if err == nil {
+		t.Fatal("interceptRequest did not return the error of the request")
+	}
+
+	traces := recorder.GetTraces()
+	if len(traces) != 2 || observer.completed != 2 {
+		t.Fatalf("RequestRecorder recorded '%d' traces and observer '%d' requests", len(traces), observer.completed)
+	}
+	if traces[0].VerbName != "QueryRequest" || traces[0].RequestId != 42 || traces[0].Size != 128 || traces[0].Error != nil || traces[0].Start.IsZero() {
+		t.Errorf("RequestRecorder recorded trace '%+v'", traces[0])
+	}
+	if traces[1].Size != 0 || traces[1].Error == nil {
+		t.Errorf("RequestRecorder recorded trace '%+v' for the failed request", traces[1])
+	}
+
+	recorder.Reset()
+	if len(recorder.GetTraces()) != 0 {
+		t.Error("RequestRecorder still has traces after Reset")
+	}
+}
+
+func TestChainRequestInterceptors(t *testing.T) {
+	calls := make([]string, 0)
+	SetRequestInterceptor(ChainRequestInterceptors(&orderInterceptor{name: "outer", calls: &calls}, &orderInterceptor{name: "inner", calls: &calls}))
+	defer SetRequestInterceptor(nil)
+
+	_, _ = interceptRequest(pdu.NewQueryRequestMessage(0, 0), func() (types.TGMessage, types.TGError) {
+		calls = append(calls, "send")
+		return nil, nil
+	})
+	expected := []string{"before outer", "before inner", "send", "after inner", "after outer"}
+	if len(calls) != len(expected) {
+		t.Fatalf("Interceptors were called as '%v'", calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Fatalf("Interceptors were called as '%v'", calls)
+		}
+	}
+
+	SetRequestInterceptor(nil)
+	if _, ok := currentInterceptor().(NoopRequestInterceptor); !ok {
+		t.Errorf("SetRequestInterceptor(nil) installed '%T' instead of the no-op interceptor", currentInterceptor())
+	}
+}
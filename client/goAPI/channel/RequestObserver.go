@@ -0,0 +1,76 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: RequestObserver.go
+ * SVN id: $id: $
+ *
+ */
+
+package channel
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/pdu"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestObserver is notified of the requests sent and of the reconnects attempted by all the channels, e.g. to
+// collect client-side metrics. It is called on the goroutine of the request, and must not block.
+type RequestObserver interface {
+	// RequestCompleted is called once the request w/ the verb has been answered - or has failed w/ the error
+	RequestCompleted(verbId int, duration time.Duration, err types.TGError)
+	// Reconnected is called after each attempt of a channel to reconnect to one of its fault tolerant URLs
+	Reconnected(url string, succeeded bool)
+}
+
+var observerLock sync.RWMutex
+var requestObserver RequestObserver
+
+// SetRequestObserver installs the observer of the requests of all the channels, replacing the previous one - nil
+// removes it
+func SetRequestObserver(observer RequestObserver) {
+	observerLock.Lock()
+	defer observerLock.Unlock()
+	requestObserver = observer
+}
+
+// GetVerbName returns the name of the verb w/o its 'Verb' prefix, e.g. 'QueryRequest' - or 'InvalidMessage' for
+// an unknown verb
+func GetVerbName(verbId int) string {
+	return strings.TrimPrefix(pdu.GetVerb(verbId).GetName(), "Verb")
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for RequestObserver
+/////////////////////////////////////////////////////////////////
+
+func currentObserver() RequestObserver {
+	observerLock.RLock()
+	defer observerLock.RUnlock()
+	return requestObserver
+}
+
+func notifyRequestCompleted(verbId int, duration time.Duration, err types.TGError) {
+	if observer := currentObserver(); observer != nil {
+		observer.RequestCompleted(verbId, duration, err)
+	}
+}
+
+func notifyReconnected(url string, succeeded bool) {
+	if observer := currentObserver(); observer != nil {
+		observer.Reconnected(url, succeeded)
+	}
+}
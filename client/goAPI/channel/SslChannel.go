@@ -486,6 +486,11 @@ func (obj *SSLChannel) writeToWire(msg types.TGMessage) types.TGError {
 		logger.Error(fmt.Sprintf("ERROR: Returning %s w/ '%+v'", errMsg, sErr.Error()))
 		return exception.GetErrorByType(types.TGErrorIOException, "TGErrorProtocolNotSupported", errMsg, sErr.Error())
 	}
+	// Record the length of the request on the wire, as reported by the request interceptor
+	msg.SetMessageByteBufLength(bufLen)
+	if obj.tracer != nil {
+		obj.tracer.TraceSent(msgBytes[0:bufLen])
+	}
 	logger.Log(fmt.Sprintf("======> Returning SSLChannel:writeToWire successfully wrote message bytes on the socket as '%+v'", msgBytes[0:bufLen]))
 	return nil
 }
@@ -851,6 +856,10 @@ func (obj *SSLChannel) ReadWireMsg() (types.TGMessage, types.TGError) {
 	//bytesRead, _ := utils.FormatHex(msgBytes)
 	//logger.Debug(fmt.Sprintf("======> Inside SSLChannel:ReadWireMsg bytes read: '%s'", bytesRead))
 
+	if obj.tracer != nil {
+		obj.tracer.TraceReceived(buffer)
+	}
+
 	msg, err := pdu.CreateMessageFromBuffer(buffer, 0, n)
 	if err != nil {
 		errMsg := "SSLChannel::ReadWireMsg - unable to create a message from the input stream bytes"
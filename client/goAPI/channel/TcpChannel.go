@@ -357,6 +357,11 @@ func (obj *TCPChannel) writeToWire(msg types.TGMessage) types.TGError {
 		logger.Error(fmt.Sprintf("ERROR: Returning %s w/ '%+v'", errMsg, sErr.Error()))
 		return exception.GetErrorByType(types.TGErrorIOException, "TGErrorProtocolNotSupported", errMsg, sErr.Error())
 	}
+	// Record the length of the request on the wire, as reported by the request interceptor
+	msg.SetMessageByteBufLength(bufLen)
+	if obj.tracer != nil {
+		obj.tracer.TraceSent(msgBytes[0:bufLen])
+	}
 	logger.Log(fmt.Sprintf("======> Returning TCPChannel:writeToWire successfully wrote message bytes on the socket as '%+v'", msgBytes[0:bufLen]))
 	return nil
 }
@@ -708,6 +713,10 @@ func (obj *TCPChannel) ReadWireMsg() (types.TGMessage, types.TGError) {
 	//bytesRead, _ := utils.FormatHex(msgBytes)
 	//logger.Debug(fmt.Sprintf("======> Inside TCPChannel:ReadWireMsg bytes read: '%s'", bytesRead))
 
+	if obj.tracer != nil {
+		obj.tracer.TraceReceived(buffer)
+	}
+
 	msg, err := pdu.CreateMessageFromBuffer(buffer, 0, n)
 	if err != nil {
 		errMsg := "TCPChannel::ReadWireMsg - unable to create a message from the input stream bytes"
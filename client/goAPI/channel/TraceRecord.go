@@ -0,0 +1,128 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: TraceRecord.go
+ * SVN id: $id: $
+ *
+ */
+
+package channel
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// A trace file starts w/ TraceFileMagic, followed by one record per message read from or written on the wire:
+//
+//	int64 timestamp in nanoseconds since the epoch | byte direction | int32 length | message bytes
+//
+// The message bytes are exactly those on the wire, so that they can be decoded by the message factory. Messages
+// that carry credentials are the exception: only their header is kept, w/ its length set to TraceHeaderLength.
+
+// TraceFileMagic identifies a trace file, and the version of its format
+const TraceFileMagic = "TGDBTRC1"
+
+// TraceDirection tells whether a traced message was sent to, or received from the server
+type TraceDirection byte
+
+const (
+	TraceSent TraceDirection = iota
+	TraceReceived
+)
+
+// maxTraceRecordLength protects the reader from allocating a corrupted length
+const maxTraceRecordLength = 1 << 30
+
+func (direction TraceDirection) String() string {
+	if direction == TraceReceived {
+		return "received"
+	}
+	return "sent"
+}
+
+// TraceRecord is a message traced on the wire
+type TraceRecord struct {
+	Timestamp time.Time
+	Direction TraceDirection
+	Bytes     []byte
+}
+
+func (obj *TraceRecord) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString("TraceRecord:{")
+	buffer.WriteString(fmt.Sprintf("Timestamp: '%s'", obj.Timestamp.Format(time.RFC3339Nano)))
+	buffer.WriteString(fmt.Sprintf(", Direction: '%s'", obj.Direction))
+	buffer.WriteString(fmt.Sprintf(", Length: '%d'", len(obj.Bytes)))
+	buffer.WriteString("}")
+	return buffer.String()
+}
+
+// TraceReader reads the records of a trace file
+type TraceReader struct {
+	in io.Reader
+}
+
+// NewTraceReader checks that the input starts w/ the magic of a trace file, and returns a reader of its records
+func NewTraceReader(in io.Reader) (*TraceReader, error) {
+	magic := make([]byte, len(TraceFileMagic))
+	if _, err := io.ReadFull(in, magic); err != nil {
+		return nil, fmt.Errorf("unable to read the trace file header: %s", err.Error())
+	}
+	if string(magic) != TraceFileMagic {
+		return nil, fmt.Errorf("not a trace file - header is '%q'", magic)
+	}
+	return &TraceReader{in: in}, nil
+}
+
+// Next returns the next record, or io.EOF once all of them have been read. A record cut short - e.g. as the
+// client was killed while tracing - is reported as io.ErrUnexpectedEOF.
+func (obj *TraceReader) Next() (*TraceRecord, error) {
+	var header struct {
+		Timestamp int64
+		Direction TraceDirection
+		Length    int32
+	}
+	if err := binary.Read(obj.in, binary.BigEndian, &header); err != nil {
+		return nil, err
+	}
+	if header.Length < 0 || header.Length > maxTraceRecordLength {
+		return nil, fmt.Errorf("invalid trace record length '%d'", header.Length)
+	}
+	record := &TraceRecord{
+		Timestamp: time.Unix(0, header.Timestamp),
+		Direction: header.Direction,
+		Bytes:     make([]byte, header.Length),
+	}
+	if _, err := io.ReadFull(obj.in, record.Bytes); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+// writeTraceRecord appends the record to a trace file, returning the number of bytes written
+func writeTraceRecord(out io.Writer, record *TraceRecord) (int, error) {
+	buf := make([]byte, 13+len(record.Bytes))
+	binary.BigEndian.PutUint64(buf[0:8], uint64(record.Timestamp.UnixNano()))
+	buf[8] = byte(record.Direction)
+	binary.BigEndian.PutUint32(buf[9:13], uint32(len(record.Bytes)))
+	copy(buf[13:], record.Bytes)
+	return out.Write(buf)
+}
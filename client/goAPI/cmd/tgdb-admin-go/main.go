@@ -0,0 +1,95 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: main.go
+ * SVN id: $id: $
+ *
+ */
+
+// tgdb-admin-go runs the admin statements of a TQL script against a TGDB server, without the server distribution, e.g.
+//
+//	tgdb-admin-go -url tcp://admin@localhost:8222 -password admin -file metadatascript.tql
+//
+// Without a file, the statements are read interactively from the standard input. A script may open the connection
+// itself w/ a 'connect <url> <user> <password>' statement, instead of the url flag. The results of the show and
+// describe statements are printed as tables, or as JSON w/ '-output json'.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/admin"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/connection"
+	"os"
+)
+
+func main() {
+	url := flag.String("url", "", "URL of the TGDB server, if not connected by the script")
+	user := flag.String("user", "", "user name, if not part of the URL")
+	password := flag.String("password", "", "password of the user")
+	file := flag.String("file", "", "TQL script of admin statements, instead of the standard input")
+	format := flag.String("output", formatTable, "output format: table or json")
+	flag.Parse()
+
+	if *format != formatTable && *format != formatJson {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	sh := newShell(connectAdmin, os.Stdout, *format)
+	defer sh.close()
+	if *url != "" {
+		if err := (&connectStatement{url: *url, user: *user, password: *password}).execute(sh); err != nil {
+			exitOnError(err)
+		}
+	}
+
+	if *file == "" {
+		if err := sh.run(os.Stdin, true); err != nil {
+			sh.close()
+			exitOnError(err)
+		}
+		return
+	}
+	script, err := os.Open(*file)
+	if err != nil {
+		exitOnError(err)
+	}
+	defer script.Close()
+	if err = sh.run(script, false); err != nil {
+		sh.close()
+		exitOnError(err)
+	}
+}
+
+// connectAdmin opens an admin connection to the server
+func connectAdmin(url, user, password string) (admin.TGAdminConnection, error) {
+	conn, err := connection.NewTGConnectionFactory().CreateAdminConnection(url, user, password, nil)
+	if err != nil {
+		return nil, err
+	}
+	adminConn, ok := conn.(admin.TGAdminConnection)
+	if !ok {
+		return nil, fmt.Errorf("connection to '%s' is not an admin connection", url)
+	}
+	if err = adminConn.Connect(); err != nil {
+		return nil, err
+	}
+	return adminConn, nil
+}
+
+func exitOnError(err error) {
+	fmt.Fprintf(os.Stderr, "tgdb-admin-go: %s\n", err.Error())
+	os.Exit(1)
+}
@@ -0,0 +1,117 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: output.go
+ * SVN id: $id: $
+ *
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	formatTable = "table"
+	formatJson  = "json"
+)
+
+// table is the result of a show or describe statement
+type table struct {
+	columns []string
+	rows    [][]interface{}
+}
+
+func newTable(columns ...string) *table {
+	return &table{columns: columns, rows: make([][]interface{}, 0)}
+}
+
+func (obj *table) add(values ...interface{}) {
+	obj.rows = append(obj.rows, values)
+}
+
+// printer writes the results of the statements as aligned columns, or as JSON - an array of objects keyed by the
+// column names for a table, and an object w/ the message otherwise
+type printer struct {
+	out    io.Writer
+	format string
+}
+
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case []string:
+		return strings.Join(v, ", ")
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func (obj *printer) printTable(result *table) error {
+	if obj.format == formatJson {
+		objects := make([]map[string]interface{}, 0, len(result.rows))
+		for _, row := range result.rows {
+			object := make(map[string]interface{}, len(result.columns))
+			for i, column := range result.columns {
+				object[column] = row[i]
+			}
+			objects = append(objects, object)
+		}
+		return obj.printJson(objects)
+	}
+	writer := tabwriter.NewWriter(obj.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, strings.Join(result.columns, "\t"))
+	for _, row := range result.rows {
+		cells := make([]string, 0, len(row))
+		for _, value := range row {
+			cells = append(cells, formatCell(value))
+		}
+		fmt.Fprintln(writer, strings.Join(cells, "\t"))
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(obj.out, "(%d rows)\n", len(result.rows))
+	return nil
+}
+
+func (obj *printer) printMessage(message string) error {
+	if obj.format == formatJson {
+		return obj.printJson(map[string]string{"status": "ok", "message": message})
+	}
+	_, err := fmt.Fprintln(obj.out, message)
+	return err
+}
+
+func (obj *printer) printError(err error) {
+	if obj.format == formatJson {
+		_ = obj.printJson(map[string]string{"status": "error", "message": err.Error()})
+		return
+	}
+	fmt.Fprintf(obj.out, "Error: %s\n", err.Error())
+}
+
+func (obj *printer) printJson(value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(obj.out, string(data))
+	return err
+}
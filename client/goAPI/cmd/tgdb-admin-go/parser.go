@@ -0,0 +1,544 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: parser.go
+ * SVN id: $id: $
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/admin"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"strconv"
+	"strings"
+)
+
+// The statements follow the TQL syntax of the admin scripts of the server distribution - keywords are case
+// insensitive, a statement takes a single line and may end w/ ';', and lines starting w/ '#' or '//' are comments:
+//
+//	connect <url> [<user> [<password>]]
+//	create attrdesc <name> as <type>[(<precision>, <scale>)] [array] [encrypted]
+//	create nodetype <name> [extends <parent>] [attributes (<attr>, ...)] [pkeys (<attr>, ...)]
+//	create edgetype <name> [extends <parent>] [directed|undirected|bidirected] from <nodetype> to <nodetype> [attributes (<attr>, ...)]
+//	create [unique] index <name> attributes (<attr>, ...) [on <nodetype>, ...]
+//	create user <name> password <password> [roles (<role>, ...)]
+//	show info|users|connections|indices|types|attrdescs
+//	describe <type>
+//	kill connection <session id>
+//	checkpoint
+//	stop server
+//	disconnect
+//	help
+//	exit | quit
+
+// attrTypeNames maps the type names of the create attrdesc statement to attribute types
+var attrTypeNames = map[string]int{
+	"boolean":   types.AttributeTypeBoolean,
+	"bool":      types.AttributeTypeBoolean,
+	"byte":      types.AttributeTypeByte,
+	"char":      types.AttributeTypeChar,
+	"short":     types.AttributeTypeShort,
+	"int":       types.AttributeTypeInteger,
+	"integer":   types.AttributeTypeInteger,
+	"long":      types.AttributeTypeLong,
+	"float":     types.AttributeTypeFloat,
+	"double":    types.AttributeTypeDouble,
+	"number":    types.AttributeTypeNumber,
+	"string":    types.AttributeTypeString,
+	"date":      types.AttributeTypeDate,
+	"time":      types.AttributeTypeTime,
+	"timestamp": types.AttributeTypeTimeStamp,
+	"blob":      types.AttributeTypeBlob,
+	"clob":      types.AttributeTypeClob,
+}
+
+// attrTypeName returns the name of an attribute type as used in the create attrdesc statement
+func attrTypeName(attrType int) string {
+	for _, name := range []string{"boolean", "byte", "char", "short", "int", "long", "float", "double", "number", "string", "date", "time", "timestamp", "blob", "clob"} {
+		if attrTypeNames[name] == attrType {
+			return name
+		}
+	}
+	return strconv.Itoa(attrType)
+}
+
+var directionNames = map[string]types.TGDirectionType{
+	"undirected": types.DirectionTypeUnDirected,
+	"directed":   types.DirectionTypeDirected,
+	"bidirected": types.DirectionTypeBiDirectional,
+}
+
+// directionName returns the name of an edge direction as used in the create edgetype statement
+func directionName(direction types.TGDirectionType) string {
+	for name, value := range directionNames {
+		if value == direction {
+			return name
+		}
+	}
+	return strconv.Itoa(int(direction))
+}
+
+// statement is a parsed admin statement, executed by the shell
+type statement interface {
+	execute(sh *shell) error
+}
+
+type connectStatement struct {
+	url      string
+	user     string
+	password string
+}
+
+type disconnectStatement struct{}
+
+type createAttrDescStatement struct {
+	def admin.AttributeDefinition
+}
+
+type createNodeTypeStatement struct {
+	def admin.NodeTypeDefinition
+}
+
+type createEdgeTypeStatement struct {
+	def admin.EdgeTypeDefinition
+}
+
+type createIndexStatement struct {
+	def admin.IndexDefinition
+}
+
+type createUserStatement struct {
+	def admin.UserDefinition
+}
+
+type showStatement struct {
+	what string
+}
+
+type describeStatement struct {
+	typeName string
+}
+
+type killConnectionStatement struct {
+	sessionId int64
+}
+
+type checkpointStatement struct{}
+
+type stopServerStatement struct{}
+
+type helpStatement struct{}
+
+type exitStatement struct{}
+
+// tokenizer splits a statement into words, quoted strings and the punctuation '(', ')' and ','
+type tokenizer struct {
+	tokens []string
+	pos    int
+}
+
+func tokenize(line string) (*tokenizer, error) {
+	tokens := make([]string, 0)
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\'' || r == '"':
+			flush()
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated quoted string")
+			}
+			tokens = append(tokens, string(runes[i+1:end]))
+			i = end
+		case r == '(' || r == ')' || r == ',':
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t':
+			flush()
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+	return &tokenizer{tokens: tokens}, nil
+}
+
+func (obj *tokenizer) done() bool {
+	return obj.pos >= len(obj.tokens)
+}
+
+func (obj *tokenizer) peek() string {
+	if obj.done() {
+		return ""
+	}
+	return obj.tokens[obj.pos]
+}
+
+// next returns the next token, failing if there is none left
+func (obj *tokenizer) next(what string) (string, error) {
+	if obj.done() {
+		return "", fmt.Errorf("missing %s", what)
+	}
+	token := obj.tokens[obj.pos]
+	obj.pos++
+	return token, nil
+}
+
+// keyword consumes the next token if it is the keyword
+func (obj *tokenizer) keyword(keyword string) bool {
+	if strings.EqualFold(obj.peek(), keyword) {
+		obj.pos++
+		return true
+	}
+	return false
+}
+
+func (obj *tokenizer) expect(keyword string) error {
+	if !obj.keyword(keyword) {
+		if obj.done() {
+			return fmt.Errorf("missing '%s'", keyword)
+		}
+		return fmt.Errorf("expected '%s' instead of '%s'", keyword, obj.peek())
+	}
+	return nil
+}
+
+// list reads a parenthesized, comma separated list of names
+func (obj *tokenizer) list(what string) ([]string, error) {
+	if err := obj.expect("("); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0)
+	for {
+		name, err := obj.next(what)
+		if err != nil {
+			return nil, err
+		}
+		if name == ")" && len(names) == 0 {
+			return names, nil
+		}
+		if name == "(" || name == ")" || name == "," {
+			return nil, fmt.Errorf("expected %s instead of '%s'", what, name)
+		}
+		names = append(names, name)
+		if obj.keyword(")") {
+			return names, nil
+		}
+		if err = obj.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// names reads a comma separated list of names, w/ or w/o parentheses
+func (obj *tokenizer) names(what string) ([]string, error) {
+	if obj.peek() == "(" {
+		return obj.list(what)
+	}
+	names := make([]string, 0)
+	for {
+		name, err := obj.next(what)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !obj.keyword(",") {
+			return names, nil
+		}
+	}
+}
+
+// parseStatement parses a line of a script, returning a nil statement for blank and comment lines
+func parseStatement(line string) (statement, error) {
+	line = strings.TrimSpace(line)
+	line = strings.TrimSpace(strings.TrimSuffix(line, ";"))
+	if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+		return nil, nil
+	}
+	tokens, err := tokenize(line)
+	if err != nil {
+		return nil, err
+	}
+	verb, _ := tokens.next("statement")
+	var stmt statement
+	switch strings.ToLower(verb) {
+	case "connect":
+		stmt, err = parseConnect(tokens)
+	case "disconnect":
+		stmt = &disconnectStatement{}
+	case "create":
+		stmt, err = parseCreate(tokens)
+	case "show":
+		stmt, err = parseShow(tokens)
+	case "describe":
+		var typeName string
+		typeName, err = tokens.next("type name")
+		stmt = &describeStatement{typeName: typeName}
+	case "kill":
+		stmt, err = parseKill(tokens)
+	case "checkpoint":
+		stmt = &checkpointStatement{}
+	case "stop":
+		tokens.keyword("server")
+		stmt = &stopServerStatement{}
+	case "help":
+		stmt = &helpStatement{}
+	case "exit", "quit":
+		stmt = &exitStatement{}
+	default:
+		return nil, fmt.Errorf("unknown statement '%s'", verb)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !tokens.done() {
+		return nil, fmt.Errorf("unexpected '%s'", tokens.peek())
+	}
+	return stmt, nil
+}
+
+func parseConnect(tokens *tokenizer) (statement, error) {
+	url, err := tokens.next("url")
+	if err != nil {
+		return nil, err
+	}
+	stmt := &connectStatement{url: url}
+	if !tokens.done() {
+		stmt.user, _ = tokens.next("user")
+	}
+	if !tokens.done() {
+		stmt.password, _ = tokens.next("password")
+	}
+	return stmt, nil
+}
+
+func parseCreate(tokens *tokenizer) (statement, error) {
+	unique := tokens.keyword("unique")
+	kind, err := tokens.next("object to create")
+	if err != nil {
+		return nil, err
+	}
+	if unique && !strings.EqualFold(kind, "index") {
+		return nil, fmt.Errorf("only an index can be unique")
+	}
+	switch strings.ToLower(kind) {
+	case "attrdesc":
+		return parseCreateAttrDesc(tokens)
+	case "nodetype":
+		return parseCreateNodeType(tokens)
+	case "edgetype":
+		return parseCreateEdgeType(tokens)
+	case "index":
+		return parseCreateIndex(tokens, unique)
+	case "user":
+		return parseCreateUser(tokens)
+	}
+	return nil, fmt.Errorf("cannot create '%s'", kind)
+}
+
+func parseCreateAttrDesc(tokens *tokenizer) (statement, error) {
+	name, err := tokens.next("attribute name")
+	if err != nil {
+		return nil, err
+	}
+	if err = tokens.expect("as"); err != nil {
+		return nil, err
+	}
+	typeName, err := tokens.next("attribute type")
+	if err != nil {
+		return nil, err
+	}
+	def := admin.AttributeDefinition{Name: name}
+	if strings.HasSuffix(typeName, "[]") {
+		typeName = strings.TrimSuffix(typeName, "[]")
+		def.IsArray = true
+	}
+	attrType, ok := attrTypeNames[strings.ToLower(typeName)]
+	if !ok {
+		return nil, fmt.Errorf("unknown attribute type '%s'", typeName)
+	}
+	def.AttrType = attrType
+	if tokens.peek() == "(" {
+		values, err := tokens.list("precision")
+		if err != nil {
+			return nil, err
+		}
+		if len(values) != 2 {
+			return nil, fmt.Errorf("expected the precision and the scale of '%s'", name)
+		}
+		precision, pErr := strconv.ParseInt(values[0], 10, 16)
+		scale, sErr := strconv.ParseInt(values[1], 10, 16)
+		if pErr != nil || sErr != nil {
+			return nil, fmt.Errorf("invalid precision and scale '%s, %s'", values[0], values[1])
+		}
+		def.Precision = int16(precision)
+		def.Scale = int16(scale)
+	}
+	for !tokens.done() {
+		switch {
+		case tokens.keyword("array"):
+			def.IsArray = true
+		case tokens.keyword("encrypted"):
+			def.IsEncrypted = true
+		default:
+			return nil, fmt.Errorf("unexpected '%s'", tokens.peek())
+		}
+	}
+	return &createAttrDescStatement{def: def}, nil
+}
+
+func parseCreateNodeType(tokens *tokenizer) (statement, error) {
+	name, err := tokens.next("node type name")
+	if err != nil {
+		return nil, err
+	}
+	def := admin.NodeTypeDefinition{Name: name}
+	for !tokens.done() {
+		switch {
+		case tokens.keyword("extends"):
+			def.Parent, err = tokens.next("parent node type")
+		case tokens.keyword("attributes"):
+			def.Attributes, err = tokens.list("attribute name")
+		case tokens.keyword("pkeys"):
+			def.PKeyAttributes, err = tokens.list("attribute name")
+		default:
+			return nil, fmt.Errorf("unexpected '%s'", tokens.peek())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &createNodeTypeStatement{def: def}, nil
+}
+
+func parseCreateEdgeType(tokens *tokenizer) (statement, error) {
+	name, err := tokens.next("edge type name")
+	if err != nil {
+		return nil, err
+	}
+	def := admin.EdgeTypeDefinition{Name: name, Direction: types.DirectionTypeBiDirectional}
+	for !tokens.done() {
+		if direction, ok := directionNames[strings.ToLower(tokens.peek())]; ok {
+			tokens.pos++
+			def.Direction = direction
+			continue
+		}
+		switch {
+		case tokens.keyword("extends"):
+			def.Parent, err = tokens.next("parent edge type")
+		case tokens.keyword("from"):
+			def.FromNodeType, err = tokens.next("from node type")
+		case tokens.keyword("to"):
+			def.ToNodeType, err = tokens.next("to node type")
+		case tokens.keyword("attributes"):
+			def.Attributes, err = tokens.list("attribute name")
+		default:
+			return nil, fmt.Errorf("unexpected '%s'", tokens.peek())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &createEdgeTypeStatement{def: def}, nil
+}
+
+func parseCreateIndex(tokens *tokenizer, unique bool) (statement, error) {
+	name, err := tokens.next("index name")
+	if err != nil {
+		return nil, err
+	}
+	def := admin.IndexDefinition{Name: name, IsUnique: unique}
+	for !tokens.done() {
+		switch {
+		case tokens.keyword("attributes"):
+			def.Attributes, err = tokens.list("attribute name")
+		case tokens.keyword("on"):
+			def.NodeTypes, err = tokens.names("node type")
+		default:
+			return nil, fmt.Errorf("unexpected '%s'", tokens.peek())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &createIndexStatement{def: def}, nil
+}
+
+func parseCreateUser(tokens *tokenizer) (statement, error) {
+	name, err := tokens.next("user name")
+	if err != nil {
+		return nil, err
+	}
+	def := admin.UserDefinition{Name: name}
+	for !tokens.done() {
+		switch {
+		case tokens.keyword("password"):
+			var password string
+			password, err = tokens.next("password")
+			def.Password = []byte(password)
+		case tokens.keyword("roles"):
+			def.Roles, err = tokens.list("role name")
+		default:
+			return nil, fmt.Errorf("unexpected '%s'", tokens.peek())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &createUserStatement{def: def}, nil
+}
+
+func parseShow(tokens *tokenizer) (statement, error) {
+	what, err := tokens.next("what to show")
+	if err != nil {
+		return nil, err
+	}
+	what = strings.ToLower(what)
+	switch what {
+	case "info", "users", "connections", "indices", "types", "attrdescs":
+		return &showStatement{what: what}, nil
+	case "indexes":
+		return &showStatement{what: "indices"}, nil
+	}
+	return nil, fmt.Errorf("cannot show '%s'", what)
+}
+
+func parseKill(tokens *tokenizer) (statement, error) {
+	if err := tokens.expect("connection"); err != nil {
+		return nil, err
+	}
+	sessionId, err := tokens.next("session id")
+	if err != nil {
+		return nil, err
+	}
+	id, pErr := strconv.ParseInt(sessionId, 10, 64)
+	if pErr != nil {
+		return nil, fmt.Errorf("invalid session id '%s'", sessionId)
+	}
+	return &killConnectionStatement{sessionId: id}, nil
+}
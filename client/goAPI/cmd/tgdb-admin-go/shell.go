@@ -0,0 +1,428 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: shell.go
+ * SVN id: $id: $
+ *
+ */
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/admin"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"io"
+	"strings"
+)
+
+const prompt = "tgdb-admin> "
+
+const helpText = `Statements:
+  connect <url> [<user> [<password>]]
+  create attrdesc <name> as <type>[(<precision>, <scale>)] [array] [encrypted]
+  create nodetype <name> [extends <parent>] [attributes (<attr>, ...)] [pkeys (<attr>, ...)]
+  create edgetype <name> [extends <parent>] [directed|undirected|bidirected] from <nodetype> to <nodetype> [attributes (<attr>, ...)]
+  create [unique] index <name> attributes (<attr>, ...) [on <nodetype>, ...]
+  create user <name> password <password> [roles (<role>, ...)]
+  show info|users|connections|indices|types|attrdescs
+  describe <type>
+  kill connection <session id>
+  checkpoint
+  stop server
+  disconnect
+  exit`
+
+// shell executes admin statements read from a script or typed interactively, on the admin connection opened by
+// the last connect statement
+type shell struct {
+	conn    admin.TGAdminConnection
+	connect func(url, user, password string) (admin.TGAdminConnection, error)
+	printer *printer
+	exit    bool
+}
+
+func newShell(connect func(url, user, password string) (admin.TGAdminConnection, error), out io.Writer, format string) *shell {
+	return &shell{connect: connect, printer: &printer{out: out, format: format}}
+}
+
+// run executes the statements of the input line by line. Interactively, errors are reported and the shell goes on
+// w/ the next statement, whereas a script stops at the first failing statement.
+func (obj *shell) run(in io.Reader, interactive bool) error {
+	scanner := bufio.NewScanner(in)
+	for lineNo := 1; !obj.exit; lineNo++ {
+		if interactive {
+			fmt.Fprint(obj.printer.out, prompt)
+		}
+		if !scanner.Scan() {
+			break
+		}
+		err := obj.executeLine(scanner.Text())
+		if err == nil {
+			continue
+		}
+		if !interactive {
+			return fmt.Errorf("line %d: %s", lineNo, err.Error())
+		}
+		obj.printer.printError(err)
+	}
+	return scanner.Err()
+}
+
+func (obj *shell) executeLine(line string) error {
+	stmt, err := parseStatement(line)
+	if err != nil || stmt == nil {
+		return err
+	}
+	return stmt.execute(obj)
+}
+
+func (obj *shell) close() {
+	if obj.conn != nil {
+		_ = obj.conn.Disconnect()
+		obj.conn = nil
+	}
+}
+
+func (obj *shell) connection() (admin.TGAdminConnection, error) {
+	if obj.conn == nil {
+		return nil, fmt.Errorf("not connected - use 'connect <url> <user> <password>' first")
+	}
+	return obj.conn, nil
+}
+
+// tgError converts the error of an admin call, which is a nil interface on success
+func tgError(err types.TGError) error {
+	if err == nil {
+		return nil
+	}
+	return err
+}
+
+func (obj *connectStatement) execute(sh *shell) error {
+	sh.close()
+	conn, err := sh.connect(obj.url, obj.user, obj.password)
+	if err != nil {
+		return err
+	}
+	sh.conn = conn
+	return sh.printer.printMessage(fmt.Sprintf("Connected to '%s'", obj.url))
+}
+
+func (obj *disconnectStatement) execute(sh *shell) error {
+	if _, err := sh.connection(); err != nil {
+		return err
+	}
+	sh.close()
+	return sh.printer.printMessage("Disconnected")
+}
+
+func (obj *createAttrDescStatement) execute(sh *shell) error {
+	conn, err := sh.connection()
+	if err != nil {
+		return err
+	}
+	if err = tgError(conn.CreateAttributeDescriptor(obj.def)); err != nil {
+		return err
+	}
+	return sh.printer.printMessage(fmt.Sprintf("Attribute descriptor '%s' created", obj.def.Name))
+}
+
+func (obj *createNodeTypeStatement) execute(sh *shell) error {
+	conn, err := sh.connection()
+	if err != nil {
+		return err
+	}
+	if err = tgError(conn.CreateNodeType(obj.def)); err != nil {
+		return err
+	}
+	return sh.printer.printMessage(fmt.Sprintf("Node type '%s' created", obj.def.Name))
+}
+
+func (obj *createEdgeTypeStatement) execute(sh *shell) error {
+	conn, err := sh.connection()
+	if err != nil {
+		return err
+	}
+	if err = tgError(conn.CreateEdgeType(obj.def)); err != nil {
+		return err
+	}
+	return sh.printer.printMessage(fmt.Sprintf("Edge type '%s' created", obj.def.Name))
+}
+
+func (obj *createIndexStatement) execute(sh *shell) error {
+	conn, err := sh.connection()
+	if err != nil {
+		return err
+	}
+	if err = tgError(conn.CreateIndex(obj.def)); err != nil {
+		return err
+	}
+	return sh.printer.printMessage(fmt.Sprintf("Index '%s' created", obj.def.Name))
+}
+
+func (obj *createUserStatement) execute(sh *shell) error {
+	conn, err := sh.connection()
+	if err != nil {
+		return err
+	}
+	if err = tgError(conn.CreateUser(obj.def)); err != nil {
+		return err
+	}
+	return sh.printer.printMessage(fmt.Sprintf("User '%s' created", obj.def.Name))
+}
+
+func (obj *showStatement) execute(sh *shell) error {
+	conn, err := sh.connection()
+	if err != nil {
+		return err
+	}
+	var result *table
+	switch obj.what {
+	case "info":
+		result, err = infoTable(conn)
+	case "users":
+		result, err = usersTable(conn)
+	case "connections":
+		result, err = connectionsTable(conn)
+	case "indices":
+		result, err = indicesTable(conn)
+	case "types":
+		result, err = typesTable(conn)
+	case "attrdescs":
+		result, err = attrDescsTable(conn)
+	}
+	if err != nil {
+		return err
+	}
+	return sh.printer.printTable(result)
+}
+
+func (obj *describeStatement) execute(sh *shell) error {
+	conn, err := sh.connection()
+	if err != nil {
+		return err
+	}
+	typeInfo, tErr := conn.Describe(obj.typeName)
+	if tErr != nil {
+		return tErr
+	}
+	result := newTable("Property", "Value")
+	result.add("Name", typeInfo.GetName())
+	if typeInfo.GetSystemType() == types.SystemTypeEdge {
+		result.add("Kind", "edgetype")
+	} else {
+		result.add("Kind", "nodetype")
+	}
+	result.add("Parent", typeInfo.GetParentName())
+	for _, attrDesc := range typeInfo.GetAttributeDescriptors() {
+		result.add("Attribute", fmt.Sprintf("%s %s", attrDesc.GetName(), attrDescTypeName(attrDesc)))
+	}
+	if typeInfo.GetSystemType() == types.SystemTypeEdge {
+		result.add("Direction", directionName(typeInfo.GetDirectionType()))
+		result.add("From", typeInfo.GetFromNodeType())
+		result.add("To", typeInfo.GetToNodeType())
+	} else {
+		result.add("PKeys", typeInfo.GetPKeyAttributeNames())
+		for _, index := range typeInfo.GetIndices() {
+			result.add("Index", fmt.Sprintf("%s (%s)%s", index.GetName(), strings.Join(index.GetAttributeNames(), ", "), uniqueSuffix(index.IsUnique())))
+		}
+	}
+	result.add("Entries", typeInfo.GetNumEntries())
+	return sh.printer.printTable(result)
+}
+
+func (obj *killConnectionStatement) execute(sh *shell) error {
+	conn, err := sh.connection()
+	if err != nil {
+		return err
+	}
+	if err = tgError(conn.KillConnection(obj.sessionId)); err != nil {
+		return err
+	}
+	return sh.printer.printMessage(fmt.Sprintf("Connection '%d' killed", obj.sessionId))
+}
+
+func (obj *checkpointStatement) execute(sh *shell) error {
+	conn, err := sh.connection()
+	if err != nil {
+		return err
+	}
+	if err = tgError(conn.CheckpointServer()); err != nil {
+		return err
+	}
+	return sh.printer.printMessage("Checkpoint done")
+}
+
+func (obj *stopServerStatement) execute(sh *shell) error {
+	conn, err := sh.connection()
+	if err != nil {
+		return err
+	}
+	if err = tgError(conn.StopServer()); err != nil {
+		return err
+	}
+	return sh.printer.printMessage("Server stopping")
+}
+
+func (obj *helpStatement) execute(sh *shell) error {
+	_, err := fmt.Fprintln(sh.printer.out, helpText)
+	return err
+}
+
+func (obj *exitStatement) execute(sh *shell) error {
+	sh.exit = true
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////
+// Tables of the show statements
+/////////////////////////////////////////////////////////////////
+
+func uniqueSuffix(unique bool) string {
+	if unique {
+		return " unique"
+	}
+	return ""
+}
+
+func attrDescTypeName(attrDesc types.TGAttributeDescriptor) string {
+	typeName := attrTypeName(attrDesc.GetAttrType())
+	if attrDesc.GetAttrType() == types.AttributeTypeNumber {
+		typeName = fmt.Sprintf("%s(%d,%d)", typeName, attrDesc.GetPrecision(), attrDesc.GetScale())
+	}
+	if attrDesc.IsAttributeArray() {
+		typeName += "[]"
+	}
+	return typeName
+}
+
+func infoTable(conn admin.TGAdminConnection) (*table, error) {
+	info, err := conn.GetInfo()
+	if err != nil {
+		return nil, err
+	}
+	result := newTable("Section", "Name", "Value")
+	if status := info.GetServerStatus(); status != nil {
+		result.add("Server", "Name", status.GetName())
+		result.add("Server", "ProcessId", status.GetProcessId())
+		result.add("Server", "Uptime", status.GetUptime().String())
+	}
+	for _, memType := range []admin.MemType{admin.MemoryProcess, admin.MemoryShared} {
+		section := "ProcessMemory"
+		if memType == admin.MemoryShared {
+			section = "SharedMemory"
+		}
+		if memory := info.GetMemoryInfo(memType); memory != nil {
+			result.add(section, "Used", memory.GetUsedMemory())
+			result.add(section, "Free", memory.GetFreeMemory())
+			result.add(section, "Max", memory.GetMaxMemory())
+		}
+	}
+	for _, listener := range info.GetNetListenersInfo() {
+		result.add("Listener", listener.GetListenerName(), fmt.Sprintf("port %s, %d/%d connections", listener.GetPortNumber(), listener.GetCurrentConnections(), listener.GetMaxConnections()))
+	}
+	if transactions := info.GetTransactionsInfo(); transactions != nil {
+		result.add("Transactions", "Processed", transactions.GetTransactionProcessedCount())
+		result.add("Transactions", "Successful", transactions.GetTransactionSuccessfulCount())
+		result.add("Transactions", "Pending", transactions.GetPendingTransactionsCount())
+		result.add("Transactions", "AverageProcessingTime", transactions.GetAverageProcessingTime())
+	}
+	if cache := info.GetCacheInfo(); cache != nil {
+		result.add("Cache", "DataEntries", fmt.Sprintf("%d/%d", cache.GetDataCacheEntries(), cache.GetDataCacheMaxEntries()))
+		result.add("Cache", "DataHits", cache.GetDataCacheHits())
+		result.add("Cache", "DataMisses", cache.GetDataCacheMisses())
+		result.add("Cache", "IndexEntries", fmt.Sprintf("%d/%d", cache.GetIndexCacheEntries(), cache.GetIndexCacheMaxEntries()))
+		result.add("Cache", "IndexHits", cache.GetIndexCacheHits())
+		result.add("Cache", "IndexMisses", cache.GetIndexCacheMisses())
+	}
+	if database := info.GetDatabaseInfo(); database != nil {
+		result.add("Database", "Size", database.GetDbSize())
+		result.add("Database", "DataUsed", database.GetDataUsed())
+		result.add("Database", "DataFree", database.GetDataFree())
+		result.add("Database", "IndexUsed", database.GetIndexUsed())
+		result.add("Database", "IndexFree", database.GetIndexFree())
+	}
+	return result, nil
+}
+
+func usersTable(conn admin.TGAdminConnection) (*table, error) {
+	users, err := conn.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+	result := newTable("Id", "Name", "Type", "Roles", "Permissions")
+	for _, user := range users {
+		result.add(user.GetSystemId(), user.GetName(), user.GetType(), user.GetRoles(), user.GetPermissions())
+	}
+	return result, nil
+}
+
+func connectionsTable(conn admin.TGAdminConnection) (*table, error) {
+	connections, err := conn.GetConnections()
+	if err != nil {
+		return nil, err
+	}
+	result := newTable("SessionId", "User", "ClientId", "Listener", "RemoteAddress", "CreatedSeconds")
+	for _, connInfo := range connections {
+		result.add(connInfo.GetSessionID(), connInfo.GetUserName(), connInfo.GetClientID(), connInfo.GetListenerName(), connInfo.GetRemoteAddress(), connInfo.GetCreatedTimeInSeconds())
+	}
+	return result, nil
+}
+
+func indicesTable(conn admin.TGAdminConnection) (*table, error) {
+	indices, err := conn.GetIndices()
+	if err != nil {
+		return nil, err
+	}
+	result := newTable("Id", "Name", "Unique", "Attributes", "NodeTypes", "Entries", "Status")
+	for _, index := range indices {
+		result.add(index.GetSystemId(), index.GetName(), index.IsUnique(), index.GetAttributeNames(), index.GetNodeTypes(), index.GetNumEntries(), index.GetStatus())
+	}
+	return result, nil
+}
+
+func typesTable(conn admin.TGAdminConnection) (*table, error) {
+	typeInfos, err := conn.GetTypes()
+	if err != nil {
+		return nil, err
+	}
+	result := newTable("Id", "Kind", "Name", "Parent", "Attributes", "Keys", "Entries")
+	for _, typeInfo := range typeInfos {
+		attrNames := make([]string, 0, len(typeInfo.GetAttributeDescriptors()))
+		for _, attrDesc := range typeInfo.GetAttributeDescriptors() {
+			attrNames = append(attrNames, attrDesc.GetName())
+		}
+		if typeInfo.GetSystemType() == types.SystemTypeEdge {
+			keys := fmt.Sprintf("%s -> %s", typeInfo.GetFromNodeType(), typeInfo.GetToNodeType())
+			result.add(typeInfo.GetSystemId(), "edgetype", typeInfo.GetName(), typeInfo.GetParentName(), attrNames, keys, typeInfo.GetNumEntries())
+		} else {
+			result.add(typeInfo.GetSystemId(), "nodetype", typeInfo.GetName(), typeInfo.GetParentName(), attrNames, typeInfo.GetPKeyAttributeNames(), typeInfo.GetNumEntries())
+		}
+	}
+	return result, nil
+}
+
+func attrDescsTable(conn admin.TGAdminConnection) (*table, error) {
+	attrDescs, err := conn.GetAttributeDescriptors()
+	if err != nil {
+		return nil, err
+	}
+	result := newTable("Id", "Name", "Type", "Encrypted")
+	for _, attrDesc := range attrDescs {
+		result.add(attrDesc.GetAttributeId(), attrDesc.GetName(), attrDescTypeName(attrDesc), attrDesc.IsEncrypted())
+	}
+	return result, nil
+}
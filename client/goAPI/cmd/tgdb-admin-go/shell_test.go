@@ -0,0 +1,195 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: shell_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/admin"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// scriptConnection records the schema objects created by a script, and reports a single user
+type scriptConnection struct {
+	admin.TGAdminConnection
+	attrDescs    []admin.AttributeDefinition
+	nodeTypes    []admin.NodeTypeDefinition
+	indices      []admin.IndexDefinition
+	checkpoints  int
+	disconnected bool
+}
+
+func (obj *scriptConnection) CreateAttributeDescriptor(def admin.AttributeDefinition) types.TGError {
+	obj.attrDescs = append(obj.attrDescs, def)
+	return nil
+}
+
+func (obj *scriptConnection) CreateNodeType(def admin.NodeTypeDefinition) types.TGError {
+	obj.nodeTypes = append(obj.nodeTypes, def)
+	return nil
+}
+
+func (obj *scriptConnection) CreateIndex(def admin.IndexDefinition) types.TGError {
+	obj.indices = append(obj.indices, def)
+	return nil
+}
+
+func (obj *scriptConnection) CheckpointServer() types.TGError {
+	obj.checkpoints++
+	return nil
+}
+
+func (obj *scriptConnection) GetUsers() ([]admin.TGUserInfo, types.TGError) {
+	user := admin.NewPrincipalInfoImpl(1, "admin", 1, []string{"root"}, []string{"all"})
+	return []admin.TGUserInfo{user}, nil
+}
+
+func (obj *scriptConnection) Disconnect() types.TGError {
+	obj.disconnected = true
+	return nil
+}
+
+func newScriptShell(conn *scriptConnection, format string) (*shell, *bytes.Buffer, *[]string) {
+	out := &bytes.Buffer{}
+	urls := make([]string, 0)
+	connect := func(url, user, password string) (admin.TGAdminConnection, error) {
+		urls = append(urls, url+" "+user+" "+password)
+		return conn, nil
+	}
+	return newShell(connect, out, format), out, &urls
+}
+
+func TestParseStatementsOfMetadataScript(t *testing.T) {
+	stmt, err := parseStatement("create attrdesc amount as number(50,20)")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	attrDesc := stmt.(*createAttrDescStatement).def
+	if attrDesc.Name != "amount" || attrDesc.AttrType != types.AttributeTypeNumber || attrDesc.Precision != 50 || attrDesc.Scale != 20 {
+		t.Errorf("Unexpected attribute descriptor: %+v", attrDesc)
+	}
+
+	stmt, err = parseStatement("create nodetype basicnode attributes (name, age) pkeys (name)")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	nodeType := stmt.(*createNodeTypeStatement).def
+	if nodeType.Name != "basicnode" || !reflect.DeepEqual(nodeType.Attributes, []string{"name", "age"}) || !reflect.DeepEqual(nodeType.PKeyAttributes, []string{"name"}) {
+		t.Errorf("Unexpected node type: %+v", nodeType)
+	}
+
+	stmt, err = parseStatement("create unique index ageidx attributes (age) on basicnode")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	index := stmt.(*createIndexStatement).def
+	if !index.IsUnique || !reflect.DeepEqual(index.Attributes, []string{"age"}) || !reflect.DeepEqual(index.NodeTypes, []string{"basicnode"}) {
+		t.Errorf("Unexpected index: %+v", index)
+	}
+
+	stmt, err = parseStatement("  # create index ageidx attributes (age) on basicnode")
+	if err != nil || stmt != nil {
+		t.Errorf("Expected a comment to be skipped, got '%v' and '%v'", stmt, err)
+	}
+	if _, err = parseStatement("create attrdesc amount as money"); err == nil {
+		t.Error("Expected an error for an unknown attribute type")
+	}
+	if _, err = parseStatement("kill connection abc"); err == nil {
+		t.Error("Expected an error for a session id that is not a number")
+	}
+}
+
+func TestRunScript(t *testing.T) {
+	conn := &scriptConnection{}
+	sh, out, urls := newScriptShell(conn, formatTable)
+	script := strings.Join([]string{
+		"connect tcp://localhost:8222 admin admin",
+		"create attrdesc name as string",
+		"create attrdesc age as int",
+		"create nodetype basicnode attributes (name, age) pkeys (name)",
+		"create index ageidx attributes (age) on basicnode",
+		"checkpoint",
+		"show users",
+	}, "\n")
+	if err := sh.run(strings.NewReader(script), false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(*urls, []string{"tcp://localhost:8222 admin admin"}) {
+		t.Errorf("Unexpected connections: %v", *urls)
+	}
+	if len(conn.attrDescs) != 2 || len(conn.nodeTypes) != 1 || len(conn.indices) != 1 || conn.checkpoints != 1 {
+		t.Errorf("Unexpected statements executed: %+v", conn)
+	}
+	if !strings.Contains(out.String(), "Node type 'basicnode' created") || !strings.Contains(out.String(), "(1 rows)") {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+}
+
+func TestRunScriptStopsAtFirstError(t *testing.T) {
+	conn := &scriptConnection{}
+	sh, _, _ := newScriptShell(conn, formatTable)
+	script := "create attrdesc name as string\n"
+	err := sh.run(strings.NewReader(script), false)
+	if err == nil || !strings.Contains(err.Error(), "line 1") || !strings.Contains(err.Error(), "not connected") {
+		t.Errorf("Expected a not connected error on line 1, got '%v'", err)
+	}
+
+	sh.conn = conn
+	err = sh.run(strings.NewReader("checkpoint\ncreate nodetype\ncheckpoint\n"), false)
+	if err == nil || !strings.Contains(err.Error(), "line 2") || conn.checkpoints != 1 {
+		t.Errorf("Expected the script to stop on line 2, got '%v' after '%d' checkpoints", err, conn.checkpoints)
+	}
+}
+
+func TestRunInteractiveJson(t *testing.T) {
+	conn := &scriptConnection{}
+	sh, out, _ := newScriptShell(conn, formatJson)
+	sh.conn = conn
+	err := sh.run(strings.NewReader("show users\nshow nothing\nexit\ncheckpoint\n"), true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if conn.checkpoints != 0 {
+		t.Error("Expected no statement to run after exit")
+	}
+	lines := strings.Split(strings.TrimSpace(strings.Replace(out.String(), prompt, "", -1)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Unexpected output:\n%s", out.String())
+	}
+	var users []map[string]interface{}
+	if err = json.Unmarshal([]byte(lines[0]), &users); err != nil {
+		t.Fatalf("Unexpected JSON '%s': %v", lines[0], err)
+	}
+	if len(users) != 1 || users[0]["Name"] != "admin" || !reflect.DeepEqual(users[0]["Roles"], []interface{}{"root"}) {
+		t.Errorf("Unexpected users: %v", users)
+	}
+	var status map[string]string
+	if err = json.Unmarshal([]byte(lines[1]), &status); err != nil || status["status"] != "error" {
+		t.Errorf("Expected an error status, got '%s'", lines[1])
+	}
+
+	sh.close()
+	if !conn.disconnected || sh.conn != nil {
+		t.Error("Expected the connection to be closed")
+	}
+}
@@ -0,0 +1,427 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: importer.go
+ * SVN id: $id: $
+ *
+ */
+
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/connection"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// importStats counts the rows of an import
+type importStats struct {
+	Rows     int
+	Nodes    int
+	Edges    int
+	Rejected int
+}
+
+// importer creates the nodes and edges of the mapping through a bulk loader, writing the rows it cannot import
+// to the reject file along w/ the reason
+type importer struct {
+	conn      types.TGConnection
+	gof       types.TGGraphObjectFactory
+	gmd       types.TGGraphMetadata
+	mapping   *Mapping
+	batchSize int
+	loader    *connection.BulkLoader
+	rejects   *csv.Writer
+	stats     importStats
+}
+
+func newImporter(conn types.TGConnection, mapping *Mapping, batchSize int, rejects io.Writer) (*importer, error) {
+	gof, err := conn.GetGraphObjectFactory()
+	if err != nil {
+		return nil, err
+	}
+	gmd, err := conn.GetGraphMetadata(true)
+	if err != nil {
+		return nil, err
+	}
+	loader := connection.NewBulkLoader(conn)
+	loader.SetChunkSize(batchSize)
+	newImporter := importer{
+		conn:      conn,
+		gof:       gof,
+		gmd:       gmd,
+		mapping:   mapping,
+		batchSize: batchSize,
+		loader:    loader,
+		rejects:   csv.NewWriter(rejects),
+	}
+	return &newImporter, nil
+}
+
+// run imports all the files of the mapping, the node files first
+func (obj *importer) run() (importStats, error) {
+	defer obj.rejects.Flush()
+	for _, nodeMapping := range obj.mapping.Nodes {
+		err := obj.importNodes(nodeMapping)
+		if err != nil {
+			return obj.stats, err
+		}
+	}
+	// Commit all the nodes, so that the lookups of the edge files see the connection idle
+	if _, err := obj.loader.Close(); err != nil {
+		return obj.stats, err
+	}
+	for _, edgeMapping := range obj.mapping.Edges {
+		err := obj.importEdges(edgeMapping)
+		if err != nil {
+			return obj.stats, err
+		}
+	}
+	return obj.stats, nil
+}
+
+func parseDirection(direction string) (types.TGDirectionType, error) {
+	switch strings.ToLower(direction) {
+	case "directed":
+		return types.DirectionTypeDirected, nil
+	case "undirected":
+		return types.DirectionTypeUnDirected, nil
+	case "bidirectional", "":
+		return types.DirectionTypeBiDirectional, nil
+	}
+	return types.DirectionTypeBiDirectional, fmt.Errorf("unknown direction '%s'", direction)
+}
+
+// csvFile reads the records of a file by column name
+type csvFile struct {
+	name    string
+	file    *os.File
+	reader  *csv.Reader
+	columns map[string]int
+	line    int
+}
+
+func openCsvFile(name string, delimiter rune) (*csvFile, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(file)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("unable to read the header of '%s': %s", name, err.Error())
+	}
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.TrimSpace(column)] = i
+	}
+	return &csvFile{name: name, file: file, reader: reader, columns: columns, line: 1}, nil
+}
+
+// next returns the next record, or nil at the end of the file
+func (obj *csvFile) next() ([]string, error) {
+	record, err := obj.reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	obj.line++
+	if err != nil {
+		return nil, fmt.Errorf("unable to read line %d of '%s': %s", obj.line, obj.name, err.Error())
+	}
+	return record, nil
+}
+
+// value returns the trimmed cell of the column, or an empty string if the record is too short
+func (obj *csvFile) value(record []string, column string) (string, error) {
+	idx, ok := obj.columns[column]
+	if !ok {
+		return "", fmt.Errorf("unknown column '%s'", column)
+	}
+	if idx >= len(record) {
+		return "", nil
+	}
+	return strings.TrimSpace(record[idx]), nil
+}
+
+func (obj *csvFile) close() {
+	obj.file.Close()
+}
+
+func (obj *importer) reject(file *csvFile, line int, record []string, reason string) {
+	obj.stats.Rejected++
+	row := append([]string{file.name, strconv.Itoa(line), reason}, record...)
+	obj.rejects.Write(row)
+}
+
+// convertValue converts the cell as the attribute of the name would, so that a bad value rejects the row
+// rather than the commit of the whole batch
+func (obj *importer) convertValue(attrName string, cell string) (interface{}, error) {
+	desc, err := obj.gmd.GetAttributeDescriptor(attrName)
+	if err != nil {
+		return nil, err
+	}
+	if desc == nil || reflect.ValueOf(desc).IsNil() {
+		return cell, nil
+	}
+	attr, err := model.CreateAttributeWithDesc(nil, desc.(*model.AttributeDescriptor), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := attr.SetValue(cell); err != nil {
+		return nil, fmt.Errorf("invalid value '%s' of attribute '%s'", cell, attrName)
+	}
+	return attr.GetValue(), nil
+}
+
+// rowValues converts the cells of the columns, and the constants, into attribute values, skipping empty cells
+func (obj *importer) rowValues(file *csvFile, record []string, columns map[string]string, constants map[string]string) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(columns)+len(constants))
+	for attrName, constant := range constants {
+		value, err := obj.convertValue(attrName, constant)
+		if err != nil {
+			return nil, err
+		}
+		values[attrName] = value
+	}
+	for attrName, column := range columns {
+		cell, err := file.value(record, column)
+		if err != nil {
+			return nil, err
+		}
+		if cell == "" {
+			continue
+		}
+		value, err := obj.convertValue(attrName, cell)
+		if err != nil {
+			return nil, err
+		}
+		values[attrName] = value
+	}
+	return values, nil
+}
+
+func (obj *importer) importNodes(nodeMapping NodeMapping) error {
+	nodeType, tErr := obj.gmd.GetNodeType(nodeMapping.NodeType)
+	if tErr != nil {
+		return tErr
+	}
+	if nodeType == nil || reflect.ValueOf(nodeType).IsNil() {
+		return fmt.Errorf("unknown node type '%s' in the mapping of '%s'", nodeMapping.NodeType, nodeMapping.File)
+	}
+	file, err := openCsvFile(nodeMapping.File, obj.mapping.delimiter())
+	if err != nil {
+		return err
+	}
+	defer file.close()
+	for {
+		record, err := file.next()
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			return nil
+		}
+		obj.stats.Rows++
+		if reason := obj.importNode(nodeMapping, nodeType, file, record); reason != "" {
+			obj.reject(file, file.line, record, reason)
+		}
+	}
+}
+
+// importNode adds the node of the record to the loader, returning the reason if the record is rejected
+func (obj *importer) importNode(nodeMapping NodeMapping, nodeType types.TGNodeType, file *csvFile, record []string) string {
+	values, err := obj.rowValues(file, record, nodeMapping.Attributes, nodeMapping.Constants)
+	if err != nil {
+		return err.Error()
+	}
+	keyValues := make(map[string]interface{}, len(nodeMapping.Key))
+	for _, attrName := range nodeMapping.Key {
+		value, ok := values[attrName]
+		if !ok {
+			return fmt.Sprintf("missing value of key attribute '%s'", attrName)
+		}
+		keyValues[attrName] = value
+	}
+	node, err := obj.gof.CreateNodeInGraph(nodeType)
+	if err != nil {
+		return err.Error()
+	}
+	for attrName, value := range values {
+		if err := node.SetOrCreateAttribute(attrName, value); err != nil {
+			return err.Error()
+		}
+	}
+	key := nodeKey(nodeMapping.NodeType, keyValues)
+	if obj.loader.HasNode(key) {
+		return "duplicate key " + key
+	}
+	if err := obj.loader.AddNode(key, node); err != nil {
+		return err.Error()
+	}
+	obj.stats.Nodes++
+	return ""
+}
+
+// edgeRow is an edge read from a record, waiting for its end nodes to be resolved
+type edgeRow struct {
+	record []string
+	line   int
+	edge   connection.BulkEdge
+}
+
+func (obj *importer) importEdges(edgeMapping EdgeMapping) error {
+	bEdge := connection.BulkEdge{}
+	if edgeMapping.EdgeType != "" {
+		edgeType, err := obj.gmd.GetEdgeType(edgeMapping.EdgeType)
+		if err != nil {
+			return err
+		}
+		if edgeType == nil || reflect.ValueOf(edgeType).IsNil() {
+			return fmt.Errorf("unknown edge type '%s' in the mapping of '%s'", edgeMapping.EdgeType, edgeMapping.File)
+		}
+		bEdge.EdgeType = edgeType
+	} else {
+		bEdge.Direction, _ = parseDirection(edgeMapping.Direction)
+	}
+	file, err := openCsvFile(edgeMapping.File, obj.mapping.delimiter())
+	if err != nil {
+		return err
+	}
+	defer file.close()
+
+	batch := make([]edgeRow, 0, obj.batchSize)
+	lookups := make(map[string]types.TGKey, 0)
+	for {
+		record, err := file.next()
+		if err != nil {
+			return err
+		}
+		if record != nil {
+			obj.stats.Rows++
+			row := edgeRow{record: record, line: file.line, edge: bEdge}
+			if reason := obj.readEdge(edgeMapping, file, &row, lookups); reason != "" {
+				obj.reject(file, file.line, record, reason)
+			} else {
+				batch = append(batch, row)
+			}
+		}
+		if len(batch) > 0 && (record == nil || len(batch) >= obj.batchSize) {
+			err = obj.loadEdges(file, batch, lookups)
+			if err != nil {
+				return err
+			}
+			batch = batch[:0]
+			lookups = make(map[string]types.TGKey, 0)
+		}
+		if record == nil {
+			return nil
+		}
+	}
+}
+
+// readEdge reads the keys of the end nodes of the record, noting those the loader does not know for lookup
+func (obj *importer) readEdge(edgeMapping EdgeMapping, file *csvFile, row *edgeRow, lookups map[string]types.TGKey) string {
+	values, err := obj.rowValues(file, row.record, edgeMapping.Attributes, edgeMapping.Constants)
+	if err != nil {
+		return err.Error()
+	}
+	row.edge.Attributes = values
+	keys := make([]string, 0, 2)
+	for _, endpoint := range []EndpointMapping{edgeMapping.From, edgeMapping.To} {
+		keyValues, err := obj.rowValues(file, row.record, endpoint.Key, nil)
+		if err != nil {
+			return err.Error()
+		}
+		if len(keyValues) != len(endpoint.Key) {
+			return fmt.Sprintf("missing key value of node type '%s'", endpoint.NodeType)
+		}
+		key := nodeKey(endpoint.NodeType, keyValues)
+		if !obj.loader.HasNode(key) && lookups[key] == nil {
+			compositeKey, err := obj.gof.CreateCompositeKey(endpoint.NodeType)
+			if err != nil {
+				return err.Error()
+			}
+			for attrName, value := range keyValues {
+				if err := compositeKey.SetOrCreateAttribute(attrName, value); err != nil {
+					return err.Error()
+				}
+			}
+			lookups[key] = compositeKey
+		}
+		keys = append(keys, key)
+	}
+	row.edge.FromKey, row.edge.ToKey = keys[0], keys[1]
+	return ""
+}
+
+// loadEdges looks up the end nodes stored in the database, then commits the edges of the batch. The rows whose end
+// node is not found, or could not be looked up, are rejected.
+func (obj *importer) loadEdges(file *csvFile, batch []edgeRow, lookups map[string]types.TGKey) error {
+	lookupErrs := make(map[string]types.TGError, 0)
+	if len(lookups) > 0 {
+		keys := make([]types.TGKey, 0, len(lookups))
+		for _, key := range lookups {
+			keys = append(keys, key)
+		}
+		results, err := obj.conn.GetEntitiesByKeys(keys, nil)
+		if err != nil {
+			return err
+		}
+		for key, compositeKey := range lookups {
+			result := results[compositeKey]
+			if result.Error != nil {
+				lookupErrs[key] = result.Error
+				continue
+			}
+			node, ok := result.Entity.(types.TGNode)
+			if !ok {
+				continue
+			}
+			if err := obj.loader.AddStoredNode(key, node); err != nil {
+				return err
+			}
+		}
+	}
+	for _, row := range batch {
+		if lookupErr := lookupErrs[row.edge.FromKey]; lookupErr != nil {
+			obj.reject(file, row.line, row.record, fmt.Sprintf("end node lookup failed: %s", lookupErr.GetErrorMsg()))
+			continue
+		}
+		if lookupErr := lookupErrs[row.edge.ToKey]; lookupErr != nil {
+			obj.reject(file, row.line, row.record, fmt.Sprintf("end node lookup failed: %s", lookupErr.GetErrorMsg()))
+			continue
+		}
+		if !obj.loader.HasNode(row.edge.FromKey) || !obj.loader.HasNode(row.edge.ToKey) {
+			obj.reject(file, row.line, row.record, "end node not found")
+			continue
+		}
+		if err := obj.loader.AddEdge(row.edge); err != nil {
+			return err
+		}
+		obj.stats.Edges++
+	}
+	_, err := obj.loader.Close()
+	return err
+}
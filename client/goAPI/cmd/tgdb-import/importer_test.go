@@ -0,0 +1,167 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: importer_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package main
+
+import (
+	"bytes"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// importConnection commits by giving the added entities ids, and finds the stored node of the 'Member' w/ name 'Stored'.
+// The lookup of the 'Member' w/ name 'Twice' fails, as if two nodes had the name.
+type importConnection struct {
+	types.TGConnection
+	gof     *model.GraphObjectFactory
+	nextId  int64
+	added   []types.TGEntity
+	stored  types.TGNode
+	commits int
+}
+
+func newImportConnection(t *testing.T) *importConnection {
+	conn := &importConnection{}
+	conn.gof = model.NewGraphObjectFactory(conn)
+	gmd := conn.gof.GetGraphMetaData()
+	name := model.NewAttributeDescriptorWithType("name", types.AttributeTypeString)
+	born := model.NewAttributeDescriptorWithType("born", types.AttributeTypeInteger)
+	gmd.SetAttributeDescriptors(map[string]types.TGAttributeDescriptor{"name": name, "born": born})
+	nodeType := model.NewNodeType("Member", nil)
+	nodeType.SetAttributeMap(map[string]*model.AttributeDescriptor{"name": name, "born": born})
+	gmd.SetNodeTypes(map[string]types.TGNodeType{"Member": nodeType})
+
+	stored, _ := conn.gof.CreateNodeInGraph(nodeType)
+	stored.SetOrCreateAttribute("name", "Stored")
+	stored.SetIsNew(false)
+	stored.SetEntityId(1000)
+	conn.stored = stored
+	return conn
+}
+
+func (obj *importConnection) GetGraphObjectFactory() (types.TGGraphObjectFactory, types.TGError) {
+	return obj.gof, nil
+}
+
+func (obj *importConnection) GetGraphMetadata(refresh bool) (types.TGGraphMetadata, types.TGError) {
+	return obj.gof.GetGraphMetaData(), nil
+}
+
+func (obj *importConnection) InsertEntity(entity types.TGEntity) types.TGError {
+	obj.added = append(obj.added, entity)
+	return nil
+}
+
+func (obj *importConnection) Commit() (types.TGResultSet, types.TGError) {
+	for _, entity := range obj.added {
+		obj.nextId++
+		entity.SetIsNew(false)
+		entity.SetEntityId(obj.nextId)
+	}
+	obj.added = nil
+	obj.commits++
+	return nil, nil
+}
+
+func (obj *importConnection) GetEntitiesByKeys(keys []types.TGKey, options types.TGQueryOption) (map[types.TGKey]types.TGKeyResult, types.TGError) {
+	results := make(map[types.TGKey]types.TGKeyResult, len(keys))
+	for _, key := range keys {
+		switch key.(*model.CompositeKey).GetAttributes()["name"].GetValue() {
+		case "Stored":
+			results[key] = types.TGKeyResult{Entity: obj.stored}
+		case "Twice":
+			errMsg := "Key 'Member:name=Twice' matches more than one entity"
+			results[key] = types.TGKeyResult{Error: exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")}
+		default:
+			results[key] = types.TGKeyResult{}
+		}
+	}
+	return results, nil
+}
+
+func writeTestFile(t *testing.T, dir string, name string, content string) {
+	if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("Unable to write '%s' w/ error '%s'", name, err.Error())
+	}
+}
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "members.csv", "name,year\nAnne,1950\n,1960\nAnne,1970\nBob,abc\nCarl,1980\n")
+	writeTestFile(t, dir, "parents.csv", "child,parent\nCarl,Anne\nAnne,Stored\nCarl,Nobody\nAnne,Twice\n")
+	writeTestFile(t, dir, "mapping.json", `{
+		"nodes": [{"file": "members.csv", "nodeType": "Member", "key": ["name"], "attributes": {"name": "name", "born": "year"}}],
+		"edges": [{"file": "parents.csv", "direction": "directed",
+			"from": {"nodeType": "Member", "key": {"name": "child"}},
+			"to": {"nodeType": "Member", "key": {"name": "parent"}},
+			"constants": {"relation": "parent"}}]
+	}`)
+	mapping, err := loadMapping(filepath.Join(dir, "mapping.json"))
+	if err != nil {
+		t.Fatalf("TestImport unable to load mapping w/ error '%s'", err.Error())
+	}
+
+	conn := newImportConnection(t)
+	var rejects bytes.Buffer
+	imp, err := newImporter(conn, mapping, 2, &rejects)
+	if err != nil {
+		t.Fatalf("TestImport unable to create importer w/ error '%s'", err.Error())
+	}
+	stats, err := imp.run()
+	if err != nil {
+		t.Fatalf("TestImport failed w/ error '%s'", err.Error())
+	}
+	expected := importStats{Rows: 9, Nodes: 2, Edges: 2, Rejected: 5}
+	if stats != expected {
+		t.Errorf("TestImport expected '%+v' and not '%+v'", expected, stats)
+	}
+	lines := strings.Split(strings.TrimSpace(rejects.String()), "\n")
+	if len(lines) != 5 || !strings.Contains(lines[0], "members.csv,3,missing value of key attribute 'name'") ||
+		!strings.Contains(lines[1], "members.csv,4,duplicate key") || !strings.Contains(lines[2], "members.csv,5,invalid value 'abc'") ||
+		!strings.Contains(lines[3], "parents.csv,4,end node not found,Carl,Nobody") ||
+		!strings.Contains(lines[4], "parents.csv,5,end node lookup failed: Key 'Member:name=Twice' matches more than one entity,Anne,Twice") {
+		t.Errorf("TestImport wrote wrong rejects '%s'", rejects.String())
+	}
+	if stored := conn.stored.GetEdges(); len(stored) != 1 {
+		t.Errorf("TestImport expected an edge to the stored node and not '%+v'", stored)
+	}
+}
+
+func TestLoadMappingErrors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := []string{
+		`{}`,
+		`{"nodes": [{"file": "a.csv", "nodeType": "Member"}]}`,
+		`{"nodes": [{"file": "a.csv", "nodeType": "Member", "key": ["name"]}]}`,
+		`{"edges": [{"file": "a.csv", "direction": "sideways", "from": {"nodeType": "A", "key": {"a": "a"}}, "to": {"nodeType": "B", "key": {"b": "b"}}}]}`,
+		`{"edges": [{"file": "a.csv", "from": {"nodeType": "A", "key": {"a": "a"}}}]}`,
+		`{"delimiter": ";;", "nodes": [{"file": "a.csv", "nodeType": "Member", "key": ["name"], "attributes": {"name": "name"}}]}`,
+	}
+	for _, content := range invalid {
+		writeTestFile(t, dir, "mapping.json", content)
+		if _, err := loadMapping(filepath.Join(dir, "mapping.json")); err == nil {
+			t.Errorf("TestLoadMappingErrors expected an error for '%s'", content)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: main.go
+ * SVN id: $id: $
+ *
+ */
+
+// tgdb-import creates nodes and edges from CSV files, as described by a mapping file, e.g.
+//
+//	tgdb-import -url tcp://scott@localhost:8222 -password scott -mapping hierarchy.json -errors rejected.csv
+//
+// The rows that cannot be imported are written to the error file, each preceded by the file name, the line
+// number and the reason of the rejection.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/connection"
+	"os"
+)
+
+func main() {
+	url := flag.String("url", "tcp://scott@localhost:8222", "URL of the TGDB server")
+	user := flag.String("user", "", "user name, if not part of the URL")
+	password := flag.String("password", "", "password of the user")
+	mappingFile := flag.String("mapping", "", "mapping of the CSV files to node and edge types")
+	errorFile := flag.String("errors", "rejected.csv", "file to write the rejected rows to")
+	batchSize := flag.Int("batch", connection.DefaultBulkChunkSize, "number of entities committed together")
+	flag.Parse()
+
+	if *mappingFile == "" || *batchSize <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	mapping, err := loadMapping(*mappingFile)
+	if err != nil {
+		exitOnError(err)
+	}
+
+	rejects, err := os.Create(*errorFile)
+	if err != nil {
+		exitOnError(err)
+	}
+	defer rejects.Close()
+	fmt.Fprintln(rejects, "file,line,reason,record")
+
+	conn, cErr := connection.NewTGConnectionFactory().CreateConnection(*url, *user, *password, nil)
+	if cErr != nil {
+		exitOnError(cErr)
+	}
+	if cErr = conn.Connect(); cErr != nil {
+		exitOnError(cErr)
+	}
+	defer conn.Disconnect()
+
+	imp, err := newImporter(conn, mapping, *batchSize, rejects)
+	if err != nil {
+		exitOnError(err)
+	}
+	stats, err := imp.run()
+	fmt.Printf("Read %d rows: imported %d nodes and %d edges, rejected %d rows\n", stats.Rows, stats.Nodes, stats.Edges, stats.Rejected)
+	if err != nil {
+		exitOnError(err)
+	}
+	if stats.Rejected > 0 {
+		fmt.Printf("Rejected rows are in '%s'\n", *errorFile)
+	}
+}
+
+func exitOnError(err error) {
+	fmt.Fprintf(os.Stderr, "tgdb-import: %s\n", err.Error())
+	os.Exit(1)
+}
@@ -0,0 +1,161 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: mapping.go
+ * SVN id: $id: $
+ *
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Mapping describes how the rows of CSV files become nodes and edges. The first row of each file names its
+// columns. Files are imported in order - all the node files first, then the edge files.
+//
+//	{
+//	  "nodes": [{"file": "members.csv", "nodeType": "houseMemberType", "key": ["memberName"],
+//	             "attributes": {"memberName": "memberName", "yearBorn": "yearBorn"}}],
+//	  "edges": [{"file": "parents.csv", "direction": "directed",
+//	             "from": {"nodeType": "houseMemberType", "key": {"memberName": "id"}},
+//	             "to": {"nodeType": "marriageType", "key": {"marriageId": "couple"}},
+//	             "constants": {"relType": "child"}}]
+//	}
+type Mapping struct {
+	Delimiter string        `json:"delimiter"`
+	Nodes     []NodeMapping `json:"nodes"`
+	Edges     []EdgeMapping `json:"edges"`
+}
+
+// NodeMapping creates a node of the node type from each row of the file. Attributes maps attribute names to
+// column names, and Key lists the attributes that identify the node for the edges of the import.
+type NodeMapping struct {
+	File       string            `json:"file"`
+	NodeType   string            `json:"nodeType"`
+	Key        []string          `json:"key"`
+	Attributes map[string]string `json:"attributes"`
+	Constants  map[string]string `json:"constants"`
+}
+
+// EndpointMapping identifies the node at one end of an edge, by the key attributes of a node type mapped to
+// column names. The node is either imported by a node mapping, or already stored in the database.
+type EndpointMapping struct {
+	NodeType string            `json:"nodeType"`
+	Key      map[string]string `json:"key"`
+}
+
+// EdgeMapping creates an edge between two nodes from each row of the file. The edge is of the edge type if one is
+// named, and otherwise of the direction - one of 'directed', 'undirected' or 'bidirectional'.
+type EdgeMapping struct {
+	File       string            `json:"file"`
+	EdgeType   string            `json:"edgeType"`
+	Direction  string            `json:"direction"`
+	From       EndpointMapping   `json:"from"`
+	To         EndpointMapping   `json:"to"`
+	Attributes map[string]string `json:"attributes"`
+	Constants  map[string]string `json:"constants"`
+}
+
+// loadMapping reads the mapping file, resolving the file names relative to the directory of the mapping file
+func loadMapping(path string) (*Mapping, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	mapping := &Mapping{}
+	err = json.Unmarshal(data, mapping)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mapping file '%s': %s", path, err.Error())
+	}
+	dir := filepath.Dir(path)
+	for i := range mapping.Nodes {
+		mapping.Nodes[i].File = resolvePath(dir, mapping.Nodes[i].File)
+	}
+	for i := range mapping.Edges {
+		mapping.Edges[i].File = resolvePath(dir, mapping.Edges[i].File)
+	}
+	return mapping, mapping.validate()
+}
+
+func resolvePath(dir string, file string) string {
+	if file == "" || filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(dir, file)
+}
+
+func (obj *Mapping) delimiter() rune {
+	if obj.Delimiter == "" {
+		return ','
+	}
+	return []rune(obj.Delimiter)[0]
+}
+
+func (obj *Mapping) validate() error {
+	if len(obj.Delimiter) > 0 && len([]rune(obj.Delimiter)) != 1 {
+		return fmt.Errorf("delimiter '%s' must be a single character", obj.Delimiter)
+	}
+	if len(obj.Nodes) == 0 && len(obj.Edges) == 0 {
+		return fmt.Errorf("mapping has neither nodes nor edges")
+	}
+	for i, nodeMapping := range obj.Nodes {
+		if nodeMapping.File == "" || nodeMapping.NodeType == "" {
+			return fmt.Errorf("node mapping #%d needs a file and a node type", i+1)
+		}
+		if len(nodeMapping.Key) == 0 {
+			return fmt.Errorf("node mapping of '%s' needs at least one key attribute", nodeMapping.File)
+		}
+		for _, attrName := range nodeMapping.Key {
+			if _, ok := nodeMapping.Attributes[attrName]; !ok {
+				return fmt.Errorf("key attribute '%s' of '%s' is not mapped to a column", attrName, nodeMapping.File)
+			}
+		}
+	}
+	for i, edgeMapping := range obj.Edges {
+		if edgeMapping.File == "" {
+			return fmt.Errorf("edge mapping #%d needs a file", i+1)
+		}
+		if _, err := parseDirection(edgeMapping.Direction); edgeMapping.EdgeType == "" && err != nil {
+			return fmt.Errorf("edge mapping of '%s': %s", edgeMapping.File, err.Error())
+		}
+		for _, endpoint := range []EndpointMapping{edgeMapping.From, edgeMapping.To} {
+			if endpoint.NodeType == "" || len(endpoint.Key) == 0 {
+				return fmt.Errorf("edge mapping of '%s' needs a node type and key columns for both ends", edgeMapping.File)
+			}
+		}
+	}
+	return nil
+}
+
+// nodeKey identifies a node of the import by its type and key values, in the order of the attribute names
+func nodeKey(nodeType string, keyValues map[string]interface{}) string {
+	names := make([]string, 0, len(keyValues))
+	for name := range keyValues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString(nodeType)
+	for _, name := range names {
+		b.WriteString(fmt.Sprintf("|%s=%v", name, keyValues[name]))
+	}
+	return b.String()
+}
@@ -0,0 +1,116 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: decoder.go
+ * SVN id: $id: $
+ *
+ */
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/admin"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/channel"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/pdu"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"io"
+	"os"
+	"time"
+)
+
+// decoder prints the messages of trace files, as filtered by verb and direction
+type decoder struct {
+	out       io.Writer
+	verb      string
+	direction string
+	dumpHex   bool
+}
+
+// decodeMessage recreates the message from its bytes on the wire. Admin messages are created by the admin
+// package, which the message factory cannot depend on.
+func decodeMessage(buf []byte) (types.TGMessage, types.TGError) {
+	verb, err := pdu.VerbIdFromBytes(buf)
+	if err != nil {
+		return nil, err
+	}
+	switch verb.GetID() {
+	case pdu.VerbAdminRequest:
+		return admin.DefaultAdminRequestMessage().FromBytes(buf)
+	case pdu.VerbAdminResponse:
+		return admin.DefaultAdminResponseMessage().FromBytes(buf)
+	}
+	return pdu.CreateMessageFromBuffer(buf, 0, len(buf))
+}
+
+// verbName returns the name of the verb of the message bytes, even if the message cannot be decoded
+func verbName(buf []byte) string {
+	verb, err := pdu.VerbIdFromBytes(buf)
+	if err != nil {
+		return "Unknown"
+	}
+	return channel.GetVerbName(verb.GetID())
+}
+
+func (obj *decoder) decodeFile(fileName string) error {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader, err := channel.NewTraceReader(file)
+	if err != nil {
+		return fmt.Errorf("%s: %s", fileName, err.Error())
+	}
+	for index := 1; ; index++ {
+		record, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err == io.ErrUnexpectedEOF {
+			// The client stopped while tracing the last message
+			fmt.Fprintf(obj.out, "%s: record %d is truncated\n", fileName, index)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: record %d: %s", fileName, index, err.Error())
+		}
+		obj.printRecord(record)
+	}
+}
+
+func (obj *decoder) printRecord(record *channel.TraceRecord) {
+	name := verbName(record.Bytes)
+	if obj.verb != "" && obj.verb != name || obj.direction != "" && obj.direction != record.Direction.String() {
+		return
+	}
+	msg, err := decodeMessage(record.Bytes)
+	timestamp := record.Timestamp.UTC().Format(time.RFC3339Nano)
+	if err != nil && len(record.Bytes) == channel.TraceHeaderLength {
+		// Only the header of a message that carries credentials is traced
+		fmt.Fprintf(obj.out, "%s %-8s %s (payload redacted)\n", timestamp, record.Direction, name)
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(obj.out, "%s %-8s %s (%d bytes)\n", timestamp, record.Direction, name, len(record.Bytes))
+		fmt.Fprintf(obj.out, "  unable to decode: %s\n", err.Error())
+	} else {
+		fmt.Fprintf(obj.out, "%s %-8s %s #%d (%d bytes)\n", timestamp, record.Direction, name, msg.GetRequestId(), len(record.Bytes))
+		fmt.Fprintf(obj.out, "  %s\n", msg.String())
+	}
+	if err != nil || obj.dumpHex {
+		fmt.Fprint(obj.out, hex.Dump(record.Bytes))
+	}
+}
@@ -0,0 +1,116 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: decoder_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package main
+
+import (
+	"bytes"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/admin"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/channel"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/pdu"
+	"strings"
+	"testing"
+)
+
+// writeTestTrace traces a query, a ping, an admin request and bytes that are not a message, and returns the file
+func writeTestTrace(t *testing.T) string {
+	query := pdu.NewQueryRequestMessage(1, 2)
+	query.SetRequestId(42)
+	query.SetQuery("@nodetype = 'Person';")
+	query.SetCommand(1)
+	queryBytes, queryLen, err := query.ToBytes()
+	if err != nil {
+		t.Fatalf("QueryRequestMessage ToBytes failed w/ error: '%+v'", err)
+	}
+	ping := pdu.NewPingMessage(1, 2)
+	pingBytes, pingLen, err := ping.ToBytes()
+	if err != nil {
+		t.Fatalf("PingMessage ToBytes failed w/ error: '%+v'", err)
+	}
+	request := admin.NewAdminRequestMessage(1, 2)
+	request.SetCommand(admin.AdminCommandShowInfo)
+	requestBytes, requestLen, err := request.ToBytes()
+	if err != nil {
+		t.Fatalf("AdminRequestMessage ToBytes failed w/ error: '%+v'", err)
+	}
+
+	tracer := channel.NewChannelTracer("client", t.TempDir())
+	tracer.Start()
+	tracer.TraceSent(queryBytes[0:queryLen])
+	tracer.TraceReceived(pingBytes[0:pingLen])
+	tracer.TraceSent(requestBytes[0:requestLen])
+	tracer.TraceReceived([]byte("garbage"))
+	tracer.Stop()
+	return tracer.GetTraceFileName() + ".1"
+}
+
+func decodeTestTrace(t *testing.T, dec *decoder, fileName string) string {
+	var out bytes.Buffer
+	dec.out = &out
+	if err := dec.decodeFile(fileName); err != nil {
+		t.Fatalf("decodeFile failed w/ error: '%+v'", err)
+	}
+	return out.String()
+}
+
+func TestDecodeFile(t *testing.T) {
+	fileName := writeTestTrace(t)
+	out := decodeTestTrace(t, &decoder{}, fileName)
+	for _, expected := range []string{"sent     QueryRequest #42", "received PingMessage",
+		"sent     AdminRequest", "received Unknown (7 bytes)", "unable to decode"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Decoded trace does not contain '%s':\n%s", expected, out)
+		}
+	}
+	if strings.Count(out, "|") != 2 {
+		t.Errorf("Decoded trace dumps more than the message that cannot be decoded:\n%s", out)
+	}
+}
+
+func TestDecodeFileFilters(t *testing.T) {
+	fileName := writeTestTrace(t)
+	out := decodeTestTrace(t, &decoder{verb: "QueryRequest", dumpHex: true}, fileName)
+	if !strings.Contains(out, "QueryRequest") || strings.Contains(out, "PingMessage") || !strings.Contains(out, "|") {
+		t.Errorf("Decoded trace filtered by verb is:\n%s", out)
+	}
+	out = decodeTestTrace(t, &decoder{direction: "received"}, fileName)
+	if strings.Contains(out, "sent") || !strings.Contains(out, "received PingMessage") {
+		t.Errorf("Decoded trace filtered by direction is:\n%s", out)
+	}
+}
+
+func TestDecodeRedactedFile(t *testing.T) {
+	auth := pdu.NewAuthenticateRequestMessage(1, 2)
+	auth.SetUserName("scott")
+	auth.SetPassword([]byte("tiger-secret"))
+	tracer := channel.NewChannelTracer("client", t.TempDir())
+	tracer.Start()
+	tracer.Trace(auth)
+	tracer.Stop()
+	out := decodeTestTrace(t, &decoder{}, tracer.GetTraceFileName()+".1")
+	if !strings.Contains(out, "sent     AuthenticateRequest") || !strings.Contains(out, "payload redacted") {
+		t.Errorf("Decoded trace of a redacted message is:\n%s", out)
+	}
+}
+
+func TestDecodeFileRejectsOtherFiles(t *testing.T) {
+	if err := (&decoder{}).decodeFile("decoder.go"); err == nil {
+		t.Errorf("decodeFile accepted a file that is not a trace")
+	}
+}
@@ -0,0 +1,57 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: main.go
+ * SVN id: $id: $
+ *
+ */
+
+// tgdb-trace decodes the wire trace files written by the channels of a client - see the enableTrace and
+// enableTraceDir connection properties - into human-readable messages, e.g.
+//
+//	tgdb-trace -verb QueryRequest traces/tgdb.go-api.client-4242-1.trace.1 traces/tgdb.go-api.client-4242-1.trace.2
+//
+// Each message is printed w/ its time, its direction, its verb and request id, followed by its decoded contents.
+// Messages that cannot be decoded are dumped in hexadecimal, as all messages are w/ '-hex'. Messages that carry
+// credentials, such as authentication requests, are traced w/o their payload and printed as redacted.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	verb := flag.String("verb", "", "only print the messages of the verb, e.g. QueryRequest")
+	direction := flag.String("direction", "", "only print the messages 'sent' or 'received'")
+	dumpHex := flag.Bool("hex", false, "dump the bytes of every message in hexadecimal")
+	flag.Parse()
+
+	if flag.NArg() == 0 || (*direction != "" && *direction != "sent" && *direction != "received") {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dec := &decoder{out: os.Stdout, verb: *verb, direction: *direction, dumpHex: *dumpHex}
+	for _, fileName := range flag.Args() {
+		if err := dec.decodeFile(fileName); err != nil {
+			exitOnError(err)
+		}
+	}
+}
+
+func exitOnError(err error) {
+	fmt.Fprintf(os.Stderr, "tgdb-trace: %s\n", err.Error())
+	os.Exit(1)
+}
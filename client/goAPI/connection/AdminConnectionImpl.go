@@ -23,17 +23,20 @@ package connection
 
 import (
 	"bytes"
+	"context"
 	"encoding/gob"
 	"fmt"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/admin"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/channel"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/iostream"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/mapper"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/pdu"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/query"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/utils"
+	"io"
 )
 
 //static TGLogger gLogger        = TGLogManager.getInstance().getLogger();
@@ -88,6 +91,7 @@ func NewAdminConnection(conPool *ConnectionPoolImpl, channel types.TGChannel, pr
 	newSGDBConnection.connPoolImpl = conPool
 	newSGDBConnection.channel = channel
 	newSGDBConnection.connProperties = props.(*utils.SortedProperties)
+	newSGDBConnection.queryCache = NewPreparedQueryCache(getQueryCacheSize(props))
 	return newSGDBConnection
 }
 
@@ -144,6 +148,16 @@ func configureAdminRequest(adminReq *admin.AdminRequestMessage, option interface
 	}
 	logger.Log(fmt.Sprintf("Entering AdminConnectionImpl:configureAdminRequest w/ AdminRequest: '%+v'", adminReq.String()))
 	switch adminReq.GetCommand() {
+	case admin.AdminCommandCreateUser, admin.AdminCommandChangePassword, admin.AdminCommandSetUserRoles, admin.AdminCommandDropUser:
+		adminReq.SetUserDefinition(option.(*admin.UserDefinition))
+	case admin.AdminCommandCreateAttrDesc:
+		adminReq.SetAttributeDescriptor(option.(types.TGAttributeDescriptor))
+	case admin.AdminCommandCreateIndex:
+		adminReq.SetIndexDefinition(option.(*admin.IndexDefinition))
+	case admin.AdminCommandCreateNodeType, admin.AdminCommandCreateEdgeType:
+		adminReq.SetEntityType(option.(types.TGEntityType))
+	case admin.AdminCommandDescribe:
+		adminReq.SetTypeName(option.(string))
 	case admin.AdminCommandKillConnection:
 		adminReq.SetSessionId(option.(int64))
 	case admin.AdminCommandSetLogLevel:
@@ -197,6 +211,20 @@ func (obj *AdminConnectionImpl) executeAdminRequest(command admin.AdminCommand,
 	return obj.populateResultSetFromAdminResponse(command, response)
 }
 
+// getServerInfo retrieves the server information, from which each of the statistics is taken
+func (obj *AdminConnectionImpl) getServerInfo() (*admin.ServerInfoImpl, types.TGError) {
+	results, err := obj.executeAdminRequest(admin.AdminCommandShowInfo, nil)
+	if err != nil {
+		return nil, err
+	}
+	info := results.(*admin.ServerInfoImpl)
+	if info == nil {
+		errMsg := "Server did not return any server information"
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	return info, nil
+}
+
 func (obj *AdminConnectionImpl) populateResultSetFromAdminResponse(command admin.AdminCommand, msgResponse *admin.AdminResponseMessage) (interface{}, types.TGError) {
 	logger.Log(fmt.Sprintf("Entering AdminConnectionImpl:populateResultSetFromAdminResponse w/ MsgResponse: '%+v'", msgResponse.String()))
 	//if !msgResponse.GetHasResult() {
@@ -216,6 +244,8 @@ func (obj *AdminConnectionImpl) populateResultSetFromAdminResponse(command admin
 		results = msgResponse.GetIndexList()
 	case admin.AdminCommandShowInfo:
 		results = msgResponse.GetServerInfo()
+	case admin.AdminCommandShowTypes, admin.AdminCommandDescribe:
+		results = msgResponse.GetTypeList()
 	case admin.AdminCommandShowUsers:
 		results = msgResponse.GetUserList()
 	case admin.AdminCommandSetLogLevel:
@@ -239,33 +269,37 @@ func (obj *AdminConnectionImpl) populateResultSetFromQueryResponse(resultId int,
 
 	respStream := msgResponse.GetEntityStream()
 	fetchedEntities := make(map[int64]types.TGEntity, 0)
-	var rSet *query.ResultSet
+	rSet := query.NewResultSet(obj, resultId)
 
 	currResultCount := 0
 	resultCount := msgResponse.GetResultCount()
 	logger.Debug(fmt.Sprintf("Inside AdminConnectionImpl::populateResultSetFromQueryResponse read resultCount: '%d' FetchedEntityCount: '%d'", resultCount, len(fetchedEntities)))
 	if resultCount > 0 {
 		respStream.SetReferenceMap(fetchedEntities)
-		rSet = query.NewResultSet(obj, resultId)
 	}
 
 	totalCount := msgResponse.GetTotalCount()
 	logger.Debug(fmt.Sprintf("Inside AdminConnectionImpl::populateResultSetFromQueryResponse read totalCount: '%d'", totalCount))
+	// Errors are recorded on the result set. Once an entry cannot be decoded, the position in the response
+	// stream is undefined, hence stop reading.
+readLoop:
 	for i := 0; i < totalCount; i++ {
 		entityType, err := respStream.(*iostream.ProtocolDataInputStream).ReadByte()
 		if err != nil {
-			logger.Error(fmt.Sprint("ERROR: Returning AdminConnectionImpl:populateResultSetFromQueryResponse - unable to read entityType in the response stream"))
+			logger.Error(fmt.Sprint("ERROR: AdminConnectionImpl:populateResultSetFromQueryResponse - unable to read entityType in the response stream"))
 			errMsg := "AdminConnectionImpl::populateResultSetFromQueryResponse unable to read entity type in the response stream"
-			return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+			rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+			break readLoop
 		}
 		kindId := types.TGEntityKind(entityType)
 		logger.Debug(fmt.Sprintf("Inside AdminConnectionImpl::populateResultSetFromQueryResponse read #'%d'-entityType: '%+v', kindId: '%s'", i, entityType, kindId.String()))
 		if kindId != types.EntityKindInvalid {
 			entityId, err := respStream.(*iostream.ProtocolDataInputStream).ReadLong()
 			if err != nil {
-				logger.Error(fmt.Sprint("ERROR: Returning AdminConnectionImpl:populateResultSetFromQueryResponse - unable to read entityId in the response stream"))
+				logger.Error(fmt.Sprint("ERROR: AdminConnectionImpl:populateResultSetFromQueryResponse - unable to read entityId in the response stream"))
 				errMsg := "AdminConnectionImpl::populateResultSetFromQueryResponse unable to read entity type in the response stream"
-				return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+				rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+				break readLoop
 			}
 			entity := fetchedEntities[entityId]
 			logger.Debug(fmt.Sprintf("Inside AdminConnectionImpl::populateResultSetFromQueryResponse read entityId: '%d', kindId: '%s', entity: '%+v'", entityId, kindId.String(), entity))
@@ -275,11 +309,10 @@ func (obj *AdminConnectionImpl) populateResultSetFromQueryResponse(resultId int,
 				if entity == nil {
 					node, nErr := obj.graphObjFactory.CreateNode()
 					if nErr != nil {
-						logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:populateResultSetFromQueryResponse - unable to CreateNode() w/ error: '%s'", nErr.Error()))
-						// TODO: Revisit later - Should we continue OR break after throwing/logging an error?
-						//continue
+						logger.Error(fmt.Sprintf("ERROR: AdminConnectionImpl:populateResultSetFromQueryResponse - unable to CreateNode() w/ error: '%s'", nErr.Error()))
 						errMsg := "AdminConnectionImpl::populateResultSetFromQueryResponse unable to create a new node from the response stream"
-						return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, nErr.Error())
+						rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, nErr.Error()))
+						break readLoop
 					}
 					entity = node
 					fetchedEntities[entityId] = node
@@ -293,7 +326,8 @@ func (obj *AdminConnectionImpl) populateResultSetFromQueryResponse(resultId int,
 				if err != nil {
 					errMsg := "AdminConnectionImpl::populateResultSetFromQueryResponse unable to node.ReadExternal() from the response stream"
 					logger.Error(errMsg)
-					return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.GetErrorDetails())
+					rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.GetErrorDetails()))
+					break readLoop
 				}
 				//logger.Debug(fmt.Sprintf("======> ======> After node.ReadExternal() FetchedEntityCount: '%d'", len(fetchedEntities)))
 				logger.Debug(fmt.Sprintf("======> ======> Node w/ Edges: '%+v'\n", node.GetEdges()))
@@ -303,11 +337,10 @@ func (obj *AdminConnectionImpl) populateResultSetFromQueryResponse(resultId int,
 					//edge, eErr := obj.graphObjFactory.CreateEdgeWithDirection(nil, nil, types.DirectionTypeBiDirectional)
 					edge, eErr := obj.graphObjFactory.CreateEntity(types.EntityKindEdge)
 					if eErr != nil {
-						logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:populateResultSetFromQueryResponse - unable to CreateEdgeWithDirection() w/ error: '%s'", eErr.Error()))
-						// TODO: Revisit later - Should we continue OR break after throwing/logging an error?
-						//continue
+						logger.Error(fmt.Sprintf("ERROR: AdminConnectionImpl:populateResultSetFromQueryResponse - unable to CreateEdgeWithDirection() w/ error: '%s'", eErr.Error()))
 						errMsg := "AdminConnectionImpl::populateResultSetFromQueryResponse unable to create a new bi-directional edge from the response stream"
-						return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, eErr.Error())
+						rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, eErr.Error()))
+						break readLoop
 					}
 					entity = edge
 					fetchedEntities[entityId] = edge
@@ -321,10 +354,36 @@ func (obj *AdminConnectionImpl) populateResultSetFromQueryResponse(resultId int,
 				if err != nil {
 					errMsg := fmt.Sprintf("AdminConnectionImpl::populateResultSetFromQueryResponse unable to edge.ReadExternal() from the response stream w/ error: '%s'", err.Error())
 					logger.Error(errMsg)
-					return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+					rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+					break readLoop
 				}
 				//logger.Debug(fmt.Sprintf("======> ======> After edge.ReadExternal() FetchedEntityCount: '%d'", len(fetchedEntities)))
 				logger.Debug(fmt.Sprintf("======> ======> Edge w/ Vertices: '%+v'\n", edge.GetVertices()))
+			case types.EntityKindHyperEdge:
+				// Nodes read earlier may have left a placeholder edge for the hyperedge, which is replaced here
+				hyperEdge, ok := entity.(*model.HyperEdge)
+				if !ok {
+					newEntity, hErr := obj.graphObjFactory.CreateEntity(types.EntityKindHyperEdge)
+					if hErr != nil {
+						logger.Error(fmt.Sprintf("ERROR: AdminConnectionImpl:populateResultSetFromQueryResponse - unable to CreateEntity() w/ error: '%s'", hErr.Error()))
+						errMsg := "AdminConnectionImpl::populateResultSetFromQueryResponse unable to create a new hyperedge from the response stream"
+						rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, hErr.Error()))
+						break readLoop
+					}
+					hyperEdge = newEntity.(*model.HyperEdge)
+					fetchedEntities[entityId] = hyperEdge
+					logger.Debug(fmt.Sprintf("Inside AdminConnectionImpl::populateResultSetFromQueryResponse created new hyperedge: '%+v'", hyperEdge))
+				}
+				err := hyperEdge.ReadExternal(respStream)
+				if err != nil {
+					errMsg := fmt.Sprintf("AdminConnectionImpl::populateResultSetFromQueryResponse unable to hyperEdge.ReadExternal() from the response stream w/ error: '%s'", err.Error())
+					logger.Error(errMsg)
+					rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+					break readLoop
+				}
+				if currResultCount < resultCount {
+					rSet.AddEntityToResultSet(hyperEdge)
+				}
 			case types.EntityKindGraph:
 				// TODO: Revisit later - Should we break after throwing/logging an error
 				continue
@@ -345,13 +404,15 @@ func (obj *AdminConnectionImpl) populateResultSetFromQueryResponse(resultId int,
 			//}
 		} else {
 			logger.Warning(fmt.Sprintf("WARNING: AdminConnectionImpl:populateResultSetFromQueryResponse - Received invalid entity kind %d", kindId))
+			errMsg := fmt.Sprintf("AdminConnectionImpl::populateResultSetFromQueryResponse received invalid entity kind %d", kindId)
+			rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, ""))
 		} // Valid entity types
 	} // End of for loop
 	logger.Log(fmt.Sprintf("Returning AdminConnectionImpl:populateResultSetFromQueryResponse w/ ResultSet: '%+v'", rSet))
 	return rSet, nil
 }
 
-func (obj *AdminConnectionImpl) populateResultSetFromGetEntitiesResponse(msgResponse *pdu.GetEntityResponseMessage) (types.TGResultSet, types.TGError) {
+func (obj *AdminConnectionImpl) populateResultSetFromGetEntitiesResponse(msgResponse *pdu.GetEntityResponseMessage, fetchedEntities map[int64]types.TGEntity) (types.TGResultSet, types.TGError) {
 	logger.Log(fmt.Sprintf("Entering AdminConnectionImpl:populateResultSetFromGetEntitiesResponse w/ MsgResponse: '%+v'", msgResponse.String()))
 	if !msgResponse.GetHasResult() {
 		logger.Error(fmt.Sprint("ERROR: Returning AdminConnectionImpl:populateResultSetFromGetEntitiesResponse as msgResponse does not have any results"))
@@ -360,7 +421,6 @@ func (obj *AdminConnectionImpl) populateResultSetFromGetEntitiesResponse(msgResp
 	}
 
 	respStream := msgResponse.GetEntityStream()
-	fetchedEntities := make(map[int64]types.TGEntity, 0)
 
 	totalCount, err := respStream.(*iostream.ProtocolDataInputStream).ReadInt()
 	if err != nil {
@@ -383,28 +443,34 @@ func (obj *AdminConnectionImpl) populateResultSetFromGetEntitiesResponse(msgResp
 		return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
 	}
 
+	// Errors are recorded on the result set. Once an entry cannot be decoded, the position in the response
+	// stream is undefined, hence stop reading.
+readLoop:
 	for i := 0; i < totalCount; i++ {
 		isResult, err := respStream.(*iostream.ProtocolDataInputStream).ReadBoolean()
 		if err != nil {
-			logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:populateResultSetFromGetEntitiesResponse - unable to read isResult in the response stream w/ error: '%s'", err.Error()))
+			logger.Error(fmt.Sprintf("ERROR: AdminConnectionImpl:populateResultSetFromGetEntitiesResponse - unable to read isResult in the response stream w/ error: '%s'", err.Error()))
 			errMsg := "AdminConnectionImpl::populateResultSetFromGetEntitiesResponse unable to read count of result entities in the response stream"
-			return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+			rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+			break readLoop
 		}
 		logger.Debug(fmt.Sprintf("Inside AdminConnectionImpl::populateResultSetFromGetEntitiesResponse read isResult: '%+v'", isResult))
 		entityType, err := respStream.(*iostream.ProtocolDataInputStream).ReadByte()
 		if err != nil {
-			logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:populateResultSetFromGetEntitiesResponse - unable to read entityType in the response stream w/ error: '%s'", err.Error()))
+			logger.Error(fmt.Sprintf("ERROR: AdminConnectionImpl:populateResultSetFromGetEntitiesResponse - unable to read entityType in the response stream w/ error: '%s'", err.Error()))
 			errMsg := "AdminConnectionImpl::populateResultSetFromGetEntitiesResponse unable to read entity type in the response stream"
-			return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+			rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+			break readLoop
 		}
 		kindId := types.TGEntityKind(entityType)
 		logger.Debug(fmt.Sprintf("Inside AdminConnectionImpl::populateResultSetFromGetEntitiesResponse extracted entityType: '%+v', kindId: '%d'", entityType, kindId))
 		if kindId != types.EntityKindInvalid {
 			entityId, err := respStream.(*iostream.ProtocolDataInputStream).ReadLong()
 			if err != nil {
-				logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:populateResultSetFromGetEntitiesResponse - unable to read entityId in the response stream w/ error: '%s'", err.Error()))
+				logger.Error(fmt.Sprintf("ERROR: AdminConnectionImpl:populateResultSetFromGetEntitiesResponse - unable to read entityId in the response stream w/ error: '%s'", err.Error()))
 				errMsg := "AdminConnectionImpl::populateResultSetFromGetEntitiesResponse unable to read entity type in the response stream"
-				return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+				rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+				break readLoop
 			}
 			entity := fetchedEntities[entityId]
 			logger.Debug(fmt.Sprintf("Inside AdminConnectionImpl::populateResultSetFromGetEntitiesResponse extracted entityId: '%d', entity: '%+v'", entityId, entity))
@@ -414,11 +480,10 @@ func (obj *AdminConnectionImpl) populateResultSetFromGetEntitiesResponse(msgResp
 				if entity == nil {
 					node, nErr := obj.graphObjFactory.CreateNode()
 					if nErr != nil {
-						logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:populateResultSetFromGetEntitiesResponse - unable to CreateNode() w/ error: '%s'", nErr.Error()))
-						// TODO: Revisit later - Should we continue OR break after throwing/logging an error?
-						//continue
+						logger.Error(fmt.Sprintf("ERROR: AdminConnectionImpl:populateResultSetFromGetEntitiesResponse - unable to CreateNode() w/ error: '%s'", nErr.Error()))
 						errMsg := "AdminConnectionImpl::populateResultSetFromGetEntitiesResponse unable to create a new node from the response stream"
-						return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, nErr.GetErrorDetails())
+						rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, nErr.GetErrorDetails()))
+						break readLoop
 					}
 					entity = node
 					fetchedEntities[entityId] = node
@@ -429,7 +494,8 @@ func (obj *AdminConnectionImpl) populateResultSetFromGetEntitiesResponse(msgResp
 				if err != nil {
 					errMsg := fmt.Sprintf("AdminConnectionImpl::populateResultSetFromGetEntitiesResponse unable to node.ReadExternal() from the response stream w/ error: '%s'", err.Error())
 					logger.Error(errMsg)
-					return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+					rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+					break readLoop
 				}
 				if isResult {
 					rSet.AddEntityToResultSet(entity)
@@ -440,11 +506,10 @@ func (obj *AdminConnectionImpl) populateResultSetFromGetEntitiesResponse(msgResp
 				if entity == nil {
 					edge, eErr := obj.graphObjFactory.CreateEdgeWithDirection(nil, nil, types.DirectionTypeBiDirectional)
 					if eErr != nil {
-						logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:populateResultSetFromGetEntitiesResponse - unable to CreateEdgeWithDirection() w/ error: '%s'", eErr.Error()))
-						// TODO: Revisit later - Should we break after throwing/logging an error
-						//continue
+						logger.Error(fmt.Sprintf("ERROR: AdminConnectionImpl:populateResultSetFromGetEntitiesResponse - unable to CreateEdgeWithDirection() w/ error: '%s'", eErr.Error()))
 						errMsg := "AdminConnectionImpl::populateResultSetFromGetEntitiesResponse unable to create a new bi-directional edge from the response stream"
-						return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, eErr.Error())
+						rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, eErr.Error()))
+						break readLoop
 					}
 					entity = edge
 					fetchedEntities[entityId] = edge
@@ -455,18 +520,47 @@ func (obj *AdminConnectionImpl) populateResultSetFromGetEntitiesResponse(msgResp
 				if err != nil {
 					errMsg := fmt.Sprintf("AdminConnectionImpl::populateResultSetFromGetEntitiesResponse unable to edge.ReadExternal() from the response stream w/ error: '%s'", err.Error())
 					logger.Error(errMsg)
-					return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+					rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+					break readLoop
 				}
 				if isResult {
 					rSet.AddEntityToResultSet(entity)
 					currResultCount++
 				}
+			case types.EntityKindHyperEdge:
+				// Nodes read earlier may have left a placeholder edge for the hyperedge, which is replaced here
+				hyperEdge, ok := entity.(*model.HyperEdge)
+				if !ok {
+					newEntity, hErr := obj.graphObjFactory.CreateEntity(types.EntityKindHyperEdge)
+					if hErr != nil {
+						logger.Error(fmt.Sprintf("ERROR: AdminConnectionImpl:populateResultSetFromGetEntitiesResponse - unable to CreateEntity() w/ error: '%s'", hErr.Error()))
+						errMsg := "AdminConnectionImpl::populateResultSetFromGetEntitiesResponse unable to create a new hyperedge from the response stream"
+						rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, hErr.Error()))
+						break readLoop
+					}
+					hyperEdge = newEntity.(*model.HyperEdge)
+					fetchedEntities[entityId] = hyperEdge
+					logger.Debug(fmt.Sprintf("Inside AdminConnectionImpl::populateResultSetFromGetEntitiesResponse created new hyperedge: '%+v'", hyperEdge))
+				}
+				err := hyperEdge.ReadExternal(respStream)
+				if err != nil {
+					errMsg := fmt.Sprintf("AdminConnectionImpl::populateResultSetFromGetEntitiesResponse unable to hyperEdge.ReadExternal() from the response stream w/ error: '%s'", err.Error())
+					logger.Error(errMsg)
+					rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+					break readLoop
+				}
+				if isResult {
+					rSet.AddEntityToResultSet(hyperEdge)
+					currResultCount++
+				}
 			case types.EntityKindGraph:
 				// TODO: Revisit later - Should we break after throwing/logging an error
 				continue
 			}
 		} else {
 			logger.Warning(fmt.Sprintf("WARNING: AdminConnectionImpl:populateResultSetFromGetEntitiesResponse - Received invalid entity kind %d", kindId))
+			errMsg := fmt.Sprintf("AdminConnectionImpl::populateResultSetFromGetEntitiesResponse received invalid entity kind %d", kindId)
+			rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, ""))
 		} // Valid entity types
 	} // End of for loop
 	logger.Log(fmt.Sprintf("Returning AdminConnectionImpl:populateResultSetFromGetEntitiesResponse w/ ResultSe: '%+v'", rSet))
@@ -566,6 +660,29 @@ func (obj *AdminConnectionImpl) populateResultSetFromGetEntityResponse(msgRespon
 						logger.Error(errMsg)
 						return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
 					}
+				case types.EntityKindHyperEdge:
+					// Nodes read earlier may have left a placeholder edge for the hyperedge, which is replaced here
+					hyperEdge, ok := entity.(*model.HyperEdge)
+					if !ok {
+						newEntity, hErr := obj.graphObjFactory.CreateEntity(types.EntityKindHyperEdge)
+						if hErr != nil {
+							logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:populateResultSetFromGetEntityResponse - unable to CreateEntity() w/ error: '%s'", hErr.Error()))
+							errMsg := "AdminConnectionImpl::populateResultSetFromGetEntityResponse unable to create a new hyperedge from the response stream"
+							return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, hErr.Error())
+						}
+						hyperEdge = newEntity.(*model.HyperEdge)
+						fetchedEntities[entityId] = hyperEdge
+						if entityFound == nil {
+							entityFound = hyperEdge
+						}
+						logger.Debug(fmt.Sprintf("Inside AdminConnectionImpl::populateResultSetFromGetEntityResponse created new hyperedge: '%+v'", hyperEdge))
+					}
+					err := hyperEdge.ReadExternal(respStream)
+					if err != nil {
+						errMsg := fmt.Sprintf("AdminConnectionImpl::populateResultSetFromGetEntityResponse unable to hyperEdge.ReadExternal() from the response stream w/ error: '%s'", err.Error())
+						logger.Error(errMsg)
+						return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+					}
 				case types.EntityKindGraph:
 					// TODO: Revisit later - Should we break after throwing/logging an error
 					continue
@@ -583,6 +700,11 @@ func (obj *AdminConnectionImpl) populateResultSetFromGetEntityResponse(msgRespon
 // Implement functions from Interface ==> TGAdminConnection
 /////////////////////////////////////////////////////////////////
 
+// ChangeUserPassword changes the password of an existing user
+func (obj *AdminConnectionImpl) ChangeUserPassword(userName string, password []byte) types.TGError {
+	return obj.manageUser(admin.AdminCommandChangePassword, &admin.UserDefinition{Name: userName, Password: password})
+}
+
 // CheckpointServer allows the programmatic control to do a checkpoint on server
 func (obj *AdminConnectionImpl) CheckpointServer() types.TGError {
 	_, err := obj.executeAdminRequest(admin.AdminCommandCheckpointServer, nil)
@@ -592,6 +714,79 @@ func (obj *AdminConnectionImpl) CheckpointServer() types.TGError {
 	return nil
 }
 
+// CreateAttributeDescriptor creates an attribute descriptor on the server
+func (obj *AdminConnectionImpl) CreateAttributeDescriptor(def admin.AttributeDefinition) types.TGError {
+	return obj.createSchemaObject(admin.AdminCommandCreateAttrDesc, func(gmd types.TGGraphMetadata) (interface{}, types.TGError) {
+		desc, err := newSchemaAttributeDescriptor(gmd, def)
+		if err != nil {
+			return nil, err
+		}
+		return types.TGAttributeDescriptor(desc), nil
+	})
+}
+
+// CreateEdgeType creates an edge type between two existing node types on the server
+func (obj *AdminConnectionImpl) CreateEdgeType(def admin.EdgeTypeDefinition) types.TGError {
+	return obj.createSchemaObject(admin.AdminCommandCreateEdgeType, func(gmd types.TGGraphMetadata) (interface{}, types.TGError) {
+		edgeType, err := newSchemaEdgeType(gmd, def)
+		if err != nil {
+			return nil, err
+		}
+		return types.TGEntityType(edgeType), nil
+	})
+}
+
+// CreateIndex creates an index on existing attribute descriptors on the server
+func (obj *AdminConnectionImpl) CreateIndex(def admin.IndexDefinition) types.TGError {
+	return obj.createSchemaObject(admin.AdminCommandCreateIndex, func(gmd types.TGGraphMetadata) (interface{}, types.TGError) {
+		err := validateIndexDefinition(gmd, &def)
+		if err != nil {
+			return nil, err
+		}
+		return &def, nil
+	})
+}
+
+// CreateNodeType creates a node type on the server
+func (obj *AdminConnectionImpl) CreateNodeType(def admin.NodeTypeDefinition) types.TGError {
+	return obj.createSchemaObject(admin.AdminCommandCreateNodeType, func(gmd types.TGGraphMetadata) (interface{}, types.TGError) {
+		nodeType, err := newSchemaNodeType(gmd, def)
+		if err != nil {
+			return nil, err
+		}
+		return types.TGEntityType(nodeType), nil
+	})
+}
+
+// CreateUser creates a user w/ its password and roles
+func (obj *AdminConnectionImpl) CreateUser(def admin.UserDefinition) types.TGError {
+	return obj.manageUser(admin.AdminCommandCreateUser, &def)
+}
+
+// Describe gets the definition of a node or edge type
+func (obj *AdminConnectionImpl) Describe(typeName string) (admin.TGTypeInfo, types.TGError) {
+	if typeName == "" {
+		errMsg := "Name of the type to describe is required"
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	results, err := obj.executeAdminRequest(admin.AdminCommandDescribe, typeName)
+	if err != nil {
+		return nil, err
+	}
+	for _, typeInfo := range results.([]admin.TGTypeInfo) {
+		if typeInfo.GetName() == typeName {
+			return typeInfo, nil
+		}
+	}
+	errMsg := fmt.Sprintf("Type '%s' does not exist", typeName)
+	return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+}
+
+// DropUser removes an existing user
+func (obj *AdminConnectionImpl) DropUser(userName string) types.TGError {
+	return obj.manageUser(admin.AdminCommandDropUser, &admin.UserDefinition{Name: userName})
+}
+
 // DumpServerStackTrace prints the stack trace
 func (obj *AdminConnectionImpl) DumpServerStackTrace() types.TGError {
 	logger.Log(fmt.Sprint("Entering AdminConnectionImpl:DumpServerStackTrace for Admin Command: DumpServerStackTrace"))
@@ -623,6 +818,12 @@ func (obj *AdminConnectionImpl) DumpServerStackTrace() types.TGError {
 	return nil
 }
 
+// ExportDatabase streams a full export of the database into the writer, cancelling it on the server if the
+// context is done before the export completes
+func (obj *AdminConnectionImpl) ExportDatabase(ctx context.Context, w io.Writer) types.TGError {
+	return exportDatabase(ctx, obj, w)
+}
+
 // GetAttributeDescriptors gets the list of attribute descriptors
 func (obj *AdminConnectionImpl) GetAttributeDescriptors() ([]types.TGAttributeDescriptor, types.TGError) {
 	results, err := obj.executeAdminRequest(admin.AdminCommandShowAttrDescs, nil)
@@ -632,6 +833,15 @@ func (obj *AdminConnectionImpl) GetAttributeDescriptors() ([]types.TGAttributeDe
 	return results.([]types.TGAttributeDescriptor), nil
 }
 
+// GetCacheStatistics gets the statistics of the data and index caches of the server
+func (obj *AdminConnectionImpl) GetCacheStatistics() (admin.TGCacheStatistics, types.TGError) {
+	info, err := obj.getServerInfo()
+	if err != nil {
+		return nil, err
+	}
+	return info.GetCacheInfo(), nil
+}
+
 // GetConnections gets the list of all socket connections using this connection type
 func (obj *AdminConnectionImpl) GetConnections() ([]admin.TGConnectionInfo, types.TGError) {
 	results, err := obj.executeAdminRequest(admin.AdminCommandShowConnections, nil)
@@ -641,6 +851,15 @@ func (obj *AdminConnectionImpl) GetConnections() ([]admin.TGConnectionInfo, type
 	return results.([]admin.TGConnectionInfo), nil
 }
 
+// GetDatabaseStatistics gets the statistics of the data and index segments of the database
+func (obj *AdminConnectionImpl) GetDatabaseStatistics() (admin.TGDatabaseStatistics, types.TGError) {
+	info, err := obj.getServerInfo()
+	if err != nil {
+		return nil, err
+	}
+	return info.GetDatabaseInfo(), nil
+}
+
 // GetIndices gets the list of all indices
 func (obj *AdminConnectionImpl) GetIndices() ([]admin.TGIndexInfo, types.TGError) {
 	results, err := obj.executeAdminRequest(admin.AdminCommandShowIndices, nil)
@@ -659,6 +878,51 @@ func (obj *AdminConnectionImpl) GetInfo() (admin.TGServerInfo, types.TGError) {
 	return results.(admin.TGServerInfo), nil
 }
 
+// GetMemoryInfo gets the process or shared memory information of the server
+func (obj *AdminConnectionImpl) GetMemoryInfo(memType admin.MemType) (admin.TGMemoryInfo, types.TGError) {
+	info, err := obj.getServerInfo()
+	if err != nil {
+		return nil, err
+	}
+	return info.GetMemoryInfo(memType), nil
+}
+
+// GetNetListeners gets the information of the net listeners of the server
+func (obj *AdminConnectionImpl) GetNetListeners() ([]admin.TGNetListenerInfo, types.TGError) {
+	info, err := obj.getServerInfo()
+	if err != nil {
+		return nil, err
+	}
+	return info.GetNetListenersInfo(), nil
+}
+
+// GetServerStatus gets the name, process id, state and uptime of the server
+func (obj *AdminConnectionImpl) GetServerStatus() (admin.TGServerStatus, types.TGError) {
+	info, err := obj.getServerInfo()
+	if err != nil {
+		return nil, err
+	}
+	return info.GetServerStatus(), nil
+}
+
+// GetTransactionStatistics gets the statistics of the transactions processed by the server
+func (obj *AdminConnectionImpl) GetTransactionStatistics() (admin.TGTransactionStatistics, types.TGError) {
+	info, err := obj.getServerInfo()
+	if err != nil {
+		return nil, err
+	}
+	return info.GetTransactionsInfo(), nil
+}
+
+// GetTypes gets the definitions of all node and edge types
+func (obj *AdminConnectionImpl) GetTypes() ([]admin.TGTypeInfo, types.TGError) {
+	results, err := obj.executeAdminRequest(admin.AdminCommandShowTypes, nil)
+	if err != nil {
+		return nil, err
+	}
+	return results.([]admin.TGTypeInfo), nil
+}
+
 // GetUsers gets the list of users
 func (obj *AdminConnectionImpl) GetUsers() ([]admin.TGUserInfo, types.TGError) {
 	results, err := obj.executeAdminRequest(admin.AdminCommandShowUsers, nil)
@@ -668,6 +932,11 @@ func (obj *AdminConnectionImpl) GetUsers() ([]admin.TGUserInfo, types.TGError) {
 	return results.([]admin.TGUserInfo), nil
 }
 
+// ImportDatabase imports an export written by ExportDatabase from the reader, and returns the number of entities imported
+func (obj *AdminConnectionImpl) ImportDatabase(ctx context.Context, r io.Reader) (int64, types.TGError) {
+	return importDatabase(ctx, obj, r)
+}
+
 // KillConnection terminates the connection forcefully
 func (obj *AdminConnectionImpl) KillConnection(sessionId int64) types.TGError {
 	_, err := obj.executeAdminRequest(admin.AdminCommandKillConnection, sessionId)
@@ -687,6 +956,11 @@ func (obj *AdminConnectionImpl) SetServerLogLevel(logLevel int, logComponent int
 	return nil
 }
 
+// SetUserRoles replaces the roles of an existing user
+func (obj *AdminConnectionImpl) SetUserRoles(userName string, roles []string) types.TGError {
+	return obj.manageUser(admin.AdminCommandSetUserRoles, &admin.UserDefinition{Name: userName, Roles: roles})
+}
+
 // StopServer stops the admin connection
 func (obj *AdminConnectionImpl) StopServer() types.TGError {
 	_, err := obj.executeAdminRequest(admin.AdminCommandStopServer, nil)
@@ -709,8 +983,8 @@ func (obj *AdminConnectionImpl) Commit() (types.TGResultSet, types.TGError) {
 	logger.Debug(fmt.Sprint("Inside AdminConnectionImpl::Commit - about to loop through addedList to include existing nodes to the changed list if it's part of a new edge"))
 	// Include existing nodes to the changed list if it's part of a new edge
 	for _, addEntity := range obj.GetAddedList() {
-		if addEntity.GetEntityKind() == types.EntityKindEdge {
-			nodes := addEntity.(*model.Edge).GetVertices()
+		if addEntity.GetEntityKind() == types.EntityKindEdge || addEntity.GetEntityKind() == types.EntityKindHyperEdge {
+			nodes := addEntity.(types.TGEdge).GetVertices()
 			if len(nodes) > 0 {
 				for _, vNode := range nodes {
 					node := vNode.(*model.Node)
@@ -726,8 +1000,8 @@ func (obj *AdminConnectionImpl) Commit() (types.TGResultSet, types.TGError) {
 	logger.Debug(fmt.Sprint("Inside AdminConnectionImpl::Commit - about to loop through changedList to include existing nodes to the changed list even for edge update"))
 	// Need to include existing node to the changed list even for edge update
 	for _, modEntity := range obj.GetChangedList() {
-		if modEntity.GetEntityKind() == types.EntityKindEdge {
-			nodes := modEntity.(*model.Edge).GetVertices()
+		if modEntity.GetEntityKind() == types.EntityKindEdge || modEntity.GetEntityKind() == types.EntityKindHyperEdge {
+			nodes := modEntity.(types.TGEdge).GetVertices()
 			if len(nodes) > 0 {
 				for _, vNode := range nodes {
 					node := vNode.(*model.Node)
@@ -743,8 +1017,8 @@ func (obj *AdminConnectionImpl) Commit() (types.TGResultSet, types.TGError) {
 	logger.Debug(fmt.Sprint("Inside AdminConnectionImpl::Commit - about to loop through removedList to include existing nodes to the changed list even for edge update"))
 	// Need to include existing node to the changed list even for edge update
 	for _, delEntity := range obj.GetRemovedList() {
-		if delEntity.GetEntityKind() == types.EntityKindEdge {
-			nodes := delEntity.(*model.Edge).GetVertices()
+		if delEntity.GetEntityKind() == types.EntityKindEdge || delEntity.GetEntityKind() == types.EntityKindHyperEdge {
+			nodes := delEntity.(types.TGEdge).GetVertices()
 			if len(nodes) > 0 {
 				for _, vNode := range nodes {
 					node := vNode.(*model.Node)
@@ -828,6 +1102,8 @@ func (obj *AdminConnectionImpl) Connect() types.TGError {
 		logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl::Connect - error in obj.GetChannel().Connect() as '%+v'", err.Error()))
 		return err
 	}
+	// Queries of an earlier session are gone on the server
+	obj.queryCache.Invalidate()
 	logger.Debug(fmt.Sprint("Inside AdminConnectionImpl::Connect about to obj.GetChannel().Start()"))
 	err = obj.GetChannel().Start()
 	if err != nil {
@@ -841,6 +1117,7 @@ func (obj *AdminConnectionImpl) Connect() types.TGError {
 // CloseQuery closes a specific query and associated objects
 func (obj *AdminConnectionImpl) CloseQuery(queryHashId int64) (types.TGQuery, types.TGError) {
 	logger.Log(fmt.Sprintf("Entering AdminConnectionImpl:CloseQuery for QueryHashId: '%+v'", queryHashId))
+	obj.queryCache.Remove(queryHashId)
 	obj.connPoolImpl.AdminLock()
 	defer obj.connPoolImpl.AdminUnlock()
 
@@ -900,11 +1177,14 @@ func (obj *AdminConnectionImpl) CreateQuery(expr string) (types.TGQuery, types.T
 	response := msgResponse.(*pdu.QueryResponseMessage)
 	queryHashId := response.GetQueryHashId()
 
-	logger.Log(fmt.Sprint("Returning AdminConnectionImpl:CreateQuery"))
-	if response.GetResult() == 0 && queryHashId > 0 {
-		return query.NewQuery(obj, queryHashId), nil
+	if response.GetResult() != 0 || queryHashId <= 0 {
+		errMsg := fmt.Sprintf("Unable to create query '%s' - server returned result '%d'", expr, response.GetResult())
+		logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:CreateQuery - %s", errMsg))
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
 	}
-	return nil, nil
+
+	logger.Log(fmt.Sprint("Returning AdminConnectionImpl:CreateQuery"))
+	return query.NewPreparedQuery(obj, queryHashId, expr), nil
 }
 
 // DecryptBuffer decrypts the encrypted buffer by sending a DecryptBufferRequest to the server
@@ -978,6 +1258,7 @@ func (obj *AdminConnectionImpl) Disconnect() types.TGError {
 	}
 	logger.Debug(fmt.Sprint("Inside AdminConnectionImpl::Disconnect about to obj.GetChannel().Stop()"))
 	obj.GetChannel().Stop(false)
+	obj.queryCache.Invalidate()
 	logger.Log(fmt.Sprint("Returning AdminConnectionImpl:Disconnect"))
 	return nil
 }
@@ -1128,6 +1409,16 @@ func (obj *AdminConnectionImpl) ExecuteQueryWithFilter(expr, edgeFilter, travers
 
 // ExecuteQueryWithId executes an immediate query for specified id & query options
 func (obj *AdminConnectionImpl) ExecuteQueryWithId(queryHashId int64, options types.TGQueryOption) (types.TGResultSet, types.TGError) {
+	return obj.executeQueryWithId(queryHashId, nil, options)
+}
+
+// ExecuteQueryWithParameters executes the server side query w/ the values bound to its named parameters
+func (obj *AdminConnectionImpl) ExecuteQueryWithParameters(qry *query.TGQueryImpl, options types.TGQueryOption) (types.TGResultSet, types.TGError) {
+	return obj.executeQueryWithId(qry.GetQueryId(), qry, options)
+}
+
+// executeQueryWithId sends EXECUTEID for the query, followed by the values bound to the parameters of qry, if any
+func (obj *AdminConnectionImpl) executeQueryWithId(queryHashId int64, qry *query.TGQueryImpl, options types.TGQueryOption) (types.TGResultSet, types.TGError) {
 	logger.Log(fmt.Sprintf("Entering AdminConnectionImpl:ExecuteQueryWithId for QueryHashId: '%+v'", queryHashId))
 	err := obj.InitMetadata()
 	if err != nil {
@@ -1146,19 +1437,29 @@ func (obj *AdminConnectionImpl) ExecuteQueryWithId(queryHashId int64, options ty
 	queryRequest := msgRequest.(*pdu.QueryRequestMessage)
 	queryRequest.SetCommand(EXECUTED)
 	queryRequest.SetQueryHashId(queryHashId)
+	if qry != nil {
+		queryRequest.SetQueryObject(qry)
+	}
 	logger.Debug(fmt.Sprint("Inside AdminConnectionImpl::ExecuteQueryWithId about to obj.configureQueryRequest() for: pdu.VerbQueryRequest"))
 	configureQueryRequest(queryRequest, options)
 
 	logger.Debug(fmt.Sprint("Inside AdminConnectionImpl::ExecuteQueryWithId about to obj.GetChannel().SendRequest() for: pdu.VerbQueryRequest"))
 	// Execute request on channel and get the response
-	_, channelErr := obj.GetChannel().SendRequest(queryRequest, channelResponse.(*channel.BlockingChannelResponse))
+	msgResponse, channelErr := obj.GetChannel().SendRequest(queryRequest, channelResponse.(*channel.BlockingChannelResponse))
 	if channelErr != nil {
 		logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:ExecuteQueryWithId - unable to channel.SendRequest() w/ error: '%s'", channelErr.Error()))
 		return nil, channelErr
 	}
+	logger.Debug(fmt.Sprintf("Inside AdminConnectionImpl::ExecuteQueryWithId received response for: pdu.VerbQueryRequest as '%+v'", msgResponse))
+	response := msgResponse.(*pdu.QueryResponseMessage)
 
-	logger.Log(fmt.Sprint("Returning AdminConnectionImpl:ExecuteQueryWithId"))
-	return nil, nil
+	if !response.GetHasResult() {
+		logger.Warning(fmt.Sprint("WARNING: Returning AdminConnectionImpl::ExecuteQueryWithId - The query does not have any results in QueryResponseMessage"))
+		return nil, nil
+	}
+
+	logger.Log(fmt.Sprintf("Returning AdminConnectionImpl:ExecuteQueryWithId w/ '%+v'", response))
+	return obj.populateResultSetFromQueryResponse(0, response)
 }
 
 // GetAddedList gets a list of added entities
@@ -1227,8 +1528,13 @@ func (obj *AdminConnectionImpl) GetEntities(qryKey types.TGKey, props types.TGPr
 		return nil, nil
 	}
 
+	fetchedEntities := make(map[int64]types.TGEntity, 0)
+	rSet, err := obj.populateResultSetFromGetEntitiesResponse(response, fetchedEntities)
+	if err != nil {
+		return nil, err
+	}
 	logger.Log(fmt.Sprintf("Returning AdminConnectionImpl:GetEntities w/ '%+v'", response))
-	return obj.populateResultSetFromGetEntitiesResponse(response)
+	return newStreamingResultSet(obj, newResultBatchFetcher(obj.TGDBConnection, fetchedEntities), rSet, queryRequest.GetBatchSize(), props), nil
 }
 
 // GetEntity gets an Entity given an UniqueKey for the Object
@@ -1276,6 +1582,22 @@ func (obj *AdminConnectionImpl) GetEntity(qryKey types.TGKey, options types.TGQu
 	return obj.populateResultSetFromGetEntityResponse(response)
 }
 
+// GetEntitiesByKeys gets the entities of many unique keys, possibly of different types, in as few requests as possible
+func (obj *AdminConnectionImpl) GetEntitiesByKeys(keys []types.TGKey, options types.TGQueryOption) (map[types.TGKey]types.TGKeyResult, types.TGError) {
+	logger.Log(fmt.Sprintf("Entering AdminConnectionImpl:GetEntitiesByKeys for '%d' keys", len(keys)))
+	err := obj.InitMetadata()
+	if err != nil {
+		logger.Error(fmt.Sprint("ERROR: Returning AdminConnectionImpl:GetEntitiesByKeys - unable to InitMetadata"))
+		return nil, err
+	}
+	if options == nil {
+		options = query.NewQueryOption()
+	}
+	results := getEntitiesByKeys(obj, keys, options)
+	logger.Log(fmt.Sprint("Returning AdminConnectionImpl:GetEntitiesByKeys"))
+	return results, nil
+}
+
 // GetGraphMetadata gets the Graph Metadata
 func (obj *AdminConnectionImpl) GetGraphMetadata(refresh bool) (types.TGGraphMetadata, types.TGError) {
 	logger.Log(fmt.Sprint("Entering AdminConnectionImpl:GetGraphMetadata"))
@@ -1387,6 +1709,13 @@ func (obj *AdminConnectionImpl) InsertEntity(entity types.TGEntity) types.TGErro
 	return nil
 }
 
+// Prepare returns a reusable query for the expression, sharing the server side query with earlier
+// calls for the same expression on this connection. Closing the returned query only releases it - the cached
+// server side query is closed once it has been evicted and no other returned query uses it.
+func (obj *AdminConnectionImpl) Prepare(expr string) (types.TGQuery, types.TGError) {
+	return prepareQuery(obj, obj.queryCache, expr)
+}
+
 // Rollback rolls back the current transaction on this connection
 func (obj *AdminConnectionImpl) Rollback() types.TGError {
 	logger.Log(fmt.Sprint("Entering AdminConnectionImpl:Rollback"))
@@ -1403,6 +1732,47 @@ func (obj *AdminConnectionImpl) Rollback() types.TGError {
 	return nil
 }
 
+// SaveObject creates or updates the node for a Go struct tagged w/ its node type, along w/ the edges of its
+// tagged relationship fields, and commits the changes. As the commit would include any other pending change,
+// the connection must not have any. The changes are rolled back if the commit fails.
+func (obj *AdminConnectionImpl) SaveObject(object interface{}) (types.TGNode, types.TGError) {
+	logger.Log(fmt.Sprintf("Entering AdminConnectionImpl:SaveObject to save object of type '%T'", object))
+	if len(obj.GetAddedList())+len(obj.GetChangedList())+len(obj.GetRemovedList()) > 0 {
+		logger.Error(fmt.Sprint("ERROR: Returning AdminConnectionImpl:SaveObject - the connection has pending changes"))
+		errMsg := "AdminConnectionImpl::SaveObject requires a connection w/o pending changes - commit or roll them back first"
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+	}
+	node, err := mapper.Persist(obj, object)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:SaveObject - unable to persist object w/ Error: '%+v'", err.Error()))
+		return nil, err
+	}
+	_, err = obj.Commit()
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:SaveObject - unable to commit w/ Error: '%+v'", err.Error()))
+		_ = obj.Rollback()
+		return nil, err
+	}
+	logger.Log(fmt.Sprint("Returning AdminConnectionImpl:SaveObject"))
+	return node, nil
+}
+
+// UpsertNode merges the attributes into the node of the node type w/ the primary key values, creating it if needed
+func (obj *AdminConnectionImpl) UpsertNode(nodeType types.TGNodeType, keyValues map[string]interface{}, attrs map[string]interface{}) (types.TGNode, types.TGError) {
+	logger.Log(fmt.Sprintf("Entering AdminConnectionImpl:UpsertNode for key '%+v'", keyValues))
+	err := obj.InitMetadata()
+	if err != nil {
+		return nil, err
+	}
+	node, err := upsertNode(obj, nodeType, keyValues, attrs)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:UpsertNode - unable to upsert node w/ Error: '%+v'", err.Error()))
+		return nil, err
+	}
+	logger.Log(fmt.Sprint("Returning AdminConnectionImpl:UpsertNode"))
+	return node, nil
+}
+
 // SetExceptionListener sets exception listener
 func (obj *AdminConnectionImpl) SetExceptionListener(listener types.TGConnectionExceptionListener) {
 	obj.connPoolImpl.SetExceptionListener(listener) //delegate it to the Pool.
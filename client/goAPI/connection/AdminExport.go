@@ -0,0 +1,439 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: AdminExport.go
+ * SVN id: $id: $
+ *
+ */
+
+package connection
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/channel"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/pdu"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"io"
+	"math"
+)
+
+// DefaultExportBatchSize is the maximum number of entities the server puts in a batch of an export
+const DefaultExportBatchSize = 1000
+
+// An export archive written by ExportDatabase, and read back by ImportDatabase, starts w/ the magic and version,
+// followed by the types listed by the server when the export began - each as its name and number of entities. The
+// batches follow as they are received, each preceded by a record marker, and the archive ends w/ an end marker.
+// All numbers are in big endian order, and strings are prefixed by their length.
+const (
+	exportArchiveMagic   = "TGDBEXPORT"
+	exportArchiveVersion = uint16(1)
+
+	exportRecordEnd   = byte(0)
+	exportRecordBatch = byte(1)
+
+	// maxArchiveBatchLength bounds the memory allocated for a batch read from an archive that may be corrupt
+	maxArchiveBatchLength = 256 * 1024 * 1024
+)
+
+// exportExchange is the exchange of export and import messages w/ the server, one request at a time. The layouts of
+// these messages are experimental - see the Import/Export verbs in pdu.
+type exportExchange interface {
+	beginExport(batchSize int) (*pdu.BeginExportResponseMessage, types.TGError)
+	partialExport(exportId int) (*pdu.PartialExportResponseMessage, types.TGError)
+	cancelExport(exportId int) types.TGError
+	beginImport() (*pdu.BeginImportResponseMessage, types.TGError)
+	partialImport(importId int, typeName string, batchIdx int, hasMore bool, data []byte) (*pdu.PartialImportResponseMessage, types.TGError)
+}
+
+// exportBatch is a batch of exported entities of a type, as stored in an export archive
+type exportBatch struct {
+	typeName string
+	batchIdx int
+	data     []byte
+}
+
+// exportDatabase streams a full export of the database into the writer, asking the server for one batch after the
+// other. If the context is done, or a batch cannot be written, the export is cancelled on the server.
+func exportDatabase(ctx context.Context, exchange exportExchange, w io.Writer) types.TGError {
+	logger.Log(fmt.Sprint("Entering AdminConnectionImpl:exportDatabase"))
+	if err := ctx.Err(); err != nil {
+		return cancelledError("Export", err)
+	}
+	beginResp, err := exchange.beginExport(DefaultExportBatchSize)
+	if err != nil {
+		return err
+	}
+	exportId := beginResp.GetExportId()
+	logger.Debug(fmt.Sprintf("Inside AdminConnectionImpl:exportDatabase began export '%d' of types '%+v'", exportId, beginResp.GetTypeList()))
+
+	bw := bufio.NewWriter(w)
+	if wErr := writeArchiveHeader(bw, beginResp.GetTypeList()); wErr != nil {
+		return abortExport(exchange, exportId, archiveError("write", wErr))
+	}
+	for {
+		if cErr := ctx.Err(); cErr != nil {
+			return abortExport(exchange, exportId, cancelledError("Export", cErr))
+		}
+		partialResp, err := exchange.partialExport(exportId)
+		if err != nil {
+			return abortExport(exchange, exportId, err)
+		}
+		if len(partialResp.GetData()) > 0 {
+			batch := exportBatch{typeName: partialResp.GetTypeName(), batchIdx: partialResp.GetBatchIdx(), data: partialResp.GetData()}
+			if wErr := writeArchiveBatch(bw, batch); wErr != nil {
+				return abortExport(exchange, exportId, archiveError("write", wErr))
+			}
+		}
+		if !partialResp.GetHasMore() {
+			break
+		}
+	}
+	if wErr := bw.WriteByte(exportRecordEnd); wErr != nil {
+		return archiveError("write", wErr)
+	}
+	if wErr := bw.Flush(); wErr != nil {
+		return archiveError("write", wErr)
+	}
+	logger.Log(fmt.Sprintf("Returning AdminConnectionImpl:exportDatabase after export '%d'", exportId))
+	return nil
+}
+
+// importDatabase sends the batches of an export archive read from the reader to an import on the server, and returns
+// the number of entities imported. The import completes w/ the request sent after the last batch - if the context is
+// done before, the import is abandoned w/o it.
+func importDatabase(ctx context.Context, exchange exportExchange, r io.Reader) (int64, types.TGError) {
+	logger.Log(fmt.Sprint("Entering AdminConnectionImpl:importDatabase"))
+	br := bufio.NewReader(r)
+	typeList, rErr := readArchiveHeader(br)
+	if rErr != nil {
+		return 0, archiveError("read", rErr)
+	}
+	logger.Debug(fmt.Sprintf("Inside AdminConnectionImpl:importDatabase read archive of types '%+v'", typeList))
+	if err := ctx.Err(); err != nil {
+		return 0, cancelledError("Import", err)
+	}
+	beginResp, err := exchange.beginImport()
+	if err != nil {
+		return 0, err
+	}
+	importId := beginResp.GetImportId()
+
+	numEntities := int64(0)
+	for {
+		batch, rErr := readArchiveBatch(br)
+		if rErr != nil {
+			return numEntities, archiveError("read", rErr)
+		}
+		if cErr := ctx.Err(); cErr != nil {
+			return numEntities, cancelledError("Import", cErr)
+		}
+		if batch == nil {
+			break
+		}
+		partialResp, err := exchange.partialImport(importId, batch.typeName, batch.batchIdx, true, batch.data)
+		if err != nil {
+			return numEntities, err
+		}
+		numEntities += partialResp.GetNumEntities()
+	}
+	partialResp, err := exchange.partialImport(importId, "", 0, false, nil)
+	if err != nil {
+		return numEntities, err
+	}
+	numEntities += partialResp.GetNumEntities()
+	logger.Log(fmt.Sprintf("Returning AdminConnectionImpl:importDatabase after import '%d' of '%d' entities", importId, numEntities))
+	return numEntities, nil
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for export archives
+/////////////////////////////////////////////////////////////////
+
+// abortExport cancels the export on the server, and returns the error that stopped it
+func abortExport(exchange exportExchange, exportId int, cause types.TGError) types.TGError {
+	err := exchange.cancelExport(exportId)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("WARNING: AdminConnectionImpl:abortExport unable to cancel export '%d' w/ error: '%s'", exportId, err.Error()))
+	}
+	return cause
+}
+
+func cancelledError(operation string, cause error) types.TGError {
+	errMsg := fmt.Sprintf("%s of the database was cancelled", operation)
+	return exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, cause.Error())
+}
+
+func archiveError(operation string, cause error) types.TGError {
+	errMsg := fmt.Sprintf("Unable to %s the export archive", operation)
+	return exception.GetErrorByType(types.TGErrorIOException, types.INTERNAL_SERVER_ERROR, errMsg, cause.Error())
+}
+
+func writeArchiveString(w io.Writer, value string) error {
+	if len(value) > math.MaxUint16 {
+		return fmt.Errorf("string of '%d' bytes is too long", len(value))
+	}
+	err := binary.Write(w, binary.BigEndian, uint16(len(value)))
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, value)
+	return err
+}
+
+func readArchiveString(r io.Reader) (string, error) {
+	var length uint16
+	err := binary.Read(r, binary.BigEndian, &length)
+	if err != nil {
+		return "", err
+	}
+	buf := make([]byte, length)
+	_, err = io.ReadFull(r, buf)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+func writeArchiveHeader(w io.Writer, typeList []pdu.ExportTypeInfo) error {
+	_, err := io.WriteString(w, exportArchiveMagic)
+	if err != nil {
+		return err
+	}
+	err = binary.Write(w, binary.BigEndian, exportArchiveVersion)
+	if err != nil {
+		return err
+	}
+	err = binary.Write(w, binary.BigEndian, uint32(len(typeList)))
+	if err != nil {
+		return err
+	}
+	for _, typeInfo := range typeList {
+		err = writeArchiveString(w, typeInfo.TypeName)
+		if err != nil {
+			return err
+		}
+		err = binary.Write(w, binary.BigEndian, typeInfo.NumEntities)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readArchiveHeader(r io.Reader) ([]pdu.ExportTypeInfo, error) {
+	magic := make([]byte, len(exportArchiveMagic))
+	_, err := io.ReadFull(r, magic)
+	if err != nil {
+		return nil, err
+	}
+	if string(magic) != exportArchiveMagic {
+		return nil, fmt.Errorf("not an export archive")
+	}
+	var version uint16
+	err = binary.Read(r, binary.BigEndian, &version)
+	if err != nil {
+		return nil, err
+	}
+	if version != exportArchiveVersion {
+		return nil, fmt.Errorf("unsupported version '%d' of export archive", version)
+	}
+	var numTypes uint32
+	err = binary.Read(r, binary.BigEndian, &numTypes)
+	if err != nil {
+		return nil, err
+	}
+	typeList := make([]pdu.ExportTypeInfo, 0)
+	for i := uint32(0); i < numTypes; i++ {
+		typeName, err := readArchiveString(r)
+		if err != nil {
+			return nil, err
+		}
+		var numEntities int64
+		err = binary.Read(r, binary.BigEndian, &numEntities)
+		if err != nil {
+			return nil, err
+		}
+		typeList = append(typeList, pdu.ExportTypeInfo{TypeName: typeName, NumEntities: numEntities})
+	}
+	return typeList, nil
+}
+
+func writeArchiveBatch(w *bufio.Writer, batch exportBatch) error {
+	err := w.WriteByte(exportRecordBatch)
+	if err != nil {
+		return err
+	}
+	err = writeArchiveString(w, batch.typeName)
+	if err != nil {
+		return err
+	}
+	err = binary.Write(w, binary.BigEndian, int32(batch.batchIdx))
+	if err != nil {
+		return err
+	}
+	if len(batch.data) > maxArchiveBatchLength {
+		return fmt.Errorf("batch of '%d' bytes is too long", len(batch.data))
+	}
+	err = binary.Write(w, binary.BigEndian, uint32(len(batch.data)))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(batch.data)
+	return err
+}
+
+// readArchiveBatch reads the next batch of the archive, or nil at its end
+func readArchiveBatch(r *bufio.Reader) (*exportBatch, error) {
+	marker, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch marker {
+	case exportRecordEnd:
+		return nil, nil
+	case exportRecordBatch:
+	default:
+		return nil, fmt.Errorf("invalid record marker '%d'", marker)
+	}
+	typeName, err := readArchiveString(r)
+	if err != nil {
+		return nil, err
+	}
+	var batchIdx int32
+	err = binary.Read(r, binary.BigEndian, &batchIdx)
+	if err != nil {
+		return nil, err
+	}
+	var length uint32
+	err = binary.Read(r, binary.BigEndian, &length)
+	if err != nil {
+		return nil, err
+	}
+	if length > maxArchiveBatchLength {
+		return nil, fmt.Errorf("batch of '%d' bytes is too long", length)
+	}
+	data := make([]byte, length)
+	_, err = io.ReadFull(r, data)
+	if err != nil {
+		return nil, err
+	}
+	return &exportBatch{typeName: typeName, batchIdx: int(batchIdx), data: data}, nil
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> exportExchange
+/////////////////////////////////////////////////////////////////
+
+func (obj *AdminConnectionImpl) beginExport(batchSize int) (*pdu.BeginExportResponseMessage, types.TGError) {
+	obj.connPoolImpl.AdminLock()
+	defer obj.connPoolImpl.AdminUnlock()
+
+	msgRequest, channelResponse, err := createChannelRequest(obj, pdu.VerbBeginExportRequest)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:beginExport - unable to createChannelRequest(pdu.VerbBeginExportRequest w/ error: '%s'", err.Error()))
+		return nil, err
+	}
+	request := msgRequest.(*pdu.BeginExportRequestMessage)
+	request.SetBatchSize(batchSize)
+
+	msgResponse, err := obj.GetChannel().SendRequest(request, channelResponse.(*channel.BlockingChannelResponse))
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:beginExport - unable to channel.SendRequest() w/ error: '%s'", err.Error()))
+		return nil, err
+	}
+	return msgResponse.(*pdu.BeginExportResponseMessage), nil
+}
+
+func (obj *AdminConnectionImpl) partialExport(exportId int) (*pdu.PartialExportResponseMessage, types.TGError) {
+	obj.connPoolImpl.AdminLock()
+	defer obj.connPoolImpl.AdminUnlock()
+
+	msgRequest, channelResponse, err := createChannelRequest(obj, pdu.VerbPartialExportRequest)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:partialExport - unable to createChannelRequest(pdu.VerbPartialExportRequest w/ error: '%s'", err.Error()))
+		return nil, err
+	}
+	request := msgRequest.(*pdu.PartialExportRequestMessage)
+	request.SetExportId(exportId)
+
+	msgResponse, err := obj.GetChannel().SendRequest(request, channelResponse.(*channel.BlockingChannelResponse))
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:partialExport - unable to channel.SendRequest() w/ error: '%s'", err.Error()))
+		return nil, err
+	}
+	return msgResponse.(*pdu.PartialExportResponseMessage), nil
+}
+
+func (obj *AdminConnectionImpl) cancelExport(exportId int) types.TGError {
+	obj.connPoolImpl.AdminLock()
+	defer obj.connPoolImpl.AdminUnlock()
+
+	msgRequest, _, err := createChannelRequest(obj, pdu.VerbCancelExportRequest)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:cancelExport - unable to createChannelRequest(pdu.VerbCancelExportRequest w/ error: '%s'", err.Error()))
+		return err
+	}
+	request := msgRequest.(*pdu.CancelExportRequestMessage)
+	request.SetExportId(exportId)
+
+	// The cancellation has no response
+	return obj.GetChannel().SendMessage(request)
+}
+
+func (obj *AdminConnectionImpl) beginImport() (*pdu.BeginImportResponseMessage, types.TGError) {
+	obj.connPoolImpl.AdminLock()
+	defer obj.connPoolImpl.AdminUnlock()
+
+	msgRequest, channelResponse, err := createChannelRequest(obj, pdu.VerbBeginImportRequest)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:beginImport - unable to createChannelRequest(pdu.VerbBeginImportRequest w/ error: '%s'", err.Error()))
+		return nil, err
+	}
+	request := msgRequest.(*pdu.BeginImportRequestMessage)
+
+	msgResponse, err := obj.GetChannel().SendRequest(request, channelResponse.(*channel.BlockingChannelResponse))
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:beginImport - unable to channel.SendRequest() w/ error: '%s'", err.Error()))
+		return nil, err
+	}
+	return msgResponse.(*pdu.BeginImportResponseMessage), nil
+}
+
+func (obj *AdminConnectionImpl) partialImport(importId int, typeName string, batchIdx int, hasMore bool, data []byte) (*pdu.PartialImportResponseMessage, types.TGError) {
+	obj.connPoolImpl.AdminLock()
+	defer obj.connPoolImpl.AdminUnlock()
+
+	msgRequest, channelResponse, err := createChannelRequest(obj, pdu.VerbPartialImportRequest)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:partialImport - unable to createChannelRequest(pdu.VerbPartialImportRequest w/ error: '%s'", err.Error()))
+		return nil, err
+	}
+	request := msgRequest.(*pdu.PartialImportRequestMessage)
+	request.SetImportId(importId)
+	request.SetTypeName(typeName)
+	request.SetBatchIdx(batchIdx)
+	request.SetHasMore(hasMore)
+	request.SetData(data)
+
+	msgResponse, err := obj.GetChannel().SendRequest(request, channelResponse.(*channel.BlockingChannelResponse))
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:partialImport - unable to channel.SendRequest() w/ error: '%s'", err.Error()))
+		return nil, err
+	}
+	return msgResponse.(*pdu.PartialImportResponseMessage), nil
+}
@@ -0,0 +1,202 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: AdminExport_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package connection
+
+import (
+	"bytes"
+	"context"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/pdu"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"reflect"
+	"testing"
+)
+
+// exportServer fakes the export and import verbs of a server, w/ the batches to export
+type exportServer struct {
+	typeList   []pdu.ExportTypeInfo
+	batches    []exportBatch
+	next       int
+	cancelled  []int
+	imported   []exportBatch
+	completed  bool
+	onPartial  func()
+	partialErr types.TGError
+}
+
+func (obj *exportServer) beginExport(batchSize int) (*pdu.BeginExportResponseMessage, types.TGError) {
+	resp := pdu.DefaultBeginExportResponseMessage()
+	resp.SetExportId(7)
+	resp.SetTypeList(obj.typeList)
+	return resp, nil
+}
+
+func (obj *exportServer) partialExport(exportId int) (*pdu.PartialExportResponseMessage, types.TGError) {
+	if obj.onPartial != nil {
+		obj.onPartial()
+	}
+	if obj.partialErr != nil {
+		return nil, obj.partialErr
+	}
+	resp := pdu.DefaultPartialExportResponseMessage()
+	resp.SetExportId(exportId)
+	if obj.next < len(obj.batches) {
+		batch := obj.batches[obj.next]
+		resp.SetTypeName(batch.typeName)
+		resp.SetBatchIdx(batch.batchIdx)
+		resp.SetData(batch.data)
+		obj.next++
+	}
+	resp.SetHasMore(obj.next < len(obj.batches))
+	return resp, nil
+}
+
+func (obj *exportServer) cancelExport(exportId int) types.TGError {
+	obj.cancelled = append(obj.cancelled, exportId)
+	return nil
+}
+
+func (obj *exportServer) beginImport() (*pdu.BeginImportResponseMessage, types.TGError) {
+	resp := pdu.DefaultBeginImportResponseMessage()
+	resp.SetImportId(9)
+	return resp, nil
+}
+
+func (obj *exportServer) partialImport(importId int, typeName string, batchIdx int, hasMore bool, data []byte) (*pdu.PartialImportResponseMessage, types.TGError) {
+	resp := pdu.DefaultPartialImportResponseMessage()
+	resp.SetImportId(importId)
+	resp.SetTypeName(typeName)
+	if !hasMore {
+		obj.completed = true
+		return resp, nil
+	}
+	obj.imported = append(obj.imported, exportBatch{typeName: typeName, batchIdx: batchIdx, data: data})
+	resp.SetNumEntities(int64(len(data)))
+	return resp, nil
+}
+
+func newExportServer() *exportServer {
+	return &exportServer{
+		typeList: []pdu.ExportTypeInfo{{TypeName: "Account", NumEntities: 3}, {TypeName: "Transfer", NumEntities: 1}},
+		batches: []exportBatch{
+			{typeName: "Account", batchIdx: 0, data: []byte("ab")},
+			{typeName: "Account", batchIdx: 1, data: []byte("c")},
+			{typeName: "Transfer", batchIdx: 0, data: []byte("t")},
+		},
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	source := newExportServer()
+	var archive bytes.Buffer
+	err := exportDatabase(context.Background(), source, &archive)
+	if err != nil {
+		t.Fatalf("Export failed w/ error: '%s'", err.Error())
+	}
+	if len(source.cancelled) != 0 {
+		t.Errorf("Completed export was cancelled: '%+v'", source.cancelled)
+	}
+
+	target := newExportServer()
+	numEntities, err := importDatabase(context.Background(), target, &archive)
+	if err != nil {
+		t.Fatalf("Import failed w/ error: '%s'", err.Error())
+	}
+	if !reflect.DeepEqual(target.imported, source.batches) {
+		t.Errorf("Imported batches '%+v' instead of '%+v'", target.imported, source.batches)
+	}
+	if !target.completed {
+		t.Error("Import was not completed")
+	}
+	if numEntities != 4 {
+		t.Errorf("Imported '%d' entities instead of 4", numEntities)
+	}
+}
+
+func TestExportCancelled(t *testing.T) {
+	server := newExportServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	server.onPartial = cancel
+	var archive bytes.Buffer
+	err := exportDatabase(ctx, server, &archive)
+	if err == nil {
+		t.Fatal("Cancelled export did not fail")
+	}
+	if !reflect.DeepEqual(server.cancelled, []int{7}) {
+		t.Errorf("Export was not cancelled on the server: '%+v'", server.cancelled)
+	}
+}
+
+func TestExportPartialFailure(t *testing.T) {
+	server := newExportServer()
+	server.partialErr = exception.GetErrorByType(types.TGErrorIOException, types.INTERNAL_SERVER_ERROR, "Connection reset", "")
+	var archive bytes.Buffer
+	err := exportDatabase(context.Background(), server, &archive)
+	if err != server.partialErr {
+		t.Fatalf("Export returned '%+v' instead of the error of the partial export", err)
+	}
+	if !reflect.DeepEqual(server.cancelled, []int{7}) {
+		t.Errorf("Failed export was not cancelled on the server: '%+v'", server.cancelled)
+	}
+}
+
+func TestImportInvalidArchive(t *testing.T) {
+	server := newExportServer()
+	_, err := importDatabase(context.Background(), server, bytes.NewBufferString("NOTANEXPORT"))
+	if err == nil {
+		t.Fatal("Import of an invalid archive did not fail")
+	}
+
+	var archive bytes.Buffer
+	err = exportDatabase(context.Background(), newExportServer(), &archive)
+	if err != nil {
+		t.Fatalf("Export failed w/ error: '%s'", err.Error())
+	}
+	truncated := archive.Bytes()[:archive.Len()-2]
+	_, err = importDatabase(context.Background(), server, bytes.NewReader(truncated))
+	if err == nil {
+		t.Fatal("Import of a truncated archive did not fail")
+	}
+	if server.completed {
+		t.Error("Import of a truncated archive was completed")
+	}
+}
+
+func TestImportOversizedBatch(t *testing.T) {
+	var archive bytes.Buffer
+	if err := writeArchiveHeader(&archive, nil); err != nil {
+		t.Fatalf("Unable to write the archive header w/ error: '%s'", err.Error())
+	}
+	archive.WriteByte(exportRecordBatch)
+	if err := writeArchiveString(&archive, "Account"); err != nil {
+		t.Fatalf("Unable to write the type name w/ error: '%s'", err.Error())
+	}
+	// Batch index 0, followed by a length that an intact archive never holds
+	archive.Write([]byte{0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff})
+
+	server := newExportServer()
+	_, err := importDatabase(context.Background(), server, &archive)
+	if err == nil {
+		t.Fatal("Import of a batch longer than the limit did not fail")
+	}
+	if len(server.imported) != 0 || server.completed {
+		t.Errorf("Import of an oversized batch was sent to the server: '%+v'", server.imported)
+	}
+}
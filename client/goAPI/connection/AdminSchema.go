@@ -0,0 +1,247 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: AdminSchema.go
+ * SVN id: $id: $
+ *
+ */
+
+package connection
+
+import (
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/admin"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"reflect"
+)
+
+// The functions below validate the definitions of the admin schema commands against the graph metadata known
+// to the connection, and build the model objects written on the wire. Attributes, parent types and the node
+// types of an edge type must already exist on the server, and the type or descriptor being created must not.
+
+func invalidSchemaError(errMsg string) types.TGError {
+	logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:Create - %s", errMsg))
+	return exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+}
+
+func isNilSchemaObject(obj interface{}) bool {
+	return obj == nil || reflect.ValueOf(obj).IsNil()
+}
+
+// newSchemaAttributeDescriptor builds the attribute descriptor to create
+func newSchemaAttributeDescriptor(gmd types.TGGraphMetadata, def admin.AttributeDefinition) (*model.AttributeDescriptor, types.TGError) {
+	if def.Name == "" {
+		return nil, invalidSchemaError("Name of the attribute descriptor is required")
+	}
+	if def.AttrType <= types.AttributeTypeInvalid || def.AttrType > types.AttributeTypeClob {
+		return nil, invalidSchemaError(fmt.Sprintf("Attribute descriptor '%s' has invalid type '%d'", def.Name, def.AttrType))
+	}
+	existing, err := gmd.GetAttributeDescriptor(def.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !isNilSchemaObject(existing) {
+		return nil, invalidSchemaError(fmt.Sprintf("Attribute descriptor '%s' already exists", def.Name))
+	}
+	if def.IsArray && def.IsEncrypted {
+		return nil, invalidSchemaError(fmt.Sprintf("Array attribute descriptor '%s' cannot be encrypted", def.Name))
+	}
+	desc := model.NewAttributeDescriptorAsArray(def.Name, def.AttrType, def.IsArray)
+	desc.SetIsEncrypted(def.IsEncrypted)
+	if def.AttrType == types.AttributeTypeNumber {
+		if def.Precision > 0 {
+			desc.SetPrecision(def.Precision)
+		}
+		if def.Scale > 0 {
+			desc.SetScale(def.Scale)
+		}
+		if desc.GetScale() > desc.GetPrecision() {
+			return nil, invalidSchemaError(fmt.Sprintf("Scale '%d' of attribute descriptor '%s' exceeds its precision '%d'", desc.GetScale(), def.Name, desc.GetPrecision()))
+		}
+	} else if def.Precision != 0 || def.Scale != 0 {
+		return nil, invalidSchemaError(fmt.Sprintf("Precision and scale only apply to number attributes, and not to '%s'", def.Name))
+	}
+	return desc, nil
+}
+
+// schemaAttributeDescriptors resolves the attribute descriptors of the names, in the order given
+func schemaAttributeDescriptors(gmd types.TGGraphMetadata, typeName string, attrNames []string) ([]*model.AttributeDescriptor, types.TGError) {
+	descs := make([]*model.AttributeDescriptor, 0, len(attrNames))
+	seen := make(map[string]bool, len(attrNames))
+	for _, attrName := range attrNames {
+		if seen[attrName] {
+			continue
+		}
+		seen[attrName] = true
+		desc, err := gmd.GetAttributeDescriptor(attrName)
+		if err != nil {
+			return nil, err
+		}
+		if isNilSchemaObject(desc) {
+			return nil, invalidSchemaError(fmt.Sprintf("Attribute descriptor '%s' of '%s' does not exist", attrName, typeName))
+		}
+		descs = append(descs, desc.(*model.AttributeDescriptor))
+	}
+	return descs, nil
+}
+
+// schemaNodeType resolves an existing node type by name
+func schemaNodeType(gmd types.TGGraphMetadata, typeName string) (types.TGNodeType, types.TGError) {
+	nodeType, err := gmd.GetNodeType(typeName)
+	if err != nil {
+		return nil, err
+	}
+	if isNilSchemaObject(nodeType) {
+		return nil, invalidSchemaError(fmt.Sprintf("Node type '%s' does not exist", typeName))
+	}
+	return nodeType, nil
+}
+
+// newSchemaNodeType builds the node type to create, w/ its attributes and primary key
+func newSchemaNodeType(gmd types.TGGraphMetadata, def admin.NodeTypeDefinition) (*model.NodeType, types.TGError) {
+	if def.Name == "" {
+		return nil, invalidSchemaError("Name of the node type is required")
+	}
+	existing, err := gmd.GetNodeType(def.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !isNilSchemaObject(existing) {
+		return nil, invalidSchemaError(fmt.Sprintf("Node type '%s' already exists", def.Name))
+	}
+	var parent types.TGEntityType
+	if def.Parent != "" {
+		parent, err = schemaNodeType(gmd, def.Parent)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// The primary key attributes are attributes of the node type too, whether listed as such or not
+	descs, err := schemaAttributeDescriptors(gmd, def.Name, append(append([]string{}, def.Attributes...), def.PKeyAttributes...))
+	if err != nil {
+		return nil, err
+	}
+	pKeys, err := schemaAttributeDescriptors(gmd, def.Name, def.PKeyAttributes)
+	if err != nil {
+		return nil, err
+	}
+	nodeType := model.NewNodeType(def.Name, parent)
+	for _, desc := range descs {
+		nodeType.AddAttributeDescriptor(desc.GetName(), desc)
+	}
+	nodeType.SetPKeyAttributeDescriptors(pKeys)
+	return nodeType, nil
+}
+
+// newSchemaEdgeType builds the edge type to create, between the existing from and to node types
+func newSchemaEdgeType(gmd types.TGGraphMetadata, def admin.EdgeTypeDefinition) (*model.EdgeType, types.TGError) {
+	if def.Name == "" {
+		return nil, invalidSchemaError("Name of the edge type is required")
+	}
+	switch def.Direction {
+	case types.DirectionTypeUnDirected, types.DirectionTypeDirected, types.DirectionTypeBiDirectional:
+	default:
+		return nil, invalidSchemaError(fmt.Sprintf("Edge type '%s' has invalid direction '%d'", def.Name, def.Direction))
+	}
+	existing, err := gmd.GetEdgeType(def.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !isNilSchemaObject(existing) {
+		return nil, invalidSchemaError(fmt.Sprintf("Edge type '%s' already exists", def.Name))
+	}
+	var parent types.TGEntityType
+	if def.Parent != "" {
+		parentType, err := gmd.GetEdgeType(def.Parent)
+		if err != nil {
+			return nil, err
+		}
+		if isNilSchemaObject(parentType) {
+			return nil, invalidSchemaError(fmt.Sprintf("Edge type '%s' does not exist", def.Parent))
+		}
+		parent = parentType
+	}
+	if def.FromNodeType == "" || def.ToNodeType == "" {
+		return nil, invalidSchemaError(fmt.Sprintf("Edge type '%s' needs both a from and a to node type", def.Name))
+	}
+	fromType, err := schemaNodeType(gmd, def.FromNodeType)
+	if err != nil {
+		return nil, err
+	}
+	toType, err := schemaNodeType(gmd, def.ToNodeType)
+	if err != nil {
+		return nil, err
+	}
+	descs, err := schemaAttributeDescriptors(gmd, def.Name, def.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	edgeType := model.NewEdgeType(def.Name, def.Direction, parent)
+	edgeType.SetFromNodeType(fromType)
+	edgeType.SetFromTypeId(fromType.GetEntityTypeId())
+	edgeType.SetToNodeType(toType)
+	edgeType.SetToTypeId(toType.GetEntityTypeId())
+	for _, desc := range descs {
+		edgeType.AddAttributeDescriptor(desc.GetName(), desc)
+	}
+	return edgeType, nil
+}
+
+// validateIndexDefinition verifies that the index has a name, and at least one attribute - all of them existing,
+// as do the node types the index is restricted to
+func validateIndexDefinition(gmd types.TGGraphMetadata, def *admin.IndexDefinition) types.TGError {
+	if def.Name == "" {
+		return invalidSchemaError("Name of the index is required")
+	}
+	if len(def.Attributes) == 0 {
+		return invalidSchemaError(fmt.Sprintf("Index '%s' needs at least one attribute", def.Name))
+	}
+	_, err := schemaAttributeDescriptors(gmd, def.Name, def.Attributes)
+	if err != nil {
+		return err
+	}
+	for _, typeName := range def.NodeTypes {
+		_, err = schemaNodeType(gmd, typeName)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createSchemaObject builds the object of an admin create command against the current metadata of the server,
+// executes the command, and refreshes the metadata so that the new object can be used right away
+func (obj *AdminConnectionImpl) createSchemaObject(command admin.AdminCommand, build func(gmd types.TGGraphMetadata) (interface{}, types.TGError)) types.TGError {
+	logger.Log(fmt.Sprintf("Entering AdminConnectionImpl:createSchemaObject for Admin Command: '%+v'", command))
+	gmd, err := obj.GetGraphMetadata(true)
+	if err != nil {
+		return err
+	}
+	schemaObj, err := build(gmd)
+	if err != nil {
+		return err
+	}
+	_, err = obj.executeAdminRequest(command, schemaObj)
+	if err != nil {
+		return err
+	}
+	_, err = obj.GetGraphMetadata(true)
+	if err != nil {
+		return err
+	}
+	logger.Log(fmt.Sprintf("Returning AdminConnectionImpl:createSchemaObject for Admin Command: '%+v'", command))
+	return nil
+}
@@ -0,0 +1,131 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: AdminSchema_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package connection
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/admin"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"testing"
+)
+
+func newSchemaMetadata() types.TGGraphMetadata {
+	gmd := model.NewGraphMetadata(nil)
+	id := model.NewAttributeDescriptorWithType("id", types.AttributeTypeLong)
+	name := model.NewAttributeDescriptorWithType("name", types.AttributeTypeString)
+	gmd.SetAttributeDescriptors(map[string]types.TGAttributeDescriptor{"id": id, "name": name})
+	nodeType := model.NewNodeType("Account", nil)
+	nodeType.SetEntityTypeId(12)
+	nodeType.SetAttributeMap(map[string]*model.AttributeDescriptor{"id": id, "name": name})
+	nodeType.SetPKeyAttributeDescriptors([]*model.AttributeDescriptor{id})
+	gmd.SetNodeTypes(map[string]types.TGNodeType{"Account": nodeType})
+	return gmd
+}
+
+func TestNewSchemaAttributeDescriptor(t *testing.T) {
+	gmd := newSchemaMetadata()
+	desc, err := newSchemaAttributeDescriptor(gmd, admin.AttributeDefinition{Name: "balance", AttrType: types.AttributeTypeNumber, IsEncrypted: true, Precision: 12, Scale: 2})
+	if err != nil {
+		t.Fatalf("newSchemaAttributeDescriptor failed w/ error: '%+v'", err)
+	}
+	if desc.GetPrecision() != 12 || desc.GetScale() != 2 || !desc.IsEncrypted() || desc.IsAttributeArray() {
+		t.Errorf("newSchemaAttributeDescriptor built '%+v'", desc)
+	}
+
+	invalid := []admin.AttributeDefinition{
+		{Name: "", AttrType: types.AttributeTypeString},
+		{Name: "tags", AttrType: types.AttributeTypeInvalid},
+		{Name: "name", AttrType: types.AttributeTypeString},
+		{Name: "label", AttrType: types.AttributeTypeString, Precision: 5},
+		{Name: "rate", AttrType: types.AttributeTypeNumber, Precision: 4, Scale: 6},
+		{Name: "pins", AttrType: types.AttributeTypeInteger, IsArray: true, IsEncrypted: true},
+	}
+	for _, def := range invalid {
+		_, err = newSchemaAttributeDescriptor(gmd, def)
+		if err == nil {
+			t.Errorf("newSchemaAttributeDescriptor accepted invalid definition '%+v'", def)
+		}
+	}
+}
+
+func TestNewSchemaNodeType(t *testing.T) {
+	gmd := newSchemaMetadata()
+	nodeType, err := newSchemaNodeType(gmd, admin.NodeTypeDefinition{Name: "Savings", Parent: "Account", Attributes: []string{"name"}, PKeyAttributes: []string{"id"}})
+	if err != nil {
+		t.Fatalf("newSchemaNodeType failed w/ error: '%+v'", err)
+	}
+	if nodeType.DerivedFrom().GetName() != "Account" || len(nodeType.GetAttributeDescriptors()) != 2 {
+		t.Errorf("newSchemaNodeType built '%+v'", nodeType)
+	}
+	pKeys := nodeType.GetPKeyAttributeDescriptors()
+	if len(pKeys) != 1 || pKeys[0].GetName() != "id" {
+		t.Errorf("newSchemaNodeType built primary key '%+v'", pKeys)
+	}
+
+	invalid := []admin.NodeTypeDefinition{
+		{Name: "Account"},
+		{Name: "Savings", Parent: "Bank"},
+		{Name: "Savings", Attributes: []string{"iban"}},
+		{Name: "Savings", PKeyAttributes: []string{"iban"}},
+	}
+	for _, def := range invalid {
+		_, err = newSchemaNodeType(gmd, def)
+		if err == nil {
+			t.Errorf("newSchemaNodeType accepted invalid definition '%+v'", def)
+		}
+	}
+}
+
+func TestNewSchemaEdgeType(t *testing.T) {
+	gmd := newSchemaMetadata()
+	edgeType, err := newSchemaEdgeType(gmd, admin.EdgeTypeDefinition{Name: "Transfer", Direction: types.DirectionTypeDirected, FromNodeType: "Account", ToNodeType: "Account", Attributes: []string{"id"}})
+	if err != nil {
+		t.Fatalf("newSchemaEdgeType failed w/ error: '%+v'", err)
+	}
+	if edgeType.GetDirectionType() != types.DirectionTypeDirected || edgeType.GetFromTypeId() != 12 || edgeType.GetToTypeId() != 12 {
+		t.Errorf("newSchemaEdgeType built '%+v'", edgeType)
+	}
+
+	invalid := []admin.EdgeTypeDefinition{
+		{Name: "Transfer", FromNodeType: "Account"},
+		{Name: "Transfer", FromNodeType: "Account", ToNodeType: "Bank"},
+		{Name: "Transfer", Parent: "Payment", FromNodeType: "Account", ToNodeType: "Account"},
+		{Name: "Transfer", Direction: types.TGDirectionType(7), FromNodeType: "Account", ToNodeType: "Account"},
+	}
+	for _, def := range invalid {
+		_, err = newSchemaEdgeType(gmd, def)
+		if err == nil {
+			t.Errorf("newSchemaEdgeType accepted invalid definition '%+v'", def)
+		}
+	}
+}
+
+func TestValidateIndexDefinition(t *testing.T) {
+	gmd := newSchemaMetadata()
+	err := validateIndexDefinition(gmd, &admin.IndexDefinition{Name: "nameIdx", IsUnique: true, Attributes: []string{"name"}, NodeTypes: []string{"Account"}})
+	if err != nil {
+		t.Fatalf("validateIndexDefinition failed w/ error: '%+v'", err)
+	}
+	for _, def := range []admin.IndexDefinition{{Attributes: []string{"name"}}, {Name: "emptyIdx"}, {Name: "ibanIdx", Attributes: []string{"iban"}}, {Name: "nameIdx", Attributes: []string{"name"}, NodeTypes: []string{"Bank"}}} {
+		if validateIndexDefinition(gmd, &def) == nil {
+			t.Errorf("validateIndexDefinition accepted invalid definition '%+v'", def)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: AdminUsers.go
+ * SVN id: $id: $
+ *
+ */
+
+package connection
+
+import (
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/admin"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"strings"
+)
+
+func invalidUserError(errMsg string) types.TGError {
+	logger.Error(fmt.Sprintf("ERROR: Returning AdminConnectionImpl:manageUser - %s", errMsg))
+	return exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+}
+
+// validateUserDefinition verifies the parts of the user definition sent to the server for the command: the name
+// always, the password when creating a user or changing its password, and the roles when creating a user or
+// changing its roles
+func validateUserDefinition(command admin.AdminCommand, def *admin.UserDefinition) types.TGError {
+	if strings.TrimSpace(def.Name) == "" {
+		return invalidUserError("Name of the user is required")
+	}
+	if command == admin.AdminCommandCreateUser || command == admin.AdminCommandChangePassword {
+		if len(def.Password) == 0 {
+			return invalidUserError(fmt.Sprintf("Password of user '%s' cannot be empty", def.Name))
+		}
+	}
+	if command == admin.AdminCommandCreateUser || command == admin.AdminCommandSetUserRoles {
+		seen := make(map[string]bool, len(def.Roles))
+		for _, role := range def.Roles {
+			// Roles are listed comma separated for a user on the server
+			if strings.TrimSpace(role) == "" || strings.Contains(role, ",") {
+				return invalidUserError(fmt.Sprintf("Role '%s' of user '%s' is not a valid role name", role, def.Name))
+			}
+			if seen[role] {
+				return invalidUserError(fmt.Sprintf("Role '%s' is listed more than once for user '%s'", role, def.Name))
+			}
+			seen[role] = true
+		}
+	}
+	return nil
+}
+
+// manageUser validates the user definition and executes the user management command
+func (obj *AdminConnectionImpl) manageUser(command admin.AdminCommand, def *admin.UserDefinition) types.TGError {
+	err := validateUserDefinition(command, def)
+	if err != nil {
+		return err
+	}
+	_, err = obj.executeAdminRequest(command, def)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: AdminUsers_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package connection
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/admin"
+	"testing"
+)
+
+func TestValidateUserDefinition(t *testing.T) {
+	valid := map[admin.AdminCommand]admin.UserDefinition{
+		admin.AdminCommandCreateUser:     {Name: "scott", Password: []byte("tiger"), Roles: []string{"operator", "auditor"}},
+		admin.AdminCommandChangePassword: {Name: "scott", Password: []byte("lion")},
+		admin.AdminCommandSetUserRoles:   {Name: "scott"},
+		admin.AdminCommandDropUser:       {Name: "scott"},
+	}
+	for command, def := range valid {
+		err := validateUserDefinition(command, &def)
+		if err != nil {
+			t.Errorf("validateUserDefinition rejected '%+v' for command '%d' w/ error: '%+v'", &def, command, err)
+		}
+	}
+
+	invalid := map[admin.AdminCommand]admin.UserDefinition{
+		admin.AdminCommandCreateUser:     {Name: "scott", Password: []byte("tiger"), Roles: []string{"operator", "operator"}},
+		admin.AdminCommandChangePassword: {Name: "scott"},
+		admin.AdminCommandSetUserRoles:   {Name: "scott", Roles: []string{"operator,auditor"}},
+		admin.AdminCommandDropUser:       {Name: " "},
+	}
+	for command, def := range invalid {
+		if validateUserDefinition(command, &def) == nil {
+			t.Errorf("validateUserDefinition accepted '%+v' for command '%d'", &def, command)
+		}
+	}
+}
@@ -0,0 +1,458 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: BulkLoader.go
+ * SVN id: $id: $
+ *
+ */
+
+package connection
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"reflect"
+	"sync"
+)
+
+const (
+	DefaultBulkChunkSize   = 1000
+	DefaultBulkChunkBytes  = 4 * 1024 * 1024
+	DefaultBulkMaxInFlight = 2
+
+	// entityOverhead is the estimated serialized size of an entity w/o its attribute values
+	entityOverhead = 32
+)
+
+// BulkNode is a node to be loaded, along w/ the key by which the edges of the load refer to it
+type BulkNode struct {
+	Key  string
+	Node types.TGNode
+}
+
+// BulkEdge is an edge to be loaded between the nodes w/ the keys. The edge is of the edge type if one is
+// specified, and otherwise of the direction.
+type BulkEdge struct {
+	FromKey    string
+	ToKey      string
+	EdgeType   types.TGEdgeType
+	Direction  types.TGDirectionType
+	Attributes map[string]interface{}
+}
+
+// BulkLoadProgress counts what has been committed so far by a BulkLoader
+type BulkLoadProgress struct {
+	Chunks int
+	Nodes  int64
+	Edges  int64
+	Bytes  int64
+}
+
+// BulkLoadListener gets called after each chunk committed by a BulkLoader
+type BulkLoadListener func(progress BulkLoadProgress)
+
+// committedNode is what a BulkLoader keeps of a committed node, to connect the edges of later chunks to it
+type committedNode struct {
+	id       int64
+	version  int
+	nodeType types.TGNodeType
+}
+
+// loadChunk is the set of nodes and edges committed together. Once its edges are created, the nodes also hold
+// references to the nodes of earlier chunks that the edges connect to.
+type loadChunk struct {
+	keys  []string
+	nodes map[string]types.TGNode
+	edges []BulkEdge
+	size  int64
+}
+
+// BulkLoader inserts a stream of nodes and edges, committing them in chunks of bounded count and estimated size.
+// Edges refer to their nodes by key, and are created only when their chunk is committed, so that an edge can
+// connect to a node committed in an earlier chunk, which is no longer held in memory. Chunks are committed in
+// order by a background goroutine, and adding blocks while the maximum number of chunks are waiting for, or
+// undergoing, commit. The connection must not be used by anything else until the loader is closed.
+type BulkLoader struct {
+	conn          types.TGConnection
+	chunkSize     int
+	chunkBytes    int64
+	maxInFlight   int
+	listener      BulkLoadListener
+	keys          map[string]bool
+	chunk         *loadChunk
+	chunks        chan *loadChunk
+	done          chan bool
+	committedKeys map[string]committedNode
+	lock          sync.Mutex
+	progress      BulkLoadProgress
+	err           types.TGError
+}
+
+func NewBulkLoader(conn types.TGConnection) *BulkLoader {
+	newLoader := BulkLoader{
+		conn:          conn,
+		chunkSize:     DefaultBulkChunkSize,
+		chunkBytes:    DefaultBulkChunkBytes,
+		maxInFlight:   DefaultBulkMaxInFlight,
+		keys:          make(map[string]bool, 0),
+		committedKeys: make(map[string]committedNode, 0),
+	}
+	return &newLoader
+}
+
+/////////////////////////////////////////////////////////////////
+// Helper functions for BulkLoader
+/////////////////////////////////////////////////////////////////
+
+// SetChunkSize sets the maximum number of entities committed together
+func (obj *BulkLoader) SetChunkSize(size int) {
+	if size > 0 {
+		obj.chunkSize = size
+	}
+}
+
+// SetChunkBytes sets the maximum estimated size of the entities committed together
+func (obj *BulkLoader) SetChunkBytes(size int64) {
+	if size > 0 {
+		obj.chunkBytes = size
+	}
+}
+
+// SetMaxInFlight sets the maximum number of chunks waiting for, or undergoing, commit before adding blocks. It
+// only applies if set before adding anything to the loader.
+func (obj *BulkLoader) SetMaxInFlight(count int) {
+	if count > 0 {
+		obj.maxInFlight = count
+	}
+}
+
+// SetProgressListener sets the listener called after each committed chunk
+func (obj *BulkLoader) SetProgressListener(listener BulkLoadListener) {
+	obj.listener = listener
+}
+
+// GetProgress returns what has been committed so far
+func (obj *BulkLoader) GetProgress() BulkLoadProgress {
+	obj.lock.Lock()
+	defer obj.lock.Unlock()
+	return obj.progress
+}
+
+// AddNode adds the node to the current chunk, committing the chunk if it is full
+func (obj *BulkLoader) AddNode(key string, node types.TGNode) types.TGError {
+	if err := obj.getError(); err != nil {
+		return err
+	}
+	if key == "" || node == nil || reflect.ValueOf(node).IsNil() {
+		return bulkLoadError("Node to load needs a key and a node")
+	}
+	if obj.keys[key] {
+		return bulkLoadError(fmt.Sprintf("Node key '%s' is already loaded", key))
+	}
+	if !node.GetIsNew() {
+		return bulkLoadError(fmt.Sprintf("Node '%s' to load is already stored in the database", key))
+	}
+	obj.keys[key] = true
+	chunk := obj.currentChunk()
+	chunk.keys = append(chunk.keys, key)
+	chunk.nodes[key] = node
+	chunk.size += estimateEntitySize(node)
+	return obj.flushIfFull()
+}
+
+// AddStoredNode lets the edges of the load connect to a node already stored in the database, under the key
+func (obj *BulkLoader) AddStoredNode(key string, node types.TGNode) types.TGError {
+	if err := obj.getError(); err != nil {
+		return err
+	}
+	if key == "" || node == nil || reflect.ValueOf(node).IsNil() {
+		return bulkLoadError("Stored node needs a key and a node")
+	}
+	if obj.keys[key] {
+		return bulkLoadError(fmt.Sprintf("Node key '%s' is already loaded", key))
+	}
+	if node.GetIsNew() {
+		return bulkLoadError(fmt.Sprintf("Node '%s' is not stored in the database", key))
+	}
+	obj.keys[key] = true
+	obj.currentChunk().nodes[key] = node
+	return nil
+}
+
+// HasNode checks whether a node has been added to the loader under the key
+func (obj *BulkLoader) HasNode(key string) bool {
+	return obj.keys[key]
+}
+
+// AddEdge adds the edge to the current chunk, committing the chunk if it is full. The nodes of the edge must have
+// been added to the loader before.
+func (obj *BulkLoader) AddEdge(edge BulkEdge) types.TGError {
+	if err := obj.getError(); err != nil {
+		return err
+	}
+	for _, key := range []string{edge.FromKey, edge.ToKey} {
+		if !obj.keys[key] {
+			return bulkLoadError(fmt.Sprintf("Node key '%s' of the edge to load is unknown", key))
+		}
+	}
+	chunk := obj.currentChunk()
+	chunk.edges = append(chunk.edges, edge)
+	size := int64(entityOverhead)
+	for name, value := range edge.Attributes {
+		size += estimateValueSize(name, value)
+	}
+	chunk.size += size
+	return obj.flushIfFull()
+}
+
+// LoadNodes adds the nodes received from the channel until it is closed
+func (obj *BulkLoader) LoadNodes(nodes <-chan BulkNode) types.TGError {
+	for bNode := range nodes {
+		if err := obj.AddNode(bNode.Key, bNode.Node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadEdges adds the edges received from the channel until it is closed
+func (obj *BulkLoader) LoadEdges(edges <-chan BulkEdge) types.TGError {
+	for edge := range edges {
+		if err := obj.AddEdge(edge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush hands the current chunk over for commit, even if it is not full
+func (obj *BulkLoader) Flush() types.TGError {
+	if err := obj.getError(); err != nil {
+		return err
+	}
+	if obj.chunk == nil {
+		return nil
+	}
+	if obj.chunks == nil {
+		obj.chunks = make(chan *loadChunk, obj.maxInFlight-1)
+		obj.done = make(chan bool)
+		go obj.commitChunks()
+	}
+	obj.chunks <- obj.chunk
+	obj.chunk = nil
+	return nil
+}
+
+// Close commits whatever is left, waits for all the chunks to be committed and returns the final progress. The
+// loader can still be used afterwards, e.g. once the connection has been used for something else in between.
+func (obj *BulkLoader) Close() (BulkLoadProgress, types.TGError) {
+	obj.Flush()
+	if obj.chunks != nil {
+		close(obj.chunks)
+		<-obj.done
+		obj.chunks = nil
+	}
+	logger.Log(fmt.Sprintf("Returning BulkLoader:Close w/ progress '%+v'", obj.GetProgress()))
+	return obj.GetProgress(), obj.getError()
+}
+
+func (obj *BulkLoader) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString("BulkLoader:{")
+	buffer.WriteString(fmt.Sprintf("ChunkSize: %d", obj.chunkSize))
+	buffer.WriteString(fmt.Sprintf(", ChunkBytes: %d", obj.chunkBytes))
+	buffer.WriteString(fmt.Sprintf(", MaxInFlight: %d", obj.maxInFlight))
+	buffer.WriteString(fmt.Sprintf(", Progress: %+v", obj.GetProgress()))
+	buffer.WriteString("}")
+	return buffer.String()
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for BulkLoader
+/////////////////////////////////////////////////////////////////
+
+func bulkLoadError(errMsg string) types.TGError {
+	logger.Error(fmt.Sprintf("ERROR: Returning BulkLoader - %s", errMsg))
+	return exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+}
+
+// estimateValueSize estimates the serialized size of an attribute
+func estimateValueSize(name string, value interface{}) int64 {
+	size := int64(len(name) + 8)
+	switch v := value.(type) {
+	case string:
+		size += int64(len(v))
+	case []byte:
+		size += int64(len(v))
+	default:
+		size += 8
+	}
+	return size
+}
+
+// estimateEntitySize estimates the serialized size of an entity from its attribute values
+func estimateEntitySize(entity types.TGEntity) int64 {
+	size := int64(entityOverhead)
+	attrs, err := entity.GetAttributes()
+	if err != nil {
+		return size
+	}
+	for _, attr := range attrs {
+		size += estimateValueSize(attr.GetName(), attr.GetValue())
+	}
+	return size
+}
+
+func (obj *BulkLoader) getError() types.TGError {
+	obj.lock.Lock()
+	defer obj.lock.Unlock()
+	return obj.err
+}
+
+func (obj *BulkLoader) currentChunk() *loadChunk {
+	if obj.chunk == nil {
+		obj.chunk = &loadChunk{nodes: make(map[string]types.TGNode, 0)}
+	}
+	return obj.chunk
+}
+
+func (obj *BulkLoader) flushIfFull() types.TGError {
+	if len(obj.chunk.keys)+len(obj.chunk.edges) < obj.chunkSize && obj.chunk.size < obj.chunkBytes {
+		return nil
+	}
+	return obj.Flush()
+}
+
+// commitChunks commits the chunks in order. Once a commit fails, the chunks that follow are dropped.
+func (obj *BulkLoader) commitChunks() {
+	defer close(obj.done)
+	for chunk := range obj.chunks {
+		if obj.getError() != nil {
+			continue
+		}
+		err := obj.commitChunk(chunk)
+		obj.lock.Lock()
+		if err != nil {
+			obj.err = err
+			obj.lock.Unlock()
+			continue
+		}
+		obj.progress.Chunks++
+		obj.progress.Nodes += int64(len(chunk.keys))
+		obj.progress.Edges += int64(len(chunk.edges))
+		obj.progress.Bytes += chunk.size
+		progress := obj.progress
+		obj.lock.Unlock()
+		if obj.listener != nil {
+			obj.listener(progress)
+		}
+	}
+}
+
+// resolveNode returns the node of the key, either from the chunk or as a reference to the committed node
+func (obj *BulkLoader) resolveNode(chunk *loadChunk, key string, gof types.TGGraphObjectFactory) (types.TGNode, types.TGError) {
+	if node, ok := chunk.nodes[key]; ok {
+		return node, nil
+	}
+	committed, ok := obj.committedKeys[key]
+	if !ok {
+		return nil, bulkLoadError(fmt.Sprintf("Node key '%s' of the edge to load is not committed", key))
+	}
+	var node types.TGNode
+	var err types.TGError
+	if committed.nodeType == nil || reflect.ValueOf(committed.nodeType).IsNil() {
+		node, err = gof.CreateNode()
+	} else {
+		node, err = gof.CreateNodeInGraph(committed.nodeType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	node.SetIsNew(false)
+	node.SetEntityId(committed.id)
+	node.SetVersion(committed.version)
+	chunk.nodes[key] = node
+	return node, nil
+}
+
+// commitChunk inserts the nodes and edges of the chunk, and commits them. If the chunk cannot be committed, its
+// pending changes are rolled back, so that they are not committed later on along w/ other changes of the connection.
+func (obj *BulkLoader) commitChunk(chunk *loadChunk) types.TGError {
+	logger.Log(fmt.Sprintf("Entering BulkLoader:commitChunk w/ '%d' nodes and '%d' edges", len(chunk.keys), len(chunk.edges)))
+	err := obj.insertChunk(chunk)
+	if err == nil && (len(chunk.keys) > 0 || len(chunk.edges) > 0) {
+		_, err = obj.conn.Commit()
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning BulkLoader:commitChunk w/ error '%s'", err.Error()))
+		_ = obj.conn.Rollback()
+		return err
+	}
+	// Keep only the ids of the committed nodes, and the versions of the referenced ones as updated by the commit
+	for key, node := range chunk.nodes {
+		if node.GetIsNew() {
+			return bulkLoadError(fmt.Sprintf("Node '%s' did not get an id from the commit", key))
+		}
+		nodeType, _ := node.GetEntityType().(types.TGNodeType)
+		obj.committedKeys[key] = committedNode{id: node.GetVirtualId(), version: node.GetVersion(), nodeType: nodeType}
+	}
+	logger.Log(fmt.Sprint("Returning BulkLoader:commitChunk"))
+	return nil
+}
+
+// insertChunk inserts the nodes of the chunk, then its edges between the nodes of the chunk or committed ones
+func (obj *BulkLoader) insertChunk(chunk *loadChunk) types.TGError {
+	gof, err := obj.conn.GetGraphObjectFactory()
+	if err != nil {
+		return err
+	}
+	for _, key := range chunk.keys {
+		err = obj.conn.InsertEntity(chunk.nodes[key])
+		if err != nil {
+			return err
+		}
+	}
+	for _, bEdge := range chunk.edges {
+		fromNode, err := obj.resolveNode(chunk, bEdge.FromKey, gof)
+		if err != nil {
+			return err
+		}
+		toNode, err := obj.resolveNode(chunk, bEdge.ToKey, gof)
+		if err != nil {
+			return err
+		}
+		var edge types.TGEdge
+		if bEdge.EdgeType != nil && !reflect.ValueOf(bEdge.EdgeType).IsNil() {
+			edge, err = gof.CreateEdgeWithEdgeType(fromNode, toNode, bEdge.EdgeType)
+		} else {
+			edge, err = gof.CreateEdgeWithDirection(fromNode, toNode, bEdge.Direction)
+		}
+		if err != nil {
+			return err
+		}
+		for name, value := range bEdge.Attributes {
+			err = edge.SetOrCreateAttribute(name, value)
+			if err != nil {
+				return err
+			}
+		}
+		err = obj.conn.InsertEntity(edge)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: BulkLoader_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package connection
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"testing"
+)
+
+// loaderConnection commits by giving the added entities ids, the way the server does, and records the commits.
+// Once failCommits is reached, the commits fail.
+type loaderConnection struct {
+	*upsertConnection
+	nextId      int64
+	commits     [][]types.TGEntity
+	failCommits int
+	rollbacks   int
+}
+
+func (obj *loaderConnection) Commit() (types.TGResultSet, types.TGError) {
+	if obj.failCommits > 0 && len(obj.commits) >= obj.failCommits {
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, "Commit failed", "")
+	}
+	committed := make([]types.TGEntity, 0)
+	for _, entity := range obj.added {
+		obj.nextId++
+		entity.SetIsNew(false)
+		entity.SetEntityId(obj.nextId)
+		committed = append(committed, entity)
+	}
+	obj.commits = append(obj.commits, committed)
+	obj.added = make(map[int64]types.TGEntity)
+	obj.changed = make(map[int64]types.TGEntity)
+	return nil, nil
+}
+
+func (obj *loaderConnection) Rollback() types.TGError {
+	obj.rollbacks++
+	obj.added = make(map[int64]types.TGEntity)
+	obj.changed = make(map[int64]types.TGEntity)
+	return nil
+}
+
+func (obj *loaderConnection) newAccount(t *testing.T, id int64) types.TGNode {
+	nodeType, _ := obj.gof.GetGraphMetaData().GetNodeType("Account")
+	node, err := obj.gof.CreateNodeInGraph(nodeType)
+	if err != nil {
+		t.Fatalf("Unable to create node w/ error '%s'", err.Error())
+	}
+	node.SetOrCreateAttribute("id", id)
+	return node
+}
+
+func TestBulkLoader(t *testing.T) {
+	conn := &loaderConnection{upsertConnection: newUpsertConnection()}
+	loader := NewBulkLoader(conn)
+	loader.SetChunkSize(3)
+	loader.SetMaxInFlight(1)
+	reported := make([]BulkLoadProgress, 0)
+	loader.SetProgressListener(func(progress BulkLoadProgress) {
+		reported = append(reported, progress)
+	})
+
+	nodes := make(chan BulkNode, 4)
+	for i, key := range []string{"a", "b", "c", "d"} {
+		nodes <- BulkNode{Key: key, Node: conn.newAccount(t, int64(i))}
+	}
+	close(nodes)
+	if err := loader.LoadNodes(nodes); err != nil {
+		t.Fatalf("TestBulkLoader unable to load nodes w/ error '%s'", err.Error())
+	}
+	if err := loader.AddEdge(BulkEdge{FromKey: "a", ToKey: "d", Direction: types.DirectionTypeDirected}); err != nil {
+		t.Fatalf("TestBulkLoader unable to add edge w/ error '%s'", err.Error())
+	}
+	if err := loader.AddEdge(BulkEdge{FromKey: "a", ToKey: "x"}); err == nil {
+		t.Errorf("TestBulkLoader expected an error for an edge to an unknown node")
+	}
+	if err := loader.AddNode("a", conn.newAccount(t, 9)); err == nil {
+		t.Errorf("TestBulkLoader expected an error for a duplicate node key")
+	}
+
+	progress, err := loader.Close()
+	if err != nil {
+		t.Fatalf("TestBulkLoader unable to close w/ error '%s'", err.Error())
+	}
+	if progress.Chunks != 2 || progress.Nodes != 4 || progress.Edges != 1 || len(reported) != 2 || reported[1] != progress {
+		t.Fatalf("TestBulkLoader reported wrong progress '%+v' and '%+v'", progress, reported)
+	}
+	if len(conn.commits) != 2 || len(conn.commits[0]) != 3 || len(conn.commits[1]) != 2 {
+		t.Fatalf("TestBulkLoader committed wrong chunks '%+v'", conn.commits)
+	}
+	var edge types.TGEdge
+	for _, entity := range conn.commits[1] {
+		if entity.GetEntityKind() == types.EntityKindEdge {
+			edge = entity.(types.TGEdge)
+		}
+	}
+	if edge == nil {
+		t.Fatalf("TestBulkLoader expected the edge in the second chunk")
+	}
+	vertices := edge.GetVertices()
+	committedA := loader.committedKeys["a"]
+	if vertices[0].GetVirtualId() != committedA.id || vertices[0].GetIsNew() {
+		t.Errorf("TestBulkLoader expected the edge to refer to the committed node '%d' and not '%+v'", committedA.id, vertices[0])
+	}
+	if vertices[1].GetAttribute("id").GetValue() != int64(3) {
+		t.Errorf("TestBulkLoader expected the edge to the node of the same chunk and not '%+v'", vertices[1])
+	}
+}
+
+func TestBulkLoaderChunkBytes(t *testing.T) {
+	conn := &loaderConnection{upsertConnection: newUpsertConnection()}
+	loader := NewBulkLoader(conn)
+	loader.SetChunkBytes(1)
+	for _, key := range []string{"a", "b", "c"} {
+		if err := loader.AddNode(key, conn.newAccount(t, 1)); err != nil {
+			t.Fatalf("TestBulkLoaderChunkBytes unable to add node w/ error '%s'", err.Error())
+		}
+	}
+	progress, err := loader.Close()
+	if err != nil || progress.Chunks != 3 || len(conn.commits) != 3 {
+		t.Errorf("TestBulkLoaderChunkBytes expected a chunk per node and not '%+v' w/ error '%+v'", progress, err)
+	}
+	if progress.Bytes <= 0 {
+		t.Errorf("TestBulkLoaderChunkBytes expected the estimated bytes to be reported")
+	}
+}
+
+func TestBulkLoaderRollsBackFailedChunk(t *testing.T) {
+	conn := &loaderConnection{upsertConnection: newUpsertConnection(), failCommits: 1}
+	loader := NewBulkLoader(conn)
+	loader.SetChunkSize(1)
+	for i, key := range []string{"a", "b", "c"} {
+		// The commit of a chunk may fail before the next node is added, which then reports the error
+		if err := loader.AddNode(key, conn.newAccount(t, int64(i))); err != nil {
+			break
+		}
+	}
+	progress, err := loader.Close()
+	if err == nil {
+		t.Fatalf("TestBulkLoaderRollsBackFailedChunk expected the error of the failed commit")
+	}
+	if progress.Chunks != 1 || len(conn.commits) != 1 {
+		t.Errorf("TestBulkLoaderRollsBackFailedChunk expected only the first chunk to be committed and not '%+v'", progress)
+	}
+	if conn.rollbacks != 1 || len(conn.GetAddedList()) != 0 {
+		t.Errorf("TestBulkLoaderRollsBackFailedChunk expected the failed chunk to be rolled back and not '%d' rollbacks w/ '%d' pending nodes",
+			conn.rollbacks, len(conn.GetAddedList()))
+	}
+}
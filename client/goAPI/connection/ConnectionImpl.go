@@ -28,11 +28,13 @@ import (
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/channel"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/iostream"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/mapper"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/pdu"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/query"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/utils"
+	"strconv"
 	"strings"
 	"sync/atomic"
 )
@@ -62,6 +64,7 @@ type TGDBConnection struct {
 	changedList     map[int64]types.TGEntity
 	removedList     map[int64]types.TGEntity
 	attrByTypeList  map[int][]types.TGAttribute
+	queryCache      *PreparedQueryCache
 }
 
 func DefaultTGDBConnection() *TGDBConnection {
@@ -81,6 +84,7 @@ func DefaultTGDBConnection() *TGDBConnection {
 	// We cannot get meta data before we connect to the server
 	newSGDBConnection.graphObjFactory = model.NewGraphObjectFactory(newSGDBConnection)
 	newSGDBConnection.connProperties = utils.NewSortedProperties()
+	newSGDBConnection.queryCache = DefaultPreparedQueryCache()
 	return newSGDBConnection
 }
 
@@ -89,6 +93,7 @@ func NewTGDBConnection(conPool *ConnectionPoolImpl, channel types.TGChannel, pro
 	newSGDBConnection.connPoolImpl = conPool
 	newSGDBConnection.channel = channel
 	newSGDBConnection.connProperties = props.(*utils.SortedProperties)
+	newSGDBConnection.queryCache = NewPreparedQueryCache(getQueryCacheSize(props))
 	return newSGDBConnection
 }
 
@@ -104,6 +109,10 @@ func (obj *TGDBConnection) GetConnectionGraphObjectFactory() *model.GraphObjectF
 	return obj.graphObjFactory
 }
 
+func (obj *TGDBConnection) GetPreparedQueryCache() *PreparedQueryCache {
+	return obj.queryCache
+}
+
 func (obj *TGDBConnection) InitMetadata() types.TGError {
 	if obj.graphObjFactory == nil {
 		// TODO: Revisit later - Should we not throw an appropriate exception?
@@ -138,6 +147,114 @@ func (obj *TGDBConnection) SetConnectionProperties(connProps types.TGProperties)
 // Private functions for types.TGConnection
 /////////////////////////////////////////////////////////////////
 
+func getQueryCacheSize(props types.TGProperties) int {
+	cn := utils.GetConfigFromKey(utils.ConnectionQueryCacheSize)
+	size, err := strconv.Atoi(props.GetProperty(cn, cn.GetDefaultValue()))
+	if err != nil || size < 0 {
+		logger.Warning(fmt.Sprintf("WARNING: TGDBConnection:getQueryCacheSize - invalid '%s', using default '%s'", cn.GetName(), cn.GetDefaultValue()))
+		size, _ = strconv.Atoi(cn.GetDefaultValue())
+	}
+	return size
+}
+
+// prepareQuery returns a query handle for the expression, reusing the server side query cached for
+// it in the current session, and creating and caching a new one otherwise. The cache owns the server
+// side query - closing the handle releases it, see PreparedQueryCache.
+func prepareQuery(obj types.TGConnection, cache *PreparedQueryCache, expr string) (types.TGQuery, types.TGError) {
+	logger.Log(fmt.Sprintf("Entering TGDBConnection:prepareQuery for Query: '%+v'", expr))
+	if cache == nil || cache.GetCapacity() <= 0 {
+		return obj.CreateQuery(expr)
+	}
+	sessionId := obj.GetChannel().GetSessionId()
+	if queryHashId, ok := cache.Get(expr, sessionId); ok {
+		logger.Log(fmt.Sprintf("Returning TGDBConnection:prepareQuery w/ cached QueryHashId: '%+v'", queryHashId))
+		return newCachedQuery(obj, cache, query.NewPreparedQuery(obj, queryHashId, expr), sessionId), nil
+	}
+	qry, err := obj.CreateQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	queryHashId := qry.(*query.TGQueryImpl).GetQueryId()
+	for _, evictedId := range cache.Put(expr, queryHashId, sessionId) {
+		closeCachedQuery(obj, evictedId)
+	}
+	logger.Log(fmt.Sprintf("Returning TGDBConnection:prepareQuery w/ new QueryHashId: '%+v'", queryHashId))
+	return newCachedQuery(obj, cache, qry.(*query.TGQueryImpl), sessionId), nil
+}
+
+// newCachedQuery makes closing the handle release its reference on the cached query, and close the query on the
+// server only if it has been evicted from the cache in the meantime and no other handle uses it
+func newCachedQuery(obj types.TGConnection, cache *PreparedQueryCache, qry *query.TGQueryImpl, sessionId int64) *query.TGQueryImpl {
+	qry.SetCloseHandler(func(queryHashId int64) types.TGError {
+		if cache.Release(queryHashId, sessionId) {
+			_, err := obj.CloseQuery(queryHashId)
+			return err
+		}
+		return nil
+	})
+	return qry
+}
+
+func closeCachedQuery(obj types.TGConnection, queryHashId int64) {
+	logger.Debug(fmt.Sprintf("Inside TGDBConnection:prepareQuery about to close evicted QueryHashId: '%+v'", queryHashId))
+	_, err := obj.CloseQuery(queryHashId)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("WARNING: TGDBConnection:prepareQuery - unable to close evicted query '%d' w/ error: '%s'", queryHashId, err.Error()))
+	}
+}
+
+// resultBatchFetcher fetches the remaining batches of one open result. The entities of a batch can refer to
+// entities sent in earlier ones, so it keeps the reference map the first batch was read into, and every entity
+// added to it, until the server has sent the last batch or the result is cancelled. Memory used by a stream
+// therefore grows w/ the number of entities fetched, even though the result set only keeps the current batch.
+type resultBatchFetcher struct {
+	conn            *TGDBConnection
+	fetchedEntities map[int64]types.TGEntity
+}
+
+func newResultBatchFetcher(conn *TGDBConnection, fetchedEntities map[int64]types.TGEntity) *resultBatchFetcher {
+	return &resultBatchFetcher{conn: conn, fetchedEntities: fetchedEntities}
+}
+
+// FetchResultBatch fetches the next batch of the result from the server, and releases the reference map after the last one
+func (obj *resultBatchFetcher) FetchResultBatch(resultId int, batchSize int) ([]interface{}, bool, types.TGError) {
+	entities, hasMore, err := obj.conn.fetchResultBatch(resultId, batchSize, obj.fetchedEntities)
+	if err != nil || !hasMore {
+		obj.fetchedEntities = nil
+	}
+	return entities, hasMore, err
+}
+
+// CancelResult releases the remaining, unfetched part of the result on the server, and the reference map
+func (obj *resultBatchFetcher) CancelResult(resultId int) types.TGError {
+	obj.fetchedEntities = nil
+	return obj.conn.CancelResult(resultId)
+}
+
+// newStreamingResultSet wraps the first batch of a get entities result into a cursor that fetches the
+// remaining batches on demand. A batch smaller than the requested batch size is the last one.
+func newStreamingResultSet(obj types.TGConnection, fetcher query.TGResultFetcher, rSet types.TGResultSet, batchSize int, props types.TGProperties) types.TGResultSet {
+	firstBatch := rSet.(*query.ResultSet).GetResults()
+	hasMore := len(firstBatch) > 0 && len(firstBatch) >= batchSize
+	var options types.TGQueryOption
+	if qryOption, ok := props.(*query.TGQueryOptionImpl); ok {
+		options = qryOption
+	}
+	resultId := rSet.(*query.ResultSet).GetResultId()
+	if rSet.HasExceptions() && hasMore {
+		// The first batch could not be decoded completely - there is no point in streaming the rest
+		if err := fetcher.CancelResult(resultId); err != nil {
+			rSet.AddException(err)
+		}
+		hasMore = false
+	}
+	streamingSet := query.NewStreamingResultSet(obj, fetcher, resultId, firstBatch, hasMore, options)
+	for _, ex := range rSet.GetExceptions() {
+		streamingSet.AddException(ex)
+	}
+	return streamingSet
+}
+
 func fixUpAttrDescriptors(response *pdu.CommitTransactionResponse, attrDescSet []types.TGAttributeDescriptor) {
 	logger.Log(fmt.Sprint("Entering TGDBConnection:fixUpAttrDescriptors"))
 	attrDescCount := response.GetAttrDescCount()
@@ -260,33 +377,37 @@ func (obj *TGDBConnection) populateResultSetFromQueryResponse(resultId int, msgR
 
 	respStream := msgResponse.GetEntityStream()
 	fetchedEntities := make(map[int64]types.TGEntity, 0)
-	var rSet *query.ResultSet
+	rSet := query.NewResultSet(obj, resultId)
 
 	currResultCount := 0
 	resultCount := msgResponse.GetResultCount()
 	logger.Debug(fmt.Sprintf("Inside TGDBConnection::populateResultSetFromQueryResponse read resultCount: '%d' FetchedEntityCount: '%d'", resultCount, len(fetchedEntities)))
 	if resultCount > 0 {
 		respStream.SetReferenceMap(fetchedEntities)
-		rSet = query.NewResultSet(obj, resultId)
 	}
 
 	totalCount := msgResponse.GetTotalCount()
 	logger.Debug(fmt.Sprintf("Inside TGDBConnection::populateResultSetFromQueryResponse read totalCount: '%d'", totalCount))
+	// Errors are recorded on the result set. Once an entry cannot be decoded, the position in the response
+	// stream is undefined, hence stop reading.
+readLoop:
 	for i := 0; i < totalCount; i++ {
 		entityType, err := respStream.(*iostream.ProtocolDataInputStream).ReadByte()
 		if err != nil {
-			logger.Error(fmt.Sprint("ERROR: Returning TGDBConnection:populateResultSetFromQueryResponse - unable to read entityType in the response stream"))
+			logger.Error(fmt.Sprint("ERROR: TGDBConnection:populateResultSetFromQueryResponse - unable to read entityType in the response stream"))
 			errMsg := "TGDBConnection::populateResultSetFromQueryResponse unable to read entity type in the response stream"
-			return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+			rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+			break readLoop
 		}
 		kindId := types.TGEntityKind(entityType)
 		logger.Debug(fmt.Sprintf("Inside TGDBConnection::populateResultSetFromQueryResponse read #'%d'-entityType: '%+v', kindId: '%s'", i, entityType, kindId.String()))
 		if kindId != types.EntityKindInvalid {
 			entityId, err := respStream.(*iostream.ProtocolDataInputStream).ReadLong()
 			if err != nil {
-				logger.Error(fmt.Sprint("ERROR: Returning TGDBConnection:populateResultSetFromQueryResponse - unable to read entityId in the response stream"))
+				logger.Error(fmt.Sprint("ERROR: TGDBConnection:populateResultSetFromQueryResponse - unable to read entityId in the response stream"))
 				errMsg := "TGDBConnection::populateResultSetFromQueryResponse unable to read entity type in the response stream"
-				return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+				rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+				break readLoop
 			}
 			entity := fetchedEntities[entityId]
 			logger.Debug(fmt.Sprintf("Inside TGDBConnection::populateResultSetFromQueryResponse read entityId: '%d', kindId: '%s', entity: '%+v'", entityId, kindId.String(), entity))
@@ -296,11 +417,10 @@ func (obj *TGDBConnection) populateResultSetFromQueryResponse(resultId int, msgR
 				if entity == nil {
 					node, nErr := obj.graphObjFactory.CreateNode()
 					if nErr != nil {
-						logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:populateResultSetFromQueryResponse - unable to CreateNode() w/ error: '%s'", nErr.Error()))
-						// TODO: Revisit later - Should we continue OR break after throwing/logging an error?
-						//continue
+						logger.Error(fmt.Sprintf("ERROR: TGDBConnection:populateResultSetFromQueryResponse - unable to CreateNode() w/ error: '%s'", nErr.Error()))
 						errMsg := "TGDBConnection::populateResultSetFromQueryResponse unable to create a new node from the response stream"
-						return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, nErr.GetErrorDetails())
+						rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, nErr.GetErrorDetails()))
+						break readLoop
 					}
 					entity = node
 					fetchedEntities[entityId] = node
@@ -311,7 +431,8 @@ func (obj *TGDBConnection) populateResultSetFromQueryResponse(resultId int, msgR
 				if err != nil {
 					errMsg := fmt.Sprintf("TGDBConnection::populateResultSetFromQueryResponse unable to node.ReadExternal() from the response stream w/ error: '%s'", err.Error())
 					logger.Error(errMsg)
-					return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.GetErrorDetails())
+					rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.GetErrorDetails()))
+					break readLoop
 				}
 				if currResultCount < resultCount {
 					rSet.AddEntityToResultSet(node)
@@ -324,11 +445,10 @@ func (obj *TGDBConnection) populateResultSetFromQueryResponse(resultId int, msgR
 					//edge, eErr := obj.graphObjFactory.CreateEdgeWithDirection(nil, nil, types.DirectionTypeBiDirectional)
 					edge, eErr := obj.graphObjFactory.CreateEntity(types.EntityKindEdge)
 					if eErr != nil {
-						logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:populateResultSetFromQueryResponse - unable to CreateEdgeWithDirection() w/ error: '%s'", eErr.Error()))
-						// TODO: Revisit later - Should we continue OR break after throwing/logging an error?
-						//continue
+						logger.Error(fmt.Sprintf("ERROR: TGDBConnection:populateResultSetFromQueryResponse - unable to CreateEdgeWithDirection() w/ error: '%s'", eErr.Error()))
 						errMsg := "TGDBConnection::populateResultSetFromQueryResponse unable to create a new bi-directional edge from the response stream"
-						return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, eErr.GetErrorDetails())
+						rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, eErr.GetErrorDetails()))
+						break readLoop
 					}
 					entity = edge
 					fetchedEntities[entityId] = edge
@@ -339,13 +459,39 @@ func (obj *TGDBConnection) populateResultSetFromQueryResponse(resultId int, msgR
 				if err != nil {
 					errMsg := fmt.Sprintf("TGDBConnection::populateResultSetFromQueryResponse unable to edge.ReadExternal() from the response stream w/ error: '%s'", err.Error())
 					logger.Error(errMsg)
-					return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.GetErrorDetails())
+					rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.GetErrorDetails()))
+					break readLoop
 				}
 				if currResultCount < resultCount {
 					rSet.AddEntityToResultSet(edge)
 				}
 				//logger.Debug(fmt.Sprintf("======> ======> After edge.ReadExternal() FetchedEntityCount: '%d'", len(fetchedEntities)))
 				logger.Debug(fmt.Sprintf("======> ======> Edge w/ Vertices: '%+v'\n", edge.GetVertices()))
+			case types.EntityKindHyperEdge:
+				// Nodes read earlier may have left a placeholder edge for the hyperedge, which is replaced here
+				hyperEdge, ok := entity.(*model.HyperEdge)
+				if !ok {
+					newEntity, hErr := obj.graphObjFactory.CreateEntity(types.EntityKindHyperEdge)
+					if hErr != nil {
+						logger.Error(fmt.Sprintf("ERROR: TGDBConnection:populateResultSetFromQueryResponse - unable to CreateEntity() w/ error: '%s'", hErr.Error()))
+						errMsg := "TGDBConnection::populateResultSetFromQueryResponse unable to create a new hyperedge from the response stream"
+						rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, hErr.Error()))
+						break readLoop
+					}
+					hyperEdge = newEntity.(*model.HyperEdge)
+					fetchedEntities[entityId] = hyperEdge
+					logger.Debug(fmt.Sprintf("Inside TGDBConnection::populateResultSetFromQueryResponse created new hyperedge: '%+v'", hyperEdge))
+				}
+				err := hyperEdge.ReadExternal(respStream)
+				if err != nil {
+					errMsg := fmt.Sprintf("TGDBConnection::populateResultSetFromQueryResponse unable to hyperEdge.ReadExternal() from the response stream w/ error: '%s'", err.Error())
+					logger.Error(errMsg)
+					rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+					break readLoop
+				}
+				if currResultCount < resultCount {
+					rSet.AddEntityToResultSet(hyperEdge)
+				}
 			case types.EntityKindGraph:
 				// TODO: Revisit later - Should we break after throwing/logging an error
 				continue
@@ -366,13 +512,15 @@ func (obj *TGDBConnection) populateResultSetFromQueryResponse(resultId int, msgR
 			//}
 		} else {
 			logger.Warning(fmt.Sprintf("WARNING: TGDBConnection:populateResultSetFromQueryResponse - Received invalid entity kind %d", kindId))
+			errMsg := fmt.Sprintf("TGDBConnection::populateResultSetFromQueryResponse received invalid entity kind %d", kindId)
+			rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, ""))
 		} // Valid entity types
 	} // End of for loop
 	logger.Log(fmt.Sprintf("Returning TGDBConnection:populateResultSetFromQueryResponse w/ ResultSet: '%+v'", rSet))
 	return rSet, nil
 }
 
-func (obj *TGDBConnection) populateResultSetFromGetEntitiesResponse(msgResponse *pdu.GetEntityResponseMessage) (types.TGResultSet, types.TGError) {
+func (obj *TGDBConnection) populateResultSetFromGetEntitiesResponse(msgResponse *pdu.GetEntityResponseMessage, fetchedEntities map[int64]types.TGEntity) (types.TGResultSet, types.TGError) {
 	logger.Log(fmt.Sprintf("Entering TGDBConnection:populateResultSetFromGetEntitiesResponse w/ MsgResponse: '%+v'", msgResponse.String()))
 	if !msgResponse.GetHasResult() {
 		logger.Error(fmt.Sprint("ERROR: Returning TGDBConnection:populateResultSetFromGetEntitiesResponse as msgResponse does not have any results"))
@@ -381,7 +529,6 @@ func (obj *TGDBConnection) populateResultSetFromGetEntitiesResponse(msgResponse
 	}
 
 	respStream := msgResponse.GetEntityStream()
-	fetchedEntities := make(map[int64]types.TGEntity, 0)
 
 	totalCount, err := respStream.(*iostream.ProtocolDataInputStream).ReadInt()
 	if err != nil {
@@ -404,28 +551,34 @@ func (obj *TGDBConnection) populateResultSetFromGetEntitiesResponse(msgResponse
 		return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
 	}
 
+	// Errors are recorded on the result set. Once an entry cannot be decoded, the position in the response
+	// stream is undefined, hence stop reading.
+readLoop:
 	for i := 0; i < totalCount; i++ {
 		isResult, err := respStream.(*iostream.ProtocolDataInputStream).ReadBoolean()
 		if err != nil {
-			logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:populateResultSetFromGetEntitiesResponse - unable to read isResult in the response stream w/ error: '%s'", err.Error()))
+			logger.Error(fmt.Sprintf("ERROR: TGDBConnection:populateResultSetFromGetEntitiesResponse - unable to read isResult in the response stream w/ error: '%s'", err.Error()))
 			errMsg := "TGDBConnection::populateResultSetFromGetEntitiesResponse unable to read count of result entities in the response stream"
-			return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+			rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+			break readLoop
 		}
 		logger.Debug(fmt.Sprintf("Inside TGDBConnection::populateResultSetFromGetEntitiesResponse read isResult: '%+v'", isResult))
 		entityType, err := respStream.(*iostream.ProtocolDataInputStream).ReadByte()
 		if err != nil {
-			logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:populateResultSetFromGetEntitiesResponse - unable to read entityType in the response stream w/ error: '%s'", err.Error()))
+			logger.Error(fmt.Sprintf("ERROR: TGDBConnection:populateResultSetFromGetEntitiesResponse - unable to read entityType in the response stream w/ error: '%s'", err.Error()))
 			errMsg := "TGDBConnection::populateResultSetFromGetEntitiesResponse unable to read entity type in the response stream"
-			return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+			rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+			break readLoop
 		}
 		kindId := types.TGEntityKind(entityType)
 		logger.Debug(fmt.Sprintf("Inside TGDBConnection::populateResultSetFromGetEntitiesResponse extracted entityType: '%+v', kindId: '%d'", entityType, kindId))
 		if kindId != types.EntityKindInvalid {
 			entityId, err := respStream.(*iostream.ProtocolDataInputStream).ReadLong()
 			if err != nil {
-				logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:populateResultSetFromGetEntitiesResponse - unable to read entityId in the response stream w/ error: '%s'", err.Error()))
+				logger.Error(fmt.Sprintf("ERROR: TGDBConnection:populateResultSetFromGetEntitiesResponse - unable to read entityId in the response stream w/ error: '%s'", err.Error()))
 				errMsg := "TGDBConnection::populateResultSetFromGetEntitiesResponse unable to read entity type in the response stream"
-				return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+				rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+				break readLoop
 			}
 			entity := fetchedEntities[entityId]
 			logger.Debug(fmt.Sprintf("Inside TGDBConnection::populateResultSetFromGetEntitiesResponse extracted entityId: '%d', entity: '%+v'", entityId, entity))
@@ -435,11 +588,10 @@ func (obj *TGDBConnection) populateResultSetFromGetEntitiesResponse(msgResponse
 				if entity == nil {
 					node, nErr := obj.graphObjFactory.CreateNode()
 					if nErr != nil {
-						logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:populateResultSetFromGetEntitiesResponse - unable to CreateNode() w/ error: '%s'", nErr.Error()))
-						// TODO: Revisit later - Should we continue OR break after throwing/logging an error?
-						//continue
+						logger.Error(fmt.Sprintf("ERROR: TGDBConnection:populateResultSetFromGetEntitiesResponse - unable to CreateNode() w/ error: '%s'", nErr.Error()))
 						errMsg := "TGDBConnection::populateResultSetFromGetEntitiesResponse unable to create a new node from the response stream"
-						return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, nErr.GetErrorDetails())
+						rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, nErr.GetErrorDetails()))
+						break readLoop
 					}
 					entity = node
 					fetchedEntities[entityId] = node
@@ -450,7 +602,8 @@ func (obj *TGDBConnection) populateResultSetFromGetEntitiesResponse(msgResponse
 				if err != nil {
 					errMsg := fmt.Sprintf("TGDBConnection::populateResultSetFromGetEntitiesResponse unable to node.ReadExternal() from the response stream w/ error: '%s'", err.Error())
 					logger.Error(errMsg)
-					return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+					rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+					break readLoop
 				}
 				if isResult {
 					rSet.AddEntityToResultSet(node)
@@ -461,11 +614,10 @@ func (obj *TGDBConnection) populateResultSetFromGetEntitiesResponse(msgResponse
 				if entity == nil {
 					edge, eErr := obj.graphObjFactory.CreateEdgeWithDirection(nil, nil, types.DirectionTypeBiDirectional)
 					if eErr != nil {
-						logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:populateResultSetFromGetEntitiesResponse - unable to CreateEdgeWithDirection() w/ error: '%s'", eErr.Error()))
-						// TODO: Revisit later - Should we break after throwing/logging an error
-						//continue
+						logger.Error(fmt.Sprintf("ERROR: TGDBConnection:populateResultSetFromGetEntitiesResponse - unable to CreateEdgeWithDirection() w/ error: '%s'", eErr.Error()))
 						errMsg := "TGDBConnection::populateResultSetFromGetEntitiesResponse unable to create a new bi-directional edge from the response stream"
-						return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, eErr.Error())
+						rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, eErr.Error()))
+						break readLoop
 					}
 					entity = edge
 					fetchedEntities[entityId] = edge
@@ -476,18 +628,47 @@ func (obj *TGDBConnection) populateResultSetFromGetEntitiesResponse(msgResponse
 				if err != nil {
 					errMsg := fmt.Sprintf("TGDBConnection::populateResultSetFromGetEntitiesResponse unable to edge.ReadExternal() from the response stream w/ error: '%s'", err.Error())
 					logger.Error(errMsg)
-					return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+					rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+					break readLoop
 				}
 				if isResult {
 					rSet.AddEntityToResultSet(edge)
 					currResultCount++
 				}
+			case types.EntityKindHyperEdge:
+				// Nodes read earlier may have left a placeholder edge for the hyperedge, which is replaced here
+				hyperEdge, ok := entity.(*model.HyperEdge)
+				if !ok {
+					newEntity, hErr := obj.graphObjFactory.CreateEntity(types.EntityKindHyperEdge)
+					if hErr != nil {
+						logger.Error(fmt.Sprintf("ERROR: TGDBConnection:populateResultSetFromGetEntitiesResponse - unable to CreateEntity() w/ error: '%s'", hErr.Error()))
+						errMsg := "TGDBConnection::populateResultSetFromGetEntitiesResponse unable to create a new hyperedge from the response stream"
+						rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, hErr.Error()))
+						break readLoop
+					}
+					hyperEdge = newEntity.(*model.HyperEdge)
+					fetchedEntities[entityId] = hyperEdge
+					logger.Debug(fmt.Sprintf("Inside TGDBConnection::populateResultSetFromGetEntitiesResponse created new hyperedge: '%+v'", hyperEdge))
+				}
+				err := hyperEdge.ReadExternal(respStream)
+				if err != nil {
+					errMsg := fmt.Sprintf("TGDBConnection::populateResultSetFromGetEntitiesResponse unable to hyperEdge.ReadExternal() from the response stream w/ error: '%s'", err.Error())
+					logger.Error(errMsg)
+					rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error()))
+					break readLoop
+				}
+				if isResult {
+					rSet.AddEntityToResultSet(hyperEdge)
+					currResultCount++
+				}
 			case types.EntityKindGraph:
 				// TODO: Revisit later - Should we break after throwing/logging an error
 				continue
 			}
 		} else {
 			logger.Warning(fmt.Sprintf("WARNING: TGDBConnection:populateResultSetFromGetEntitiesResponse - Received invalid entity kind %d", kindId))
+			errMsg := fmt.Sprintf("TGDBConnection::populateResultSetFromGetEntitiesResponse received invalid entity kind %d", kindId)
+			rSet.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, ""))
 		} // Valid entity types
 	} // End of for loop
 	logger.Log(fmt.Sprintf("Returning TGDBConnection:populateResultSetFromGetEntitiesResponse w/ ResultSe: '%+v'", rSet))
@@ -587,6 +768,29 @@ func (obj *TGDBConnection) populateResultSetFromGetEntityResponse(msgResponse *p
 						logger.Error(errMsg)
 						return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
 					}
+				case types.EntityKindHyperEdge:
+					// Nodes read earlier may have left a placeholder edge for the hyperedge, which is replaced here
+					hyperEdge, ok := entity.(*model.HyperEdge)
+					if !ok {
+						newEntity, hErr := obj.graphObjFactory.CreateEntity(types.EntityKindHyperEdge)
+						if hErr != nil {
+							logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:populateResultSetFromGetEntityResponse - unable to CreateEntity() w/ error: '%s'", hErr.Error()))
+							errMsg := "TGDBConnection::populateResultSetFromGetEntityResponse unable to create a new hyperedge from the response stream"
+							return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, hErr.Error())
+						}
+						hyperEdge = newEntity.(*model.HyperEdge)
+						fetchedEntities[entityId] = hyperEdge
+						if entityFound == nil {
+							entityFound = hyperEdge
+						}
+						logger.Debug(fmt.Sprintf("Inside TGDBConnection::populateResultSetFromGetEntityResponse created new hyperedge: '%+v'", hyperEdge))
+					}
+					err := hyperEdge.ReadExternal(respStream)
+					if err != nil {
+						errMsg := fmt.Sprintf("TGDBConnection::populateResultSetFromGetEntityResponse unable to hyperEdge.ReadExternal() from the response stream w/ error: '%s'", err.Error())
+						logger.Error(errMsg)
+						return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+					}
 				case types.EntityKindGraph:
 					// TODO: Revisit later - Should we break after throwing/logging an error
 					continue
@@ -613,8 +817,8 @@ func (obj *TGDBConnection) Commit() (types.TGResultSet, types.TGError) {
 	logger.Debug(fmt.Sprint("Inside TGDBConnection::Commit - about to loop through addedList to include existing nodes to the changed list if it's part of a new edge"))
 	// Include existing nodes to the changed list if it's part of a new edge
 	for _, addEntity := range obj.GetAddedList() {
-		if addEntity.GetEntityKind() == types.EntityKindEdge {
-			nodes := addEntity.(*model.Edge).GetVertices()
+		if addEntity.GetEntityKind() == types.EntityKindEdge || addEntity.GetEntityKind() == types.EntityKindHyperEdge {
+			nodes := addEntity.(types.TGEdge).GetVertices()
 			if len(nodes) > 0 {
 				for _, vNode := range nodes {
 					node := vNode.(*model.Node)
@@ -630,8 +834,8 @@ func (obj *TGDBConnection) Commit() (types.TGResultSet, types.TGError) {
 	logger.Debug(fmt.Sprint("Inside TGDBConnection::Commit - about to loop through changedList to include existing nodes to the changed list even for edge update"))
 	// Need to include existing node to the changed list even for edge update
 	for _, modEntity := range obj.GetChangedList() {
-		if modEntity.GetEntityKind() == types.EntityKindEdge {
-			nodes := modEntity.(*model.Edge).GetVertices()
+		if modEntity.GetEntityKind() == types.EntityKindEdge || modEntity.GetEntityKind() == types.EntityKindHyperEdge {
+			nodes := modEntity.(types.TGEdge).GetVertices()
 			if len(nodes) > 0 {
 				for _, vNode := range nodes {
 					node := vNode.(*model.Node)
@@ -647,8 +851,8 @@ func (obj *TGDBConnection) Commit() (types.TGResultSet, types.TGError) {
 	logger.Debug(fmt.Sprint("Inside TGDBConnection::Commit - about to loop through removedList to include existing nodes to the changed list even for edge update"))
 	// Need to include existing node to the changed list even for edge update
 	for _, delEntity := range obj.GetRemovedList() {
-		if delEntity.GetEntityKind() == types.EntityKindEdge {
-			nodes := delEntity.(*model.Edge).GetVertices()
+		if delEntity.GetEntityKind() == types.EntityKindEdge || delEntity.GetEntityKind() == types.EntityKindHyperEdge {
+			nodes := delEntity.(types.TGEdge).GetVertices()
 			if len(nodes) > 0 {
 				for _, vNode := range nodes {
 					node := vNode.(*model.Node)
@@ -732,6 +936,8 @@ func (obj *TGDBConnection) Connect() types.TGError {
 		logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection::Connect - error in obj.GetChannel().Connect() as '%+v'", err.Error()))
 		return err
 	}
+	// Queries of an earlier session are gone on the server
+	obj.queryCache.Invalidate()
 	logger.Debug(fmt.Sprint("Inside TGDBConnection::Connect about to obj.GetChannel().Start()"))
 	err = obj.GetChannel().Start()
 	if err != nil {
@@ -745,6 +951,7 @@ func (obj *TGDBConnection) Connect() types.TGError {
 // CloseQuery closes a specific query and associated objects
 func (obj *TGDBConnection) CloseQuery(queryHashId int64) (types.TGQuery, types.TGError) {
 	logger.Log(fmt.Sprintf("Entering TGDBConnection:CloseQuery for QueryHashId: '%+v'", queryHashId))
+	obj.queryCache.Remove(queryHashId)
 	obj.connPoolImpl.AdminLock()
 	defer obj.connPoolImpl.AdminUnlock()
 
@@ -804,11 +1011,14 @@ func (obj *TGDBConnection) CreateQuery(expr string) (types.TGQuery, types.TGErro
 	response := msgResponse.(*pdu.QueryResponseMessage)
 	queryHashId := response.GetQueryHashId()
 
-	logger.Log(fmt.Sprint("Returning TGDBConnection:CreateQuery"))
-	if response.GetResult() == 0 && queryHashId > 0 {
-		return query.NewQuery(obj, queryHashId), nil
+	if response.GetResult() != 0 || queryHashId <= 0 {
+		errMsg := fmt.Sprintf("Unable to create query '%s' - server returned result '%d'", expr, response.GetResult())
+		logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:CreateQuery - %s", errMsg))
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
 	}
-	return nil, nil
+
+	logger.Log(fmt.Sprint("Returning TGDBConnection:CreateQuery"))
+	return query.NewPreparedQuery(obj, queryHashId, expr), nil
 }
 
 // DecryptBuffer decrypts the encrypted buffer by sending a DecryptBufferRequest to the server
@@ -882,6 +1092,7 @@ func (obj *TGDBConnection) Disconnect() types.TGError {
 	}
 	logger.Debug(fmt.Sprint("Inside TGDBConnection::Disconnect about to obj.GetChannel().Stop()"))
 	obj.GetChannel().Stop(false)
+	obj.queryCache.Invalidate()
 	logger.Log(fmt.Sprint("Returning TGDBConnection:Disconnect"))
 	return nil
 }
@@ -931,12 +1142,13 @@ func (obj *TGDBConnection) ExecuteGremlinQuery(expr string, collection []interfa
 	}
 
 	respStream := response.GetEntityStream()
-	logger.Debug(fmt.Sprintf("Returning TGDBConnection:ExecuteGremlinQuery w/ '%+v'", response))
-	err = query.FillCollection(respStream, obj.graphObjFactory, collection)
+	logger.Debug(fmt.Sprintf("Inside TGDBConnection:ExecuteGremlinQuery about to query.ReadGremlinResult() w/ '%+v'", response))
+	result, err := query.ReadGremlinResult(respStream, obj.graphObjFactory)
 	if err != nil {
-		logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:ExecuteGremlinQuery - unable to query.FillCollection w/ error: '%s'", err.Error()))
+		logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:ExecuteGremlinQuery - unable to query.ReadGremlinResult w/ error: '%s'", err.Error()))
 		return nil, err
 	}
+	collection = append(collection, result.ToCollection()...)
 	logger.Log(fmt.Sprintf("Returning TGDBConnection:ExecuteGremlinQuery w/ '%+v'", collection))
 	return collection, nil
 }
@@ -979,20 +1191,29 @@ func (obj *TGDBConnection) ExecuteGremlinStrQuery(strQuery string, options types
 		return nil, nil
 	}
 
-	// TODO: Revisit later once Gremlin Package and GremlinQueryResult are implemented
-	//This is just a dummy value where > 0 means it has results
-	//resultCount := response.GetResultCount()
-	resultSet := query.NewResultSet(obj, 0)
 	respStream := response.GetEntityStream()
-	err = query.FillCollection(respStream, obj.graphObjFactory, resultSet.GetResults())
+	result, err := query.ReadGremlinResult(respStream, obj.graphObjFactory)
 	if err != nil {
-		logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:ExecuteGremlinQuery - unable to query.FillCollection w/ error: '%s'", err.Error()))
+		logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:ExecuteGremlinStrQuery - unable to query.ReadGremlinResult w/ error: '%s'", err.Error()))
 		return nil, err
 	}
+	resultSet := query.NewGremlinResultSet(obj, result)
 	logger.Log(fmt.Sprintf("Returning TGDBConnection:ExecuteGremlinStrQuery w/ '%+v'", resultSet))
 	return resultSet, nil
 }
 
+// ExecuteGremlinResultQuery executes a Gremlin Grammer-Based string query with query options and returns the typed result
+func (obj *TGDBConnection) ExecuteGremlinResultQuery(strQuery string, options types.TGQueryOption) (*query.GremlinResult, types.TGError) {
+	rSet, err := obj.ExecuteGremlinStrQuery(strQuery, options)
+	if err != nil {
+		return nil, err
+	}
+	if rSet == nil {
+		return query.NewGremlinResult(query.ElementTypeList, make([]*query.GremlinResult, 0)), nil
+	}
+	return rSet.(*query.ResultSet).GetGremlinResult(), nil
+}
+
 // ExecuteQuery executes an immediate query with associated query options
 func (obj *TGDBConnection) ExecuteQuery(expr string, options types.TGQueryOption) (types.TGResultSet, types.TGError) {
 	logger.Log(fmt.Sprintf("Entering TGDBConnection:ExecuteQuery for Query: '%+v'", expr))
@@ -1003,7 +1224,7 @@ func (obj *TGDBConnection) ExecuteQuery(expr string, options types.TGQueryOption
 
 	idx := strings.Index(expr, "://")
 	if idx != -1 {
-		tokens := strings.Split(expr, "://")
+		tokens := strings.SplitN(expr, "://", 2)
 		switch tokens[0] {
 		case "tgql":
 			return obj.ExecuteTGDBQuery(tokens[1], options)
@@ -1120,6 +1341,16 @@ func (obj *TGDBConnection) ExecuteQueryWithFilter(expr, edgeFilter, traversalCon
 
 // ExecuteQueryWithId executes an immediate query for specified id & query options
 func (obj *TGDBConnection) ExecuteQueryWithId(queryHashId int64, options types.TGQueryOption) (types.TGResultSet, types.TGError) {
+	return obj.executeQueryWithId(queryHashId, nil, options)
+}
+
+// ExecuteQueryWithParameters executes the server side query w/ the values bound to its named parameters
+func (obj *TGDBConnection) ExecuteQueryWithParameters(qry *query.TGQueryImpl, options types.TGQueryOption) (types.TGResultSet, types.TGError) {
+	return obj.executeQueryWithId(qry.GetQueryId(), qry, options)
+}
+
+// executeQueryWithId sends EXECUTEID for the query, followed by the values bound to the parameters of qry, if any
+func (obj *TGDBConnection) executeQueryWithId(queryHashId int64, qry *query.TGQueryImpl, options types.TGQueryOption) (types.TGResultSet, types.TGError) {
 	logger.Log(fmt.Sprintf("Entering TGDBConnection:ExecuteQueryWithId for QueryHashId: '%+v'", queryHashId))
 	err := obj.InitMetadata()
 	if err != nil {
@@ -1138,19 +1369,106 @@ func (obj *TGDBConnection) ExecuteQueryWithId(queryHashId int64, options types.T
 	queryRequest := msgRequest.(*pdu.QueryRequestMessage)
 	queryRequest.SetCommand(EXECUTED)
 	queryRequest.SetQueryHashId(queryHashId)
+	if qry != nil {
+		queryRequest.SetQueryObject(qry)
+	}
 	logger.Debug(fmt.Sprint("Inside TGDBConnection::ExecuteQueryWithId about to obj.configureQueryRequest() for: pdu.VerbQueryRequest"))
 	configureQueryRequest(queryRequest, options)
 
 	logger.Debug(fmt.Sprint("Inside TGDBConnection::ExecuteQueryWithId about to obj.GetChannel().SendRequest() for: pdu.VerbQueryRequest"))
 	// Execute request on channel and get the response
-	_, channelErr := obj.GetChannel().SendRequest(queryRequest, channelResponse.(*channel.BlockingChannelResponse))
+	msgResponse, channelErr := obj.GetChannel().SendRequest(queryRequest, channelResponse.(*channel.BlockingChannelResponse))
 	if channelErr != nil {
 		logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:ExecuteQueryWithId - unable to channel.SendRequest() w/ error: '%s'", channelErr.Error()))
 		return nil, channelErr
 	}
+	logger.Debug(fmt.Sprintf("Inside TGDBConnection::ExecuteQueryWithId received response for: pdu.VerbQueryRequest as '%+v'", msgResponse))
+	response := msgResponse.(*pdu.QueryResponseMessage)
 
-	logger.Log(fmt.Sprint("Returning TGDBConnection:ExecuteQueryWithId"))
-	return nil, nil
+	if !response.GetHasResult() {
+		logger.Warning(fmt.Sprint("WARNING: Returning TGDBConnection::ExecuteQueryWithId - The query does not have any results in QueryResponseMessage"))
+		return nil, nil
+	}
+
+	logger.Log(fmt.Sprintf("Returning TGDBConnection:ExecuteQueryWithId w/ '%+v'", response))
+	return obj.populateResultSetFromQueryResponse(0, response)
+}
+
+// CancelResult releases the remaining, unfetched part of an open result on the server
+func (obj *TGDBConnection) CancelResult(resultId int) types.TGError {
+	logger.Log(fmt.Sprintf("Entering TGDBConnection:CancelResult for ResultId: '%+v'", resultId))
+	obj.connPoolImpl.AdminLock()
+	defer obj.connPoolImpl.AdminUnlock()
+
+	logger.Debug(fmt.Sprint("Inside TGDBConnection::CancelResult about to createChannelRequest() for: pdu.VerbGetEntityRequest"))
+	// Create a channel request
+	msgRequest, channelResponse, err := createChannelRequest(obj, pdu.VerbGetEntityRequest)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:CancelResult - unable to createChannelRequest(pdu.VerbGetEntityRequest w/ error: '%s'", err.Error()))
+		return err
+	}
+	getRequest := msgRequest.(*pdu.GetEntityRequestMessage)
+	getRequest.SetCommand(pdu.GetEntityCommandClose)
+	getRequest.SetResultId(resultId)
+
+	logger.Debug(fmt.Sprint("Inside TGDBConnection::CancelResult about to obj.GetChannel().SendRequest() for: pdu.VerbGetEntityRequest"))
+	// Execute request on channel and get the response
+	_, channelErr := obj.GetChannel().SendRequest(getRequest, channelResponse.(*channel.BlockingChannelResponse))
+	if channelErr != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:CancelResult - unable to channel.SendRequest() w/ error: '%s'", channelErr.Error()))
+		return channelErr
+	}
+	logger.Log(fmt.Sprint("Returning TGDBConnection:CancelResult"))
+	return nil
+}
+
+// fetchResultBatch fetches the next batch of an open result from the server. The entities are read into the
+// reference map of the result, as they may refer to entities sent in earlier batches.
+func (obj *TGDBConnection) fetchResultBatch(resultId int, batchSize int, fetchedEntities map[int64]types.TGEntity) ([]interface{}, bool, types.TGError) {
+	logger.Log(fmt.Sprintf("Entering TGDBConnection:fetchResultBatch for ResultId: '%+v'", resultId))
+	obj.connPoolImpl.AdminLock()
+	defer obj.connPoolImpl.AdminUnlock()
+
+	logger.Debug(fmt.Sprint("Inside TGDBConnection::fetchResultBatch about to createChannelRequest() for: pdu.VerbGetEntityRequest"))
+	// Create a channel request
+	msgRequest, channelResponse, err := createChannelRequest(obj, pdu.VerbGetEntityRequest)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:fetchResultBatch - unable to createChannelRequest(pdu.VerbGetEntityRequest w/ error: '%s'", err.Error()))
+		return nil, false, err
+	}
+	getRequest := msgRequest.(*pdu.GetEntityRequestMessage)
+	getRequest.SetCommand(pdu.GetEntityCommandContinue)
+	getRequest.SetResultId(resultId)
+
+	logger.Debug(fmt.Sprint("Inside TGDBConnection::fetchResultBatch about to obj.GetChannel().SendRequest() for: pdu.VerbGetEntityRequest"))
+	// Execute request on channel and get the response
+	msgResponse, channelErr := obj.GetChannel().SendRequest(getRequest, channelResponse.(*channel.BlockingChannelResponse))
+	if channelErr != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:fetchResultBatch - unable to channel.SendRequest() w/ error: '%s'", channelErr.Error()))
+		return nil, false, channelErr
+	}
+	response := msgResponse.(*pdu.GetEntityResponseMessage)
+	if !response.GetHasResult() {
+		logger.Log(fmt.Sprintf("Returning TGDBConnection:fetchResultBatch - result '%d' has no more entities", resultId))
+		return make([]interface{}, 0), false, nil
+	}
+
+	rSet, err := obj.populateResultSetFromGetEntitiesResponse(response, fetchedEntities)
+	if err != nil {
+		return nil, false, err
+	}
+	entities := rSet.(*query.ResultSet).GetResults()
+	if rSet.HasExceptions() {
+		// Hand over the entities decoded before the first error, the streaming result set cancels the rest
+		exceptions := rSet.GetExceptions()
+		for _, ex := range exceptions[1:] {
+			logger.Warning(fmt.Sprintf("WARNING: TGDBConnection:fetchResultBatch - additional error in result '%d': '%s'", resultId, ex.Error()))
+		}
+		logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:fetchResultBatch w/ '%d' entities and error: '%s'", len(entities), exceptions[0].Error()))
+		return entities, len(entities) >= batchSize, exceptions[0]
+	}
+	logger.Log(fmt.Sprintf("Returning TGDBConnection:fetchResultBatch w/ '%d' entities", len(entities)))
+	return entities, len(entities) >= batchSize, nil
 }
 
 // GetAddedList gets a list of added entities
@@ -1219,8 +1537,13 @@ func (obj *TGDBConnection) GetEntities(qryKey types.TGKey, props types.TGPropert
 		return nil, nil
 	}
 
+	fetchedEntities := make(map[int64]types.TGEntity, 0)
+	rSet, err := obj.populateResultSetFromGetEntitiesResponse(response, fetchedEntities)
+	if err != nil {
+		return nil, err
+	}
 	logger.Log(fmt.Sprintf("Returning TGDBConnection:GetEntities w/ '%+v'", response))
-	return obj.populateResultSetFromGetEntitiesResponse(response)
+	return newStreamingResultSet(obj, newResultBatchFetcher(obj, fetchedEntities), rSet, queryRequest.GetBatchSize(), props), nil
 }
 
 // GetEntity gets an Entity given an UniqueKey for the Object
@@ -1268,6 +1591,22 @@ func (obj *TGDBConnection) GetEntity(qryKey types.TGKey, options types.TGQueryOp
 	return obj.populateResultSetFromGetEntityResponse(response)
 }
 
+// GetEntitiesByKeys gets the entities of many unique keys, possibly of different types, in as few requests as possible
+func (obj *TGDBConnection) GetEntitiesByKeys(keys []types.TGKey, options types.TGQueryOption) (map[types.TGKey]types.TGKeyResult, types.TGError) {
+	logger.Log(fmt.Sprintf("Entering TGDBConnection:GetEntitiesByKeys for '%d' keys", len(keys)))
+	err := obj.InitMetadata()
+	if err != nil {
+		logger.Error(fmt.Sprint("ERROR: Returning TGDBConnection:GetEntitiesByKeys - unable to InitMetadata"))
+		return nil, err
+	}
+	if options == nil {
+		options = query.NewQueryOption()
+	}
+	results := getEntitiesByKeys(obj, keys, options)
+	logger.Log(fmt.Sprint("Returning TGDBConnection:GetEntitiesByKeys"))
+	return results, nil
+}
+
 // GetGraphMetadata gets the Graph Metadata
 func (obj *TGDBConnection) GetGraphMetadata(refresh bool) (types.TGGraphMetadata, types.TGError) {
 	logger.Log(fmt.Sprint("Entering TGDBConnection:GetGraphMetadata"))
@@ -1379,6 +1718,13 @@ func (obj *TGDBConnection) InsertEntity(entity types.TGEntity) types.TGError {
 	return nil
 }
 
+// Prepare returns a reusable query for the expression, sharing the server side query with earlier
+// calls for the same expression on this connection. Closing the returned query only releases it - the cached
+// server side query is closed once it has been evicted and no other returned query uses it.
+func (obj *TGDBConnection) Prepare(expr string) (types.TGQuery, types.TGError) {
+	return prepareQuery(obj, obj.queryCache, expr)
+}
+
 // Rollback rolls back the current transaction on this connection
 func (obj *TGDBConnection) Rollback() types.TGError {
 	logger.Log(fmt.Sprint("Entering TGDBConnection:Rollback"))
@@ -1395,6 +1741,47 @@ func (obj *TGDBConnection) Rollback() types.TGError {
 	return nil
 }
 
+// SaveObject creates or updates the node for a Go struct tagged w/ its node type, along w/ the edges of its
+// tagged relationship fields, and commits the changes. As the commit would include any other pending change,
+// the connection must not have any. The changes are rolled back if the commit fails.
+func (obj *TGDBConnection) SaveObject(object interface{}) (types.TGNode, types.TGError) {
+	logger.Log(fmt.Sprintf("Entering TGDBConnection:SaveObject to save object of type '%T'", object))
+	if len(obj.GetAddedList())+len(obj.GetChangedList())+len(obj.GetRemovedList()) > 0 {
+		logger.Error(fmt.Sprint("ERROR: Returning TGDBConnection:SaveObject - the connection has pending changes"))
+		errMsg := "TGDBConnection::SaveObject requires a connection w/o pending changes - commit or roll them back first"
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+	}
+	node, err := mapper.Persist(obj, object)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:SaveObject - unable to persist object w/ Error: '%+v'", err.Error()))
+		return nil, err
+	}
+	_, err = obj.Commit()
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:SaveObject - unable to commit w/ Error: '%+v'", err.Error()))
+		_ = obj.Rollback()
+		return nil, err
+	}
+	logger.Log(fmt.Sprint("Returning TGDBConnection:SaveObject"))
+	return node, nil
+}
+
+// UpsertNode merges the attributes into the node of the node type w/ the primary key values, creating it if needed
+func (obj *TGDBConnection) UpsertNode(nodeType types.TGNodeType, keyValues map[string]interface{}, attrs map[string]interface{}) (types.TGNode, types.TGError) {
+	logger.Log(fmt.Sprintf("Entering TGDBConnection:UpsertNode for key '%+v'", keyValues))
+	err := obj.InitMetadata()
+	if err != nil {
+		return nil, err
+	}
+	node, err := upsertNode(obj, nodeType, keyValues, attrs)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning TGDBConnection:UpsertNode - unable to upsert node w/ Error: '%+v'", err.Error()))
+		return nil, err
+	}
+	logger.Log(fmt.Sprint("Returning TGDBConnection:UpsertNode"))
+	return node, nil
+}
+
 // SetExceptionListener sets exception listener
 func (obj *TGDBConnection) SetExceptionListener(listener types.TGConnectionExceptionListener) {
 	obj.connPoolImpl.SetExceptionListener(listener) //delegate it to the Pool.
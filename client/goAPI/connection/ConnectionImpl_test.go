@@ -21,6 +21,11 @@
 
 package connection
 
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"testing"
+)
+
 const (
 	url = "tcp://scott@localhost:8222"
 	password = "scott"
@@ -131,3 +136,12 @@ const (
 //	}
 //	t.Log("Returning from TestConnectionImpl_GetGraphMetadata - successfully disconnected.")
 //}
+
+func TestResultBatchFetcherKeepsReferenceMap(t *testing.T) {
+	fetchedEntities := make(map[int64]types.TGEntity, 0)
+	fetcher := newResultBatchFetcher(DefaultTGDBConnection(), fetchedEntities)
+	fetchedEntities[1] = nil
+	if len(fetcher.fetchedEntities) != 1 {
+		t.Errorf("TestResultBatchFetcherKeepsReferenceMap expected the batches to share the map of the first batch")
+	}
+}
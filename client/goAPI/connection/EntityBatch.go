@@ -0,0 +1,199 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: EntityBatch.go
+ * SVN id: $id: $
+ *
+ */
+
+package connection
+
+import (
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/query"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// maxKeysPerQuery limits the number of keys looked up by one query, to keep the query expression reasonably small
+const maxKeysPerQuery = 100
+
+// keyLookup is a distinct key of a batch, along w/ all the keys of the batch equal to it
+type keyLookup struct {
+	key    *model.CompositeKey
+	values []pKeyValue
+	keys   []types.TGKey
+	entity types.TGEntity
+	err    types.TGError
+}
+
+// getEntitiesByKeys fetches the entities of the keys. The GetEntity request carries a single key, so the keys of
+// the same type and attribute names are looked up together by a query instead, and only a key w/o any other of its
+// kind - or w/ values that cannot be part of a query expression - is fetched by its own GetEntity request. Keys
+// equal to each other are fetched once.
+func getEntitiesByKeys(conn types.TGConnection, keys []types.TGKey, options types.TGQueryOption) map[types.TGKey]types.TGKeyResult {
+	results := make(map[types.TGKey]types.TGKeyResult, len(keys))
+	lookups := make(map[string]*keyLookup, len(keys))
+	groups := make(map[string][]*keyLookup, 0)
+	groupNames := make([]string, 0)
+	for _, key := range keys {
+		compositeKey, err := validateBatchKey(key)
+		if err != nil {
+			results[key] = types.TGKeyResult{Error: err}
+			continue
+		}
+		values, err := compositeKeyValues(compositeKey)
+		if err != nil {
+			results[key] = types.TGKeyResult{Error: err}
+			continue
+		}
+		signature := keySignature(compositeKey.GetKeyName(), values, true)
+		if lookup, ok := lookups[signature]; ok {
+			lookup.keys = append(lookup.keys, key)
+			continue
+		}
+		lookup := &keyLookup{key: compositeKey, values: values, keys: []types.TGKey{key}}
+		lookups[signature] = lookup
+		groupName := keySignature(compositeKey.GetKeyName(), values, false)
+		if _, ok := groups[groupName]; !ok {
+			groupNames = append(groupNames, groupName)
+		}
+		groups[groupName] = append(groups[groupName], lookup)
+	}
+
+	for _, groupName := range groupNames {
+		group := groups[groupName]
+		for start := 0; start < len(group); start += maxKeysPerQuery {
+			end := start + maxKeysPerQuery
+			if end > len(group) {
+				end = len(group)
+			}
+			if end-start > 1 && queryKeyLookups(conn, group[start:end], options) {
+				continue
+			}
+			for _, lookup := range group[start:end] {
+				lookup.entity, lookup.err = conn.GetEntity(lookup.key, options)
+			}
+		}
+		for _, lookup := range group {
+			for _, key := range lookup.keys {
+				results[key] = types.TGKeyResult{Entity: lookup.entity, Error: lookup.err}
+			}
+		}
+	}
+	logger.Log(fmt.Sprintf("Returning Connection:getEntitiesByKeys w/ '%d' distinct keys in '%d' groups", len(lookups), len(groupNames)))
+	return results
+}
+
+// validateBatchKey checks that the key is a composite key w/ a type name and attribute values
+func validateBatchKey(key types.TGKey) (*model.CompositeKey, types.TGError) {
+	compositeKey, ok := key.(*model.CompositeKey)
+	if !ok || compositeKey == nil {
+		errMsg := fmt.Sprintf("Key '%+v' of type '%T' is not a composite key", key, key)
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	if compositeKey.GetKeyName() == "" || len(compositeKey.GetAttributes()) == 0 {
+		errMsg := "Composite key needs a type name and at least one attribute value"
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	return compositeKey, nil
+}
+
+// compositeKeyValues returns the attribute values of the key in the order of the attribute names
+func compositeKeyValues(key *model.CompositeKey) ([]pKeyValue, types.TGError) {
+	values := make([]pKeyValue, 0, len(key.GetAttributes()))
+	for _, attr := range key.GetAttributes() {
+		pKey, err := newPKeyValue(attr.GetAttributeDescriptor(), attr.GetValue())
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, pKey)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].desc.GetName() < values[j].desc.GetName()
+	})
+	return values, nil
+}
+
+// keySignature identifies the key by its type name and attribute names, and also by its values if asked to
+func keySignature(typeName string, values []pKeyValue, withValues bool) string {
+	var b strings.Builder
+	b.WriteString(typeName)
+	for _, pKey := range values {
+		b.WriteString("|")
+		b.WriteString(pKey.desc.GetName())
+		if withValues {
+			b.WriteString(fmt.Sprintf("=%+v", pKey.value))
+		}
+	}
+	return b.String()
+}
+
+// queryKeyLookups looks up the entities of keys of the same type and attribute names by a single query. It returns
+// false, w/o sending anything to the server, if the keys cannot be expressed as a query.
+func queryKeyLookups(conn types.TGConnection, lookups []*keyLookup, options types.TGQueryOption) bool {
+	typeName := lookups[0].key.GetKeyName()
+	alternatives := make([]types.TGFilter, 0, len(lookups))
+	for _, lookup := range lookups {
+		comparisons := make([]types.TGFilter, 0, len(lookup.values))
+		for _, pKey := range lookup.values {
+			comparisons = append(comparisons, query.Eq(pKey.desc.GetName(), pKey.value))
+		}
+		alternatives = append(alternatives, query.And(comparisons...))
+	}
+	expr, err := query.And(query.IsNodeType(typeName), query.Or(alternatives...)).GetExpression()
+	if err != nil {
+		logger.Warning(fmt.Sprintf("WARNING: Connection:queryKeyLookups - keys of type '%s' are fetched one by one as '%s'", typeName, err.Error()))
+		return false
+	}
+
+	rSet, err := conn.ExecuteQuery("tgql://"+expr+";", options)
+	if err != nil {
+		for _, lookup := range lookups {
+			lookup.err = err
+		}
+		return true
+	}
+	if rSet == nil || reflect.ValueOf(rSet).IsNil() {
+		return true
+	}
+	defer rSet.Close()
+	for entity, eErr := range rSet.All() {
+		if eErr != nil {
+			errMsg := fmt.Sprintf("Unable to look up the entities of type '%s' by their keys", typeName)
+			for _, lookup := range lookups {
+				if lookup.err == nil {
+					lookup.entity, lookup.err = nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, eErr.Error())
+				}
+			}
+			return true
+		}
+		for _, lookup := range lookups {
+			if lookup.err != nil || !matchesKey(entity, typeName, lookup.values) {
+				continue
+			}
+			if lookup.entity != nil && lookup.entity != entity {
+				errMsg := fmt.Sprintf("Key '%s' matches more than one entity", keySignature(typeName, lookup.values, true))
+				lookup.entity, lookup.err = nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+				continue
+			}
+			lookup.entity = entity
+		}
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: EntityBatch_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package connection
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/query"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"testing"
+)
+
+// batchConnection answers every query w/ all the stored nodes, and GetEntity w/ the stored node of the key
+type batchConnection struct {
+	*upsertConnection
+	queries []string
+	gets    int
+}
+
+func (obj *batchConnection) ExecuteQuery(expr string, options types.TGQueryOption) (types.TGResultSet, types.TGError) {
+	obj.queries = append(obj.queries, expr)
+	rSet := query.NewResultSet(nil, 0)
+	for _, entity := range obj.stored {
+		rSet.AddEntityToResultSet(entity)
+	}
+	obj.rSet = rSet
+	return rSet, nil
+}
+
+func (obj *batchConnection) GetEntity(key types.TGKey, options types.TGQueryOption) (types.TGEntity, types.TGError) {
+	obj.gets++
+	values, _ := compositeKeyValues(key.(*model.CompositeKey))
+	for _, entity := range obj.stored {
+		if matchesKey(entity, "Account", values) {
+			return entity, nil
+		}
+	}
+	return nil, nil
+}
+
+func (obj *batchConnection) storeAccount(id int64) types.TGNode {
+	nodeType, _ := obj.gof.GetGraphMetaData().GetNodeType("Account")
+	node, _ := obj.gof.CreateNodeInGraph(nodeType)
+	node.SetOrCreateAttribute("id", id)
+	obj.stored = append(obj.stored, node)
+	return node
+}
+
+func (obj *batchConnection) accountKey(id interface{}) types.TGKey {
+	key, _ := obj.gof.CreateCompositeKey("Account")
+	key.SetOrCreateAttribute("id", id)
+	return key
+}
+
+func TestGetEntitiesByKeys(t *testing.T) {
+	conn := &batchConnection{upsertConnection: newUpsertConnection()}
+	first := conn.storeAccount(1)
+	second := conn.storeAccount(2)
+	keys := []types.TGKey{conn.accountKey(1), conn.accountKey(2), conn.accountKey(3), conn.accountKey(int64(1)), &model.CompositeKey{}}
+
+	results := getEntitiesByKeys(conn, keys, query.NewQueryOption())
+	if len(results) != len(keys) {
+		t.Fatalf("TestGetEntitiesByKeys expected a result for each of the '%d' keys and not '%+v'", len(keys), results)
+	}
+	if len(conn.queries) != 1 || conn.gets != 0 {
+		t.Fatalf("TestGetEntitiesByKeys expected a single query and not '%+v' w/ '%d' gets", conn.queries, conn.gets)
+	}
+	expected := "tgql://@nodetype = 'Account' and (id = 1 or id = 2 or id = 3);"
+	if conn.queries[0] != expected {
+		t.Errorf("TestGetEntitiesByKeys expected query '%s' and not '%s'", expected, conn.queries[0])
+	}
+	if results[keys[0]].Entity != first || results[keys[1]].Entity != second || results[keys[3]].Entity != first {
+		t.Errorf("TestGetEntitiesByKeys returned wrong entities '%+v'", results)
+	}
+	if results[keys[2]].IsFound() || results[keys[2]].Error != nil {
+		t.Errorf("TestGetEntitiesByKeys expected key 3 to be not found and not '%+v'", results[keys[2]])
+	}
+	if results[keys[4]].Error == nil {
+		t.Errorf("TestGetEntitiesByKeys expected an error for the key w/o a type name")
+	}
+}
+
+func TestGetEntitiesByKeysFetchesSingleKey(t *testing.T) {
+	conn := &batchConnection{upsertConnection: newUpsertConnection()}
+	stored := conn.storeAccount(5)
+	key := conn.accountKey(5)
+
+	results := getEntitiesByKeys(conn, []types.TGKey{key, conn.accountKey(5)}, query.NewQueryOption())
+	if len(conn.queries) != 0 || conn.gets != 1 {
+		t.Fatalf("TestGetEntitiesByKeysFetchesSingleKey expected a single GetEntity and not '%+v' w/ '%d' gets", conn.queries, conn.gets)
+	}
+	if results[key].Entity != stored {
+		t.Errorf("TestGetEntitiesByKeysFetchesSingleKey returned wrong entity '%+v'", results[key])
+	}
+
+	conn.storeAccount(6)
+	conn.storeAccount(6)
+	first, second := conn.accountKey(5), conn.accountKey(6)
+	results = getEntitiesByKeys(conn, []types.TGKey{first, second}, query.NewQueryOption())
+	if results[first].Entity != stored || results[second].Error == nil {
+		t.Errorf("TestGetEntitiesByKeysFetchesSingleKey expected key 6 to match more than one entity and not '%+v'", results)
+	}
+}
+
+func TestGetEntitiesByKeysQuotesValues(t *testing.T) {
+	conn := &batchConnection{upsertConnection: newUpsertConnection()}
+	gmd := conn.gof.GetGraphMetaData()
+	nodeType, _ := gmd.GetNodeType("Account")
+	name, _ := gmd.GetAttributeDescriptor("name")
+	nodeType.(*model.NodeType).SetPKeyAttributeDescriptors([]*model.AttributeDescriptor{name.(*model.AttributeDescriptor)})
+	names := []string{`C:\accounts\`, "tgdb://accounts"}
+	keys := make([]types.TGKey, 0, len(names))
+	for _, value := range names {
+		node, _ := conn.gof.CreateNodeInGraph(nodeType)
+		node.SetOrCreateAttribute("name", value)
+		conn.stored = append(conn.stored, node)
+		key, _ := conn.gof.CreateCompositeKey("Account")
+		key.SetOrCreateAttribute("name", value)
+		keys = append(keys, key)
+	}
+
+	results := getEntitiesByKeys(conn, keys, query.NewQueryOption())
+	expected := `tgql://@nodetype = 'Account' and (name = 'C:\\accounts\\' or name = 'tgdb://accounts');`
+	if len(conn.queries) != 1 || conn.queries[0] != expected {
+		t.Fatalf("TestGetEntitiesByKeysQuotesValues expected query '%s' and not '%+v'", expected, conn.queries)
+	}
+	for i, key := range keys {
+		if results[key].Entity != conn.stored[i] {
+			t.Errorf("TestGetEntitiesByKeysQuotesValues returned wrong entity for key '%s': '%+v'", names[i], results[key])
+		}
+	}
+	if conn.rSet == nil || conn.rSet.GetIsOpen() {
+		t.Errorf("TestGetEntitiesByKeysQuotesValues expected the result set of the query to be closed")
+	}
+}
@@ -0,0 +1,243 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: NodeUpsert.go
+ * SVN id: $id: $
+ *
+ */
+
+package connection
+
+import (
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"reflect"
+	"sort"
+)
+
+// upsertNode resolves the node of the node type w/ the primary key values - first among the nodes already part
+// of the change set of the connection, then on the server through GetEntities - and merges the attributes into
+// it, marking it for update. If there is no such node, a new one is created w/ the key values and the attributes,
+// and marked for insert. Nothing is sent to the server for the changes until the connection is committed.
+func upsertNode(conn types.TGConnection, nodeType types.TGNodeType, keyValues map[string]interface{}, attrs map[string]interface{}) (types.TGNode, types.TGError) {
+	if nodeType == nil || reflect.ValueOf(nodeType).IsNil() {
+		errMsg := "Node type is required to upsert a node"
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	pKeys, err := primaryKeyValues(nodeType, keyValues)
+	if err != nil {
+		return nil, err
+	}
+	for name := range attrs {
+		if _, ok := keyValues[name]; ok {
+			errMsg := fmt.Sprintf("Primary key attribute '%s' cannot be upserted as a regular attribute", name)
+			return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+		}
+	}
+
+	node, err := findPendingNode(conn, nodeType, pKeys)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		node, err = findStoredNode(conn, nodeType, pKeys)
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			err = conn.UpdateEntity(node)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if node == nil {
+		gof, err := conn.GetGraphObjectFactory()
+		if err != nil {
+			return nil, err
+		}
+		node, err = gof.CreateNodeInGraph(nodeType)
+		if err != nil {
+			return nil, err
+		}
+		for _, pKey := range pKeys {
+			err = node.SetOrCreateAttribute(pKey.desc.GetName(), pKey.value)
+			if err != nil {
+				return nil, err
+			}
+		}
+		err = conn.InsertEntity(node)
+		if err != nil {
+			return nil, err
+		}
+		logger.Debug(fmt.Sprintf("Inside Connection:upsertNode created new node of type '%s'", nodeType.GetName()))
+	}
+	err = mergeAttributes(node, attrs)
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// pKeyValue is the value of a primary key attribute, as converted by the attribute type
+type pKeyValue struct {
+	desc  types.TGAttributeDescriptor
+	value interface{}
+}
+
+// newPKeyValue converts the value as the attribute would, so that it compares equal to the value of an existing node
+func newPKeyValue(desc types.TGAttributeDescriptor, value interface{}) (pKeyValue, types.TGError) {
+	attrDesc, ok := desc.(*model.AttributeDescriptor)
+	if !ok {
+		errMsg := fmt.Sprintf("Primary key attribute '%s' has an unsupported descriptor type '%T'", desc.GetName(), desc)
+		return pKeyValue{}, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	attr, err := model.CreateAttributeWithDesc(nil, attrDesc, nil)
+	if err != nil {
+		return pKeyValue{}, err
+	}
+	err = attr.SetValue(value)
+	if err != nil {
+		return pKeyValue{}, err
+	}
+	return pKeyValue{desc: desc, value: attr.GetValue()}, nil
+}
+
+// primaryKeyValues verifies that there is a value for each primary key attribute of the node type - and only for
+// those - and returns them in the order of the attribute names
+func primaryKeyValues(nodeType types.TGNodeType, keyValues map[string]interface{}) ([]pKeyValue, types.TGError) {
+	pKeyDescs := nodeType.GetPKeyAttributeDescriptors()
+	if len(pKeyDescs) == 0 {
+		errMsg := fmt.Sprintf("Node type '%s' does not have a primary key", nodeType.GetName())
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	if len(keyValues) != len(pKeyDescs) {
+		errMsg := fmt.Sprintf("Node type '%s' needs '%d' primary key values and not '%d'", nodeType.GetName(), len(pKeyDescs), len(keyValues))
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	pKeys := make([]pKeyValue, 0, len(pKeyDescs))
+	for _, desc := range pKeyDescs {
+		value, ok := keyValues[desc.GetName()]
+		if !ok || value == nil {
+			errMsg := fmt.Sprintf("Value of primary key attribute '%s' of node type '%s' is required", desc.GetName(), nodeType.GetName())
+			return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+		}
+		pKey, err := newPKeyValue(desc, value)
+		if err != nil {
+			return nil, err
+		}
+		pKeys = append(pKeys, pKey)
+	}
+	sort.Slice(pKeys, func(i, j int) bool {
+		return pKeys[i].desc.GetName() < pKeys[j].desc.GetName()
+	})
+	return pKeys, nil
+}
+
+// matchesKey checks whether the node is of the node type, and has the primary key values
+func matchesKey(entity types.TGEntity, typeName string, pKeys []pKeyValue) bool {
+	node, ok := entity.(types.TGNode)
+	if !ok || entity.GetEntityKind() != types.EntityKindNode || entity.GetIsDeleted() {
+		return false
+	}
+	eType := node.GetEntityType()
+	if eType == nil || reflect.ValueOf(eType).IsNil() || eType.GetName() != typeName {
+		return false
+	}
+	for _, pKey := range pKeys {
+		attr := node.GetAttribute(pKey.desc.GetName())
+		if attr == nil || !reflect.DeepEqual(attr.GetValue(), pKey.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// findPendingNode looks for the node among those already inserted or updated on the connection, so that upserting
+// the same key twice before a commit does not create two nodes
+func findPendingNode(conn types.TGConnection, nodeType types.TGNodeType, pKeys []pKeyValue) (types.TGNode, types.TGError) {
+	for _, entities := range []map[int64]types.TGEntity{conn.GetAddedList(), conn.GetChangedList()} {
+		for _, entity := range entities {
+			if matchesKey(entity, nodeType.GetName(), pKeys) {
+				return entity.(types.TGNode), nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// findStoredNode looks up the node on the server, streaming the entities of the primary key through GetEntities
+func findStoredNode(conn types.TGConnection, nodeType types.TGNodeType, pKeys []pKeyValue) (types.TGNode, types.TGError) {
+	gof, err := conn.GetGraphObjectFactory()
+	if err != nil {
+		return nil, err
+	}
+	key, err := gof.CreateCompositeKey(nodeType.GetName())
+	if err != nil {
+		return nil, err
+	}
+	for _, pKey := range pKeys {
+		err = key.SetOrCreateAttribute(pKey.desc.GetName(), pKey.value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	rSet, err := conn.GetEntities(key, nil)
+	if err != nil {
+		return nil, err
+	}
+	if rSet == nil {
+		return nil, nil
+	}
+	// Returning early must release the rest of the stream on the server
+	defer rSet.Close()
+	var found types.TGNode
+	for entity, eErr := range rSet.All() {
+		if eErr != nil {
+			errMsg := fmt.Sprintf("Unable to look up the node of type '%s' by its primary key", nodeType.GetName())
+			return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, eErr.Error())
+		}
+		if !matchesKey(entity, nodeType.GetName(), pKeys) {
+			continue
+		}
+		if found != nil {
+			errMsg := fmt.Sprintf("Primary key of node type '%s' matches more than one node", nodeType.GetName())
+			return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+		}
+		found = entity.(types.TGNode)
+	}
+	return found, nil
+}
+
+// mergeAttributes sets the attributes on the node, clearing those w/ a nil value
+func mergeAttributes(node types.TGNode, attrs map[string]interface{}) types.TGError {
+	for name, value := range attrs {
+		if value == nil {
+			if attr := node.GetAttribute(name); attr != nil {
+				err := attr.SetValue(nil)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		err := node.SetOrCreateAttribute(name, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: NodeUpsert_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package connection
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/query"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"testing"
+)
+
+// upsertConnection keeps the change set in memory, and returns the stored nodes for every key lookup
+type upsertConnection struct {
+	types.TGConnection
+	gof     *model.GraphObjectFactory
+	stored  []types.TGEntity
+	lookups int
+	rSet    *query.ResultSet
+	added   map[int64]types.TGEntity
+	changed map[int64]types.TGEntity
+}
+
+func newUpsertConnection() *upsertConnection {
+	conn := &upsertConnection{added: make(map[int64]types.TGEntity), changed: make(map[int64]types.TGEntity)}
+	conn.gof = model.NewGraphObjectFactory(conn)
+	gmd := conn.gof.GetGraphMetaData()
+	id := model.NewAttributeDescriptorWithType("id", types.AttributeTypeLong)
+	name := model.NewAttributeDescriptorWithType("name", types.AttributeTypeString)
+	gmd.SetAttributeDescriptors(map[string]types.TGAttributeDescriptor{"id": id, "name": name})
+	nodeType := model.NewNodeType("Account", nil)
+	nodeType.SetAttributeMap(map[string]*model.AttributeDescriptor{"id": id, "name": name})
+	nodeType.SetPKeyAttributeDescriptors([]*model.AttributeDescriptor{id})
+	gmd.SetNodeTypes(map[string]types.TGNodeType{"Account": nodeType})
+	return conn
+}
+
+func (obj *upsertConnection) GetGraphObjectFactory() (types.TGGraphObjectFactory, types.TGError) {
+	return obj.gof, nil
+}
+
+func (obj *upsertConnection) GetEntities(key types.TGKey, props types.TGProperties) (types.TGResultSet, types.TGError) {
+	obj.lookups++
+	rSet := query.NewResultSet(nil, 0)
+	for _, entity := range obj.stored {
+		rSet.AddEntityToResultSet(entity)
+	}
+	obj.rSet = rSet
+	return rSet, nil
+}
+
+func (obj *upsertConnection) GetAddedList() map[int64]types.TGEntity {
+	return obj.added
+}
+
+func (obj *upsertConnection) GetChangedList() map[int64]types.TGEntity {
+	return obj.changed
+}
+
+func (obj *upsertConnection) InsertEntity(entity types.TGEntity) types.TGError {
+	obj.added[entity.GetVirtualId()] = entity
+	return nil
+}
+
+func (obj *upsertConnection) UpdateEntity(entity types.TGEntity) types.TGError {
+	obj.changed[entity.GetVirtualId()] = entity
+	return nil
+}
+
+func TestUpsertNode(t *testing.T) {
+	conn := newUpsertConnection()
+	nodeType, _ := conn.gof.GetGraphMetaData().GetNodeType("Account")
+	node, err := upsertNode(conn, nodeType, map[string]interface{}{"id": 7}, map[string]interface{}{"name": "first"})
+	if err != nil {
+		t.Fatalf("TestUpsertNode unable to insert w/ error '%s'", err.Error())
+	}
+	if len(conn.added) != 1 || node.GetAttribute("id").GetValue() != int64(7) {
+		t.Fatalf("TestUpsertNode expected a new node w/ id 7 and not '%+v'", node)
+	}
+	again, err := upsertNode(conn, nodeType, map[string]interface{}{"id": int64(7)}, map[string]interface{}{"name": "second"})
+	if err != nil || again != node || len(conn.added) != 1 || conn.lookups != 1 {
+		t.Fatalf("TestUpsertNode expected the pending node to be merged w/o a lookup and not '%+v' w/ error '%+v'", again, err)
+	}
+	if node.GetAttribute("name").GetValue() != "second" {
+		t.Errorf("TestUpsertNode expected the merged name and not '%+v'", node.GetAttribute("name").GetValue())
+	}
+}
+
+func TestUpsertNodeUpdatesStoredNode(t *testing.T) {
+	conn := newUpsertConnection()
+	nodeType, _ := conn.gof.GetGraphMetaData().GetNodeType("Account")
+	stored, _ := conn.gof.CreateNodeInGraph(nodeType)
+	stored.SetOrCreateAttribute("id", int64(9))
+	stored.SetOrCreateAttribute("name", "stored")
+	conn.stored = []types.TGEntity{stored}
+
+	node, err := upsertNode(conn, nodeType, map[string]interface{}{"id": 9}, map[string]interface{}{"name": nil})
+	if err != nil || node != stored || len(conn.changed) != 1 || len(conn.added) != 0 {
+		t.Fatalf("TestUpsertNodeUpdatesStoredNode expected the stored node to be updated and not '%+v' w/ error '%+v'", node, err)
+	}
+	if !node.GetAttribute("name").IsNull() {
+		t.Errorf("TestUpsertNodeUpdatesStoredNode expected the name to be cleared")
+	}
+
+	invalid := []map[string]interface{}{{}, {"name": "x"}, {"id": nil}, {"id": "not a number"}}
+	for _, keyValues := range invalid {
+		if _, err := upsertNode(conn, nodeType, keyValues, nil); err == nil {
+			t.Errorf("TestUpsertNodeUpdatesStoredNode expected an error for key '%+v'", keyValues)
+		}
+	}
+	if _, err := upsertNode(conn, nodeType, map[string]interface{}{"id": 9}, map[string]interface{}{"id": 10}); err == nil {
+		t.Errorf("TestUpsertNodeUpdatesStoredNode expected an error for upserting the primary key attribute")
+	}
+}
+
+func TestUpsertNodeDuplicateStoredNodes(t *testing.T) {
+	conn := newUpsertConnection()
+	nodeType, _ := conn.gof.GetGraphMetaData().GetNodeType("Account")
+	for i := 0; i < 2; i++ {
+		stored, _ := conn.gof.CreateNodeInGraph(nodeType)
+		stored.SetOrCreateAttribute("id", int64(9))
+		conn.stored = append(conn.stored, stored)
+	}
+	if _, err := upsertNode(conn, nodeType, map[string]interface{}{"id": 9}, nil); err == nil {
+		t.Errorf("TestUpsertNodeDuplicateStoredNodes expected an error for a primary key matching two nodes")
+	}
+	if conn.rSet == nil || conn.rSet.GetIsOpen() {
+		t.Errorf("TestUpsertNodeDuplicateStoredNodes expected the lookup result set to be closed")
+	}
+	if _, err := newPKeyValue(&otherAttributeDescriptor{}, 9); err == nil {
+		t.Errorf("TestUpsertNodeDuplicateStoredNodes expected an error for a descriptor of another type")
+	}
+}
+
+// otherAttributeDescriptor is an attribute descriptor that is not created by the model package
+type otherAttributeDescriptor struct {
+	types.TGAttributeDescriptor
+}
+
+func (obj *otherAttributeDescriptor) GetName() string {
+	return "id"
+}
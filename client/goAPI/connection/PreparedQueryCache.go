@@ -0,0 +1,234 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: PreparedQueryCache.go
+ * SVN id: $id: $
+ *
+ */
+
+package connection
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"sync"
+)
+
+// PreparedQueryCache is a LRU cache of server side query hash ids keyed by the query expression.
+// The hash ids are only valid for the server session they were created in, so the cache remembers
+// the session id and drops all its entries when it is used with a different session.
+//
+// The cache owns the server side queries, and counts the query handles using each of them: Get and Put
+// take a reference for the handle they are called for, and Release gives it back. A query evicted while
+// handles still use it stays open until the last of them is released.
+type PreparedQueryCache struct {
+	capacity  int
+	sessionId int64
+	lruList   *list.List
+	byExpr    map[string]*list.Element
+	byHashId  map[int64]*list.Element
+	evicted   map[int64]*preparedQueryEntry
+	mutex     sync.Mutex
+}
+
+type preparedQueryEntry struct {
+	expr        string
+	queryHashId int64
+	refCount    int
+}
+
+func DefaultPreparedQueryCache() *PreparedQueryCache {
+	newCache := PreparedQueryCache{
+		capacity: 0,
+		lruList:  list.New(),
+		byExpr:   make(map[string]*list.Element, 0),
+		byHashId: make(map[int64]*list.Element, 0),
+		evicted:  make(map[int64]*preparedQueryEntry, 0),
+	}
+	return &newCache
+}
+
+func NewPreparedQueryCache(capacity int) *PreparedQueryCache {
+	newCache := DefaultPreparedQueryCache()
+	if capacity > 0 {
+		newCache.capacity = capacity
+	}
+	return newCache
+}
+
+/////////////////////////////////////////////////////////////////
+// Helper functions for PreparedQueryCache
+/////////////////////////////////////////////////////////////////
+
+// GetCapacity returns the maximum number of cached queries - 0 means that caching is disabled
+func (obj *PreparedQueryCache) GetCapacity() int {
+	return obj.capacity
+}
+
+// Len returns the number of cached queries
+func (obj *PreparedQueryCache) Len() int {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	return obj.lruList.Len()
+}
+
+// Get returns the query hash id cached for the expression, marks it as the most recently used, and takes a
+// reference on it for the caller's query handle
+func (obj *PreparedQueryCache) Get(expr string, sessionId int64) (int64, bool) {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	obj.checkSession(sessionId)
+	elem, ok := obj.byExpr[expr]
+	if !ok {
+		return -1, false
+	}
+	obj.lruList.MoveToFront(elem)
+	entry := elem.Value.(*preparedQueryEntry)
+	entry.refCount++
+	return entry.queryHashId, true
+}
+
+// Put caches the query hash id for the expression w/ a reference for the caller's query handle, and returns
+// the hash ids of the queries evicted to make room for it that no handle uses. The caller is expected to close
+// those on the server - evicted queries still in use are returned by Release once they are not anymore.
+func (obj *PreparedQueryCache) Put(expr string, queryHashId int64, sessionId int64) []int64 {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	evicted := make([]int64, 0)
+	if obj.capacity <= 0 {
+		return evicted
+	}
+	obj.checkSession(sessionId)
+	if elem, ok := obj.byExpr[expr]; ok {
+		entry := elem.Value.(*preparedQueryEntry)
+		if entry.queryHashId == queryHashId {
+			obj.lruList.MoveToFront(elem)
+			entry.refCount++
+			return evicted
+		}
+		obj.evictElement(elem, &evicted)
+	}
+	elem := obj.lruList.PushFront(&preparedQueryEntry{expr: expr, queryHashId: queryHashId, refCount: 1})
+	obj.byExpr[expr] = elem
+	obj.byHashId[queryHashId] = elem
+	for obj.lruList.Len() > obj.capacity {
+		obj.evictElement(obj.lruList.Back(), &evicted)
+	}
+	return evicted
+}
+
+// Release gives back the reference taken by Get or Put for a query handle, and returns true if the query has
+// been evicted and is not used anymore, i.e. if the caller is now expected to close it on the server
+func (obj *PreparedQueryCache) Release(queryHashId int64, sessionId int64) bool {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	if obj.sessionId != sessionId {
+		// The query went away w/ its session
+		return false
+	}
+	if elem, ok := obj.byHashId[queryHashId]; ok {
+		entry := elem.Value.(*preparedQueryEntry)
+		if entry.refCount > 0 {
+			entry.refCount--
+		}
+		return false
+	}
+	entry, ok := obj.evicted[queryHashId]
+	if !ok {
+		return false
+	}
+	entry.refCount--
+	if entry.refCount > 0 {
+		return false
+	}
+	delete(obj.evicted, queryHashId)
+	return true
+}
+
+// Remove drops the query with the hash id from the cache, e.g. after it has been closed
+func (obj *PreparedQueryCache) Remove(queryHashId int64) bool {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	if _, ok := obj.evicted[queryHashId]; ok {
+		delete(obj.evicted, queryHashId)
+		return true
+	}
+	elem, ok := obj.byHashId[queryHashId]
+	if !ok {
+		return false
+	}
+	obj.removeElement(elem)
+	return true
+}
+
+// Invalidate drops all the cached queries w/o closing them - used when the server session is gone
+func (obj *PreparedQueryCache) Invalidate() {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	obj.invalidate()
+}
+
+func (obj *PreparedQueryCache) String() string {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	var buffer bytes.Buffer
+	buffer.WriteString("PreparedQueryCache:{")
+	buffer.WriteString(fmt.Sprintf("Capacity: %d", obj.capacity))
+	buffer.WriteString(fmt.Sprintf(", SessionId: %d", obj.sessionId))
+	buffer.WriteString(", Queries: [")
+	for elem := obj.lruList.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*preparedQueryEntry)
+		buffer.WriteString(fmt.Sprintf("%d:'%s' ", entry.queryHashId, entry.expr))
+	}
+	buffer.WriteString(fmt.Sprintf("], EvictedInUse: %d}", len(obj.evicted)))
+	return buffer.String()
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for PreparedQueryCache
+/////////////////////////////////////////////////////////////////
+
+func (obj *PreparedQueryCache) checkSession(sessionId int64) {
+	if obj.sessionId != sessionId {
+		logger.Debug(fmt.Sprintf("Inside PreparedQueryCache:checkSession - session changed from '%d' to '%d', invalidating %d queries", obj.sessionId, sessionId, obj.lruList.Len()))
+		obj.invalidate()
+		obj.sessionId = sessionId
+	}
+}
+
+func (obj *PreparedQueryCache) invalidate() {
+	obj.lruList.Init()
+	obj.byExpr = make(map[string]*list.Element, 0)
+	obj.byHashId = make(map[int64]*list.Element, 0)
+	obj.evicted = make(map[int64]*preparedQueryEntry, 0)
+}
+
+// evictElement removes the entry from the cache, adding its hash id to evicted if no handle uses it anymore
+func (obj *PreparedQueryCache) evictElement(elem *list.Element, evicted *[]int64) {
+	entry := elem.Value.(*preparedQueryEntry)
+	obj.removeElement(elem)
+	if entry.refCount > 0 {
+		obj.evicted[entry.queryHashId] = entry
+		return
+	}
+	*evicted = append(*evicted, entry.queryHashId)
+}
+
+func (obj *PreparedQueryCache) removeElement(elem *list.Element) int64 {
+	entry := obj.lruList.Remove(elem).(*preparedQueryEntry)
+	delete(obj.byExpr, entry.expr)
+	delete(obj.byHashId, entry.queryHashId)
+	return entry.queryHashId
+}
@@ -0,0 +1,154 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: PreparedQueryCache_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package connection
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/query"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/utils"
+	"testing"
+)
+
+// preparingConnection creates a server side query for every CreateQuery call in session 1
+type preparingConnection struct {
+	types.TGConnection
+	created int64
+}
+
+type preparingChannel struct {
+	types.TGChannel
+}
+
+func (obj *preparingChannel) GetSessionId() int64 {
+	return 1
+}
+
+func (obj *preparingConnection) GetChannel() types.TGChannel {
+	return &preparingChannel{}
+}
+
+func (obj *preparingConnection) CreateQuery(expr string) (types.TGQuery, types.TGError) {
+	obj.created++
+	return query.NewPreparedQuery(obj, 100+obj.created, expr), nil
+}
+
+func TestPreparedQueryCacheEviction(t *testing.T) {
+	cache := NewPreparedQueryCache(2)
+	if evicted := cache.Put("q1", 101, 1); len(evicted) != 0 {
+		t.Errorf("TestPreparedQueryCacheEviction expected no evictions and not '%+v'", evicted)
+	}
+	cache.Put("q2", 102, 1)
+	// No handle uses q2 anymore, so it can be closed as soon as it is evicted
+	if cache.Release(102, 1) {
+		t.Errorf("TestPreparedQueryCacheEviction expected the cached q2 not to be closed on release")
+	}
+	// Touch q1 so that q2 becomes the least recently used query
+	if id, ok := cache.Get("q1", 1); !ok || id != 101 {
+		t.Errorf("TestPreparedQueryCacheEviction expected q1 to be cached as 101 and not '%d'", id)
+	}
+	evicted := cache.Put("q3", 103, 1)
+	if len(evicted) != 1 || evicted[0] != 102 {
+		t.Errorf("TestPreparedQueryCacheEviction expected q2 to be evicted and not '%+v'", evicted)
+	}
+	if _, ok := cache.Get("q2", 1); ok {
+		t.Errorf("TestPreparedQueryCacheEviction expected q2 to be gone from '%s'", cache.String())
+	}
+	if !cache.Remove(101) || cache.Len() != 1 {
+		t.Errorf("TestPreparedQueryCacheEviction expected only q3 to be left in '%s'", cache.String())
+	}
+}
+
+func TestPreparedQueryCacheEvictedInUse(t *testing.T) {
+	cache := NewPreparedQueryCache(1)
+	cache.Put("q1", 101, 1)
+	cache.Get("q1", 1)
+	// q1 is used by two handles, so evicting it must not close it
+	if evicted := cache.Put("q2", 102, 1); len(evicted) != 0 {
+		t.Errorf("TestPreparedQueryCacheEvictedInUse expected q1 to stay open and not '%+v'", evicted)
+	}
+	if _, ok := cache.Get("q1", 1); ok {
+		t.Errorf("TestPreparedQueryCacheEvictedInUse expected q1 to be gone from '%s'", cache.String())
+	}
+	if cache.Release(101, 1) {
+		t.Errorf("TestPreparedQueryCacheEvictedInUse expected q1 to stay open while a handle uses it")
+	}
+	if !cache.Release(101, 1) {
+		t.Errorf("TestPreparedQueryCacheEvictedInUse expected q1 to be closed w/ its last handle")
+	}
+	if cache.Release(101, 1) {
+		t.Errorf("TestPreparedQueryCacheEvictedInUse expected q1 to be closed only once")
+	}
+}
+
+func TestPreparedQueryCacheSessionChange(t *testing.T) {
+	cache := NewPreparedQueryCache(4)
+	cache.Put("q1", 101, 1)
+	cache.Put("q2", 102, 1)
+	if _, ok := cache.Get("q1", 2); ok || cache.Len() != 0 {
+		t.Errorf("TestPreparedQueryCacheSessionChange expected a new session to invalidate '%s'", cache.String())
+	}
+	cache.Put("q1", 201, 2)
+	cache.Invalidate()
+	if cache.Len() != 0 {
+		t.Errorf("TestPreparedQueryCacheSessionChange expected an empty cache and not '%s'", cache.String())
+	}
+}
+
+func TestPreparedQueryCacheSize(t *testing.T) {
+	props := utils.NewSortedProperties()
+	if size := getQueryCacheSize(props); size != 32 {
+		t.Errorf("TestPreparedQueryCacheSize expected the default size of 32 and not '%d'", size)
+	}
+	props.AddProperty("tgdb.connection.queryCacheSize", "0")
+	cache := NewPreparedQueryCache(getQueryCacheSize(props))
+	if cache.GetCapacity() != 0 || len(cache.Put("q1", 101, 1)) != 0 || cache.Len() != 0 {
+		t.Errorf("TestPreparedQueryCacheSize expected a disabled cache and not '%s'", cache.String())
+	}
+}
+
+func TestAdminConnectionQueryCacheSize(t *testing.T) {
+	props := utils.NewSortedProperties()
+	props.AddProperty("tgdb.connection.queryCacheSize", "8")
+	conn := NewAdminConnection(nil, nil, props)
+	if conn.queryCache.GetCapacity() != 8 {
+		t.Errorf("TestAdminConnectionQueryCacheSize expected a capacity of 8 and not '%s'", conn.queryCache.String())
+	}
+}
+
+func TestPrepareQueryWithParameters(t *testing.T) {
+	conn := &preparingConnection{}
+	cache := NewPreparedQueryCache(2)
+	first, err := prepareQuery(conn, cache, "@nodetype = 'House' and name = $name")
+	if err != nil {
+		t.Errorf("TestPrepareQueryWithParameters returned error message %s", err.Error())
+		return
+	}
+	second, err := prepareQuery(conn, cache, "@nodetype = 'House' and name = $name")
+	if err != nil {
+		t.Errorf("TestPrepareQueryWithParameters returned error message %s", err.Error())
+		return
+	}
+	if conn.created != 1 || second.(*query.TGQueryImpl).GetQueryId() != first.(*query.TGQueryImpl).GetQueryId() {
+		t.Errorf("TestPrepareQueryWithParameters expected a query w/ parameters to be cached and not '%s'", cache.String())
+	}
+	if err := second.Bind("name", "Gamble House"); err != nil {
+		t.Errorf("TestPrepareQueryWithParameters returned error message %s", err.Error())
+	}
+}
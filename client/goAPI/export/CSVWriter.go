@@ -0,0 +1,160 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: CSVWriter.go
+ * SVN id: $id: $
+ *
+ */
+
+package export
+
+import (
+	"encoding/csv"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// untypedName names the files of the entities w/o a type
+const untypedName = "untyped"
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// CSVWriter writes the nodes of each node type to 'nodes-<type>.csv', and the edges of each edge type to
+// 'edges-<type>.csv', in the directory. The columns are the id - along w/ the direction and the node ids of an
+// edge - followed by the attributes set on any entity of the type. The 'to' column of a hyperedge lists the ids
+// of the nodes other than the first one, separated by ';'.
+type CSVWriter struct {
+	dir   string
+	files []string
+}
+
+func NewCSVWriter(dir string) *CSVWriter {
+	return &CSVWriter{dir: dir, files: make([]string, 0)}
+}
+
+// GetFiles returns the files written so far
+func (obj *CSVWriter) GetFiles() []string {
+	return obj.files
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for CSVWriter
+/////////////////////////////////////////////////////////////////
+
+// csvTable holds the rows of the entities of a type until all their attribute names are known
+type csvTable struct {
+	fileName string
+	columns  []string
+	rows     [][]string
+	values   []map[string]interface{}
+}
+
+func fileNameOf(prefix string, typeName string) string {
+	if typeName == "" {
+		typeName = untypedName
+	}
+	return prefix + "-" + unsafeFileChars.ReplaceAllString(typeName, "_") + ".csv"
+}
+
+// groupTables adds the row of the entity to the table of its type, creating the table if needed
+func groupTables(tables map[string]*csvTable, order []string, fileName string, columns []string, row []string, values map[string]interface{}) []string {
+	table, ok := tables[fileName]
+	if !ok {
+		table = &csvTable{fileName: fileName, columns: columns}
+		tables[fileName] = table
+		order = append(order, fileName)
+	}
+	table.rows = append(table.rows, row)
+	table.values = append(table.values, values)
+	return order
+}
+
+func (obj *CSVWriter) writeTable(table *csvTable) error {
+	attrNames := make(map[string]bool, 0)
+	for _, values := range table.values {
+		for name := range values {
+			attrNames[name] = true
+		}
+	}
+	names := make([]string, 0, len(attrNames))
+	for name := range attrNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	path := filepath.Join(obj.dir, table.fileName)
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	writer := csv.NewWriter(file)
+	err = writer.Write(append(table.columns, names...))
+	if err != nil {
+		return err
+	}
+	for i, row := range table.rows {
+		for _, name := range names {
+			cell := ""
+			if value, ok := table.values[i][name]; ok {
+				cell = formatValue(value)
+			}
+			row = append(row, cell)
+		}
+		err = writer.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		return err
+	}
+	obj.files = append(obj.files, path)
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> GraphWriter
+/////////////////////////////////////////////////////////////////
+
+func (obj *CSVWriter) WriteGraph(nodes []types.TGNode, edges []types.TGEdge) error {
+	tables := make(map[string]*csvTable, 0)
+	order := make([]string, 0)
+	for _, node := range nodes {
+		row := []string{strconv.FormatInt(node.GetVirtualId(), 10)}
+		order = groupTables(tables, order, fileNameOf("nodes", typeName(node)), []string{"id"}, row, attributeValues(node))
+	}
+	for _, edge := range edges {
+		ids := vertexIds(edge)
+		others := make([]string, 0, len(ids)-1)
+		for _, id := range ids[1:] {
+			others = append(others, strconv.FormatInt(id, 10))
+		}
+		row := []string{strconv.FormatInt(edge.GetVirtualId(), 10), directionName(edge.GetDirectionType()), strconv.FormatInt(ids[0], 10), strings.Join(others, ";")}
+		order = groupTables(tables, order, fileNameOf("edges", typeName(edge)), []string{"id", "direction", "from", "to"}, row, attributeValues(edge))
+	}
+	for _, fileName := range order {
+		err := obj.writeTable(tables[fileName])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
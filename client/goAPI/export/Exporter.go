@@ -0,0 +1,258 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: Exporter.go
+ * SVN id: $id: $
+ *
+ */
+
+package export
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/logging"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/query"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"reflect"
+	"sort"
+	"time"
+)
+
+var logger = logging.DefaultTGLogManager().GetLogger()
+
+// GraphWriter writes the nodes and edges of an exported subgraph in some format
+type GraphWriter interface {
+	WriteGraph(nodes []types.TGNode, edges []types.TGEdge) error
+}
+
+// Exporter dumps the subgraph returned by a query or a traversal. The nodes and edges of the result set are
+// walked as far as the server returned them - see the traversal depth and edge limit of the query option - and
+// only the edges whose nodes are all part of the subgraph are exported.
+type Exporter struct {
+	conn    types.TGConnection
+	options types.TGQueryOption
+}
+
+func NewExporter(conn types.TGConnection) *Exporter {
+	newExporter := Exporter{
+		conn:    conn,
+		options: query.NewQueryOption(),
+	}
+	return &newExporter
+}
+
+/////////////////////////////////////////////////////////////////
+// Helper functions for Exporter
+/////////////////////////////////////////////////////////////////
+
+// SetQueryOption sets the options of the queries run by the exporter
+func (obj *Exporter) SetQueryOption(options types.TGQueryOption) {
+	obj.options = options
+}
+
+// ExportQuery runs the query and writes the subgraph of its result
+func (obj *Exporter) ExportQuery(expr string, writer GraphWriter) types.TGError {
+	logger.Log(fmt.Sprintf("Entering Exporter:ExportQuery w/ query '%s'", expr))
+	rSet, err := obj.conn.ExecuteQuery(expr, obj.options)
+	if err != nil {
+		return err
+	}
+	return ExportResultSet(rSet, writer)
+}
+
+// ExportTraversal runs the traversal and writes the subgraph of its result
+func (obj *Exporter) ExportTraversal(expr, edgeFilter, traversalCondition, endCondition string, writer GraphWriter) types.TGError {
+	logger.Log(fmt.Sprintf("Entering Exporter:ExportTraversal w/ query '%s'", expr))
+	rSet, err := obj.conn.ExecuteQueryWithFilter(expr, edgeFilter, traversalCondition, endCondition, obj.options)
+	if err != nil {
+		return err
+	}
+	return ExportResultSet(rSet, writer)
+}
+
+// ExportResultSet writes the subgraph of the result set
+func ExportResultSet(rSet types.TGResultSet, writer GraphWriter) types.TGError {
+	nodes, edges, err := CollectGraph(rSet)
+	if err != nil {
+		return err
+	}
+	wErr := writer.WriteGraph(nodes, edges)
+	if wErr != nil {
+		errMsg := fmt.Sprintf("Unable to write the exported graph of '%d' nodes and '%d' edges", len(nodes), len(edges))
+		logger.Error(fmt.Sprintf("ERROR: Returning Exporter:ExportResultSet - %s w/ error '%s'", errMsg, wErr.Error()))
+		return exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, wErr.Error())
+	}
+	logger.Log(fmt.Sprintf("Returning Exporter:ExportResultSet w/ '%d' nodes and '%d' edges", len(nodes), len(edges)))
+	return nil
+}
+
+// CollectGraph walks the entities of the result set, and the nodes and edges reachable from them, returning each
+// node and edge once in the order they were reached. The result set is closed once walked.
+func CollectGraph(rSet types.TGResultSet) ([]types.TGNode, []types.TGEdge, types.TGError) {
+	walker := newGraphWalker()
+	if rSet == nil || reflect.ValueOf(rSet).IsNil() {
+		return walker.nodes, walker.edges, nil
+	}
+	defer rSet.Close()
+	for entity, err := range rSet.All() {
+		if err != nil {
+			errMsg := "Unable to read the entities of the result set to export"
+			return nil, nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, err.Error())
+		}
+		walker.visit(entity)
+	}
+	return walker.nodes, walker.connectedEdges(), nil
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for Exporter
+/////////////////////////////////////////////////////////////////
+
+// graphWalker collects the entities reachable from the visited ones
+type graphWalker struct {
+	nodes   []types.TGNode
+	edges   []types.TGEdge
+	visited map[types.TGEntity]bool
+}
+
+func newGraphWalker() *graphWalker {
+	return &graphWalker{nodes: make([]types.TGNode, 0), edges: make([]types.TGEdge, 0), visited: make(map[types.TGEntity]bool, 0)}
+}
+
+// isInitialized checks whether the entity was read from the server, rather than only referred to by another one
+func isInitialized(entity types.TGEntity) bool {
+	if initialized, ok := entity.(interface{ GetIsInitialized() bool }); ok {
+		return initialized.GetIsInitialized()
+	}
+	return true
+}
+
+func (obj *graphWalker) visit(entity types.TGEntity) {
+	pending := []types.TGEntity{entity}
+	for len(pending) > 0 {
+		entity = pending[0]
+		pending = pending[1:]
+		if entity == nil || reflect.ValueOf(entity).IsNil() || obj.visited[entity] || !isInitialized(entity) {
+			continue
+		}
+		obj.visited[entity] = true
+		switch e := entity.(type) {
+		case types.TGEdge:
+			obj.edges = append(obj.edges, e)
+			for _, node := range e.GetVertices() {
+				pending = append(pending, node)
+			}
+		case types.TGNode:
+			obj.nodes = append(obj.nodes, e)
+			for _, edge := range e.GetEdges() {
+				pending = append(pending, edge)
+			}
+		}
+	}
+}
+
+// connectedEdges returns the edges whose nodes are all part of the collected subgraph
+func (obj *graphWalker) connectedEdges() []types.TGEdge {
+	edges := make([]types.TGEdge, 0, len(obj.edges))
+	for _, edge := range obj.edges {
+		vertices := edge.GetVertices()
+		connected := len(vertices) > 0
+		for _, node := range vertices {
+			if node == nil || reflect.ValueOf(node).IsNil() || !obj.visited[node] {
+				connected = false
+				break
+			}
+		}
+		if connected {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// typeName returns the name of the type of the entity, or an empty string for an entity w/o a type
+func typeName(entity types.TGEntity) string {
+	entityType := entity.GetEntityType()
+	if entityType == nil || reflect.ValueOf(entityType).IsNil() {
+		return ""
+	}
+	return entityType.GetName()
+}
+
+func directionName(direction types.TGDirectionType) string {
+	switch direction {
+	case types.DirectionTypeDirected:
+		return "directed"
+	case types.DirectionTypeBiDirectional:
+		return "bidirectional"
+	}
+	return "undirected"
+}
+
+// vertexIds returns the ids of the nodes of the edge
+func vertexIds(edge types.TGEdge) []int64 {
+	vertices := edge.GetVertices()
+	ids := make([]int64, 0, len(vertices))
+	for _, node := range vertices {
+		ids = append(ids, node.GetVirtualId())
+	}
+	return ids
+}
+
+// attributeValues returns the values of the attributes of the entity that are set, by name
+func attributeValues(entity types.TGEntity) map[string]interface{} {
+	values := make(map[string]interface{}, 0)
+	attrs, err := entity.GetAttributes()
+	if err != nil {
+		return values
+	}
+	for _, attr := range attrs {
+		if attr.IsNull() {
+			continue
+		}
+		values[attr.GetName()] = attr.GetValue()
+	}
+	return values
+}
+
+func sortedNames(values map[string]interface{}) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatValue renders an attribute value as text, for the formats w/o typed values
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	}
+	if kind := reflect.TypeOf(value).Kind(); kind == reflect.Slice || kind == reflect.Array {
+		data, err := json.Marshal(value)
+		if err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprintf("%v", value)
+}
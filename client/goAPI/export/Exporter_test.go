@@ -0,0 +1,174 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: Exporter_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/query"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// createTestGraph returns a result set w/ the node 'Anne', connected to 'Bob' by a 'knows' edge, and to a node
+// that was not read from the server
+func createTestGraph(t *testing.T) types.TGResultSet {
+	gof := model.NewGraphObjectFactory(nil)
+	gmd := gof.GetGraphMetaData()
+	name := model.NewAttributeDescriptorWithType("name", types.AttributeTypeString)
+	age := model.NewAttributeDescriptorWithType("age", types.AttributeTypeInteger)
+	since := model.NewAttributeDescriptorWithType("since", types.AttributeTypeLong)
+	gmd.SetAttributeDescriptors(map[string]types.TGAttributeDescriptor{"name": name, "age": age, "since": since})
+	person := model.NewNodeType("Person", nil)
+	gmd.SetNodeTypes(map[string]types.TGNodeType{"Person": person})
+	knows := model.NewEdgeType("knows", types.DirectionTypeDirected, nil)
+	gmd.SetEdgeTypes(map[string]types.TGEdgeType{"knows": knows})
+
+	nextId := int64(0)
+	stored := func(entity types.TGEntity) {
+		nextId++
+		entity.SetIsNew(false)
+		entity.SetEntityId(nextId)
+	}
+	anne, _ := gof.CreateNodeInGraph(person)
+	anne.SetOrCreateAttribute("name", "Anne <A&B>")
+	anne.SetOrCreateAttribute("age", 30)
+	stored(anne)
+	bob, _ := gof.CreateNodeInGraph(person)
+	bob.SetOrCreateAttribute("name", "Bob")
+	stored(bob)
+	placeholder, _ := gof.CreateNodeInGraph(person)
+	placeholder.SetIsInitialized(false)
+	stored(placeholder)
+
+	edge, err := gof.CreateEdgeWithEdgeType(anne, bob, knows)
+	if err != nil {
+		t.Fatalf("Unable to create edge w/ error '%s'", err.Error())
+	}
+	edge.SetOrCreateAttribute("since", int64(2001))
+	stored(edge)
+	dangling, _ := gof.CreateEdgeWithDirection(anne, placeholder, types.DirectionTypeUnDirected)
+	stored(dangling)
+
+	rSet := query.NewResultSet(nil, 0)
+	rSet.AddEntityToResultSet(anne)
+	return rSet
+}
+
+func TestCollectGraph(t *testing.T) {
+	rSet := createTestGraph(t)
+	nodes, edges, err := CollectGraph(rSet)
+	if err != nil {
+		t.Fatalf("TestCollectGraph failed w/ error '%s'", err.Error())
+	}
+	if len(nodes) != 2 || len(edges) != 1 {
+		t.Fatalf("TestCollectGraph expected 2 nodes and the edge between them and not '%d' nodes and '%d' edges", len(nodes), len(edges))
+	}
+	if edges[0].GetAttribute("since").GetValue() != int64(2001) {
+		t.Errorf("TestCollectGraph returned wrong edge '%+v'", edges[0])
+	}
+	if rSet.(*query.ResultSet).GetIsOpen() {
+		t.Errorf("TestCollectGraph expected the result set to be closed")
+	}
+}
+
+func TestJSONLinesWriter(t *testing.T) {
+	var out bytes.Buffer
+	if err := ExportResultSet(createTestGraph(t), NewJSONLinesWriter(&out)); err != nil {
+		t.Fatalf("TestJSONLinesWriter failed w/ error '%s'", err.Error())
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("TestJSONLinesWriter expected 3 lines and not '%s'", out.String())
+	}
+	var edge map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[2]), &edge); err != nil {
+		t.Fatalf("TestJSONLinesWriter wrote invalid JSON '%s'", lines[2])
+	}
+	if edge["kind"] != "edge" || edge["type"] != "knows" || edge["direction"] != "directed" || edge["from"] != float64(1) || edge["to"] != float64(2) {
+		t.Errorf("TestJSONLinesWriter wrote wrong edge '%s'", lines[2])
+	}
+	if !strings.Contains(lines[0], `"type":"Person"`) || !strings.Contains(lines[0], `"age":30`) {
+		t.Errorf("TestJSONLinesWriter wrote wrong node '%s'", lines[0])
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	dir := t.TempDir()
+	writer := NewCSVWriter(dir)
+	if err := ExportResultSet(createTestGraph(t), writer); err != nil {
+		t.Fatalf("TestCSVWriter failed w/ error '%s'", err.Error())
+	}
+	if len(writer.GetFiles()) != 2 {
+		t.Fatalf("TestCSVWriter expected a node and an edge file and not '%+v'", writer.GetFiles())
+	}
+	nodes, _ := ioutil.ReadFile(filepath.Join(dir, "nodes-Person.csv"))
+	expected := "id,age,name\n1,30,Anne <A&B>\n2,,Bob\n"
+	if string(nodes) != expected {
+		t.Errorf("TestCSVWriter expected nodes '%s' and not '%s'", expected, string(nodes))
+	}
+	edges, _ := ioutil.ReadFile(filepath.Join(dir, "edges-knows.csv"))
+	expected = "id,direction,from,to,since\n4,directed,1,2,2001\n"
+	if string(edges) != expected {
+		t.Errorf("TestCSVWriter expected edges '%s' and not '%s'", expected, string(edges))
+	}
+}
+
+func TestGraphMLWriter(t *testing.T) {
+	var out bytes.Buffer
+	if err := ExportResultSet(createTestGraph(t), NewGraphMLWriter(&out)); err != nil {
+		t.Fatalf("TestGraphMLWriter failed w/ error '%s'", err.Error())
+	}
+	var doc struct {
+		Keys []struct {
+			Id   string `xml:"id,attr"`
+			Type string `xml:"attr.type,attr"`
+		} `xml:"key"`
+		Nodes []struct {
+			Id string `xml:"id,attr"`
+		} `xml:"graph>node"`
+		Edges []struct {
+			Source   string `xml:"source,attr"`
+			Target   string `xml:"target,attr"`
+			Directed bool   `xml:"directed,attr"`
+		} `xml:"graph>edge"`
+	}
+	if err := xml.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatalf("TestGraphMLWriter wrote invalid XML w/ error '%s': %s", err.Error(), out.String())
+	}
+	if len(doc.Nodes) != 2 || len(doc.Edges) != 1 || doc.Edges[0].Source != "n1" || doc.Edges[0].Target != "n2" || !doc.Edges[0].Directed {
+		t.Errorf("TestGraphMLWriter wrote wrong graph '%s'", out.String())
+	}
+	keyTypes := make(map[string]string, 0)
+	for _, key := range doc.Keys {
+		keyTypes[key.Id] = key.Type
+	}
+	if keyTypes["n_age"] != "int" || keyTypes["n_name"] != "string" || keyTypes["e_since"] != "long" || keyTypes["edgetype"] != "string" {
+		t.Errorf("TestGraphMLWriter declared wrong keys '%+v'", keyTypes)
+	}
+	if !strings.Contains(out.String(), "Anne &lt;A&amp;B&gt;") {
+		t.Errorf("TestGraphMLWriter expected escaped attribute values in '%s'", out.String())
+	}
+}
@@ -0,0 +1,174 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: GraphMLWriter.go
+ * SVN id: $id: $
+ *
+ */
+
+package export
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	nodeTypeKey  = "nodetype"
+	edgeTypeKey  = "edgetype"
+	directionKey = "direction"
+)
+
+// GraphMLWriter writes the subgraph as a GraphML document. The type of each node and edge, and the direction of
+// each edge, are written as data of the 'nodetype', 'edgetype' and 'direction' keys, and the attributes as data
+// of keys named after them. Hyperedges are written as GraphML hyperedges.
+type GraphMLWriter struct {
+	writer io.Writer
+}
+
+func NewGraphMLWriter(writer io.Writer) *GraphMLWriter {
+	return &GraphMLWriter{writer: writer}
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for GraphMLWriter
+/////////////////////////////////////////////////////////////////
+
+// graphMLKey is the declaration of the data of an attribute of nodes or edges
+type graphMLKey struct {
+	id       string
+	forKind  string
+	name     string
+	attrType string
+}
+
+// graphMLType returns the GraphML type of the value of an attribute
+func graphMLType(value interface{}) string {
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int:
+		return "int"
+	case reflect.Int64:
+		return "long"
+	case reflect.Float32:
+		return "float"
+	case reflect.Float64:
+		return "double"
+	}
+	return "string"
+}
+
+// collectKeys declares a key for each attribute of the entities, typed as a string if its values differ in type
+func collectKeys(keys map[string]*graphMLKey, prefix string, forKind string, values map[string]interface{}) {
+	for name, value := range values {
+		id := prefix + name
+		attrType := graphMLType(value)
+		if key, ok := keys[id]; ok {
+			if key.attrType != attrType {
+				key.attrType = "string"
+			}
+			continue
+		}
+		keys[id] = &graphMLKey{id: id, forKind: forKind, name: name, attrType: attrType}
+	}
+}
+
+func escapeXML(value string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
+
+func writeData(w *bufio.Writer, key string, value string) {
+	fmt.Fprintf(w, "      <data key=\"%s\">%s</data>\n", escapeXML(key), escapeXML(value))
+}
+
+func writeAttributeData(w *bufio.Writer, prefix string, values map[string]interface{}) {
+	for _, name := range sortedNames(values) {
+		writeData(w, prefix+name, formatValue(values[name]))
+	}
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> GraphWriter
+/////////////////////////////////////////////////////////////////
+
+func (obj *GraphMLWriter) WriteGraph(nodes []types.TGNode, edges []types.TGEdge) error {
+	nodeValues := make([]map[string]interface{}, 0, len(nodes))
+	edgeValues := make([]map[string]interface{}, 0, len(edges))
+	keys := make(map[string]*graphMLKey, 0)
+	for _, node := range nodes {
+		values := attributeValues(node)
+		collectKeys(keys, "n_", "node", values)
+		nodeValues = append(nodeValues, values)
+	}
+	for _, edge := range edges {
+		values := attributeValues(edge)
+		collectKeys(keys, "e_", "edge", values)
+		edgeValues = append(edgeValues, values)
+	}
+	keys[nodeTypeKey] = &graphMLKey{id: nodeTypeKey, forKind: "node", name: nodeTypeKey, attrType: "string"}
+	keys[edgeTypeKey] = &graphMLKey{id: edgeTypeKey, forKind: "all", name: edgeTypeKey, attrType: "string"}
+	keys[directionKey] = &graphMLKey{id: directionKey, forKind: "all", name: directionKey, attrType: "string"}
+	keyIds := make([]string, 0, len(keys))
+	for id := range keys {
+		keyIds = append(keyIds, id)
+	}
+	sort.Strings(keyIds)
+
+	w := bufio.NewWriter(obj.writer)
+	w.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	w.WriteString("<graphml xmlns=\"http://graphml.graphdrawing.org/xmlns\">\n")
+	for _, id := range keyIds {
+		key := keys[id]
+		fmt.Fprintf(w, "  <key id=\"%s\" for=\"%s\" attr.name=\"%s\" attr.type=\"%s\"/>\n", escapeXML(key.id), key.forKind, escapeXML(key.name), key.attrType)
+	}
+	w.WriteString("  <graph id=\"G\" edgedefault=\"directed\">\n")
+	for i, node := range nodes {
+		fmt.Fprintf(w, "    <node id=\"n%d\">\n", node.GetVirtualId())
+		writeData(w, nodeTypeKey, typeName(node))
+		writeAttributeData(w, "n_", nodeValues[i])
+		w.WriteString("    </node>\n")
+	}
+	for i, edge := range edges {
+		ids := vertexIds(edge)
+		direction := edge.GetDirectionType()
+		if edge.GetEntityKind() == types.EntityKindHyperEdge {
+			fmt.Fprintf(w, "    <hyperedge id=\"e%d\">\n", edge.GetVirtualId())
+			for _, id := range ids {
+				fmt.Fprintf(w, "      <endpoint node=\"n%d\"/>\n", id)
+			}
+		} else {
+			fmt.Fprintf(w, "    <edge id=\"e%d\" source=\"n%d\" target=\"n%d\" directed=\"%t\">\n", edge.GetVirtualId(), ids[0], ids[len(ids)-1], direction == types.DirectionTypeDirected)
+		}
+		writeData(w, edgeTypeKey, typeName(edge))
+		writeData(w, directionKey, directionName(direction))
+		writeAttributeData(w, "e_", edgeValues[i])
+		if edge.GetEntityKind() == types.EntityKindHyperEdge {
+			w.WriteString("    </hyperedge>\n")
+		} else {
+			w.WriteString("    </edge>\n")
+		}
+	}
+	w.WriteString("  </graph>\n")
+	w.WriteString("</graphml>\n")
+	return w.Flush()
+}
@@ -0,0 +1,76 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: JSONLinesWriter.go
+ * SVN id: $id: $
+ *
+ */
+
+package export
+
+import (
+	"encoding/json"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"io"
+)
+
+// jsonEntity is the line of a node, edge or hyperedge
+type jsonEntity struct {
+	Kind       string                 `json:"kind"`
+	Id         int64                  `json:"id"`
+	Type       string                 `json:"type,omitempty"`
+	Direction  string                 `json:"direction,omitempty"`
+	From       *int64                 `json:"from,omitempty"`
+	To         *int64                 `json:"to,omitempty"`
+	Vertices   []int64                `json:"vertices,omitempty"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// JSONLinesWriter writes a JSON object per line, for each node then for each edge. An edge has 'from' and 'to'
+// node ids, and a hyperedge has the ids of all its 'vertices'.
+type JSONLinesWriter struct {
+	writer io.Writer
+}
+
+func NewJSONLinesWriter(writer io.Writer) *JSONLinesWriter {
+	return &JSONLinesWriter{writer: writer}
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> GraphWriter
+/////////////////////////////////////////////////////////////////
+
+func (obj *JSONLinesWriter) WriteGraph(nodes []types.TGNode, edges []types.TGEdge) error {
+	encoder := json.NewEncoder(obj.writer)
+	for _, node := range nodes {
+		err := encoder.Encode(jsonEntity{Kind: "node", Id: node.GetVirtualId(), Type: typeName(node), Attributes: attributeValues(node)})
+		if err != nil {
+			return err
+		}
+	}
+	for _, edge := range edges {
+		line := jsonEntity{Id: edge.GetVirtualId(), Type: typeName(edge), Direction: directionName(edge.GetDirectionType()), Attributes: attributeValues(edge)}
+		ids := vertexIds(edge)
+		if edge.GetEntityKind() == types.EntityKindHyperEdge {
+			line.Kind, line.Vertices = "hyperedge", ids
+		} else {
+			line.Kind, line.From, line.To = "edge", &ids[0], &ids[len(ids)-1]
+		}
+		err := encoder.Encode(line)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
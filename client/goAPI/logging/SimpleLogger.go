@@ -200,25 +200,26 @@ func (m *Logger) formatMessage(callDepth int, logMsg string) string {
 	return formattedMsg
 }
 
+// simpleLog logs at the configured level. The call depth is passed down rather than kept in the logger, so that
+// goroutines can log at the same time.
 func (m *Logger) simpleLog(logMsg string) {
-	m.depth += 2
-	switch m.level {
-	case types.FatalLog:
-		m.Fatal(logMsg)
-	case types.ErrorLog:
-		m.Error(logMsg)
-	case types.WarningLog:
-		m.Warning(logMsg)
-	case types.InfoLog:
-		m.Info(logMsg)
-	case types.DebugLog:
-		m.Debug(logMsg)
-	case types.TraceLog:
-		m.Trace(logMsg)
+	level := m.level
+	switch level {
+	case types.FatalLog, types.ErrorLog, types.WarningLog, types.InfoLog, types.DebugLog, types.TraceLog:
 	default:
-		m.Debug(logMsg)
+		level = types.DebugLog
+	}
+	m.logAt(level, m.depth+3, logMsg)
+}
+
+// logAt logs the statement if the logger is configured for its level
+func (m *Logger) logAt(level types.LogLevel, callDepth int, logMsg string) {
+	if m.level <= level {
+		// Format log message according to configured msgFormat
+		formattedLogMsg := m.formatMessage(callDepth, logMsg)
+		// Ignore Error Handling
+		_ = m.log.Output(callDepth, formattedLogMsg)
 	}
-	m.depth -= 2
 }
 
 // GetFileAndLine returns the file and line from the stack at the given call depth
@@ -235,68 +236,32 @@ func (m *Logger) GetFileAndLine(callDepth int) (string, int) {
 
 // Trace logs Trace (Down-to-the-wire) Statements
 func (m *Logger) Trace(logMsg string) {
-	callDepth := m.depth+1
-	if m.level <= types.TraceLog {
-		// Format log message according to configured msgFormat
-		formattedLogMsg := m.formatMessage(callDepth, logMsg)
-		// Ignore Error Handling
-		_ = m.log.Output(callDepth, formattedLogMsg)
-	}
+	m.logAt(types.TraceLog, m.depth+2, logMsg)
 }
 
 // Debug logs Debug Statements
 func (m *Logger) Debug(logMsg string) {
-	callDepth := m.depth+1
-	if m.level <= types.DebugLog {
-		// Format log message according to configured msgFormat
-		formattedLogMsg := m.formatMessage(callDepth, logMsg)
-		// Ignore Error Handling
-		_ = m.log.Output(callDepth, formattedLogMsg)
-	}
+	m.logAt(types.DebugLog, m.depth+2, logMsg)
 }
 
 // Info logs Informative Statements
 func (m *Logger) Info(logMsg string) {
-	callDepth := m.depth+1
-	if m.level <= types.InfoLog {
-		// Format log message according to configured msgFormat
-		formattedLogMsg := m.formatMessage(callDepth, logMsg)
-		// Ignore Error Handling
-		_ = m.log.Output(callDepth, formattedLogMsg)
-	}
+	m.logAt(types.InfoLog, m.depth+2, logMsg)
 }
 
 // Warning logs Warning Statements
 func (m *Logger) Warning(logMsg string) {
-	callDepth := m.depth+1
-	if m.level <= types.WarningLog {
-		// Format log message according to configured msgFormat
-		formattedLogMsg := m.formatMessage(callDepth, logMsg)
-		// Ignore Error Handling
-		_ = m.log.Output(callDepth, formattedLogMsg)
-	}
+	m.logAt(types.WarningLog, m.depth+2, logMsg)
 }
 
 // Error logs Error Statements
 func (m *Logger) Error(logMsg string) {
-	callDepth := m.depth+1
-	if m.level <= types.ErrorLog {
-		// Format log message according to configured msgFormat
-		formattedLogMsg := m.formatMessage(callDepth, logMsg)
-		// Ignore Error Handling
-		_ = m.log.Output(callDepth, formattedLogMsg)
-	}
+	m.logAt(types.ErrorLog, m.depth+2, logMsg)
 }
 
 // Fatal logs Fatal Statements
 func (m *Logger) Fatal(logMsg string) {
-	callDepth := m.depth+1
-	if m.level <= types.FatalLog {
-		// Format log message according to configured msgFormat
-		formattedLogMsg := m.formatMessage(callDepth, logMsg)
-		// Ignore Error Handling
-		_ = m.log.Output(callDepth, formattedLogMsg)
-	}
+	m.logAt(types.FatalLog, m.depth+2, logMsg)
 }
 
 // Log is a generic function that introspects the log level configuration set for the current session, and
@@ -0,0 +1,469 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: AttributeCoercion.go
+ * SVN id: $id: $
+ *
+ */
+
+package mapper
+
+import (
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/utils"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// The coercion rules follow types.PreDefinedAttributeTypes - an attribute value is assigned to a Go field
+// of the same family only:
+//   Boolean                      ==> bool
+//   Byte, Short, Integer, Long   ==> any integer type that can hold the value, float32 or float64
+//   Char                         ==> rune (or any integer type) and string
+//   Float, Double                ==> float32 or float64, if the value fits
+//   Number                       ==> utils.TGDecimal, string, float32 or float64, and integer types for integral values
+//   String                       ==> string or []byte
+//   Date, Time, TimeStamp        ==> time.Time
+//   Blob                         ==> []byte
+//   Clob                         ==> []byte or string
+// The elements of an array attribute are assigned to a slice - or an array of the same length - element by element.
+// A pointer field is nil for a null attribute, a non-pointer field gets its zero value. An interface{} field
+// receives the raw attribute value. The same rules apply in reverse when a field is stored into an attribute.
+
+// AssignAttributeValue sets the target from the value of an attribute of the given attribute type
+func AssignAttributeValue(attrType int, value interface{}, target reflect.Value) types.TGError {
+	if target.Kind() == reflect.Ptr {
+		if value == nil {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		}
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return AssignAttributeValue(attrType, value, target.Elem())
+	}
+	if value == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+	source := reflect.ValueOf(value)
+	if target.Kind() == reflect.Interface && source.Type().AssignableTo(target.Type()) {
+		target.Set(source)
+		return nil
+	}
+	if elements, ok := value.([]interface{}); ok {
+		return assignArray(attrType, elements, target)
+	}
+
+	switch attrType {
+	case types.AttributeTypeBoolean:
+		if target.Kind() == reflect.Bool && source.Kind() == reflect.Bool {
+			target.SetBool(source.Bool())
+			return nil
+		}
+	case types.AttributeTypeByte, types.AttributeTypeShort, types.AttributeTypeInteger, types.AttributeTypeLong:
+		if i, ok := integerValue(source); ok {
+			return assignInteger(attrType, i, target)
+		}
+	case types.AttributeTypeChar:
+		r, ok := charValue(source)
+		if ok {
+			if target.Kind() == reflect.String {
+				target.SetString(string(r))
+				return nil
+			}
+			return assignInteger(attrType, int64(r), target)
+		}
+	case types.AttributeTypeFloat, types.AttributeTypeDouble:
+		if isFloatKind(source.Kind()) && isFloatKind(target.Kind()) {
+			f := source.Float()
+			if target.OverflowFloat(f) {
+				return overflowError(attrType, value, target)
+			}
+			target.SetFloat(f)
+			return nil
+		}
+	case types.AttributeTypeNumber:
+		return assignNumber(value, target)
+	case types.AttributeTypeString:
+		if source.Kind() == reflect.String {
+			if target.Kind() == reflect.String {
+				target.SetString(source.String())
+				return nil
+			}
+			if isByteSlice(target.Type()) {
+				target.SetBytes([]byte(source.String()))
+				return nil
+			}
+		}
+	case types.AttributeTypeDate, types.AttributeTypeTime, types.AttributeTypeTimeStamp:
+		if t, ok := value.(time.Time); ok && target.Type() == reflect.TypeOf(t) {
+			target.Set(source)
+			return nil
+		}
+	case types.AttributeTypeBlob, types.AttributeTypeClob:
+		if b, ok := value.([]byte); ok {
+			if isByteSlice(target.Type()) {
+				target.SetBytes(b)
+				return nil
+			}
+			if attrType == types.AttributeTypeClob && target.Kind() == reflect.String {
+				target.SetString(string(b))
+				return nil
+			}
+		}
+	default:
+		if source.Type().AssignableTo(target.Type()) {
+			target.Set(source)
+			return nil
+		}
+	}
+	return coercionError(attrType, value, target)
+}
+
+// AttributeTypeOfValue returns the attribute type whose coercion rules apply to a value read w/o its attribute
+// descriptor, e.g. a scalar of a Gremlin result. Integers are treated as Long and strings as String, so that the
+// value is range checked against the target instead of being converted blindly.
+func AttributeTypeOfValue(value interface{}) int {
+	switch v := value.(type) {
+	case nil:
+		return types.AttributeTypeInvalid
+	case time.Time:
+		return types.AttributeTypeTimeStamp
+	case utils.TGDecimal, *utils.TGDecimal:
+		return types.AttributeTypeNumber
+	case []byte:
+		return types.AttributeTypeBlob
+	case []interface{}:
+		if len(v) > 0 {
+			return AttributeTypeOfValue(v[0])
+		}
+		return types.AttributeTypeInvalid
+	}
+	kind := reflect.TypeOf(value).Kind()
+	switch {
+	case kind == reflect.Bool:
+		return types.AttributeTypeBoolean
+	case isIntKind(kind) || isUintKind(kind):
+		return types.AttributeTypeLong
+	case kind == reflect.Float32:
+		return types.AttributeTypeFloat
+	case kind == reflect.Float64:
+		return types.AttributeTypeDouble
+	case kind == reflect.String:
+		return types.AttributeTypeString
+	}
+	return types.AttributeTypeInvalid
+}
+
+// AttributeValueOf converts the field into the value to set on an attribute of the given attribute type. A nil
+// pointer or interface yields a nil value, i.e. a null attribute.
+func AttributeValueOf(attrType int, field reflect.Value) (interface{}, types.TGError) {
+	for field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+		if field.IsNil() {
+			return nil, nil
+		}
+		field = field.Elem()
+	}
+	value := field.Interface()
+	switch attrType {
+	case types.AttributeTypeBoolean:
+		if field.Kind() == reflect.Bool {
+			return field.Bool(), nil
+		}
+	case types.AttributeTypeByte:
+		if i, ok := integerValue(field); ok {
+			if i < 0 || i > math.MaxUint8 {
+				return nil, valueOverflowError(attrType, value)
+			}
+			return uint8(i), nil
+		}
+	case types.AttributeTypeChar:
+		if r, ok := charValue(field); ok {
+			return r, nil
+		}
+	case types.AttributeTypeShort:
+		if i, ok := integerValue(field); ok {
+			if i < math.MinInt16 || i > math.MaxInt16 {
+				return nil, valueOverflowError(attrType, value)
+			}
+			return int16(i), nil
+		}
+	case types.AttributeTypeInteger:
+		if i, ok := integerValue(field); ok {
+			if i < math.MinInt32 || i > math.MaxInt32 {
+				return nil, valueOverflowError(attrType, value)
+			}
+			return int(i), nil
+		}
+	case types.AttributeTypeLong:
+		if isUintKind(field.Kind()) && field.Uint() > math.MaxInt64 {
+			return nil, valueOverflowError(attrType, value)
+		}
+		if i, ok := integerValue(field); ok {
+			return i, nil
+		}
+	case types.AttributeTypeFloat:
+		if isFloatKind(field.Kind()) {
+			if math.Abs(field.Float()) > math.MaxFloat32 {
+				return nil, valueOverflowError(attrType, value)
+			}
+			return float32(field.Float()), nil
+		}
+		if i, ok := integerValue(field); ok {
+			return float32(i), nil
+		}
+	case types.AttributeTypeDouble:
+		if isFloatKind(field.Kind()) {
+			return field.Float(), nil
+		}
+		if i, ok := integerValue(field); ok {
+			return float64(i), nil
+		}
+	case types.AttributeTypeNumber:
+		// Numbers are sent to the server in their string representation
+		switch v := value.(type) {
+		case utils.TGDecimal:
+			return v.String(), nil
+		case string:
+			if _, err := utils.NewTGDecimalFromString(v); err == nil {
+				return v, nil
+			}
+		}
+		if isFloatKind(field.Kind()) {
+			return strconv.FormatFloat(field.Float(), 'f', -1, 64), nil
+		}
+		if i, ok := integerValue(field); ok {
+			return strconv.FormatInt(i, 10), nil
+		}
+	case types.AttributeTypeString:
+		if field.Kind() == reflect.String {
+			return field.String(), nil
+		}
+		if isByteSlice(field.Type()) {
+			return string(field.Bytes()), nil
+		}
+	case types.AttributeTypeDate, types.AttributeTypeTime, types.AttributeTypeTimeStamp:
+		if t, ok := value.(time.Time); ok {
+			return t, nil
+		}
+	case types.AttributeTypeBlob, types.AttributeTypeClob:
+		// The large object attributes take their content as a string
+		if isByteSlice(field.Type()) {
+			if field.IsNil() {
+				return nil, nil
+			}
+			return string(field.Bytes()), nil
+		}
+		if attrType == types.AttributeTypeClob && field.Kind() == reflect.String {
+			return field.String(), nil
+		}
+	default:
+		return value, nil
+	}
+	errMsg := fmt.Sprintf("Value '%+v' of type '%s' cannot be stored into an attribute of type '%s'", value, field.Type().String(), types.GetAttributeTypeFromId(attrType).GetTypeName())
+	return nil, exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, "")
+}
+
+// AttributeArrayValueOf converts the slice or array field into the elements to set on an array attribute of the
+// given attribute type. A nil slice or pointer yields a nil value, i.e. a null attribute.
+func AttributeArrayValueOf(attrType int, field reflect.Value) (interface{}, types.TGError) {
+	for field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+		if field.IsNil() {
+			return nil, nil
+		}
+		field = field.Elem()
+	}
+	if field.Kind() == reflect.Slice && field.IsNil() {
+		return nil, nil
+	}
+	if field.Kind() != reflect.Slice && field.Kind() != reflect.Array {
+		errMsg := fmt.Sprintf("Value of type '%s' cannot be stored into an array attribute of type '%s'", field.Type().String(), types.GetAttributeTypeFromId(attrType).GetTypeName())
+		return nil, exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, "")
+	}
+	elements := make([]interface{}, 0, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		element, err := AttributeValueOf(attrType, field.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		if element == nil {
+			errMsg := fmt.Sprintf("Element '%d' of an array attribute cannot be null", i)
+			return nil, exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, "")
+		}
+		elements = append(elements, element)
+	}
+	return elements, nil
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for AttributeCoercion
+/////////////////////////////////////////////////////////////////
+
+func assignArray(attrType int, elements []interface{}, target reflect.Value) types.TGError {
+	switch target.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(target.Type(), len(elements), len(elements))
+		for i, element := range elements {
+			err := AssignAttributeValue(attrType, element, slice.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+		return nil
+	case reflect.Array:
+		if target.Len() != len(elements) {
+			errMsg := fmt.Sprintf("Array attribute of '%d' elements cannot be assigned to '%s'", len(elements), target.Type().String())
+			return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, "")
+		}
+		for i, element := range elements {
+			err := AssignAttributeValue(attrType, element, target.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return coercionError(attrType, elements, target)
+}
+
+func assignInteger(attrType int, i int64, target reflect.Value) types.TGError {
+	switch {
+	case isIntKind(target.Kind()):
+		if target.OverflowInt(i) {
+			return overflowError(attrType, i, target)
+		}
+		target.SetInt(i)
+		return nil
+	case isUintKind(target.Kind()):
+		if i < 0 || target.OverflowUint(uint64(i)) {
+			return overflowError(attrType, i, target)
+		}
+		target.SetUint(uint64(i))
+		return nil
+	case isFloatKind(target.Kind()):
+		target.SetFloat(float64(i))
+		return nil
+	}
+	return coercionError(attrType, i, target)
+}
+
+func assignNumber(value interface{}, target reflect.Value) types.TGError {
+	var str string
+	switch v := value.(type) {
+	case utils.TGDecimal:
+		str = v.String()
+	case *utils.TGDecimal:
+		str = v.String()
+	case string:
+		str = v
+	default:
+		return coercionError(types.AttributeTypeNumber, value, target)
+	}
+	switch {
+	case target.Type() == reflect.TypeOf(utils.TGDecimal{}):
+		d, err := utils.NewTGDecimalFromString(str)
+		if err != nil {
+			return coercionError(types.AttributeTypeNumber, value, target)
+		}
+		target.Set(reflect.ValueOf(d))
+		return nil
+	case target.Kind() == reflect.String:
+		target.SetString(str)
+		return nil
+	case isFloatKind(target.Kind()):
+		f, err := strconv.ParseFloat(str, 64)
+		if err != nil || target.OverflowFloat(f) {
+			return coercionError(types.AttributeTypeNumber, value, target)
+		}
+		target.SetFloat(f)
+		return nil
+	case isIntKind(target.Kind()) || isUintKind(target.Kind()):
+		i, err := strconv.ParseInt(str, 10, 64)
+		if err != nil {
+			// Only integral numbers can be assigned to integer fields
+			return coercionError(types.AttributeTypeNumber, value, target)
+		}
+		return assignInteger(types.AttributeTypeNumber, i, target)
+	}
+	return coercionError(types.AttributeTypeNumber, value, target)
+}
+
+// charValue extracts the character from a Char attribute value, which is a one character string when read from
+// the server and a rune when set on the client side
+func charValue(source reflect.Value) (rune, bool) {
+	if source.Kind() == reflect.String {
+		r, size := utf8.DecodeRuneInString(source.String())
+		return r, size > 0 && size == len(source.String())
+	}
+	if i, ok := integerValue(source); ok {
+		return rune(i), true
+	}
+	return 0, false
+}
+
+func integerValue(source reflect.Value) (int64, bool) {
+	switch {
+	case isIntKind(source.Kind()):
+		return source.Int(), true
+	case isUintKind(source.Kind()):
+		return int64(source.Uint()), true
+	}
+	return 0, false
+}
+
+func isByteSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+func isFloatKind(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isUintKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func coercionError(attrType int, value interface{}, target reflect.Value) types.TGError {
+	errMsg := fmt.Sprintf("Value '%+v' of attribute type '%s' cannot be assigned to '%s'", value, types.GetAttributeTypeFromId(attrType).GetTypeName(), target.Type().String())
+	return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, "")
+}
+
+func overflowError(attrType int, value interface{}, target reflect.Value) types.TGError {
+	errMsg := fmt.Sprintf("Value '%+v' of attribute type '%s' overflows '%s'", value, types.GetAttributeTypeFromId(attrType).GetTypeName(), target.Type().String())
+	return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, "")
+}
+
+func valueOverflowError(attrType int, value interface{}) types.TGError {
+	errMsg := fmt.Sprintf("Value '%+v' overflows an attribute of type '%s'", value, types.GetAttributeTypeFromId(attrType).GetTypeName())
+	return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, "")
+}
@@ -0,0 +1,376 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: EntityPersister.go
+ * SVN id: $id: $
+ *
+ */
+
+package mapper
+
+import (
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"reflect"
+)
+
+// entityPersister keeps track of the structs already saved while persisting a single object, so that
+// pointers to the same struct map to the same node and cycles in the object graph terminate. The entities
+// are collected in changes, and only handed to the connection once the whole object graph has been mapped.
+type entityPersister struct {
+	conn    types.TGConnection
+	gmd     types.TGGraphMetadata
+	gof     types.TGGraphObjectFactory
+	saved   map[persistKey]types.TGNode
+	changes []persistChange
+}
+
+type persistChange struct {
+	entity types.TGEntity
+	isNew  bool
+}
+
+type persistKey struct {
+	address    uintptr
+	structType reflect.Type
+}
+
+// Persist stages the struct - or pointer to a struct - as a node of the node type declared w/ the 'nodetype'
+// option, along w/ the edges and nodes reachable through its 'edges' fields. A node whose primary key attributes
+// match an existing node is updated, any other node is inserted. The changes are sent to the server on the next
+// commit of the connection. Nothing is staged if the object graph cannot be mapped, and the entities staged
+// before a failure to stage one of them are removed again.
+func Persist(conn types.TGConnection, object interface{}) (types.TGNode, types.TGError) {
+	value := reflect.ValueOf(object)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		errMsg := fmt.Sprintf("StructMapper:Persist requires a struct or a non-nil pointer to a struct and not '%T'", object)
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+	}
+	if !value.CanAddr() {
+		// Struct values are copied to have an address to track them by
+		ptr := reflect.New(value.Type())
+		ptr.Elem().Set(value)
+		value = ptr.Elem()
+	}
+	gmd, err := conn.GetGraphMetadata(false)
+	if err != nil {
+		return nil, err
+	}
+	gof, err := conn.GetGraphObjectFactory()
+	if err != nil {
+		return nil, err
+	}
+	persister := &entityPersister{conn: conn, gmd: gmd, gof: gof, saved: make(map[persistKey]types.TGNode, 0)}
+	node, err := persister.persistNode(value)
+	if err == nil {
+		err = persister.stageChanges()
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning StructMapper:Persist w/ error: '%s'", err.Error()))
+		return nil, err
+	}
+	return node, nil
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for EntityPersister
+/////////////////////////////////////////////////////////////////
+
+// persistNode stages the addressable struct value as a node, and then its edges
+func (obj *entityPersister) persistNode(value reflect.Value) (types.TGNode, types.TGError) {
+	key := persistKey{address: value.Addr().Pointer(), structType: value.Type()}
+	if node, ok := obj.saved[key]; ok {
+		return node, nil
+	}
+	mapping, err := GetStructMapping(value.Type())
+	if err != nil {
+		return nil, err
+	}
+	if mapping.GetNodeTypeName() == "" {
+		errMsg := fmt.Sprintf("StructMapper:Persist requires struct '%s' to declare its node type w/ the '%s' option", value.Type().String(), TagOptionNodeType)
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+	}
+	nodeType, err := obj.gmd.GetNodeType(mapping.GetNodeTypeName())
+	if err != nil {
+		return nil, err
+	}
+	if isNilEntity(nodeType) {
+		errMsg := fmt.Sprintf("StructMapper:Persist unable to find node type '%s'", mapping.GetNodeTypeName())
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+	}
+
+	node, err := obj.findNode(mapping, nodeType, value)
+	if err != nil {
+		return nil, err
+	}
+	isNew := node == nil
+	if isNew {
+		node, err = obj.gof.CreateNodeInGraph(nodeType)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Register the node before following its edges, which may lead back to it
+	obj.saved[key] = node
+
+	pKeys := make(map[string]bool, 0)
+	if !isNew {
+		for _, pKey := range nodeType.GetPKeyAttributeDescriptors() {
+			pKeys[pKey.GetName()] = true
+		}
+	}
+	for _, field := range mapping.GetFields() {
+		if field.GetKind() != FieldKindAttribute || pKeys[field.GetName()] {
+			continue
+		}
+		err = obj.setAttribute(node, nodeType, field, field.GetValue(value))
+		if err != nil {
+			return nil, err
+		}
+	}
+	obj.changes = append(obj.changes, persistChange{entity: node, isNew: isNew})
+
+	for _, field := range mapping.GetFields() {
+		if field.GetKind() != FieldKindEdges {
+			continue
+		}
+		err = obj.persistEdges(node, field, field.GetValue(value))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// findNode looks up the existing node w/ the primary key values of the struct, if the node type has a primary key
+func (obj *entityPersister) findNode(mapping *StructMapping, nodeType types.TGNodeType, value reflect.Value) (types.TGNode, types.TGError) {
+	pKeys := nodeType.GetPKeyAttributeDescriptors()
+	if len(pKeys) == 0 {
+		return nil, nil
+	}
+	key, err := obj.gof.CreateCompositeKey(nodeType.GetName())
+	if err != nil {
+		return nil, err
+	}
+	for _, pKey := range pKeys {
+		field := mapping.GetField(pKey.GetName())
+		if field == nil {
+			errMsg := fmt.Sprintf("StructMapper:Persist requires struct '%s' to map primary key attribute '%s'", value.Type().String(), pKey.GetName())
+			return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+		}
+		keyValue, err := AttributeValueOf(pKey.GetAttrType(), field.GetValue(value))
+		if err != nil {
+			return nil, err
+		}
+		if keyValue == nil {
+			errMsg := fmt.Sprintf("StructMapper:Persist requires a value for primary key attribute '%s' in field '%s'", pKey.GetName(), field.GetFieldName())
+			return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+		}
+		err = key.SetOrCreateAttribute(pKey.GetName(), keyValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+	entity, err := obj.conn.GetEntity(key, nil)
+	if err != nil {
+		return nil, err
+	}
+	if isNilEntity(entity) {
+		return nil, nil
+	}
+	node, ok := entity.(types.TGNode)
+	if !ok {
+		errMsg := fmt.Sprintf("StructMapper:Persist expected a node for the primary key of '%s' and not '%T'", nodeType.GetName(), entity)
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+	}
+	return node, nil
+}
+
+// persistEdges stages an edge - along w/ the node at its other end - for each element of an edges field
+func (obj *entityPersister) persistEdges(node types.TGNode, field *FieldMapping, value reflect.Value) types.TGError {
+	if value.Kind() != reflect.Slice {
+		return obj.persistEdge(node, field, value)
+	}
+	for i := 0; i < value.Len(); i++ {
+		err := obj.persistEdge(node, field, value.Index(i))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (obj *entityPersister) persistEdge(node types.TGNode, field *FieldMapping, value reflect.Value) types.TGError {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	mapping, err := GetStructMapping(value.Type())
+	if err != nil {
+		return err
+	}
+	// The node owning the field is the 'from' vertex, unless the field is restricted to inbound edges
+	otherKind := FieldKindToNode
+	if field.GetDirection() == types.DirectionInbound {
+		otherKind = FieldKindFromNode
+	}
+	var other reflect.Value
+	for _, f := range mapping.GetFields() {
+		if f.GetKind() == otherKind {
+			other = f.GetValue(value)
+		}
+	}
+	if other.IsValid() && other.Kind() == reflect.Ptr && !other.IsNil() {
+		other = other.Elem()
+	}
+	if !other.IsValid() || other.Kind() != reflect.Struct {
+		errMsg := fmt.Sprintf("StructMapper:Persist requires the edges of field '%s' to refer to the '%s' node", field.GetFieldName(), otherKind.String())
+		return exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+	}
+	otherNode, err := obj.persistNode(other)
+	if err != nil {
+		return err
+	}
+	fromNode, toNode := node, otherNode
+	if otherKind == FieldKindFromNode {
+		fromNode, toNode = otherNode, node
+	}
+
+	var edgeType types.TGEdgeType
+	if field.GetName() != "" {
+		edgeType, err = obj.gmd.GetEdgeType(field.GetName())
+		if err != nil {
+			return err
+		}
+		if isNilEntity(edgeType) {
+			errMsg := fmt.Sprintf("StructMapper:Persist unable to find edge type '%s'", field.GetName())
+			return exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+		}
+	}
+	edge := findEdge(fromNode, toNode, field.GetName())
+	isNew := edge == nil
+	if isNew {
+		if edgeType != nil {
+			edge, err = obj.gof.CreateEdgeWithEdgeType(fromNode, toNode, edgeType)
+		} else {
+			edge, err = obj.gof.CreateEdgeWithDirection(fromNode, toNode, types.DirectionTypeDirected)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, f := range mapping.GetFields() {
+		if f.GetKind() != FieldKindAttribute {
+			continue
+		}
+		err = obj.setAttribute(edge, edgeType, f, f.GetValue(value))
+		if err != nil {
+			return err
+		}
+	}
+	obj.changes = append(obj.changes, persistChange{entity: edge, isNew: isNew})
+	return nil
+}
+
+// stageChanges inserts or updates the mapped entities in the connection. Should that fail, the entities that
+// were not pending on the connection before are removed from its added and changed lists again.
+func (obj *entityPersister) stageChanges() types.TGError {
+	addedList := obj.conn.GetAddedList()
+	changedList := obj.conn.GetChangedList()
+	staged := make([]persistChange, 0)
+	for _, change := range obj.changes {
+		var err types.TGError
+		var wasPending bool
+		if change.isNew {
+			_, wasPending = addedList[change.entity.GetVirtualId()]
+			err = obj.conn.InsertEntity(change.entity)
+		} else {
+			_, wasPending = changedList[change.entity.GetVirtualId()]
+			err = obj.conn.UpdateEntity(change.entity)
+		}
+		if err != nil {
+			for _, done := range staged {
+				if done.isNew {
+					delete(addedList, done.entity.GetVirtualId())
+				} else {
+					delete(changedList, done.entity.GetVirtualId())
+				}
+			}
+			return err
+		}
+		if !wasPending {
+			staged = append(staged, change)
+		}
+	}
+	return nil
+}
+
+// setAttribute sets the attribute of the entity from the field, clearing an existing attribute for a nil field
+func (obj *entityPersister) setAttribute(entity types.TGEntity, entityType types.TGEntityType, field *FieldMapping, value reflect.Value) types.TGError {
+	attrType := types.AttributeTypeInvalid
+	isArray := false
+	var attrDesc types.TGAttributeDescriptor
+	if !isNilEntity(entityType) {
+		attrDesc = entityType.GetAttributeDescriptor(field.GetName())
+	}
+	if isNilEntity(attrDesc) {
+		attrDesc, _ = obj.gmd.GetAttributeDescriptor(field.GetName())
+	}
+	if !isNilEntity(attrDesc) {
+		attrType = attrDesc.GetAttrType()
+		isArray = attrDesc.IsAttributeArray()
+	}
+	var attrValue interface{}
+	var err types.TGError
+	if isArray {
+		attrValue, err = AttributeArrayValueOf(attrType, value)
+	} else {
+		attrValue, err = AttributeValueOf(attrType, value)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("StructMapper:Persist unable to map field '%s' into attribute '%s'", field.GetFieldName(), field.GetName())
+		return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, err.Error())
+	}
+	if attrValue == nil {
+		if attr := entity.GetAttribute(field.GetName()); attr != nil && !attr.IsNull() {
+			return attr.SetValue(nil)
+		}
+		return nil
+	}
+	return entity.SetOrCreateAttribute(field.GetName(), attrValue)
+}
+
+// findEdge returns the edge of the edge type already connecting the nodes, if any
+func findEdge(fromNode, toNode types.TGNode, edgeTypeName string) types.TGEdge {
+	for _, edge := range fromNode.GetEdges() {
+		if isNilEntity(edge) {
+			continue
+		}
+		if edgeTypeName != "" && (isNilEntity(edge.GetEntityType()) || edge.GetEntityType().GetName() != edgeTypeName) {
+			continue
+		}
+		vertices := edge.GetVertices()
+		if len(vertices) > 1 && !isNilEntity(vertices[0]) && !isNilEntity(vertices[1]) &&
+			vertices[0].GetVirtualId() == fromNode.GetVirtualId() && vertices[1].GetVirtualId() == toNode.GetVirtualId() {
+			return edge
+		}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: EntityPersister_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package mapper
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"reflect"
+	"testing"
+)
+
+type testMember struct {
+	_       struct{}     `tgdb:"Member,nodetype"`
+	Name    string       `tgdb:"name"`
+	Age     int          `tgdb:"age"`
+	Friends []*testKnows `tgdb:"knows,edges,out"`
+}
+
+type testKnows struct {
+	Since  int64       `tgdb:"since"`
+	Friend *testMember `tgdb:",to"`
+}
+
+// persistConnection stages entities in memory, and returns the existing node - if any - for every key lookup.
+// The insert #failInsert fails, if set.
+type persistConnection struct {
+	types.TGConnection
+	gof        *model.GraphObjectFactory
+	existing   types.TGNode
+	inserted   []types.TGEntity
+	updated    []types.TGEntity
+	added      map[int64]types.TGEntity
+	changed    map[int64]types.TGEntity
+	failInsert int
+}
+
+func newPersistConnection() *persistConnection {
+	conn := &persistConnection{added: make(map[int64]types.TGEntity, 0), changed: make(map[int64]types.TGEntity, 0)}
+	conn.gof = model.NewGraphObjectFactory(conn)
+	gmd := conn.gof.GetGraphMetaData()
+	name := model.NewAttributeDescriptorWithType("name", types.AttributeTypeString)
+	age := model.NewAttributeDescriptorWithType("age", types.AttributeTypeInteger)
+	since := model.NewAttributeDescriptorWithType("since", types.AttributeTypeLong)
+	gmd.SetAttributeDescriptors(map[string]types.TGAttributeDescriptor{"name": name, "age": age, "since": since})
+	nodeType := model.NewNodeType("Member", nil)
+	nodeType.SetAttributeMap(map[string]*model.AttributeDescriptor{"name": name, "age": age})
+	nodeType.SetPKeyAttributeDescriptors([]*model.AttributeDescriptor{name})
+	gmd.SetNodeTypes(map[string]types.TGNodeType{"Member": nodeType})
+	edgeType := model.NewEdgeType("knows", types.DirectionTypeDirected, nil)
+	gmd.SetEdgeTypes(map[string]types.TGEdgeType{"knows": edgeType})
+	return conn
+}
+
+func (obj *persistConnection) GetEntity(key types.TGKey, options types.TGQueryOption) (types.TGEntity, types.TGError) {
+	if obj.existing == nil {
+		return nil, nil
+	}
+	return obj.existing, nil
+}
+
+func (obj *persistConnection) GetGraphMetadata(refresh bool) (types.TGGraphMetadata, types.TGError) {
+	return obj.gof.GetGraphMetaData(), nil
+}
+
+func (obj *persistConnection) GetGraphObjectFactory() (types.TGGraphObjectFactory, types.TGError) {
+	return obj.gof, nil
+}
+
+func (obj *persistConnection) GetAddedList() map[int64]types.TGEntity {
+	return obj.added
+}
+
+func (obj *persistConnection) GetChangedList() map[int64]types.TGEntity {
+	return obj.changed
+}
+
+func (obj *persistConnection) InsertEntity(entity types.TGEntity) types.TGError {
+	if obj.failInsert > 0 && len(obj.inserted)+1 == obj.failInsert {
+		return exception.GetErrorByType(types.TGErrorGeneralException, "", "Test insert failure", "")
+	}
+	obj.inserted = append(obj.inserted, entity)
+	obj.added[entity.GetVirtualId()] = entity
+	return nil
+}
+
+func (obj *persistConnection) UpdateEntity(entity types.TGEntity) types.TGError {
+	obj.updated = append(obj.updated, entity)
+	obj.changed[entity.GetVirtualId()] = entity
+	return nil
+}
+
+func TestPersistNewNodesAndEdges(t *testing.T) {
+	conn := newPersistConnection()
+	alice := &testMember{Name: "Alice", Age: 42}
+	bob := &testMember{Name: "Bob", Age: 7, Friends: []*testKnows{{Since: 2001, Friend: alice}}}
+	alice.Friends = []*testKnows{{Since: 2001, Friend: bob}}
+	node, err := Persist(conn, alice)
+	if err != nil {
+		t.Fatalf("TestPersistNewNodesAndEdges unable to persist w/ error '%s'", err.Error())
+	}
+	if len(conn.inserted) != 4 || len(conn.updated) != 0 {
+		t.Fatalf("TestPersistNewNodesAndEdges expected 2 nodes and 2 edges to be inserted and not '%d' ('%d' updated)", len(conn.inserted), len(conn.updated))
+	}
+	if node.GetAttribute("name").GetValue() != "Alice" || node.GetAttribute("age").GetValue() != 42 {
+		t.Errorf("TestPersistNewNodesAndEdges unexpected attributes '%+v'", node.GetAttribute("age").GetValue())
+	}
+	edges := node.GetEdges()
+	if len(edges) != 2 {
+		t.Fatalf("TestPersistNewNodesAndEdges expected an outgoing and an incoming edge and not '%d'", len(edges))
+	}
+	out := edges[0]
+	if out.GetVertices()[0] != node {
+		out = edges[1]
+	}
+	if out.GetEntityType().GetName() != "knows" || out.GetAttribute("since").GetValue() != int64(2001) ||
+		out.GetVertices()[1].GetAttribute("name").GetValue() != "Bob" {
+		t.Errorf("TestPersistNewNodesAndEdges unexpected edge '%+v'", out)
+	}
+}
+
+func TestPersistUpdatesExistingNode(t *testing.T) {
+	conn := newPersistConnection()
+	nodeType, _ := conn.gof.GetGraphMetaData().GetNodeType("Member")
+	existing, _ := conn.gof.CreateNodeInGraph(nodeType)
+	existing.SetOrCreateAttribute("name", "Alice")
+	existing.SetOrCreateAttribute("age", 41)
+	conn.existing = existing
+	node, err := Persist(conn, testMember{Name: "Alice", Age: 42})
+	if err != nil {
+		t.Fatalf("TestPersistUpdatesExistingNode unable to persist w/ error '%s'", err.Error())
+	}
+	if node != existing || len(conn.updated) != 1 || len(conn.inserted) != 0 {
+		t.Fatalf("TestPersistUpdatesExistingNode expected the existing node to be updated")
+	}
+	if node.GetAttribute("age").GetValue() != 42 {
+		t.Errorf("TestPersistUpdatesExistingNode expected age 42 and not '%+v'", node.GetAttribute("age").GetValue())
+	}
+
+	_, err = Persist(conn, &testKnows{Since: 1})
+	if err == nil {
+		t.Errorf("TestPersistUpdatesExistingNode expected an error for a struct w/o node type")
+	}
+}
+
+func TestPersistStagesNothingOnMappingError(t *testing.T) {
+	conn := newPersistConnection()
+	// The edge to Bob is mapped after Alice, and lacks the node at its other end
+	alice := &testMember{Name: "Alice", Age: 42, Friends: []*testKnows{{Since: 2001}}}
+	if _, err := Persist(conn, alice); err == nil {
+		t.Fatalf("TestPersistStagesNothingOnMappingError expected an error for the edge w/o a node")
+	}
+	if len(conn.inserted) != 0 || len(conn.updated) != 0 {
+		t.Errorf("TestPersistStagesNothingOnMappingError expected nothing to be staged and not '%d' inserts and '%d' updates", len(conn.inserted), len(conn.updated))
+	}
+}
+
+func TestPersistRollsBackOnStagingError(t *testing.T) {
+	conn := newPersistConnection()
+	pending, _ := conn.gof.CreateNodeInGraph(nil)
+	conn.added[pending.GetVirtualId()] = pending
+	bob := &testMember{Name: "Bob", Age: 7}
+	alice := &testMember{Name: "Alice", Age: 42, Friends: []*testKnows{{Since: 2001, Friend: bob}}}
+	conn.failInsert = 3
+	if _, err := Persist(conn, alice); err == nil {
+		t.Fatalf("TestPersistRollsBackOnStagingError expected the failing insert to be reported")
+	}
+	if len(conn.added) != 1 || conn.added[pending.GetVirtualId()] != pending {
+		t.Errorf("TestPersistRollsBackOnStagingError expected only the change pending before to be left and not '%+v'", conn.added)
+	}
+}
+
+func TestAttributeValueOf(t *testing.T) {
+	var nilName *string
+	if v, err := AttributeValueOf(types.AttributeTypeShort, reflect.ValueOf(int64(70000))); err == nil {
+		t.Errorf("TestAttributeValueOf expected an overflow error for a short and not '%+v'", v)
+	}
+	if v, err := AttributeValueOf(types.AttributeTypeInteger, reflect.ValueOf(uint8(7))); err != nil || v != 7 {
+		t.Errorf("TestAttributeValueOf expected 7 and not '%+v' w/ error '%+v'", v, err)
+	}
+	if v, err := AttributeValueOf(types.AttributeTypeNumber, reflect.ValueOf(12.5)); err != nil || v != "12.5" {
+		t.Errorf("TestAttributeValueOf expected '12.5' and not '%+v' w/ error '%+v'", v, err)
+	}
+	if v, err := AttributeValueOf(types.AttributeTypeChar, reflect.ValueOf("Z")); err != nil || v != 'Z' {
+		t.Errorf("TestAttributeValueOf expected 'Z' and not '%+v' w/ error '%+v'", v, err)
+	}
+	if v, err := AttributeValueOf(types.AttributeTypeString, reflect.ValueOf(nilName)); err != nil || v != nil {
+		t.Errorf("TestAttributeValueOf expected nil and not '%+v' w/ error '%+v'", v, err)
+	}
+	if v, err := AttributeValueOf(types.AttributeTypeBoolean, reflect.ValueOf("true")); err == nil {
+		t.Errorf("TestAttributeValueOf expected a coercion error for a string into a boolean and not '%+v'", v)
+	}
+	if v, err := AttributeArrayValueOf(types.AttributeTypeLong, reflect.ValueOf([]int{1, 2})); err != nil || !reflect.DeepEqual(v, []interface{}{int64(1), int64(2)}) {
+		t.Errorf("TestAttributeValueOf expected [1 2] and not '%+v' w/ error '%+v'", v, err)
+	}
+}
@@ -0,0 +1,276 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: EntityScanner.go
+ * SVN id: $id: $
+ *
+ */
+
+package mapper
+
+import (
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"reflect"
+	"strings"
+)
+
+// entityScanner keeps track of the entities mapped while scanning a single entity, so that pointer fields
+// referring to the same entity share the same struct and cycles in the graph terminate
+type entityScanner struct {
+	scanned  map[scanKey]reflect.Value
+	visiting map[types.TGEntity]bool
+}
+
+type scanKey struct {
+	entity     types.TGEntity
+	structType reflect.Type
+}
+
+func newEntityScanner() *entityScanner {
+	return &entityScanner{
+		scanned:  make(map[scanKey]reflect.Value, 0),
+		visiting: make(map[types.TGEntity]bool, 0),
+	}
+}
+
+// Scan populates the struct pointed to by target from the attributes of the node or edge. Fields tagged as
+// 'edges' are populated from the edges of a node, and fields tagged as 'from' / 'to' from the vertices of an edge.
+func Scan(entity types.TGEntity, target interface{}) types.TGError {
+	ptr := reflect.ValueOf(target)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Struct {
+		errMsg := fmt.Sprintf("StructMapper:Scan requires a non-nil pointer to a struct and not '%T'", target)
+		return exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+	}
+	if isNilEntity(entity) {
+		errMsg := "StructMapper:Scan requires an entity to scan from"
+		return exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+	}
+	return newEntityScanner().scanPointer(entity, ptr)
+}
+
+// ScanAll replaces the content of the slice pointed to by target - a slice of structs or of pointers to structs -
+// w/ the entities of the result set. It stops at the first entry that cannot be read or mapped, and returns that
+// error along w/ the entries mapped so far. The result set is closed once scanned.
+func ScanAll(rs types.TGResultSet, target interface{}) types.TGError {
+	ptr := reflect.ValueOf(target)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Slice ||
+		indirectType(ptr.Elem().Type().Elem()).Kind() != reflect.Struct {
+		errMsg := fmt.Sprintf("StructMapper:ScanAll requires a non-nil pointer to a slice of structs and not '%T'", target)
+		return exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+	}
+	slice := ptr.Elem()
+	slice.SetLen(0)
+	if rs == nil {
+		return nil
+	}
+	defer rs.Close()
+	elemType := slice.Type().Elem()
+	for entity, err := range rs.All() {
+		if err != nil {
+			logger.Error(fmt.Sprintf("ERROR: Returning StructMapper:ScanAll after '%d' entries w/ error: '%s'", slice.Len(), err.Error()))
+			if tgErr, ok := err.(types.TGError); ok {
+				return tgErr
+			}
+			return exception.GetErrorByType(types.TGErrorGeneralException, "", err.Error(), "")
+		}
+		elem := reflect.New(indirectType(elemType))
+		scanErr := newEntityScanner().scanPointer(entity, elem)
+		if scanErr != nil {
+			logger.Error(fmt.Sprintf("ERROR: Returning StructMapper:ScanAll after '%d' entries w/ error: '%s'", slice.Len(), scanErr.Error()))
+			return scanErr
+		}
+		if elemType.Kind() == reflect.Ptr {
+			slice.Set(reflect.Append(slice, elem))
+		} else {
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+	}
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for EntityScanner
+/////////////////////////////////////////////////////////////////
+
+// scanPointer populates the struct the pointer refers to, and remembers it for the entity
+func (obj *entityScanner) scanPointer(entity types.TGEntity, ptr reflect.Value) types.TGError {
+	obj.scanned[scanKey{entity: entity, structType: ptr.Elem().Type()}] = ptr
+	return obj.scanStruct(entity, ptr.Elem())
+}
+
+func (obj *entityScanner) scanStruct(entity types.TGEntity, target reflect.Value) types.TGError {
+	mapping, err := GetStructMapping(target.Type())
+	if err != nil {
+		return err
+	}
+	obj.visiting[entity] = true
+	defer delete(obj.visiting, entity)
+
+	for _, field := range mapping.GetFields() {
+		value := field.GetValue(target)
+		var fErr types.TGError
+		switch field.GetKind() {
+		case FieldKindAttribute:
+			fErr = scanAttribute(entity, field, value)
+		case FieldKindEdges:
+			fErr = obj.scanEdges(entity, field, value)
+		case FieldKindFromNode, FieldKindToNode:
+			fErr = obj.scanVertex(entity, field, value)
+		}
+		if fErr != nil {
+			return fErr
+		}
+	}
+	return nil
+}
+
+func scanAttribute(entity types.TGEntity, field *FieldMapping, value reflect.Value) types.TGError {
+	attr := findAttribute(entity, field.GetName())
+	if attr == nil || attr.IsNull() {
+		value.Set(reflect.Zero(value.Type()))
+		return nil
+	}
+	attrType := types.AttributeTypeInvalid
+	if attr.GetAttributeDescriptor() != nil {
+		attrType = attr.GetAttributeDescriptor().GetAttrType()
+	}
+	err := AssignAttributeValue(attrType, attr.GetValue(), value)
+	if err != nil {
+		errMsg := fmt.Sprintf("StructMapper:Scan unable to map attribute '%s' into field '%s'", field.GetName(), field.GetFieldName())
+		return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, err.Error())
+	}
+	return nil
+}
+
+// scanEdges populates a struct, pointer or slice field from the matching edges of a node
+func (obj *entityScanner) scanEdges(entity types.TGEntity, field *FieldMapping, value reflect.Value) types.TGError {
+	node, ok := entity.(types.TGNode)
+	if !ok {
+		value.Set(reflect.Zero(value.Type()))
+		return nil
+	}
+	edges := make([]types.TGEdge, 0)
+	for _, edge := range node.GetEdges() {
+		if matchesEdge(node, edge, field) {
+			edges = append(edges, edge)
+		}
+	}
+	if value.Kind() != reflect.Slice {
+		if len(edges) == 0 {
+			value.Set(reflect.Zero(value.Type()))
+			return nil
+		}
+		return obj.scanRelated(edges[0], value)
+	}
+	slice := reflect.MakeSlice(value.Type(), 0, len(edges))
+	for _, edge := range edges {
+		elem := reflect.New(value.Type().Elem()).Elem()
+		err := obj.scanRelated(edge, elem)
+		if err != nil {
+			return err
+		}
+		slice = reflect.Append(slice, elem)
+	}
+	value.Set(slice)
+	return nil
+}
+
+// scanVertex populates a struct or pointer field from the vertex an edge starts from or points to
+func (obj *entityScanner) scanVertex(entity types.TGEntity, field *FieldMapping, value reflect.Value) types.TGError {
+	edge, ok := entity.(types.TGEdge)
+	if !ok {
+		value.Set(reflect.Zero(value.Type()))
+		return nil
+	}
+	vertices := edge.GetVertices()
+	var vertex types.TGNode
+	if field.GetKind() == FieldKindFromNode && len(vertices) > 0 {
+		vertex = vertices[0]
+	} else if field.GetKind() == FieldKindToNode && len(vertices) > 1 {
+		vertex = vertices[1]
+	}
+	if isNilEntity(vertex) {
+		value.Set(reflect.Zero(value.Type()))
+		return nil
+	}
+	return obj.scanRelated(vertex, value)
+}
+
+// scanRelated populates a struct or pointer to a struct from a related entity. Pointers to an entity already
+// being mapped share its struct, while struct values of such an entity are left empty to stop the recursion.
+func (obj *entityScanner) scanRelated(entity types.TGEntity, value reflect.Value) types.TGError {
+	if value.Kind() == reflect.Ptr {
+		if ptr, ok := obj.scanned[scanKey{entity: entity, structType: value.Type().Elem()}]; ok {
+			value.Set(ptr)
+			return nil
+		}
+		ptr := reflect.New(value.Type().Elem())
+		value.Set(ptr)
+		return obj.scanPointer(entity, ptr)
+	}
+	if obj.visiting[entity] {
+		value.Set(reflect.Zero(value.Type()))
+		return nil
+	}
+	return obj.scanStruct(entity, value)
+}
+
+// findAttribute looks the attribute up by name, ignoring case if there is no exact match
+func findAttribute(entity types.TGEntity, name string) types.TGAttribute {
+	attr := entity.GetAttribute(name)
+	if attr != nil {
+		return attr
+	}
+	attrs, err := entity.GetAttributes()
+	if err != nil {
+		return nil
+	}
+	for _, a := range attrs {
+		if strings.EqualFold(a.GetName(), name) {
+			return a
+		}
+	}
+	return nil
+}
+
+func matchesEdge(node types.TGNode, edge types.TGEdge, field *FieldMapping) bool {
+	if isNilEntity(edge) {
+		return false
+	}
+	if field.GetName() != "" {
+		edgeType := edge.GetEntityType()
+		if edgeType == nil || edgeType.GetName() != field.GetName() {
+			return false
+		}
+	}
+	vertices := edge.GetVertices()
+	switch field.GetDirection() {
+	case types.DirectionOutbound:
+		return len(vertices) > 0 && vertices[0] == node
+	case types.DirectionInbound:
+		return len(vertices) > 1 && vertices[1] == node
+	}
+	return true
+}
+
+// isNilEntity checks for both a nil interface and an interface holding a nil pointer
+func isNilEntity(entity interface{}) bool {
+	if entity == nil {
+		return true
+	}
+	value := reflect.ValueOf(entity)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
@@ -0,0 +1,167 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: EntityScanner_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package mapper
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"iter"
+	"reflect"
+	"testing"
+)
+
+type testFriendship struct {
+	Since  int64       `tgdb:"since"`
+	Friend *testPerson `tgdb:",to"`
+	Owner  *testPerson `tgdb:",from"`
+}
+
+type testPerson struct {
+	Name     string           `tgdb:"name"`
+	Age      int              `tgdb:"age"`
+	Nickname *string          `tgdb:"nickname"`
+	Rating   *float64         `tgdb:"rating"`
+	Ignored  string           `tgdb:"-"`
+	Friends  []testFriendship `tgdb:"knows,edges,out"`
+}
+
+func createTestNode(gmd *model.GraphMetadata, attrs map[string]interface{}, attrTypes map[string]int) *model.Node {
+	node := model.NewNode(gmd)
+	attrMap := make(map[string]types.TGAttribute, 0)
+	for name, value := range attrs {
+		attr, _ := model.CreateAttributeWithDesc(node, model.NewAttributeDescriptorWithType(name, attrTypes[name]), value)
+		attrMap[name] = attr
+	}
+	node.SetAttributes(attrMap)
+	return node
+}
+
+func createTestGraph() (*model.Node, *model.Node) {
+	gmd := model.NewGraphMetadata(nil)
+	attrTypes := map[string]int{"name": types.AttributeTypeString, "age": types.AttributeTypeInteger, "since": types.AttributeTypeLong}
+	alice := createTestNode(gmd, map[string]interface{}{"name": "Alice", "age": 42}, attrTypes)
+	bob := createTestNode(gmd, map[string]interface{}{"name": "Bob", "age": 7}, attrTypes)
+	edgeType := model.DefaultEdgeType()
+	edgeType.SetName("knows")
+	edge := model.NewEdgeWithEdgeType(gmd, alice, bob, edgeType)
+	since, _ := model.CreateAttributeWithDesc(edge, model.NewAttributeDescriptorWithType("since", types.AttributeTypeLong), int64(2001))
+	edge.SetAttributes(map[string]types.TGAttribute{"since": since})
+	alice.AddEdge(edge)
+	bob.AddEdge(edge)
+	return alice, bob
+}
+
+func TestScanNodeWithEdges(t *testing.T) {
+	alice, _ := createTestGraph()
+	var person testPerson
+	err := Scan(alice, &person)
+	if err != nil {
+		t.Fatalf("TestScanNodeWithEdges unable to scan w/ error '%s'", err.Error())
+	}
+	if person.Name != "Alice" || person.Age != 42 || person.Nickname != nil || person.Rating != nil {
+		t.Errorf("TestScanNodeWithEdges unexpected attributes '%+v'", person)
+	}
+	if len(person.Friends) != 1 || person.Friends[0].Since != 2001 || person.Friends[0].Friend == nil {
+		t.Fatalf("TestScanNodeWithEdges unexpected friends '%+v'", person.Friends)
+	}
+	bob := person.Friends[0].Friend
+	if bob.Name != "Bob" || len(bob.Friends) != 0 {
+		t.Errorf("TestScanNodeWithEdges unexpected friend '%+v'", bob)
+	}
+	if person.Friends[0].Owner == nil || person.Friends[0].Owner.Name != "Alice" {
+		t.Errorf("TestScanNodeWithEdges expected the edge to refer back to Alice and not '%+v'", person.Friends[0].Owner)
+	}
+}
+
+// testResultSet iterates over a fixed set of entities - the query package cannot be used, as it decodes Gremlin
+// results through the mapper
+type testResultSet struct {
+	types.TGResultSet
+	entities []types.TGEntity
+	closed   bool
+}
+
+func (obj *testResultSet) Close() types.TGResultSet {
+	obj.closed = true
+	return obj
+}
+
+func (obj *testResultSet) All() iter.Seq2[types.TGEntity, error] {
+	return func(yield func(types.TGEntity, error) bool) {
+		for _, entity := range obj.entities {
+			if !yield(entity, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestScanAll(t *testing.T) {
+	alice, bob := createTestGraph()
+	rs := &testResultSet{entities: []types.TGEntity{alice, bob}}
+	people := make([]*testPerson, 3)
+	err := ScanAll(rs, &people)
+	if err != nil {
+		t.Fatalf("TestScanAll unable to scan w/ error '%s'", err.Error())
+	}
+	if len(people) != 2 || people[0].Name != "Alice" || people[1].Name != "Bob" {
+		t.Errorf("TestScanAll unexpected people '%+v'", people)
+	}
+	if !rs.closed {
+		t.Errorf("TestScanAll expected the result set to be closed")
+	}
+}
+
+func TestAssignAttributeValue(t *testing.T) {
+	var i8 int8
+	var u16 uint16
+	var f32 float32
+	var r rune
+	var s string
+	var p *int64
+	if err := AssignAttributeValue(types.AttributeTypeInteger, 300, reflect.ValueOf(&i8).Elem()); err == nil {
+		t.Errorf("TestAssignAttributeValue expected an overflow error for int8")
+	}
+	if err := AssignAttributeValue(types.AttributeTypeShort, int16(300), reflect.ValueOf(&u16).Elem()); err != nil || u16 != 300 {
+		t.Errorf("TestAssignAttributeValue expected 300 and not '%d' w/ error '%+v'", u16, err)
+	}
+	if err := AssignAttributeValue(types.AttributeTypeString, "text", reflect.ValueOf(&f32).Elem()); err == nil {
+		t.Errorf("TestAssignAttributeValue expected a coercion error for string into float32")
+	}
+	if err := AssignAttributeValue(types.AttributeTypeChar, "Z", reflect.ValueOf(&r).Elem()); err != nil || r != 'Z' {
+		t.Errorf("TestAssignAttributeValue expected 'Z' and not '%c' w/ error '%+v'", r, err)
+	}
+	if err := AssignAttributeValue(types.AttributeTypeNumber, "12.50", reflect.ValueOf(&s).Elem()); err != nil || s != "12.50" {
+		t.Errorf("TestAssignAttributeValue expected '12.50' and not '%s' w/ error '%+v'", s, err)
+	}
+	if err := AssignAttributeValue(types.AttributeTypeNumber, "12.50", reflect.ValueOf(&u16).Elem()); err == nil {
+		t.Errorf("TestAssignAttributeValue expected a coercion error for a fractional number into uint16")
+	}
+	if err := AssignAttributeValue(types.AttributeTypeLong, int64(5), reflect.ValueOf(&p).Elem()); err != nil || p == nil || *p != 5 {
+		t.Errorf("TestAssignAttributeValue expected a pointer to 5 w/ error '%+v'", err)
+	}
+	if err := AssignAttributeValue(types.AttributeTypeLong, nil, reflect.ValueOf(&p).Elem()); err != nil || p != nil {
+		t.Errorf("TestAssignAttributeValue expected a nil pointer for a null value w/ error '%+v'", err)
+	}
+	var scores []int16
+	if err := AssignAttributeValue(types.AttributeTypeInteger, []interface{}{1, 2}, reflect.ValueOf(&scores).Elem()); err != nil || len(scores) != 2 || scores[1] != 2 {
+		t.Errorf("TestAssignAttributeValue expected [1 2] and not '%+v' w/ error '%+v'", scores, err)
+	}
+}
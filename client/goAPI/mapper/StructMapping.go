@@ -0,0 +1,298 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: StructMapping.go
+ * SVN id: $id: $
+ *
+ */
+
+package mapper
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/logging"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/utils"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+var logger = logging.DefaultTGLogManager().GetLogger()
+
+// The struct tag recognized by the mapper is of the form `tgdb:"name,option,..."`. The name is the attribute
+// name - or the edge type name for edge fields - and defaults to the field name. A name of "-" skips the field.
+const (
+	TagName = "tgdb"
+
+	// TagOptionEdges maps the edges of a node - of the edge type 'name', or of any type if the name is empty -
+	// into a struct, a pointer to a struct, or a slice of either. The struct is populated from the edge.
+	TagOptionEdges = "edges"
+	// TagOptionIn restricts an edges field to the edges pointing to the node
+	TagOptionIn = "in"
+	// TagOptionOut restricts an edges field to the edges starting from the node
+	TagOptionOut = "out"
+	// TagOptionFrom maps the node an edge starts from into a struct or a pointer to a struct
+	TagOptionFrom = "from"
+	// TagOptionTo maps the node an edge points to into a struct or a pointer to a struct
+	TagOptionTo = "to"
+	// TagOptionNodeType declares the node type of the struct, typically on a blank field: _ struct{} `tgdb:"Person,nodetype"`
+	TagOptionNodeType = "nodetype"
+)
+
+// ======= Kinds of mapped struct fields =======
+type FieldKind int
+
+const (
+	FieldKindAttribute FieldKind = iota
+	FieldKindEdges
+	FieldKindFromNode
+	FieldKindToNode
+)
+
+func (kind FieldKind) String() string {
+	switch kind {
+	case FieldKindAttribute:
+		return "Attribute"
+	case FieldKindEdges:
+		return "Edges"
+	case FieldKindFromNode:
+		return "FromNode"
+	case FieldKindToNode:
+		return "ToNode"
+	}
+	return ""
+}
+
+// FieldMapping describes how a single struct field maps to an attribute, to edges or to a vertex of an entity
+type FieldMapping struct {
+	fieldName string
+	index     []int
+	name      string
+	kind      FieldKind
+	direction types.TGDirection
+	fieldType reflect.Type
+}
+
+// StructMapping is the parsed set of tagged fields of a struct type
+type StructMapping struct {
+	structType   reflect.Type
+	nodeTypeName string
+	fields       []*FieldMapping
+}
+
+var structMappings sync.Map
+
+// GetStructMapping returns the - cached - mapping of the struct type
+func GetStructMapping(structType reflect.Type) (*StructMapping, types.TGError) {
+	if structType.Kind() != reflect.Struct {
+		errMsg := fmt.Sprintf("Type '%s' is not a struct and cannot be mapped to an entity", structType.String())
+		return nil, exception.GetErrorByType(types.TGErrorTypeNotSupported, "", errMsg, "")
+	}
+	if mapping, ok := structMappings.Load(structType); ok {
+		return mapping.(*StructMapping), nil
+	}
+	mapping := &StructMapping{structType: structType, fields: make([]*FieldMapping, 0)}
+	err := mapping.parseFields(structType, nil)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug(fmt.Sprintf("Inside StructMapper:GetStructMapping parsed mapping '%s'", mapping.String()))
+	actual, _ := structMappings.LoadOrStore(structType, mapping)
+	return actual.(*StructMapping), nil
+}
+
+/////////////////////////////////////////////////////////////////
+// Helper functions for FieldMapping
+/////////////////////////////////////////////////////////////////
+
+// GetDirection returns the direction of the edges to map, DirectionAny unless restricted by an 'in' or 'out' option
+func (obj *FieldMapping) GetDirection() types.TGDirection {
+	return obj.direction
+}
+
+func (obj *FieldMapping) GetFieldName() string {
+	return obj.fieldName
+}
+
+func (obj *FieldMapping) GetFieldType() reflect.Type {
+	return obj.fieldType
+}
+
+func (obj *FieldMapping) GetKind() FieldKind {
+	return obj.kind
+}
+
+// GetName returns the attribute name, or the edge type name for edges fields
+func (obj *FieldMapping) GetName() string {
+	return obj.name
+}
+
+// GetValue returns the field of the struct value
+func (obj *FieldMapping) GetValue(structValue reflect.Value) reflect.Value {
+	return structValue.FieldByIndex(obj.index)
+}
+
+func (obj *FieldMapping) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString("FieldMapping:{")
+	buffer.WriteString(fmt.Sprintf("FieldName: %s", obj.fieldName))
+	buffer.WriteString(fmt.Sprintf(", Name: %s", obj.name))
+	buffer.WriteString(fmt.Sprintf(", Kind: %s", obj.kind.String()))
+	buffer.WriteString(fmt.Sprintf(", Direction: %s", obj.direction.String()))
+	buffer.WriteString(fmt.Sprintf(", FieldType: %s", obj.fieldType.String()))
+	buffer.WriteString("}")
+	return buffer.String()
+}
+
+/////////////////////////////////////////////////////////////////
+// Helper functions for StructMapping
+/////////////////////////////////////////////////////////////////
+
+// GetField returns the mapping of the field w/ the attribute name, ignoring case
+func (obj *StructMapping) GetField(name string) *FieldMapping {
+	for _, field := range obj.fields {
+		if field.kind == FieldKindAttribute && strings.EqualFold(field.name, name) {
+			return field
+		}
+	}
+	return nil
+}
+
+func (obj *StructMapping) GetFields() []*FieldMapping {
+	return obj.fields
+}
+
+// GetNodeTypeName returns the name of the node type declared w/ the 'nodetype' option, if any
+func (obj *StructMapping) GetNodeTypeName() string {
+	return obj.nodeTypeName
+}
+
+func (obj *StructMapping) GetStructType() reflect.Type {
+	return obj.structType
+}
+
+func (obj *StructMapping) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString("StructMapping:{")
+	buffer.WriteString(fmt.Sprintf("StructType: %s", obj.structType.String()))
+	buffer.WriteString(fmt.Sprintf(", NodeTypeName: %s", obj.nodeTypeName))
+	buffer.WriteString(fmt.Sprintf(", Fields: %+v", obj.fields))
+	buffer.WriteString("}")
+	return buffer.String()
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for StructMapping
+/////////////////////////////////////////////////////////////////
+
+// parseFields adds the fields of the struct type, flattening untagged embedded structs
+func (obj *StructMapping) parseFields(structType reflect.Type, parentIndex []int) types.TGError {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		index := append(append(make([]int, 0, len(parentIndex)+1), parentIndex...), i)
+		tag, hasTag := field.Tag.Lookup(TagName)
+		if field.Anonymous && !hasTag && indirectType(field.Type).Kind() == reflect.Struct && !isValueStruct(indirectType(field.Type)) {
+			if field.Type.Kind() == reflect.Ptr {
+				// Embedded pointers would need to be allocated on the fly - not supported
+				continue
+			}
+			err := obj.parseFields(field.Type, index)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		if hasTagOption(parts[1:], TagOptionNodeType) {
+			obj.nodeTypeName = parts[0]
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if parts[0] == "-" {
+			continue
+		}
+		fieldMapping := &FieldMapping{
+			fieldName: field.Name,
+			index:     index,
+			name:      parts[0],
+			kind:      FieldKindAttribute,
+			direction: types.DirectionAny,
+			fieldType: field.Type,
+		}
+		for _, option := range parts[1:] {
+			switch strings.TrimSpace(option) {
+			case TagOptionEdges:
+				fieldMapping.kind = FieldKindEdges
+			case TagOptionFrom:
+				fieldMapping.kind = FieldKindFromNode
+			case TagOptionTo:
+				fieldMapping.kind = FieldKindToNode
+			case TagOptionIn:
+				fieldMapping.direction = types.DirectionInbound
+			case TagOptionOut:
+				fieldMapping.direction = types.DirectionOutbound
+			case "":
+			default:
+				errMsg := fmt.Sprintf("Unknown option '%s' in the '%s' tag of field '%s.%s'", option, TagName, structType.String(), field.Name)
+				return exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+			}
+		}
+		if fieldMapping.name == "" && fieldMapping.kind == FieldKindAttribute {
+			fieldMapping.name = field.Name
+		}
+		if fieldMapping.kind != FieldKindAttribute {
+			elemType := indirectType(field.Type)
+			if fieldMapping.kind == FieldKindEdges && field.Type.Kind() == reflect.Slice {
+				elemType = indirectType(field.Type.Elem())
+			}
+			if elemType.Kind() != reflect.Struct || isValueStruct(elemType) {
+				errMsg := fmt.Sprintf("Field '%s.%s' of type '%s' cannot hold %s", structType.String(), field.Name, field.Type.String(), fieldMapping.kind.String())
+				return exception.GetErrorByType(types.TGErrorTypeNotSupported, "", errMsg, "")
+			}
+		}
+		obj.fields = append(obj.fields, fieldMapping)
+	}
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for StructMapper
+/////////////////////////////////////////////////////////////////
+
+func hasTagOption(options []string, option string) bool {
+	for _, o := range options {
+		if strings.TrimSpace(o) == option {
+			return true
+		}
+	}
+	return false
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isValueStruct checks whether the struct type holds a single attribute value rather than an entity
+func isValueStruct(t reflect.Type) bool {
+	return t == reflect.TypeOf(time.Time{}) || t == reflect.TypeOf(utils.TGDecimal{})
+}
@@ -0,0 +1,287 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: Exporter.go
+ * SVN id: $id: $
+ *
+ */
+
+// Package metrics exposes client and server statistics in the Prometheus text format, e.g.
+//
+//	exporter := metrics.Handle(http.DefaultServeMux, "/metrics")
+//	exporter.AddPool("default", pool)
+//	go exporter.SampleServer(ctx, adminConn, 30*time.Second)
+//
+// The client-side metrics - requests, errors and latencies per verb, and reconnects - are collected from the
+// request path of all the channels once the exporter observes them. The server statistics are those of the
+// last sample taken through the admin connection.
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/admin"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/channel"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/logging"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+var logger = logging.DefaultTGLogManager().GetLogger()
+
+// ContentType is the media type of the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// requestStatistics are the statistics of the requests of a verb
+type requestStatistics struct {
+	requests uint64
+	errors   uint64
+	latency  *histogram
+}
+
+// activeConnectionsCounter is implemented by the connection pools that report their connections in use
+type activeConnectionsCounter interface {
+	GetNoOfActiveConnections() int
+}
+
+// Exporter collects the metrics, and serves them as an http.Handler
+type Exporter struct {
+	mutex            sync.Mutex
+	buckets          []float64
+	requests         map[string]*requestStatistics
+	reconnects       uint64
+	failedReconnects uint64
+	pools            map[string]types.TGConnectionPool
+	serverSample     *admin.StatisticsSample
+	serverUp         bool
+	serverErrors     uint64
+}
+
+func NewExporter() *Exporter {
+	return NewExporterWithBuckets(DefaultLatencyBuckets)
+}
+
+// NewExporterWithBuckets creates an exporter whose request duration histograms have the bucket bounds, in seconds
+func NewExporterWithBuckets(buckets []float64) *Exporter {
+	return &Exporter{
+		buckets:  sortedBounds(buckets),
+		requests: make(map[string]*requestStatistics),
+		pools:    make(map[string]types.TGConnectionPool),
+	}
+}
+
+// Handle creates an exporter observing the requests of all the channels, and registers it on the mux at the path
+func Handle(mux *http.ServeMux, path string) *Exporter {
+	exporter := NewExporter()
+	exporter.ObserveChannels()
+	mux.Handle(path, exporter)
+	return exporter
+}
+
+/////////////////////////////////////////////////////////////////
+// Helper functions for Exporter
+/////////////////////////////////////////////////////////////////
+
+// ObserveChannels makes the exporter the request observer of all the channels
+func (obj *Exporter) ObserveChannels() {
+	channel.SetRequestObserver(obj)
+}
+
+// AddPool exports the size and the connections in use of the pool, w/ the name as its 'pool' label
+func (obj *Exporter) AddPool(name string, pool types.TGConnectionPool) {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	obj.pools[name] = pool
+}
+
+// SampleServer samples the server statistics through the admin connection every interval until the context is
+// done, exporting the last sample. It blocks, and is meant to run on its own goroutine.
+func (obj *Exporter) SampleServer(ctx context.Context, conn admin.TGAdminConnection, interval time.Duration) {
+	admin.NewStatisticsSampler(conn).Run(ctx, interval, obj.recordSample)
+}
+
+// Write writes all the metrics in the Prometheus text format
+func (obj *Exporter) Write(out io.Writer) error {
+	writer := &textWriter{out: bufio.NewWriter(out)}
+	obj.mutex.Lock()
+	obj.writeRequests(writer)
+	obj.writePools(writer)
+	obj.writeServer(writer)
+	obj.mutex.Unlock()
+	return writer.out.Flush()
+}
+
+// ServeHTTP serves the metrics for a Prometheus scrape
+func (obj *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	if err := obj.Write(w); err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning Exporter:ServeHTTP - unable to write the metrics w/ error: '%s'", err.Error()))
+	}
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> channel.RequestObserver
+/////////////////////////////////////////////////////////////////
+
+// RequestCompleted counts the request of the verb, and its duration
+func (obj *Exporter) RequestCompleted(verbId int, duration time.Duration, err types.TGError) {
+	verb := channel.GetVerbName(verbId)
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	stats, ok := obj.requests[verb]
+	if !ok {
+		stats = &requestStatistics{latency: newHistogram(obj.buckets)}
+		obj.requests[verb] = stats
+	}
+	stats.requests++
+	if err != nil {
+		stats.errors++
+	}
+	stats.latency.observe(duration.Seconds())
+}
+
+// Reconnected counts the attempt to reconnect a channel
+func (obj *Exporter) Reconnected(url string, succeeded bool) {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	if succeeded {
+		obj.reconnects++
+	} else {
+		obj.failedReconnects++
+	}
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for Exporter
+/////////////////////////////////////////////////////////////////
+
+func (obj *Exporter) recordSample(sample *admin.StatisticsSample, err types.TGError) {
+	obj.mutex.Lock()
+	defer obj.mutex.Unlock()
+	if err != nil {
+		obj.serverUp = false
+		obj.serverErrors++
+		return
+	}
+	obj.serverUp = true
+	obj.serverSample = sample
+}
+
+func sortedKeys(keys []string) []string {
+	sort.Strings(keys)
+	return keys
+}
+
+func (obj *Exporter) writeRequests(writer *textWriter) {
+	verbs := make([]string, 0, len(obj.requests))
+	for verb := range obj.requests {
+		verbs = append(verbs, verb)
+	}
+	verbs = sortedKeys(verbs)
+
+	writer.header("tgdb_client_requests_total", typeCounter, "Requests sent to the server, by verb.")
+	for _, verb := range verbs {
+		writer.sample("tgdb_client_requests_total", float64(obj.requests[verb].requests), "verb", verb)
+	}
+	writer.header("tgdb_client_request_errors_total", typeCounter, "Requests that failed, by verb.")
+	for _, verb := range verbs {
+		writer.sample("tgdb_client_request_errors_total", float64(obj.requests[verb].errors), "verb", verb)
+	}
+	writer.header("tgdb_client_request_duration_seconds", typeHistogram, "Duration of the requests until their response, by verb.")
+	for _, verb := range verbs {
+		writer.histogram("tgdb_client_request_duration_seconds", obj.requests[verb].latency, "verb", verb)
+	}
+	writer.header("tgdb_client_reconnects_total", typeCounter, "Attempts of the channels to reconnect, by result.")
+	writer.sample("tgdb_client_reconnects_total", float64(obj.reconnects), "result", "success")
+	writer.sample("tgdb_client_reconnects_total", float64(obj.failedReconnects), "result", "failure")
+}
+
+func (obj *Exporter) writePools(writer *textWriter) {
+	if len(obj.pools) == 0 {
+		return
+	}
+	names := make([]string, 0, len(obj.pools))
+	for name := range obj.pools {
+		names = append(names, name)
+	}
+	names = sortedKeys(names)
+
+	writer.header("tgdb_pool_size", typeGauge, "Connections of the pool.")
+	for _, name := range names {
+		writer.sample("tgdb_pool_size", float64(obj.pools[name].GetPoolSize()), "pool", name)
+	}
+	writer.header("tgdb_pool_active_connections", typeGauge, "Connections of the pool in use.")
+	for _, name := range names {
+		if counter, ok := obj.pools[name].(activeConnectionsCounter); ok {
+			writer.sample("tgdb_pool_active_connections", float64(counter.GetNoOfActiveConnections()), "pool", name)
+		}
+	}
+}
+
+func (obj *Exporter) writeServer(writer *textWriter) {
+	writer.header("tgdb_server_up", typeGauge, "Whether the last sample of the server statistics succeeded.")
+	if obj.serverUp {
+		writer.sample("tgdb_server_up", 1)
+	} else {
+		writer.sample("tgdb_server_up", 0)
+	}
+	writer.metric("tgdb_server_sample_errors_total", typeCounter, "Samples of the server statistics that failed.", float64(obj.serverErrors))
+
+	sample := obj.serverSample
+	if sample == nil {
+		return
+	}
+	writer.metric("tgdb_server_last_sample_timestamp_seconds", typeGauge, "Time of the last sample of the server statistics.", float64(sample.Timestamp.UnixNano())/1e9)
+	if sample.Info != nil && !reflect.ValueOf(sample.Info).IsNil() {
+		if status := sample.Info.GetServerStatus(); status != nil && !reflect.ValueOf(status).IsNil() {
+			writer.metric("tgdb_server_uptime_seconds", typeGauge, "Uptime of the server.", status.GetUptime().Seconds())
+		}
+	}
+	if transactions := sample.Transactions; transactions != nil {
+		writer.metric("tgdb_server_transactions_processed_total", typeCounter, "Transactions processed by the server.", float64(transactions.GetTransactionProcessedCount()))
+		writer.metric("tgdb_server_transactions_successful_total", typeCounter, "Transactions processed successfully by the server.", float64(transactions.GetTransactionSuccessfulCount()))
+		writer.metric("tgdb_server_transactions_pending", typeGauge, "Transactions pending on the server.", float64(transactions.GetPendingTransactionsCount()))
+		writer.metric("tgdb_server_transaction_average_processing_time", typeGauge, "Average processing time of the transactions, as reported by the server.", transactions.GetAverageProcessingTime())
+		writer.metric("tgdb_server_transactions_per_second", typeGauge, "Transactions processed per second since the previous sample.", sample.TransactionsPerSecond)
+	}
+	if cache := sample.Cache; cache != nil {
+		writer.header("tgdb_server_cache_entries", typeGauge, "Entries of the server caches.")
+		writer.sample("tgdb_server_cache_entries", float64(cache.GetDataCacheEntries()), "cache", "data")
+		writer.sample("tgdb_server_cache_entries", float64(cache.GetIndexCacheEntries()), "cache", "index")
+		writer.header("tgdb_server_cache_hits_total", typeCounter, "Hits of the server caches.")
+		writer.sample("tgdb_server_cache_hits_total", float64(cache.GetDataCacheHits()), "cache", "data")
+		writer.sample("tgdb_server_cache_hits_total", float64(cache.GetIndexCacheHits()), "cache", "index")
+		writer.header("tgdb_server_cache_misses_total", typeCounter, "Misses of the server caches.")
+		writer.sample("tgdb_server_cache_misses_total", float64(cache.GetDataCacheMisses()), "cache", "data")
+		writer.sample("tgdb_server_cache_misses_total", float64(cache.GetIndexCacheMisses()), "cache", "index")
+		writer.header("tgdb_server_cache_hit_ratio", typeGauge, "Hit ratio of the server caches since the previous sample.")
+		writer.sample("tgdb_server_cache_hit_ratio", sample.DataCacheHitRatio, "cache", "data")
+		writer.sample("tgdb_server_cache_hit_ratio", sample.IndexCacheHitRatio, "cache", "index")
+	}
+	if database := sample.Database; database != nil {
+		writer.metric("tgdb_server_database_size_bytes", typeGauge, "Size of the database.", float64(database.GetDbSize()))
+		writer.header("tgdb_server_database_used_bytes", typeGauge, "Used space of the database segments.")
+		writer.sample("tgdb_server_database_used_bytes", float64(database.GetDataUsed()), "segment", "data")
+		writer.sample("tgdb_server_database_used_bytes", float64(database.GetIndexUsed()), "segment", "index")
+		writer.header("tgdb_server_database_free_bytes", typeGauge, "Free space of the database segments.")
+		writer.sample("tgdb_server_database_free_bytes", float64(database.GetDataFree()), "segment", "data")
+		writer.sample("tgdb_server_database_free_bytes", float64(database.GetIndexFree()), "segment", "index")
+	}
+}
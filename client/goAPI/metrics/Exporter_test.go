@@ -0,0 +1,145 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: Exporter_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package metrics
+
+import (
+	"bytes"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/admin"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/pdu"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixedPool reports a pool of 4 connections w/ 3 of them in use
+type fixedPool struct {
+	types.TGConnectionPool
+}
+
+func (obj *fixedPool) GetPoolSize() int {
+	return 4
+}
+
+func (obj *fixedPool) GetNoOfActiveConnections() int {
+	return 3
+}
+
+func exportedText(t *testing.T, exporter *Exporter) string {
+	var out bytes.Buffer
+	if err := exporter.Write(&out); err != nil {
+		t.Fatalf("Exporter Write failed w/ error: '%+v'", err)
+	}
+	return out.String()
+}
+
+func expectLines(t *testing.T, text string, lines ...string) {
+	for _, line := range lines {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("Exporter output is missing '%s' in:\n%s", line, text)
+		}
+	}
+}
+
+func TestExporterRequestMetrics(t *testing.T) {
+	exporter := NewExporterWithBuckets([]float64{0.1, 0.01})
+	exporter.RequestCompleted(pdu.VerbQueryRequest, 5*time.Millisecond, nil)
+	exporter.RequestCompleted(pdu.VerbQueryRequest, 50*time.Millisecond, nil)
+	exporter.RequestCompleted(pdu.VerbQueryRequest, time.Second, exception.NewTGGeneralExceptionWithMsg("failed"))
+	exporter.RequestCompleted(pdu.VerbCommitTransactionRequest, 20*time.Millisecond, nil)
+	exporter.Reconnected("tcp://localhost:8222", true)
+	exporter.Reconnected("tcp://localhost:8223", false)
+	exporter.Reconnected("tcp://localhost:8223", false)
+
+	text := exportedText(t, exporter)
+	expectLines(t, text,
+		"# TYPE tgdb_client_requests_total counter",
+		`tgdb_client_requests_total{verb="CommitTransactionRequest"} 1`,
+		`tgdb_client_requests_total{verb="QueryRequest"} 3`,
+		`tgdb_client_request_errors_total{verb="QueryRequest"} 1`,
+		"# TYPE tgdb_client_request_duration_seconds histogram",
+		`tgdb_client_request_duration_seconds_bucket{verb="QueryRequest",le="0.01"} 1`,
+		`tgdb_client_request_duration_seconds_bucket{verb="QueryRequest",le="0.1"} 2`,
+		`tgdb_client_request_duration_seconds_bucket{verb="QueryRequest",le="+Inf"} 3`,
+		`tgdb_client_request_duration_seconds_sum{verb="QueryRequest"} 1.055`,
+		`tgdb_client_request_duration_seconds_count{verb="QueryRequest"} 3`,
+		`tgdb_client_reconnects_total{result="success"} 1`,
+		`tgdb_client_reconnects_total{result="failure"} 2`,
+		"tgdb_server_up 0",
+	)
+	if strings.Index(text, `verb="CommitTransactionRequest"`) > strings.Index(text, `verb="QueryRequest"`) {
+		t.Errorf("Exporter did not sort the verbs in:\n%s", text)
+	}
+	if strings.Contains(text, "tgdb_pool_size") || strings.Contains(text, "tgdb_server_uptime_seconds") {
+		t.Errorf("Exporter exported pool or server metrics w/o pool or sample in:\n%s", text)
+	}
+}
+
+func TestExporterPoolAndServerMetrics(t *testing.T) {
+	exporter := NewExporter()
+	exporter.AddPool(`main "pool"`, &fixedPool{})
+
+	cache := admin.NewCacheStatisticsImpl(100, 10, 75, 25, 1024, 100, 10, 0, 0, 1024)
+	database := admin.NewDatabaseStatisticsImpl(4096, 1, 2048, 1000, 1048, 512, 1, 2048, 0, 2048, 512)
+	transactions := admin.NewTransactionStatisticsImpl(1.5, 0, 0, 4, 100, 90)
+	status := admin.NewServerStatusImpl("tgdb", nil, "42", admin.ServerStateStarted, 100*time.Second)
+	info := admin.NewServerInfoImpl(cache, database, nil, nil, status, transactions)
+	exporter.recordSample(&admin.StatisticsSample{
+		Timestamp:             time.Unix(1760000000, 0),
+		Info:                  info,
+		Cache:                 cache,
+		Database:              database,
+		Transactions:          transactions,
+		TransactionsPerSecond: 1,
+		DataCacheHitRatio:     0.75,
+	}, nil)
+	exporter.recordSample(nil, exception.NewTGGeneralExceptionWithMsg("unreachable"))
+
+	text := exportedText(t, exporter)
+	expectLines(t, text,
+		`tgdb_pool_size{pool="main \"pool\""} 4`,
+		`tgdb_pool_active_connections{pool="main \"pool\""} 3`,
+		"tgdb_server_up 0",
+		"tgdb_server_sample_errors_total 1",
+		"tgdb_server_last_sample_timestamp_seconds 1.76e+09",
+		"tgdb_server_uptime_seconds 100",
+		"tgdb_server_transactions_processed_total 100",
+		"tgdb_server_transactions_successful_total 90",
+		`tgdb_server_cache_hit_ratio{cache="data"} 0.75`,
+		`tgdb_server_database_used_bytes{segment="data"} 1000`,
+	)
+}
+
+func TestExporterServeHTTP(t *testing.T) {
+	mux := http.NewServeMux()
+	exporter := NewExporter()
+	mux.Handle("/metrics", exporter)
+	exporter.RequestCompleted(pdu.VerbMetadataRequest, time.Millisecond, nil)
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Type") != ContentType {
+		t.Fatalf("Exporter served status '%d' w/ content type '%s'", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+	expectLines(t, recorder.Body.String(), `tgdb_client_requests_total{verb="MetadataRequest"} 1`)
+}
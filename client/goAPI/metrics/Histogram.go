@@ -0,0 +1,62 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: Histogram.go
+ * SVN id: $id: $
+ *
+ */
+
+package metrics
+
+import "sort"
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the buckets of the request duration histograms
+var DefaultLatencyBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// histogram counts the observed values in buckets of increasing upper bounds, the last bucket being unbounded
+type histogram struct {
+	bounds []float64
+	counts []uint64 // Count of each bucket, not cumulative - the last one is for the values above all the bounds
+	sum    float64
+	count  uint64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{bounds: bounds, counts: make([]uint64, len(bounds)+1)}
+}
+
+// sortedBounds returns a sorted copy of the bucket bounds
+func sortedBounds(bounds []float64) []float64 {
+	sorted := append([]float64(nil), bounds...)
+	sort.Float64s(sorted)
+	return sorted
+}
+
+func (obj *histogram) observe(value float64) {
+	i := sort.SearchFloat64s(obj.bounds, value)
+	obj.counts[i]++
+	obj.sum += value
+	obj.count++
+}
+
+// cumulativeCounts returns the count of the values less than or equal to each bound, as exposed by Prometheus
+func (obj *histogram) cumulativeCounts() []uint64 {
+	cumulative := make([]uint64, len(obj.bounds))
+	var total uint64
+	for i := range obj.bounds {
+		total += obj.counts[i]
+		cumulative[i] = total
+	}
+	return cumulative
+}
@@ -0,0 +1,95 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: TextFormat.go
+ * SVN id: $id: $
+ *
+ */
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// textWriter writes metric families in the Prometheus text exposition format
+type textWriter struct {
+	out *bufio.Writer
+}
+
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+)
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// formatLabels renders the label pairs - name, value, name, value... - as '{name="value",...}'
+func formatLabels(labels ...string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labels[i], labelEscaper.Replace(labels[i+1])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func (obj *textWriter) header(name, metricType, help string) {
+	fmt.Fprintf(obj.out, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(obj.out, "# TYPE %s %s\n", name, metricType)
+}
+
+func (obj *textWriter) sample(name string, value float64, labels ...string) {
+	fmt.Fprintf(obj.out, "%s%s %s\n", name, formatLabels(labels...), formatFloat(value))
+}
+
+// metric writes a metric family w/ a single unlabeled sample
+func (obj *textWriter) metric(name, metricType, help string, value float64) {
+	obj.header(name, metricType, help)
+	obj.sample(name, value)
+}
+
+// withLabel returns a copy of the label pairs w/ one more pair
+func withLabel(labels []string, name, value string) []string {
+	return append(append(make([]string, 0, len(labels)+2), labels...), name, value)
+}
+
+// histogram writes the bucket, sum and count samples of a histogram
+func (obj *textWriter) histogram(name string, hist *histogram, labels ...string) {
+	for i, count := range hist.cumulativeCounts() {
+		obj.sample(name+"_bucket", float64(count), withLabel(labels, "le", formatFloat(hist.bounds[i]))...)
+	}
+	obj.sample(name+"_bucket", float64(hist.count), withLabel(labels, "le", "+Inf")...)
+	obj.sample(name+"_sum", hist.sum, labels...)
+	obj.sample(name+"_count", float64(hist.count), labels...)
+}
@@ -0,0 +1,304 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: ArrayAttribute.go
+ * SVN id: $id: $
+ *
+ */
+
+package model
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/iostream"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"reflect"
+	"strings"
+)
+
+// ArrayAttribute holds the value of an attribute whose descriptor is flagged as an array. The value is a
+// []interface{} of elements, each of which is coerced and serialized by the scalar attribute of the
+// descriptor's attribute type. On the wire, the element count (int) is followed by the element values, in
+// the format of the scalar attribute - this layout requires a server w/ array attribute support, older servers
+// do not accept array descriptors. Arrays cannot be encrypted, as the protocol has no encrypted array format.
+type ArrayAttribute struct {
+	*AbstractAttribute
+}
+
+// Create NewArrayAttribute Instance
+func DefaultArrayAttribute() *ArrayAttribute {
+	// We must register the concrete type for the encoder and decoder (which would
+	// normally be on a separate machine from the encoder). On each end, this tells the
+	// engine which concrete type is being sent that implements the interface.
+	gob.Register(ArrayAttribute{})
+
+	newAttribute := ArrayAttribute{
+		AbstractAttribute: defaultNewAbstractAttribute(),
+	}
+	return &newAttribute
+}
+
+func NewArrayAttributeWithOwner(ownerEntity types.TGEntity) *ArrayAttribute {
+	newAttribute := DefaultArrayAttribute()
+	newAttribute.owner = ownerEntity
+	return newAttribute
+}
+
+func NewArrayAttribute(attrDesc *AttributeDescriptor) *ArrayAttribute {
+	newAttribute := DefaultArrayAttribute()
+	newAttribute.attrDesc = attrDesc
+	return newAttribute
+}
+
+func NewArrayAttributeWithDesc(ownerEntity types.TGEntity, attrDesc *AttributeDescriptor, value interface{}) *ArrayAttribute {
+	newAttribute := NewArrayAttributeWithOwner(ownerEntity)
+	newAttribute.attrDesc = attrDesc
+	newAttribute.attrValue = value
+	return newAttribute
+}
+
+// checkArrayAttributeDescriptor rejects encrypted array descriptors, whose values would otherwise go over the wire in plain text
+func checkArrayAttributeDescriptor(attrDesc *AttributeDescriptor) types.TGError {
+	if attrDesc != nil && attrDesc.IsEncrypted() {
+		errMsg := fmt.Sprintf("Array attribute '%s' cannot be encrypted", attrDesc.GetName())
+		return exception.GetErrorByType(types.TGErrorTypeNotSupported, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////
+// Helper functions for ArrayAttribute
+/////////////////////////////////////////////////////////////////
+
+// GetElements returns the elements of the array, or nil if the attribute is null
+func (obj *ArrayAttribute) GetElements() []interface{} {
+	if obj.IsNull() {
+		return nil
+	}
+	return obj.attrValue.([]interface{})
+}
+
+// newElementAttribute creates the scalar attribute used to coerce, read and write a single element
+func (obj *ArrayAttribute) newElementAttribute(value interface{}) (types.TGAttribute, types.TGError) {
+	elementDesc := *obj.attrDesc
+	elementDesc.isArray = false
+	return CreateAttributeWithDesc(obj.owner, &elementDesc, value)
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> TGAttribute
+/////////////////////////////////////////////////////////////////
+
+// GetAttributeDescriptor returns the AttributeDescriptor for this attribute
+func (obj *ArrayAttribute) GetAttributeDescriptor() types.TGAttributeDescriptor {
+	return obj.getAttributeDescriptor()
+}
+
+// GetIsModified checks whether the attribute modified or not
+func (obj *ArrayAttribute) GetIsModified() bool {
+	return obj.getIsModified()
+}
+
+// GetName gets the name for this attribute as the most generic form
+func (obj *ArrayAttribute) GetName() string {
+	return obj.getName()
+}
+
+// GetOwner gets owner Entity of this attribute
+func (obj *ArrayAttribute) GetOwner() types.TGEntity {
+	return obj.getOwner()
+}
+
+// GetValue gets the value for this attribute as the most generic form
+func (obj *ArrayAttribute) GetValue() interface{} {
+	return obj.getValue()
+}
+
+// IsNull checks whether the attribute value is null or not
+func (obj *ArrayAttribute) IsNull() bool {
+	return obj.isNull()
+}
+
+// ResetIsModified resets the IsModified flag - recursively, if needed
+func (obj *ArrayAttribute) ResetIsModified() {
+	obj.resetIsModified()
+}
+
+// SetOwner sets the owner entity - Need this indirection to traverse the chain
+func (obj *ArrayAttribute) SetOwner(ownerEntity types.TGEntity) {
+	obj.setOwner(ownerEntity)
+}
+
+// SetValue sets the value for this attribute from a Go slice or array. Each element is converted the same way
+// as a scalar attribute of the descriptor's attribute type would convert it, and a null element is rejected.
+func (obj *ArrayAttribute) SetValue(value interface{}) types.TGError {
+	if value == nil {
+		obj.attrValue = value
+		obj.setIsModified(true)
+		return nil
+	}
+	slice := reflect.ValueOf(value)
+	if slice.Kind() != reflect.Slice && slice.Kind() != reflect.Array {
+		logger.Error(fmt.Sprint("ERROR: Returning ArrayAttribute:SetValue - attribute value is NOT a slice or an array"))
+		errMsg := fmt.Sprintf("Failure to cast the attribute value of type '%T' to ArrayAttribute '%s'", value, obj.GetName())
+		return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	elements := make([]interface{}, 0, slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		element := slice.Index(i).Interface()
+		if element == nil {
+			errMsg := fmt.Sprintf("Element '%d' of ArrayAttribute '%s' cannot be null", i, obj.GetName())
+			return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, types.INTERNAL_SERVER_ERROR, errMsg, "")
+		}
+		elementAttr, err := obj.newElementAttribute(nil)
+		if err != nil {
+			return err
+		}
+		err = elementAttr.SetValue(element)
+		if err != nil {
+			logger.Error(fmt.Sprintf("ERROR: Returning ArrayAttribute:SetValue - unable to set element '%d' w/ Error: '%s'", i, err.Error()))
+			errMsg := fmt.Sprintf("Failure to cast element '%d' of ArrayAttribute '%s' to '%s'", i, obj.GetName(), types.GetAttributeTypeFromId(obj.attrDesc.GetAttrType()).GetTypeName())
+			return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, types.INTERNAL_SERVER_ERROR, errMsg, err.Error())
+		}
+		elements = append(elements, elementAttr.GetValue())
+	}
+	obj.attrValue = elements
+	obj.setIsModified(true)
+	return nil
+}
+
+// ReadValue reads the element count, followed by the element values, from input stream
+func (obj *ArrayAttribute) ReadValue(is types.TGInputStream) types.TGError {
+	count, err := is.(*iostream.ProtocolDataInputStream).ReadInt()
+	if err != nil {
+		logger.Error(fmt.Sprint("ERROR: Returning ArrayAttribute:ReadValue - unable to read the element count"))
+		return err
+	}
+	if count < 0 {
+		errMsg := fmt.Sprintf("Invalid element count '%d' for ArrayAttribute '%s'", count, obj.GetName())
+		return exception.GetErrorByType(types.TGErrorIOException, types.TGDB_CLIENT_READEXTERNAL, errMsg, "")
+	}
+	elements := make([]interface{}, 0, count)
+	for i := 0; i < count; i++ {
+		elementAttr, err := obj.newElementAttribute(nil)
+		if err != nil {
+			return err
+		}
+		err = elementAttr.ReadValue(is)
+		if err != nil {
+			logger.Error(fmt.Sprintf("ERROR: Returning ArrayAttribute:ReadValue - unable to read element '%d' w/ Error: '%s'", i, err.Error()))
+			return err
+		}
+		elements = append(elements, elementAttr.GetValue())
+	}
+	logger.Log(fmt.Sprintf("Returning ArrayAttribute::ReadValue - read '%d' elements", count))
+	obj.attrValue = elements
+	return nil
+}
+
+// WriteValue writes the element count, followed by the element values, to output stream
+func (obj *ArrayAttribute) WriteValue(os types.TGOutputStream) types.TGError {
+	elements := obj.GetElements()
+	os.(*iostream.ProtocolDataOutputStream).WriteInt(len(elements))
+	for i, element := range elements {
+		elementAttr, err := obj.newElementAttribute(element)
+		if err != nil {
+			return err
+		}
+		err = elementAttr.WriteValue(os)
+		if err != nil {
+			logger.Error(fmt.Sprintf("ERROR: Returning ArrayAttribute:WriteValue - unable to write element '%d' w/ Error: '%s'", i, err.Error()))
+			return err
+		}
+	}
+	return nil
+}
+
+func (obj *ArrayAttribute) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString("ArrayAttribute:{")
+	strArray := []string{buffer.String(), obj.attributeToString() + "}"}
+	msgStr := strings.Join(strArray, ", ")
+	return msgStr
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> types.TGSerializable
+/////////////////////////////////////////////////////////////////
+
+// ReadExternal reads the byte format from an external input stream and constructs a system object
+func (obj *ArrayAttribute) ReadExternal(is types.TGInputStream) types.TGError {
+	// We have already read the AttributeId, so no need to read it.
+	isNull, err := is.(*iostream.ProtocolDataInputStream).ReadBoolean()
+	if err != nil {
+		logger.Error(fmt.Sprint("ERROR: Returning ArrayAttribute:ReadExternal w/ Error in reading isNull from message buffer"))
+		return err
+	}
+	if isNull {
+		obj.attrValue = nil
+		return nil
+	}
+	if err := checkArrayAttributeDescriptor(obj.attrDesc); err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning ArrayAttribute:ReadExternal w/ Error: '%s'", err.Error()))
+		return err
+	}
+	return obj.ReadValue(is)
+}
+
+// WriteExternal writes a system object into an appropriate byte format onto an external output stream
+func (obj *ArrayAttribute) WriteExternal(os types.TGOutputStream) types.TGError {
+	if err := checkArrayAttributeDescriptor(obj.attrDesc); err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning ArrayAttribute:WriteExternal w/ Error: '%s'", err.Error()))
+		return err
+	}
+	os.(*iostream.ProtocolDataOutputStream).WriteInt(int(obj.attrDesc.GetAttributeId()))
+	os.(*iostream.ProtocolDataOutputStream).WriteBoolean(obj.IsNull())
+	if obj.IsNull() {
+		return nil
+	}
+	return obj.WriteValue(os)
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> encoding/BinaryMarshaller
+/////////////////////////////////////////////////////////////////
+
+func (obj *ArrayAttribute) MarshalBinary() ([]byte, error) {
+	// A simple encoding: plain text.
+	var b bytes.Buffer
+	_, err := fmt.Fprintln(&b, obj.owner, obj.attrDesc, obj.attrValue, obj.isModified)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning ArrayAttribute:MarshalBinary w/ Error: '%+v'", err.Error()))
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> encoding/BinaryUnmarshaller
+/////////////////////////////////////////////////////////////////
+
+func (obj *ArrayAttribute) UnmarshalBinary(data []byte) error {
+	// A simple encoding: plain text.
+	b := bytes.NewBuffer(data)
+	_, err := fmt.Fscanln(b, &obj.owner, &obj.attrDesc, &obj.attrValue, &obj.isModified)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning ArrayAttribute:UnmarshalBinary w/ Error: '%+v'", err.Error()))
+		return err
+	}
+	return err
+}
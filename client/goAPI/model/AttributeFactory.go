@@ -84,6 +84,13 @@ func CreateAttributeByType(attrTypeId int) (types.TGAttribute, types.TGError) {
 
 // CreateAttribute creates a new attribute based on AttributeDescriptor
 func CreateAttribute(attrDesc *AttributeDescriptor) (types.TGAttribute, types.TGError) {
+	// Array attributes hold a list of values of the attribute type
+	if attrDesc.IsAttributeArray() {
+		if err := checkArrayAttributeDescriptor(attrDesc); err != nil {
+			return nil, err
+		}
+		return NewArrayAttribute(attrDesc), nil
+	}
 	// Store incoming identifier, in case there is a need to find more dependency or massaging
 	attrTypeId := attrDesc.GetAttrType()
 	inputAttrTypeId := attrTypeId
@@ -147,6 +154,13 @@ func CreateAttribute(attrDesc *AttributeDescriptor) (types.TGAttribute, types.TG
 
 // CreateAttributeWithDesc creates new attribute based on the owner and AttributeDescriptor
 func CreateAttributeWithDesc(attrOwner types.TGEntity, attrDesc *AttributeDescriptor, value interface{}) (types.TGAttribute, types.TGError) {
+	// Array attributes hold a list of values of the attribute type
+	if attrDesc.IsAttributeArray() {
+		if err := checkArrayAttributeDescriptor(attrDesc); err != nil {
+			return nil, err
+		}
+		return NewArrayAttributeWithDesc(attrOwner, attrDesc, value), nil
+	}
 	// Store incoming identifier, in case there is a need to find more dependency or massaging
 	attrTypeId := attrDesc.GetAttrType()
 	inputAttrTypeId := attrTypeId
@@ -385,3 +385,59 @@ func TestWriteExternal(t *testing.T) {
 		//t.Logf("AttributeFactory ReadExternal imported '%+v' attribute value", types.GetAttributeTypeFromId(attrTypeId).TypeName)
 	}
 }
+
+func TestArrayAttribute(t *testing.T) {
+	attrDesc := NewAttributeDescriptorAsArray("scores", types.AttributeTypeInteger, true)
+	newAttr, err := CreateAttributeWithDesc(nil, attrDesc, nil)
+	if err != nil {
+		t.Fatalf("AttributeFactory could not create array attribute w/ error '%s'", err.Error())
+	}
+	if _, ok := newAttr.(*ArrayAttribute); !ok {
+		t.Fatalf("AttributeFactory returned '%T' instead of an ArrayAttribute", newAttr)
+	}
+	if err = newAttr.SetValue(42); err == nil {
+		t.Errorf("ArrayAttribute accepted a scalar value")
+	}
+	if err = newAttr.SetValue([]string{"one"}); err == nil {
+		t.Errorf("ArrayAttribute accepted an element not convertible to an integer")
+	}
+	if err = newAttr.SetValue([]int16{1, 2, 3}); err != nil {
+		t.Fatalf("ArrayAttribute could not set value w/ error '%s'", err.Error())
+	}
+
+	oNetwork := iostream.DefaultProtocolDataOutputStream()
+	err = newAttr.WriteExternal(oNetwork)
+	if err != nil {
+		t.Fatalf("ArrayAttribute could not WriteExternal w/ error '%s'", err.Error())
+	}
+	iNetwork := iostream.NewProtocolDataInputStream(oNetwork.GetBuffer())
+	attrId, _ := iNetwork.ReadInt()
+	if int64(attrId) != attrDesc.GetAttributeId() {
+		t.Errorf("ArrayAttribute wrote attribute id '%d' instead of '%d'", attrId, attrDesc.GetAttributeId())
+	}
+	readAttr := NewArrayAttribute(attrDesc)
+	err = readAttr.ReadExternal(iNetwork)
+	if err != nil {
+		t.Fatalf("ArrayAttribute could not ReadExternal w/ error '%s'", err.Error())
+	}
+	elements := readAttr.GetElements()
+	if len(elements) != 3 || elements[0] != 1 || elements[2] != 3 {
+		t.Errorf("ArrayAttribute read elements '%+v' instead of [1 2 3]", elements)
+	}
+}
+
+func TestEncryptedArrayAttribute(t *testing.T) {
+	attrDesc := NewAttributeDescriptorAsArray("pins", types.AttributeTypeInteger, true)
+	attrDesc.SetIsEncrypted(true)
+	if _, err := CreateAttribute(attrDesc); err == nil {
+		t.Errorf("AttributeFactory created an encrypted array attribute")
+	}
+	if _, err := CreateAttributeWithDesc(nil, attrDesc, []interface{}{1}); err == nil {
+		t.Errorf("AttributeFactory created an encrypted array attribute w/ a value")
+	}
+	// Descriptors flagged as encrypted after the attribute was created are not written in plain text either
+	newAttr := NewArrayAttributeWithDesc(nil, attrDesc, []interface{}{1})
+	if err := newAttr.WriteExternal(iostream.DefaultProtocolDataOutputStream()); err == nil {
+		t.Errorf("ArrayAttribute wrote an encrypted array in plain text")
+	}
+}
@@ -294,9 +294,26 @@ func (obj *EdgeType) ReadExternal(is types.TGInputStream) types.TGError {
 
 // WriteExternal writes a system object into an appropriate byte format onto an external output stream
 func (obj *EdgeType) WriteExternal(os types.TGOutputStream) types.TGError {
-	logger.Warning(fmt.Sprint("WARNING: Returning EdgeType:WriteExternal is not implemented"))
-	//errMsg := fmt.Sprint("EdgeType WriteExternal message is not implemented")
-	//return exception.GetErrorByType(types.TGErrorIOException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	logger.Log(fmt.Sprint("Entering EdgeType:WriteExternal"))
+	// Base Class EntityType's WriteExternal()
+	err := EntityTypeWriteExternal(obj, os)
+	if err != nil {
+		return err
+	}
+	logger.Debug(fmt.Sprint("Inside EdgeType:WriteExternal, wrote base entity type's attributes"))
+
+	os.(*iostream.ProtocolDataOutputStream).WriteInt(obj.fromTypeId)
+	os.(*iostream.ProtocolDataOutputStream).WriteInt(obj.toTypeId)
+	switch obj.directionType {
+	case types.DirectionTypeUnDirected:
+		os.(*iostream.ProtocolDataOutputStream).WriteByte(0)
+	case types.DirectionTypeDirected:
+		os.(*iostream.ProtocolDataOutputStream).WriteByte(1)
+	default:
+		os.(*iostream.ProtocolDataOutputStream).WriteByte(2)
+	}
+	os.(*iostream.ProtocolDataOutputStream).WriteLong(obj.numEntries)
+	logger.Log(fmt.Sprintf("Returning EdgeType:WriteExternal w/ NO error, for entityType: '%+v'", obj))
 	return nil
 }
 
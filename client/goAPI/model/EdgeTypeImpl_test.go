@@ -93,3 +93,27 @@ func TestEdgeTypeWriteExternal(t *testing.T) {
 	_ = ToBeExportedEdgeType.ReadExternal(iNetwork)
 	t.Logf("EntityType ReadExternal imported entity type as '%+v'", ToBeExportedEdgeType)
 }
+
+func TestEdgeTypeWriteExternalRoundTrip(t *testing.T) {
+	exported := CreateTestEdgeType("Transfer", types.DirectionTypeDirected, types.SystemTypeEdge, nil)
+	exported.SetEntityTypeId(21)
+	exported.SetFromTypeId(12)
+	exported.SetToTypeId(13)
+	oNetwork := iostream.DefaultProtocolDataOutputStream()
+	err := exported.WriteExternal(oNetwork)
+	if err != nil {
+		t.Fatalf("EdgeType WriteExternal failed w/ error: '%+v'", err)
+	}
+
+	imported := DefaultEdgeType()
+	err = imported.ReadExternal(iostream.NewProtocolDataInputStream(oNetwork.GetBuffer()[:oNetwork.GetLength()]))
+	if err != nil {
+		t.Fatalf("EdgeType ReadExternal failed w/ error: '%+v'", err)
+	}
+	if imported.GetName() != "Transfer" || imported.GetEntityTypeId() != 21 || imported.GetDirectionType() != types.DirectionTypeDirected {
+		t.Errorf("EdgeType ReadExternal imported '%+v' instead of '%+v'", imported, exported)
+	}
+	if imported.GetFromTypeId() != 12 || imported.GetToTypeId() != 13 || len(imported.GetAttributeDescriptors()) != 3 {
+		t.Errorf("EdgeType ReadExternal imported '%+v' instead of '%+v'", imported, exported)
+	}
+}
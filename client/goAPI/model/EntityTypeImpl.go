@@ -27,6 +27,7 @@ import (
 	"fmt"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/iostream"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"sort"
 )
 
 type EntityType struct {
@@ -140,6 +141,35 @@ func EntityTypeReadExternal(obj types.TGEntityType, is types.TGInputStream) type
 	return nil
 }
 
+// EntityTypeWriteExternal writes the attributes common to all entity types, in the order EntityTypeReadExternal reads them
+func EntityTypeWriteExternal(obj types.TGEntityType, os types.TGOutputStream) types.TGError {
+	logger.Log(fmt.Sprint("Entering EntityType:EntityTypeWriteExternal"))
+	os.(*iostream.ProtocolDataOutputStream).WriteByte(int(obj.GetSystemType()))
+	os.(*iostream.ProtocolDataOutputStream).WriteInt(obj.GetEntityTypeId())
+	err := os.(*iostream.ProtocolDataOutputStream).WriteUTF(obj.GetName())
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning EntityType:EntityTypeWriteExternal - unable to write eName w/ Error: '%+v'", err.Error()))
+		return err
+	}
+	os.(*iostream.ProtocolDataOutputStream).WriteInt(0) // pagesize - the server default
+
+	attrNames := make([]string, 0)
+	for _, attrDesc := range obj.GetAttributeDescriptors() {
+		attrNames = append(attrNames, attrDesc.GetName())
+	}
+	sort.Strings(attrNames)
+	os.(*iostream.ProtocolDataOutputStream).WriteShort(len(attrNames))
+	for _, attrName := range attrNames {
+		err = os.(*iostream.ProtocolDataOutputStream).WriteUTF(attrName)
+		if err != nil {
+			logger.Error(fmt.Sprintf("ERROR: Returning EntityType:EntityTypeWriteExternal - unable to write attrName w/ Error: '%+v'", err.Error()))
+			return err
+		}
+	}
+	logger.Log(fmt.Sprintf("Returning EntityType:EntityTypeWriteExternal w/ NO error, for entityType: '%+v'", obj.GetName()))
+	return nil
+}
+
 func EntityTypeUpdateMetadata(obj types.TGEntityType, gmd *GraphMetadata) types.TGError {
 	logger.Log(fmt.Sprint("Entering EntityType:EntityTypeUpdateMetadata"))
 	for attrName, _ := range obj.(*EntityType).attributes {
@@ -254,10 +284,7 @@ func (obj *EntityType) ReadExternal(is types.TGInputStream) types.TGError {
 
 // WriteExternal writes a system object into an appropriate byte format onto an external output stream
 func (obj *EntityType) WriteExternal(os types.TGOutputStream) types.TGError {
-	logger.Warning(fmt.Sprint("WARNING: Returning EntityType:WriteExternal is not implemented"))
-	//errMsg := fmt.Sprint("EntityType WriteExternal message is not implemented")
-	//return exception.GetErrorByType(types.TGErrorIOException, types.INTERNAL_SERVER_ERROR, errMsg, "")
-	return nil
+	return EntityTypeWriteExternal(obj, os)
 }
 
 /////////////////////////////////////////////////////////////////
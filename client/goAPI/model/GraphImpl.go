@@ -25,6 +25,7 @@ import (
 	"bytes"
 	"encoding/gob"
 	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/iostream"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
 	"strings"
@@ -72,6 +73,16 @@ func (obj *Graph) GetName() string {
 	return obj.name
 }
 
+// getConnection returns the connection of the graph object factory that the metadata of this graph belongs to
+func (obj *Graph) getConnection() (types.TGConnection, types.TGError) {
+	if obj.graphMetadata == nil || obj.graphMetadata.graphObjFactory == nil || obj.graphMetadata.graphObjFactory.GetConnection() == nil {
+		logger.Error(fmt.Sprint("ERROR: Returning Graph:getConnection as the graph is NOT associated w/ a connection"))
+		errMsg := fmt.Sprintf("Graph '%s' is not associated w/ a connection", obj.name)
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	return obj.graphMetadata.graphObjFactory.GetConnection(), nil
+}
+
 func (obj *Graph) SetName(name string) {
 	obj.name = name
 }
@@ -90,11 +101,21 @@ func (obj *Graph) AddEdges(edges []types.TGEdge) (types.TGGraph, types.TGError)
 }
 
 func (obj *Graph) GetNode(filter types.TGFilter) (types.TGNode, types.TGError) {
-	return nil, nil
+	nodes, err := obj.ListNodes(filter, true)
+	if err != nil {
+		return nil, err
+	}
+	return uniqueNode(nodes, filter)
 }
 
-func (obj *Graph) ListNodes(filter types.TGFilter, recurseAllSubGraphs bool) (types.TGNode, types.TGError) {
-	return nil, nil
+// ListNodes lists the nodes that match the filter. The server keeps all the nodes in the root graph, so the
+// nodes of sub graphs are always included.
+func (obj *Graph) ListNodes(filter types.TGFilter, recurseAllSubGraphs bool) ([]types.TGNode, types.TGError) {
+	conn, err := obj.getConnection()
+	if err != nil {
+		return nil, err
+	}
+	return queryFilteredNodes(conn, filter)
 }
 
 func (obj *Graph) CreateGraph(name string) (types.TGGraph, types.TGError) {
@@ -110,7 +131,21 @@ func (obj *Graph) RemoveNode(node types.TGNode) (types.TGGraph, types.TGError) {
 }
 
 func (obj *Graph) RemoveNodes(filter types.TGFilter) int {
-	return 0
+	conn, err := obj.getConnection()
+	if err != nil {
+		return -1
+	}
+	nodes, err := queryFilteredNodes(conn, filter)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning Graph:RemoveNodes - unable to query nodes w/ error: '%s'", err.Error()))
+		return -1
+	}
+	err = deleteNodes(conn, nodes)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning Graph:RemoveNodes - unable to delete nodes w/ error: '%s'", err.Error()))
+		return -1
+	}
+	return len(nodes)
 }
 
 /////////////////////////////////////////////////////////////////
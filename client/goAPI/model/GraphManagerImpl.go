@@ -22,13 +22,18 @@
 package model
 
 import (
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
 )
 
 type GraphManager struct {
-	name string
+	name       string
+	connection types.TGConnection
 }
 
+// NewGraphManager creates a graph manager w/o any connection, so that it cannot query or delete nodes - see
+// NewGraphManagerWithConnection
 func NewGraphManager(gmd GraphMetadata) GraphManager {
 	newGraphManager := GraphManager{
 		name: "TGDB Graph Manager",
@@ -36,6 +41,15 @@ func NewGraphManager(gmd GraphMetadata) GraphManager {
 	return newGraphManager
 }
 
+// NewGraphManagerWithConnection creates a graph manager that queries and deletes nodes on the connection
+func NewGraphManagerWithConnection(conn types.TGConnection) *GraphManager {
+	newGraphManager := GraphManager{
+		name:       "TGDB Graph Manager",
+		connection: conn,
+	}
+	return &newGraphManager
+}
+
 ///////////////////////////////////////
 // Helper functions for GraphManager //
 ///////////////////////////////////////
@@ -45,6 +59,99 @@ func (obj *GraphManager) GetName() string {
 	return obj.name
 }
 
+////////////////////////////////////////
+// Private functions for GraphManager //
+////////////////////////////////////////
+
+// queryFilteredNodes executes the filter as a query on the connection, and returns the matching nodes. The args
+// are pairs of a parameter name and its value, bound to the named parameters of the filter.
+func queryFilteredNodes(conn types.TGConnection, filter types.TGFilter, args ...interface{}) ([]types.TGNode, types.TGError) {
+	rSet, err := executeFilter(conn, filter, args...)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]types.TGNode, 0)
+	if rSet == nil {
+		return nodes, nil
+	}
+	defer rSet.Close()
+	for entity, err := range rSet.All() {
+		if err != nil {
+			logger.Error(fmt.Sprintf("ERROR: Returning GraphManager:queryFilteredNodes - unable to read the result set w/ error: '%s'", err.Error()))
+			return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", "Unable to read the nodes matching the filter", err.Error())
+		}
+		if node, ok := entity.(types.TGNode); ok && entity.GetEntityKind() == types.EntityKindNode {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// executeFilter executes the filter as an immediate query, or as a prepared query when there are parameters to bind
+func executeFilter(conn types.TGConnection, filter types.TGFilter, args ...interface{}) (types.TGResultSet, types.TGError) {
+	if conn == nil {
+		errMsg := "There is no connection to execute the filter on"
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	if filter == nil {
+		errMsg := "Filter cannot be nil"
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	if len(args)%2 != 0 {
+		errMsg := fmt.Sprintf("Filter arguments must be pairs of a parameter name and its value, and not '%d' values", len(args))
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	expr, err := filter.GetExpression()
+	if err != nil {
+		return nil, err
+	}
+	expr = expr + ";"
+	logger.Debug(fmt.Sprintf("Inside GraphManager:executeFilter about to execute query '%s'", expr))
+	if len(args) == 0 {
+		return conn.ExecuteQuery("tgql://"+expr, nil)
+	}
+	qry, err := conn.CreateQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	defer qry.Close()
+	for i := 0; i < len(args); i += 2 {
+		name, ok := args[i].(string)
+		if !ok {
+			errMsg := fmt.Sprintf("Filter argument '%+v' is not a parameter name", args[i])
+			return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+		}
+		err = qry.Bind(name, args[i+1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return qry.ExecuteQuery()
+}
+
+// uniqueNode returns the only node matching the filter, or nil if there is none
+func uniqueNode(nodes []types.TGNode, filter types.TGFilter) (types.TGNode, types.TGError) {
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+	if len(nodes) > 1 {
+		errMsg := fmt.Sprintf("Filter '%s' matches '%d' nodes instead of a unique one", filter.String(), len(nodes))
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	return nodes[0], nil
+}
+
+// deleteNodes marks the nodes for delete operation on the connection
+func deleteNodes(conn types.TGConnection, nodes []types.TGNode) types.TGError {
+	for _, node := range nodes {
+		err := conn.DeleteEntity(node)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 ///////////////////////////////////////////////////////////
 // Implement functions from Interface ==> TGGraphManager //
 ///////////////////////////////////////////////////////////
@@ -76,22 +183,66 @@ func (obj *GraphManager) CreateGraph(name string) (types.TGGraph, types.TGError)
 
 // DeleteNode removes this node from the graph
 func (obj *GraphManager) DeleteNode(filter types.TGFilter) (types.TGGraphManager, types.TGError) {
-	return nil, nil
+	nodes, err := queryFilteredNodes(obj.connection, filter)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning GraphManager:DeleteNode - unable to query nodes w/ error: '%s'", err.Error()))
+		return nil, err
+	}
+	node, err := uniqueNode(nodes, filter)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		logger.Warning(fmt.Sprintf("WARNING: Returning GraphManager:DeleteNode as there is NO node matching '%s'", filter.String()))
+		return obj, nil
+	}
+	err = obj.connection.DeleteEntity(node)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
 }
 
 // DeleteNodes removes the nodes from this graph that match the filter
 func (obj *GraphManager) DeleteNodes(filter types.TGFilter) (types.TGGraphManager, types.TGError) {
-	return nil, nil
+	nodes, err := queryFilteredNodes(obj.connection, filter)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning GraphManager:DeleteNodes - unable to query nodes w/ error: '%s'", err.Error()))
+		return nil, err
+	}
+	err = deleteNodes(obj.connection, nodes)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
 }
 
 // CreateQuery creates a Reusable Query
 func (obj *GraphManager) CreateQuery(filter types.TGFilter) types.TGQuery {
-	return nil
+	if obj.connection == nil || filter == nil {
+		logger.Error(fmt.Sprint("ERROR: Returning GraphManager:CreateQuery as there is no connection or filter"))
+		return nil
+	}
+	expr, err := filter.GetExpression()
+	if err != nil {
+		return nil
+	}
+	qry, err := obj.connection.CreateQuery(expr + ";")
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning GraphManager:CreateQuery - unable to create query w/ error: '%s'", err.Error()))
+		return nil
+	}
+	return qry
 }
 
 // QueryNodes gets Nodes based on the Filter condition with a set of Arguments
 func (obj *GraphManager) QueryNodes(filter types.TGFilter, args ...interface{}) types.TGResultSet {
-	return nil
+	rSet, err := executeFilter(obj.connection, filter, args...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning GraphManager:QueryNodes - unable to execute the filter w/ error: '%s'", err.Error()))
+		return nil
+	}
+	return rSet
 }
 
 // Traverse follows the graph using the traversal descriptor
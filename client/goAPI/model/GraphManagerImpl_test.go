@@ -0,0 +1,151 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: GraphManagerImpl_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package model
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"iter"
+	"testing"
+)
+
+type testFilter string
+
+func (obj testFilter) GetExpression() (string, types.TGError) {
+	return string(obj), nil
+}
+
+func (obj testFilter) String() string {
+	return string(obj)
+}
+
+// testResultSet yields the entities it was created with
+type testResultSet struct {
+	types.TGResultSet
+	entities []types.TGEntity
+	closed   bool
+}
+
+func (obj *testResultSet) Close() types.TGResultSet {
+	obj.closed = true
+	return obj
+}
+
+func (obj *testResultSet) All() iter.Seq2[types.TGEntity, error] {
+	return func(yield func(types.TGEntity, error) bool) {
+		for _, entity := range obj.entities {
+			if !yield(entity, nil) {
+				return
+			}
+		}
+	}
+}
+
+// testQuery records the parameters bound to it
+type testQuery struct {
+	types.TGQuery
+	conn   *filterConnection
+	params map[string]interface{}
+}
+
+func (obj *testQuery) Bind(name string, value interface{}) types.TGError {
+	obj.params[name] = value
+	return nil
+}
+
+func (obj *testQuery) ExecuteQuery() (types.TGResultSet, types.TGError) {
+	return &testResultSet{entities: obj.conn.matches}, nil
+}
+
+func (obj *testQuery) Close() {
+}
+
+// filterConnection returns the same matches for every query, and records the queries and the deleted entities
+type filterConnection struct {
+	types.TGConnection
+	matches []types.TGEntity
+	queries []string
+	query   *testQuery
+	rSet    *testResultSet
+	deleted []types.TGEntity
+}
+
+func (obj *filterConnection) ExecuteQuery(expr string, options types.TGQueryOption) (types.TGResultSet, types.TGError) {
+	obj.queries = append(obj.queries, expr)
+	obj.rSet = &testResultSet{entities: obj.matches}
+	return obj.rSet, nil
+}
+
+func (obj *filterConnection) CreateQuery(expr string) (types.TGQuery, types.TGError) {
+	obj.queries = append(obj.queries, expr)
+	obj.query = &testQuery{conn: obj, params: make(map[string]interface{})}
+	return obj.query, nil
+}
+
+func (obj *filterConnection) DeleteEntity(entity types.TGEntity) types.TGError {
+	obj.deleted = append(obj.deleted, entity)
+	return nil
+}
+
+func TestGraphManagerQueryNodes(t *testing.T) {
+	conn := &filterConnection{matches: []types.TGEntity{CreateTestNodeEntity(), CreateTestEdgeEntity(), CreateTestNodeEntity()}}
+	graphManager := NewGraphManagerWithConnection(conn)
+	filter := testFilter("@nodetype = 'Member' and age > $age and name = $name")
+	rSet := graphManager.QueryNodes(filter, "age", 30, "name", "Alice")
+	if rSet == nil || conn.queries[0] != string(filter)+";" {
+		t.Fatalf("TestGraphManagerQueryNodes expected a prepared query for '%s' and not '%+v'", filter, conn.queries)
+	}
+	if conn.query.params["age"] != 30 || conn.query.params["name"] != "Alice" {
+		t.Errorf("TestGraphManagerQueryNodes bound unexpected parameters '%+v'", conn.query.params)
+	}
+	if graphManager.QueryNodes(filter, "age") != nil || graphManager.QueryNodes(filter, 1, 2) != nil {
+		t.Errorf("TestGraphManagerQueryNodes expected no result set for invalid arguments")
+	}
+
+	if _, err := graphManager.DeleteNode(filter); err == nil {
+		t.Errorf("TestGraphManagerQueryNodes expected an error deleting a node matched by a non-unique filter")
+	}
+	if _, err := graphManager.DeleteNodes(filter); err != nil || len(conn.deleted) != 2 {
+		t.Errorf("TestGraphManagerQueryNodes expected the 2 matching nodes to be deleted and not '%+v'", conn.deleted)
+	}
+	if !conn.rSet.closed {
+		t.Errorf("TestGraphManagerQueryNodes expected the result set of the matching nodes to be closed")
+	}
+	if conn.queries[len(conn.queries)-1] != "tgql://"+string(filter)+";" {
+		t.Errorf("TestGraphManagerQueryNodes expected an immediate query and not '%s'", conn.queries[len(conn.queries)-1])
+	}
+}
+
+func TestGraphListNodes(t *testing.T) {
+	node := CreateTestNodeEntity()
+	conn := &filterConnection{matches: []types.TGEntity{node}}
+	gof := NewGraphObjectFactory(conn)
+	graph := NewGraph(gof.GetGraphMetaData())
+	filter := testFilter("@nodetype = 'Member'")
+	found, err := graph.GetNode(filter)
+	if err != nil || found != node {
+		t.Errorf("TestGraphListNodes expected the matching node and not '%+v' w/ error '%+v'", found, err)
+	}
+	if count := graph.RemoveNodes(filter); count != 1 || conn.deleted[0] != node {
+		t.Errorf("TestGraphListNodes expected the matching node to be removed and not '%d' nodes", count)
+	}
+	if _, err := NewGraph(CreateTestGraphMetadata()).ListNodes(filter, false); err == nil {
+		t.Errorf("TestGraphListNodes expected an error for a graph w/o connection")
+	}
+}
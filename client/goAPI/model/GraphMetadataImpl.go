@@ -26,6 +26,7 @@ import (
 	"encoding/gob"
 	"fmt"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"strings"
 )
 
 type GraphMetadata struct {
@@ -170,8 +171,14 @@ func (obj *GraphMetadata) CreateAttributeDescriptor(attrName string, attrType in
 // CreateAttributeDescriptorForDataType creates Attribute Descriptor for data/attribute type - New in GO Lang
 func (obj *GraphMetadata) CreateAttributeDescriptorForDataType(attrName string, dataTypeClassName string) types.TGAttributeDescriptor {
 	attrType := types.GetAttributeTypeFromName(dataTypeClassName)
+	isArray := false
+	// A slice of a known type - other than the blob and clob types - is an array of that type
+	if attrType.GetTypeId() == types.AttributeTypeInvalid && strings.HasPrefix(dataTypeClassName, "[]") {
+		attrType = types.GetAttributeTypeFromName(strings.TrimPrefix(dataTypeClassName, "[]"))
+		isArray = attrType.GetTypeId() != types.AttributeTypeInvalid
+	}
 	logger.Log(fmt.Sprintf("GraphMetadata CreateAttributeDescriptorForDataType creating attribute descriptor for '%+v' w/ type '%+v'", attrName, attrType))
-	newAttrDesc := NewAttributeDescriptorAsArray(attrName, attrType.GetTypeId(), false)
+	newAttrDesc := NewAttributeDescriptorAsArray(attrName, attrType.GetTypeId(), isArray)
 	obj.descriptors[attrName] = newAttrDesc
 	return newAttrDesc
 }
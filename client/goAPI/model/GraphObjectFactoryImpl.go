@@ -108,6 +108,30 @@ func (obj *GraphObjectFactory) CreateEdgeWithDirection(fromNode types.TGNode, to
 	return newEdge, nil
 }
 
+// CreateHyperEdge creates a HyperEdge connecting the nodes, of the edge type if one is specified
+func (obj *GraphObjectFactory) CreateHyperEdge(nodes []types.TGNode, edgeType types.TGEdgeType) (types.TGHyperEdge, types.TGError) {
+	if len(nodes) == 0 {
+		logger.Error(fmt.Sprint("ERROR: Returning GraphObjectFactory:CreateHyperEdge as there are NO nodes to connect"))
+		errMsg := fmt.Sprint("Unable to create a hyperedge without any nodes")
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	var newHyperEdge *HyperEdge
+	if eType, ok := edgeType.(*EdgeType); edgeType == nil || (ok && eType == nil) {
+		newHyperEdge = NewHyperEdgeWithDirection(obj.graphMData, nodes, types.DirectionTypeUnDirected)
+	} else {
+		newHyperEdge = NewHyperEdgeWithEdgeType(obj.graphMData, nodes, edgeType)
+	}
+	if newHyperEdge.isInitialized != true {
+		logger.Error(fmt.Sprint("ERROR: Returning GraphObjectFactory:CreateHyperEdge as hyperedge in NOT initialized"))
+		errMsg := fmt.Sprint("Unable to create a hyperedge with this Graph Object Factory")
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	for _, node := range nodes {
+		node.AddEdge(newHyperEdge)
+	}
+	return newHyperEdge, nil
+}
+
 // CreateEntity creates entity based on the entity kind specified
 func (obj *GraphObjectFactory) CreateEntity(entityKind types.TGEntityKind) (types.TGEntity, types.TGError) {
 	switch entityKind {
@@ -115,6 +139,8 @@ func (obj *GraphObjectFactory) CreateEntity(entityKind types.TGEntityKind) (type
 		return NewNode(obj.graphMData), nil
 	case types.EntityKindEdge:
 		return NewEdge(obj.graphMData), nil
+	case types.EntityKindHyperEdge:
+		return NewHyperEdge(obj.graphMData), nil
 	case types.EntityKindGraph:
 		return NewGraph(obj.graphMData), nil
 	}
@@ -0,0 +1,374 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF DirectionAny KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: HyperEdgeImpl.go
+ * SVN id: $id: $
+ *
+ */
+
+package model
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/iostream"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"strings"
+)
+
+type HyperEdge struct {
+	*AbstractEntity
+	directionType types.TGDirectionType
+	vertices      []types.TGNode
+}
+
+func DefaultHyperEdge() *HyperEdge {
+	// We must register the concrete type for the encoder and decoder (which would
+	// normally be on a separate machine from the encoder). On each end, this tells the
+	// engine which concrete type is being sent that implements the interface.
+	gob.Register(HyperEdge{})
+
+	newHyperEdge := HyperEdge{
+		AbstractEntity: DefaultAbstractEntity(),
+		directionType:  types.DirectionTypeUnDirected,
+		vertices:       make([]types.TGNode, 0),
+	}
+	newHyperEdge.EntityKind = types.EntityKindHyperEdge
+	newHyperEdge.EntityType = nil
+	return &newHyperEdge
+}
+
+func NewHyperEdge(gmd *GraphMetadata) *HyperEdge {
+	newHyperEdge := DefaultHyperEdge()
+	newHyperEdge.graphMetadata = gmd
+	return newHyperEdge
+}
+
+func NewHyperEdgeWithDirection(gmd *GraphMetadata, nodes []types.TGNode, directionType types.TGDirectionType) *HyperEdge {
+	newHyperEdge := NewHyperEdge(gmd)
+	newHyperEdge.directionType = directionType
+	newHyperEdge.vertices = append(newHyperEdge.vertices, nodes...)
+	return newHyperEdge
+}
+
+func NewHyperEdgeWithEdgeType(gmd *GraphMetadata, nodes []types.TGNode, edgeType types.TGEdgeType) *HyperEdge {
+	newHyperEdge := NewHyperEdgeWithDirection(gmd, nodes, edgeType.GetDirectionType())
+	newHyperEdge.EntityType = edgeType
+	return newHyperEdge
+}
+
+/////////////////////////////////////////////////////////////////
+// Helper functions for HyperEdge
+/////////////////////////////////////////////////////////////////
+
+func (obj *HyperEdge) GetIsInitialized() bool {
+	return obj.isInitialized
+}
+
+func (obj *HyperEdge) GetModifiedAttributes() []types.TGAttribute {
+	return obj.getModifiedAttributes()
+}
+
+func (obj *HyperEdge) SetDirectionType(dirType types.TGDirectionType) {
+	obj.directionType = dirType
+}
+
+// readVertex returns the node w/ the id from the reference map of the stream, or a placeholder for it
+func (obj *HyperEdge) readVertex(is types.TGInputStream, nodeId int64) types.TGNode {
+	refMap := is.(*iostream.ProtocolDataInputStream).GetReferenceMap()
+	if refMap != nil {
+		if entity, ok := refMap[nodeId].(types.TGNode); ok {
+			return entity
+		}
+	}
+	node := NewNode(obj.graphMetadata)
+	node.SetEntityId(nodeId)
+	node.SetIsInitialized(false)
+	if refMap != nil {
+		refMap[nodeId] = node
+	}
+	logger.Debug(fmt.Sprintf("Inside HyperEdge:ReadExternal created new vertex: '%+v'", node))
+	return node
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> TGHyperEdge
+/////////////////////////////////////////////////////////////////
+
+// AddVertex connects another node to this hyperedge
+func (obj *HyperEdge) AddVertex(node types.TGNode) {
+	obj.vertices = append(obj.vertices, node)
+}
+
+// RemoveVertex disconnects the node from this hyperedge, and reports whether it was connected
+func (obj *HyperEdge) RemoveVertex(node types.TGNode) bool {
+	for i, vertex := range obj.vertices {
+		if vertex == node {
+			obj.vertices = append(obj.vertices[:i], obj.vertices[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> TGEdge
+/////////////////////////////////////////////////////////////////
+
+// GetDirectionType gets direction type as one of the constants
+func (obj *HyperEdge) GetDirectionType() types.TGDirectionType {
+	if edgeType, ok := obj.EntityType.(*EdgeType); ok && edgeType != nil {
+		return edgeType.GetDirectionType()
+	}
+	return obj.directionType
+}
+
+// GetVertices gets array of NODE (Entity) types connected by this HYPEREDGE (Entity) type
+func (obj *HyperEdge) GetVertices() []types.TGNode {
+	return obj.vertices
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> TGEntity
+/////////////////////////////////////////////////////////////////
+
+// GetAttribute gets the attribute for the name specified
+func (obj *HyperEdge) GetAttribute(attrName string) types.TGAttribute {
+	return obj.getAttribute(attrName)
+}
+
+// GetAttributes lists of all the attributes set
+func (obj *HyperEdge) GetAttributes() ([]types.TGAttribute, types.TGError) {
+	return obj.getAttributes()
+}
+
+// GetEntityKind returns the EntityKind as a constant
+func (obj *HyperEdge) GetEntityKind() types.TGEntityKind {
+	return obj.getEntityKind()
+}
+
+// GetEntityType returns the EntityType
+func (obj *HyperEdge) GetEntityType() types.TGEntityType {
+	return obj.getEntityType()
+}
+
+// GetGraphMetadata returns the Graph Meta Data	- New in GO Lang
+func (obj *HyperEdge) GetGraphMetadata() types.TGGraphMetadata {
+	return obj.getGraphMetadata()
+}
+
+// GetIsDeleted checks whether this entity is already deleted in the system or not
+func (obj *HyperEdge) GetIsDeleted() bool {
+	return obj.getIsDeleted()
+}
+
+// GetIsNew checks whether this entity that is currently being added to the system is new or not
+func (obj *HyperEdge) GetIsNew() bool {
+	return obj.getIsNew()
+}
+
+// GetVersion gets the version of the Entity
+func (obj *HyperEdge) GetVersion() int {
+	return obj.getVersion()
+}
+
+// GetVirtualId gets Entity identifier
+// At the time of creation before reaching the server, it is the virtual id
+// Upon successful creation, server returns a valid entity id that gets set in place of virtual id
+func (obj *HyperEdge) GetVirtualId() int64 {
+	return obj.getVirtualId()
+}
+
+// IsAttributeSet checks whether this entity is an Attribute set or not
+func (obj *HyperEdge) IsAttributeSet(attrName string) bool {
+	return obj.isAttributeSet(attrName)
+}
+
+// ResetModifiedAttributes resets the dirty flag on attributes
+func (obj *HyperEdge) ResetModifiedAttributes() {
+	obj.resetModifiedAttributes()
+}
+
+// SetAttribute associates the specified Attribute to this Entity
+func (obj *HyperEdge) SetAttribute(attr types.TGAttribute) types.TGError {
+	return obj.setAttribute(attr)
+}
+
+// SetOrCreateAttribute dynamically associates the attribute to this entity
+// If the AttributeDescriptor doesn't exist in the database, create a new one
+func (obj *HyperEdge) SetOrCreateAttribute(name string, value interface{}) types.TGError {
+	return obj.setOrCreateAttribute(name, value)
+}
+
+// SetEntityId sets Entity id and reset Virtual id after creation
+func (obj *HyperEdge) SetEntityId(id int64) {
+	obj.setEntityId(id)
+}
+
+// SetIsDeleted set the deleted flag
+func (obj *HyperEdge) SetIsDeleted(flag bool) {
+	obj.setIsDeleted(flag)
+}
+
+// SetIsInitialized set the initialized flag
+func (obj *HyperEdge) SetIsInitialized(flag bool) {
+	obj.setIsInitialized(flag)
+}
+
+// SetIsNew sets the flag that this is a new entity
+func (obj *HyperEdge) SetIsNew(flag bool) {
+	obj.setIsNew(flag)
+}
+
+// SetVersion sets the version of the Entity
+func (obj *HyperEdge) SetVersion(version int) {
+	obj.setVersion(version)
+}
+
+func (obj *HyperEdge) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString("HyperEdge:{")
+	buffer.WriteString(fmt.Sprintf("DirectionType: %+v", obj.directionType))
+	vertexIds := make([]int64, 0, len(obj.vertices))
+	for _, vertex := range obj.vertices {
+		if vertex != nil {
+			vertexIds = append(vertexIds, vertex.GetVirtualId())
+		}
+	}
+	buffer.WriteString(fmt.Sprintf(", Vertices: %+v", vertexIds))
+	strArray := []string{buffer.String(), obj.entityToString() + "}"}
+	msgStr := strings.Join(strArray, ", ")
+	return msgStr
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> types.TGSerializable
+/////////////////////////////////////////////////////////////////
+
+// ReadExternal reads the byte format from an external input stream and constructs a system object
+func (obj *HyperEdge) ReadExternal(is types.TGInputStream) types.TGError {
+	logger.Log(fmt.Sprint("Entering HyperEdge:ReadExternal"))
+	hyperEdgeBufLen, err := is.(*iostream.ProtocolDataInputStream).ReadInt()
+	if err != nil {
+		return err
+	}
+	logger.Debug(fmt.Sprintf("Inside HyperEdge:ReadExternal read hyperEdgeBufLen as '%+v'", hyperEdgeBufLen))
+
+	err = obj.AbstractEntityReadExternal(is)
+	if err != nil {
+		return err
+	}
+	logger.Debug(fmt.Sprint("Inside HyperEdge:ReadExternal read abstractEntity"))
+
+	direction, err := is.(*iostream.ProtocolDataInputStream).ReadByte()
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning HyperEdge:ReadExternal - unable to read direction w/ Error: '%+v'", err.Error()))
+		return err
+	}
+	if direction == 0 {
+		obj.SetDirectionType(types.DirectionTypeUnDirected)
+	} else if direction == 1 {
+		obj.SetDirectionType(types.DirectionTypeDirected)
+	} else {
+		obj.SetDirectionType(types.DirectionTypeBiDirectional)
+	}
+
+	vertexCount, err := is.(*iostream.ProtocolDataInputStream).ReadInt()
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning HyperEdge:ReadExternal - unable to read vertexCount w/ Error: '%+v'", err.Error()))
+		return err
+	}
+	logger.Debug(fmt.Sprintf("Inside HyperEdge:ReadExternal read vertexCount as '%d'", vertexCount))
+	vertices := make([]types.TGNode, 0, vertexCount)
+	for i := 0; i < vertexCount; i++ {
+		nodeId, err := is.(*iostream.ProtocolDataInputStream).ReadLong()
+		if err != nil {
+			logger.Error(fmt.Sprintf("ERROR: Returning HyperEdge:ReadExternal - unable to read nodeId w/ Error: '%+v'", err.Error()))
+			return err
+		}
+		vertex := obj.readVertex(is, nodeId)
+		// Nodes read earlier refer to this hyperedge through a placeholder edge
+		if node, ok := vertex.(*Node); ok {
+			node.replaceEdge(obj)
+		}
+		vertices = append(vertices, vertex)
+	}
+	obj.vertices = vertices
+
+	obj.SetIsInitialized(true)
+	logger.Log(fmt.Sprintf("Returning HyperEdge:ReadExternal w/ NO error, for hyperedge: '%+v'", obj))
+	return nil
+}
+
+// WriteExternal writes a system object into an appropriate byte format onto an external output stream
+func (obj *HyperEdge) WriteExternal(os types.TGOutputStream) types.TGError {
+	logger.Log(fmt.Sprint("Entering HyperEdge:WriteExternal"))
+	startPos := os.(*iostream.ProtocolDataOutputStream).GetPosition()
+	os.(*iostream.ProtocolDataOutputStream).WriteInt(0)
+	// Write attributes from the base class
+	err := obj.AbstractEntityWriteExternal(os)
+	if err != nil {
+		return err
+	}
+	logger.Debug(fmt.Sprint("Inside HyperEdge:WriteExternal - exported base entity attributes"))
+	os.(*iostream.ProtocolDataOutputStream).WriteByte(int(obj.GetDirectionType()))
+	os.(*iostream.ProtocolDataOutputStream).WriteInt(len(obj.vertices))
+	for _, vertex := range obj.vertices {
+		os.(*iostream.ProtocolDataOutputStream).WriteLong(vertex.GetVirtualId())
+	}
+	currPos := os.(*iostream.ProtocolDataOutputStream).GetPosition()
+	length := currPos - startPos
+	_, err = os.(*iostream.ProtocolDataOutputStream).WriteIntAt(startPos, length)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning HyperEdge:WriteExternal - unable to update data length in the buffer w/ Error: '%+v'", err.Error()))
+		return err
+	}
+	logger.Log(fmt.Sprintf("Returning HyperEdge:WriteExternal w/ NO error, for hyperedge: '%+v'", obj))
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> encoding/BinaryMarshaller
+/////////////////////////////////////////////////////////////////
+
+func (obj *HyperEdge) MarshalBinary() ([]byte, error) {
+	// A simple encoding: plain text.
+	var b bytes.Buffer
+	_, err := fmt.Fprintln(&b, obj.isNew, obj.EntityKind, obj.virtualId, obj.version, obj.entityId, obj.EntityType,
+		obj.isDeleted, obj.isInitialized, obj.graphMetadata, obj.attributes, obj.modifiedAttributes,
+		obj.directionType, obj.vertices)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning HyperEdge:MarshalBinary w/ Error: '%+v'", err.Error()))
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> encoding/BinaryUnmarshaller
+/////////////////////////////////////////////////////////////////
+
+func (obj *HyperEdge) UnmarshalBinary(data []byte) error {
+	// A simple encoding: plain text.
+	b := bytes.NewBuffer(data)
+	_, err := fmt.Fscanln(b, &obj.isNew, &obj.EntityKind, &obj.virtualId, &obj.version, &obj.entityId, &obj.EntityType,
+		&obj.isDeleted, &obj.isInitialized, &obj.graphMetadata, &obj.attributes, &obj.modifiedAttributes,
+		&obj.directionType, &obj.vertices)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning HyperEdge:UnmarshalBinary w/ Error: '%+v'", err.Error()))
+		return err
+	}
+	return nil
+}
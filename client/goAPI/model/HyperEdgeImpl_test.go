@@ -0,0 +1,103 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: HyperEdgeImpl_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package model
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/iostream"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"sync/atomic"
+	"testing"
+)
+
+func CreateTestHyperEdgeEntity(nodes []types.TGNode) *HyperEdge {
+	gmd := CreateTestGraphMetadata()
+	newHyperEdgeEntity := NewHyperEdgeWithDirection(gmd, nodes, types.DirectionTypeDirected)
+	newHyperEdgeEntity.virtualId = atomic.AddInt64(&EntitySequencer, 1)
+	return newHyperEdgeEntity
+}
+
+func createTestHyperEdgeVertices(count int) []types.TGNode {
+	nodes := make([]types.TGNode, 0, count)
+	for i := 0; i < count; i++ {
+		node := CreateTestNodeEntity()
+		node.virtualId = atomic.AddInt64(&EntitySequencer, 1)
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+func TestHyperEdgeEntityAddRemoveVertex(t *testing.T) {
+	nodes := createTestHyperEdgeVertices(3)
+	first, second, third := nodes[0], nodes[1], nodes[2]
+	testEntity := CreateTestHyperEdgeEntity([]types.TGNode{first, second})
+	testEntity.AddVertex(third)
+	if !testEntity.RemoveVertex(second) || testEntity.RemoveVertex(second) {
+		t.Errorf("TestHyperEdgeEntity should remove a connected vertex exactly once")
+	}
+	vertices := testEntity.GetVertices()
+	if len(vertices) != 2 || vertices[0] != first || vertices[1] != third {
+		t.Errorf("TestHyperEdgeEntity has unexpected vertices '%+v'", vertices)
+	}
+}
+
+func TestHyperEdgeEntityWriteExternal(t *testing.T) {
+	nodes := createTestHyperEdgeVertices(3)
+	toBeExported := CreateTestHyperEdgeEntity(nodes)
+	// Mimic the server, which only streams persisted entities
+	toBeExported.SetIsNew(false)
+	toBeExported.SetEntityId(atomic.AddInt64(&EntitySequencer, 1))
+	oNetwork := iostream.DefaultProtocolDataOutputStream()
+	err := toBeExported.WriteExternal(oNetwork)
+	if err != nil {
+		t.Fatalf("HyperEdge WriteExternal failed w/ error '%s'", err.Error())
+	}
+
+	// The first node was read before the hyperedge, and refers to it through a placeholder edge
+	placeholder := NewEdge(toBeExported.graphMetadata)
+	placeholder.SetEntityId(toBeExported.GetVirtualId())
+	placeholder.SetIsInitialized(false)
+	nodes[0].AddEdge(placeholder)
+	refMap := map[int64]types.TGEntity{nodes[0].GetVirtualId(): nodes[0], nodes[2].GetVirtualId(): nodes[2]}
+
+	iNetwork := iostream.NewProtocolDataInputStream(oNetwork.GetBuffer())
+	iNetwork.SetReferenceMap(refMap)
+	imported := NewHyperEdge(toBeExported.graphMetadata)
+	err = imported.ReadExternal(iNetwork)
+	if err != nil {
+		t.Fatalf("HyperEdge ReadExternal failed w/ error '%s'", err.Error())
+	}
+	if imported.GetDirectionType() != types.DirectionTypeDirected || !imported.GetIsInitialized() {
+		t.Errorf("HyperEdge ReadExternal imported unexpected hyperedge '%+v'", imported)
+	}
+	vertices := imported.GetVertices()
+	if len(vertices) != 3 || vertices[0] != nodes[0] || vertices[2] != nodes[2] {
+		t.Fatalf("HyperEdge ReadExternal imported unexpected vertices '%+v'", vertices)
+	}
+	if vertices[1].GetVirtualId() != nodes[1].GetVirtualId() || refMap[nodes[1].GetVirtualId()] != vertices[1] {
+		t.Errorf("HyperEdge ReadExternal should create a placeholder for the unknown vertex '%d'", nodes[1].GetVirtualId())
+	}
+	edges := nodes[0].GetEdges()
+	if edges[len(edges)-1] != types.TGEdge(imported) {
+		t.Errorf("HyperEdge ReadExternal should replace the placeholder edge of the first vertex")
+	}
+	if len(nodes[0].(*Node).GetEdgesForEdgeType(nil, types.DirectionOutbound)) == 0 {
+		t.Errorf("HyperEdge should be an outbound edge of its first vertex")
+	}
+}
@@ -74,6 +74,32 @@ func (obj *Node) GetModifiedAttributes() []types.TGAttribute {
 	return obj.getModifiedAttributes()
 }
 
+// isEdgeInitialized checks whether the edge or hyperedge has been read completely from the server
+func isEdgeInitialized(edge types.TGEdge) bool {
+	if initialized, ok := edge.(interface{ GetIsInitialized() bool }); ok {
+		return initialized.GetIsInitialized()
+	}
+	return true
+}
+
+// edgeDirectionType returns the direction an edge was created with, or the direction of a hyperedge
+func edgeDirectionType(edge types.TGEdge) types.TGDirectionType {
+	if e, ok := edge.(*Edge); ok {
+		return e.directionType
+	}
+	return edge.GetDirectionType()
+}
+
+// replaceEdge swaps the placeholder edge w/ the same virtual id, if any, for the edge that has been read
+func (obj *Node) replaceEdge(edge types.TGEdge) {
+	for i, e := range obj.edges {
+		if e != edge && e.GetVirtualId() == edge.GetVirtualId() {
+			obj.edges[i] = edge
+			return
+		}
+	}
+}
+
 /////////////////////////////////////////////////////////////////
 // Implement functions from Interface ==> TGNode
 /////////////////////////////////////////////////////////////////
@@ -100,7 +126,7 @@ func (obj *Node) GetEdgesForDirectionType(directionType types.TGDirectionType) [
 	}
 
 	for _, edge := range obj.edges {
-		if edge.(*Edge).directionType == directionType {
+		if edgeDirectionType(edge) == directionType {
 			edgesWithDirections = append(edgesWithDirections, edge)
 		}
 	}
@@ -116,7 +142,7 @@ func (obj *Node) GetEdgesForEdgeType(edgeType types.TGEdgeType, direction types.
 
 	if edgeType == nil && direction == types.DirectionAny {
 		for _, edge := range obj.edges {
-			if edge.(*Edge).GetIsInitialized() {
+			if isEdgeInitialized(edge) {
 				edgesWithDirections = append(edgesWithDirections, edge)
 			}
 		}
@@ -124,7 +150,7 @@ func (obj *Node) GetEdgesForEdgeType(edgeType types.TGEdgeType, direction types.
 	}
 
 	for _, edge := range obj.edges {
-		if !edge.(*Edge).GetIsInitialized() {
+		if !isEdgeInitialized(edge) {
 			logger.Warning(fmt.Sprintf("WARNING: Continuing loop Node:GetEdgesForEdgeType - skipping uninitialized edge '%+v'", edge))
 			continue
 		}
@@ -141,9 +167,13 @@ func (obj *Node) GetEdgesForEdgeType(edgeType types.TGEdgeType, direction types.
 				edgesWithDirections = append(edgesWithDirections, edge)
 			}
 		} else {
+			// A hyperedge leads from its first vertex into all the others
 			edgesForThisNode := edge.GetVertices()
-			if obj.GetVirtualId() == edgesForThisNode[1].GetVirtualId() {
-				edgesWithDirections = append(edgesWithDirections, edge)
+			for _, vertex := range edgesForThisNode[1:] {
+				if obj.GetVirtualId() == vertex.GetVirtualId() {
+					edgesWithDirections = append(edgesWithDirections, edge)
+					break
+				}
 			}
 		}
 	}
@@ -291,7 +321,7 @@ func (obj *Node) ReadExternal(is types.TGInputStream) types.TGError {
 			return err
 		}
 		logger.Debug(fmt.Sprintf("Inside Node:ReadExternal read edgeId as '%d'", edgeId))
-		var edge *Edge
+		var edge types.TGEdge
 		var entity types.TGEntity
 		refMap := is.(*iostream.ProtocolDataInputStream).GetReferenceMap()
 		if refMap != nil {
@@ -307,7 +337,7 @@ func (obj *Node) ReadExternal(is types.TGInputStream) types.TGError {
 			edge = edge1
 			logger.Debug(fmt.Sprintf("Inside Node:ReadExternal created new edge: '%+v'", edge))
 		} else {
-			edge = entity.(*Edge)
+			edge = entity.(types.TGEdge)
 		}
 		obj.edges = append(obj.edges, edge)
 		logger.Debug(fmt.Sprintf("Inside Node:ReadExternal Node has '%d' edges & StreamEntityCount is '%d'", len(obj.edges), len(is.(*iostream.ProtocolDataInputStream).GetReferenceMap())))
@@ -268,7 +268,30 @@ func (obj *NodeType) ReadExternal(is types.TGInputStream) types.TGError {
 
 // WriteExternal writes a system object into an appropriate byte format onto an external output stream
 func (obj *NodeType) WriteExternal(os types.TGOutputStream) types.TGError {
-	logger.Warning(fmt.Sprint("WARNING: Returning NodeType:WriteExternal is not implemented"))
+	logger.Log(fmt.Sprint("Entering NodeType:WriteExternal"))
+	// Base Class EntityType's WriteExternal()
+	err := EntityTypeWriteExternal(obj, os)
+	if err != nil {
+		return err
+	}
+	logger.Debug(fmt.Sprint("Inside NodeType:WriteExternal, wrote base entity type's attributes"))
+
+	os.(*iostream.ProtocolDataOutputStream).WriteShort(len(obj.pKeys))
+	for _, pKey := range obj.pKeys {
+		err = os.(*iostream.ProtocolDataOutputStream).WriteUTF(pKey.GetName())
+		if err != nil {
+			logger.Error(fmt.Sprintf("ERROR: Returning NodeType:WriteExternal - unable to write attrName w/ Error: '%+v'", err.Error()))
+			return err
+		}
+	}
+
+	os.(*iostream.ProtocolDataOutputStream).WriteShort(len(obj.idxIds))
+	for _, indexId := range obj.idxIds {
+		os.(*iostream.ProtocolDataOutputStream).WriteInt(indexId)
+	}
+
+	os.(*iostream.ProtocolDataOutputStream).WriteLong(obj.numEntries)
+	logger.Log(fmt.Sprintf("Returning NodeType:WriteExternal w/ NO error, for NodeType: '%+v'", obj))
 	return nil
 }
 
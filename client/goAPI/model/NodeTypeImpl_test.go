@@ -85,3 +85,30 @@ func TestNodeTypeWriteExternal(t *testing.T) {
 	_ = ToBeExportedNodeType.ReadExternal(iNetwork)
 	t.Logf("EntityType ReadExternal imported entity type as '%+v'", ToBeExportedNodeType)
 }
+
+func TestNodeTypeWriteExternalRoundTrip(t *testing.T) {
+	exported := CreateTestNodeType("Account", types.SystemTypeNode, nil)
+	exported.SetEntityTypeId(12)
+	exported.SetNumEntries(42)
+	oNetwork := iostream.DefaultProtocolDataOutputStream()
+	err := exported.WriteExternal(oNetwork)
+	if err != nil {
+		t.Fatalf("NodeType WriteExternal failed w/ error: '%+v'", err)
+	}
+
+	imported := DefaultNodeType()
+	err = imported.ReadExternal(iostream.NewProtocolDataInputStream(oNetwork.GetBuffer()[:oNetwork.GetLength()]))
+	if err != nil {
+		t.Fatalf("NodeType ReadExternal failed w/ error: '%+v'", err)
+	}
+	if imported.GetName() != "Account" || imported.GetEntityTypeId() != 12 || imported.GetSystemType() != types.SystemTypeNode || imported.GetNumEntries() != 42 {
+		t.Errorf("NodeType ReadExternal imported '%+v' instead of '%+v'", imported, exported)
+	}
+	if len(imported.GetAttributeDescriptors()) != 3 || imported.GetAttributeDescriptor("IntegerDesc") == nil {
+		t.Errorf("NodeType ReadExternal imported attributes '%+v'", imported.GetAttributeDescriptors())
+	}
+	pKeys := imported.GetPKeyAttributeDescriptors()
+	if len(pKeys) != 3 || pKeys[0].GetName() != "BoolPkDesc" || pKeys[2].GetName() != "StringPkDesc" {
+		t.Errorf("NodeType ReadExternal imported primary keys '%+v'", pKeys)
+	}
+}
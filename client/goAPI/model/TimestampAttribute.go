@@ -138,6 +138,12 @@ func (obj *TimestampAttribute) SetValue(value interface{}) types.TGError {
 	}
 
 	logger.Log(fmt.Sprintf("Entering TimestampAttribute:SetValue w/ Input value '%+v' is of type: '%+v'\n", value, reflect.TypeOf(value).Kind()))
+	// A time value - as read from the server - is kept as is
+	if v, ok := value.(time.Time); ok {
+		obj.attrValue = v
+		obj.setIsModified(true)
+		return nil
+	}
 	if reflect.TypeOf(value).Kind() != reflect.Int32 &&
 		reflect.TypeOf(value).Kind() != reflect.Int64 &&
 		reflect.TypeOf(value).Kind() != reflect.String {
@@ -32,6 +32,15 @@ import (
 	"strings"
 )
 
+// Get entity commands
+const (
+	GetEntityCommandGet         = 0
+	GetEntityCommandGetById     = 1
+	GetEntityCommandGetMultiple = 2
+	GetEntityCommandContinue    = 10 // Fetch the next batch of an open result
+	GetEntityCommandClose       = 20 // Release an open result on the server
+)
+
 type GetEntityRequestMessage struct {
 	*AbstractProtocolMessage
 	commandType    int16 //0 - get, 1 - getbyid, 2 - get multiples, 10 - continue, 20 - close
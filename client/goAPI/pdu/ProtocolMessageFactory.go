@@ -104,6 +104,24 @@ func CreateMessageForVerb(verbId int) (types.TGMessage, types.TGError) {
 		return DefaultGetLargeObjectRequestMessage(), nil
 	case VerbGetLargeObjectResponse:
 		return DefaultGetLargeObjectResponseMessage(), nil
+	case VerbBeginExportRequest:
+		return DefaultBeginExportRequestMessage(), nil
+	case VerbBeginExportResponse:
+		return DefaultBeginExportResponseMessage(), nil
+	case VerbPartialExportRequest:
+		return DefaultPartialExportRequestMessage(), nil
+	case VerbPartialExportResponse:
+		return DefaultPartialExportResponseMessage(), nil
+	case VerbCancelExportRequest:
+		return DefaultCancelExportRequestMessage(), nil
+	case VerbBeginImportRequest:
+		return DefaultBeginImportRequestMessage(), nil
+	case VerbBeginImportResponse:
+		return DefaultBeginImportResponseMessage(), nil
+	case VerbPartialImportRequest:
+		return DefaultPartialImportRequestMessage(), nil
+	case VerbPartialImportResponse:
+		return DefaultPartialImportResponseMessage(), nil
 	case VerbDumpStacktraceRequest:
 		fallthrough
 		//return DefaultDumpStacktraceRequestMessage(), nil
@@ -181,6 +199,24 @@ func CreateMessageWithToken(verbId int, authToken, sessionId int64) (types.TGMes
 		return NewGetLargeObjectRequestMessage(authToken, sessionId), nil
 	case VerbGetLargeObjectResponse:
 		return NewGetLargeObjectResponseMessage(authToken, sessionId), nil
+	case VerbBeginExportRequest:
+		return NewBeginExportRequestMessage(authToken, sessionId), nil
+	case VerbBeginExportResponse:
+		return NewBeginExportResponseMessage(authToken, sessionId), nil
+	case VerbPartialExportRequest:
+		return NewPartialExportRequestMessage(authToken, sessionId), nil
+	case VerbPartialExportResponse:
+		return NewPartialExportResponseMessage(authToken, sessionId), nil
+	case VerbCancelExportRequest:
+		return NewCancelExportRequestMessage(authToken, sessionId), nil
+	case VerbBeginImportRequest:
+		return NewBeginImportRequestMessage(authToken, sessionId), nil
+	case VerbBeginImportResponse:
+		return NewBeginImportResponseMessage(authToken, sessionId), nil
+	case VerbPartialImportRequest:
+		return NewPartialImportRequestMessage(authToken, sessionId), nil
+	case VerbPartialImportResponse:
+		return NewPartialImportResponseMessage(authToken, sessionId), nil
 	case VerbDumpStacktraceRequest:
 		fallthrough
 		//return NewDumpStacktraceRequestMessage(authToken, sessionId), nil
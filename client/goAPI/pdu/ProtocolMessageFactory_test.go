@@ -94,6 +94,51 @@ func createTestBeginTransactionResponseMessage() *BeginTransactionResponseMessag
 	return msg
 }
 
+func createTestBeginExportRequestMessage() *BeginExportRequestMessage {
+	authToken := rand.Int63()
+	sessionId := rand.Int63()
+	msg := NewBeginExportRequestMessage(authToken, sessionId)
+	msg.SetBatchSize(rand.Intn(10000))
+	msg.BufLength = int(reflect.TypeOf(*msg).Size())
+	return msg
+}
+
+func createTestBeginExportResponseMessage() *BeginExportResponseMessage {
+	authToken := rand.Int63()
+	sessionId := rand.Int63()
+	msg := NewBeginExportResponseMessage(authToken, sessionId)
+	msg.SetExportId(rand.Intn(10000))
+	msg.SetTypeList([]ExportTypeInfo{{TypeName: "Test-NodeType", NumEntities: rand.Int63()}})
+	msg.BufLength = int(reflect.TypeOf(*msg).Size())
+	return msg
+}
+
+func createTestBeginImportRequestMessage() *BeginImportRequestMessage {
+	authToken := rand.Int63()
+	sessionId := rand.Int63()
+	msg := NewBeginImportRequestMessage(authToken, sessionId)
+	msg.BufLength = int(reflect.TypeOf(*msg).Size())
+	return msg
+}
+
+func createTestBeginImportResponseMessage() *BeginImportResponseMessage {
+	authToken := rand.Int63()
+	sessionId := rand.Int63()
+	msg := NewBeginImportResponseMessage(authToken, sessionId)
+	msg.SetImportId(rand.Intn(10000))
+	msg.BufLength = int(reflect.TypeOf(*msg).Size())
+	return msg
+}
+
+func createTestCancelExportRequestMessage() *CancelExportRequestMessage {
+	authToken := rand.Int63()
+	sessionId := rand.Int63()
+	msg := NewCancelExportRequestMessage(authToken, sessionId)
+	msg.SetExportId(rand.Intn(10000))
+	msg.BufLength = int(reflect.TypeOf(*msg).Size())
+	return msg
+}
+
 func createTestCommitTransactionRequestMessage() *CommitTransactionRequest {
 	authToken := rand.Int63()
 	sessionId := rand.Int63()
@@ -252,6 +297,52 @@ func createTestMetadataResponseMessage() *MetadataResponse {
 	return msg
 }
 
+func createTestPartialExportRequestMessage() *PartialExportRequestMessage {
+	authToken := rand.Int63()
+	sessionId := rand.Int63()
+	msg := NewPartialExportRequestMessage(authToken, sessionId)
+	msg.SetExportId(rand.Intn(10000))
+	msg.BufLength = int(reflect.TypeOf(*msg).Size())
+	return msg
+}
+
+func createTestPartialExportResponseMessage() *PartialExportResponseMessage {
+	authToken := rand.Int63()
+	sessionId := rand.Int63()
+	msg := NewPartialExportResponseMessage(authToken, sessionId)
+	msg.SetExportId(rand.Intn(10000))
+	msg.SetTypeName("Test-NodeType")
+	msg.SetBatchIdx(rand.Intn(100))
+	msg.SetHasMore(true)
+	msg.SetData([]byte("Test-Data"))
+	msg.BufLength = int(reflect.TypeOf(*msg).Size())
+	return msg
+}
+
+func createTestPartialImportRequestMessage() *PartialImportRequestMessage {
+	authToken := rand.Int63()
+	sessionId := rand.Int63()
+	msg := NewPartialImportRequestMessage(authToken, sessionId)
+	msg.SetImportId(rand.Intn(10000))
+	msg.SetTypeName("Test-NodeType")
+	msg.SetBatchIdx(rand.Intn(100))
+	msg.SetHasMore(true)
+	msg.SetData([]byte("Test-Data"))
+	msg.BufLength = int(reflect.TypeOf(*msg).Size())
+	return msg
+}
+
+func createTestPartialImportResponseMessage() *PartialImportResponseMessage {
+	authToken := rand.Int63()
+	sessionId := rand.Int63()
+	msg := NewPartialImportResponseMessage(authToken, sessionId)
+	msg.SetImportId(rand.Intn(10000))
+	msg.SetTypeName("Test-NodeType")
+	msg.SetNumEntities(rand.Int63())
+	msg.BufLength = int(reflect.TypeOf(*msg).Size())
+	return msg
+}
+
 func createTestPingMessage() *PingMessage {
 	authToken := rand.Int63()
 	sessionId := rand.Int63()
@@ -406,6 +497,24 @@ func createTestMessageForVerb(verbId int) types.TGMessage {
 		return createTestGetLargeObjectRequestMessage()
 	case VerbGetLargeObjectResponse:
 		return createTestGetLargeObjectResponseMessage()
+	case VerbBeginExportRequest:
+		return createTestBeginExportRequestMessage()
+	case VerbBeginExportResponse:
+		return createTestBeginExportResponseMessage()
+	case VerbPartialExportRequest:
+		return createTestPartialExportRequestMessage()
+	case VerbPartialExportResponse:
+		return createTestPartialExportResponseMessage()
+	case VerbCancelExportRequest:
+		return createTestCancelExportRequestMessage()
+	case VerbBeginImportRequest:
+		return createTestBeginImportRequestMessage()
+	case VerbBeginImportResponse:
+		return createTestBeginImportResponseMessage()
+	case VerbPartialImportRequest:
+		return createTestPartialImportRequestMessage()
+	case VerbPartialImportResponse:
+		return createTestPartialImportResponseMessage()
 	case VerbDumpStacktraceRequest:
 		fallthrough
 		//return createTestDumpStacktraceRequestMessage()
@@ -573,3 +682,49 @@ func TestUpdateSequenceAndTimeStamp(t *testing.T) {
 		t.Logf("MessageFactory::UpdateSequenceAndTimeStamp updated messages w/ '%+v'", ts)
 	}
 }
+
+func TestExportImportPayloads(t *testing.T) {
+	exportMsg := createTestPartialExportResponseMessage()
+	buf, bufLen, err := exportMsg.ToBytes()
+	if err != nil {
+		t.Fatalf("PartialExportResponseMessage could not generate buffer w/ error: '%+v'", err)
+	}
+	constructedMsg, err := CreateMessageFromBuffer(buf[0:bufLen], 0, bufLen)
+	if err != nil {
+		t.Fatalf("MessageFactory could not recreate PartialExportResponseMessage w/ error: '%+v'", err)
+	}
+	exportResp := constructedMsg.(*PartialExportResponseMessage)
+	if exportResp.GetExportId() != exportMsg.GetExportId() || exportResp.GetTypeName() != exportMsg.GetTypeName() ||
+		exportResp.GetBatchIdx() != exportMsg.GetBatchIdx() || !exportResp.GetHasMore() || string(exportResp.GetData()) != "Test-Data" {
+		t.Errorf("PartialExportResponseMessage payload '%+v' does not match '%+v'", exportResp.String(), exportMsg.String())
+	}
+
+	beginMsg := createTestBeginExportResponseMessage()
+	buf, bufLen, err = beginMsg.ToBytes()
+	if err != nil {
+		t.Fatalf("BeginExportResponseMessage could not generate buffer w/ error: '%+v'", err)
+	}
+	constructedMsg, err = CreateMessageFromBuffer(buf[0:bufLen], 0, bufLen)
+	if err != nil {
+		t.Fatalf("MessageFactory could not recreate BeginExportResponseMessage w/ error: '%+v'", err)
+	}
+	beginResp := constructedMsg.(*BeginExportResponseMessage)
+	if beginResp.GetExportId() != beginMsg.GetExportId() || !reflect.DeepEqual(beginResp.GetTypeList(), beginMsg.GetTypeList()) {
+		t.Errorf("BeginExportResponseMessage payload '%+v' does not match '%+v'", beginResp.String(), beginMsg.String())
+	}
+
+	importMsg := createTestPartialImportRequestMessage()
+	importMsg.SetHasMore(false)
+	buf, bufLen, err = importMsg.ToBytes()
+	if err != nil {
+		t.Fatalf("PartialImportRequestMessage could not generate buffer w/ error: '%+v'", err)
+	}
+	constructedMsg, err = CreateMessageFromBuffer(buf[0:bufLen], 0, bufLen)
+	if err != nil {
+		t.Fatalf("MessageFactory could not recreate PartialImportRequestMessage w/ error: '%+v'", err)
+	}
+	importReq := constructedMsg.(*PartialImportRequestMessage)
+	if importReq.GetImportId() != importMsg.GetImportId() || importReq.GetHasMore() || string(importReq.GetData()) != "Test-Data" {
+		t.Errorf("PartialImportRequestMessage payload '%+v' does not match '%+v'", importReq.String(), importMsg.String())
+	}
+}
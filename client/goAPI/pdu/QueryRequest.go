@@ -32,6 +32,11 @@ import (
 	"strings"
 )
 
+// queryParameterWriter is implemented by a query object whose parameter values are sent w/ EXECUTEID
+type queryParameterWriter interface {
+	WriteParameters(os types.TGOutputStream) types.TGError
+}
+
 type QueryRequestMessage struct {
 	*AbstractProtocolMessage
 	queryExpr       string
@@ -497,6 +502,14 @@ func (msg *QueryRequestMessage) WritePayload(os types.TGOutputStream) types.TGEr
 	} else if msg.GetCommand() == 5 || msg.GetCommand() == 6 {
 		// EXECUTED, CLOSE
 		os.(*iostream.ProtocolDataOutputStream).WriteLong(msg.GetQueryHashId())
+		// EXECUTED w/ the values bound to the named parameters of the query object, if any
+		if writer, ok := msg.queryObject.(queryParameterWriter); ok && msg.GetCommand() == 5 {
+			err := writer.WriteParameters(os)
+			if err != nil {
+				logger.Error(fmt.Sprint("ERROR: Returning QueryRequestMessage:WritePayload w/ Error in writing query parameters to message buffer"))
+				return err
+			}
+		}
 	}
 	currPos := os.GetPosition()
 	length := currPos - startPos
@@ -61,16 +61,19 @@ const (
 	// Get LargeObject
 	VerbGetLargeObjectRequest  int = 23
 	VerbGetLargeObjectResponse int = 24
-	// Import/Export verbs - They are admin request, and not supported by Java
+	// Import/Export verbs - They are admin requests, and not supported by Java. The ids are the server's, but the
+	// payloads of these messages are not published by the server - their layouts are experimental, and may change.
 	VerbBeginExportRequest    int = 25
 	VerbBeginExportResponse   int = 26
 	VerbPartialExportRequest  int = 27
 	VerbPartialExportResponse int = 28
 	VerbCancelExportRequest   int = 29
 	VerbBeginImportRequest    int = 31
-	BeginImportResponse       int = 32
+	VerbBeginImportResponse   int = 32
 	VerbPartialImportRequest  int = 33
 	VerbPartialImportResponse int = 34
+	// Deprecated: use VerbBeginImportResponse instead.
+	BeginImportResponse = VerbBeginImportResponse
 	// Dump Stacktrace request verb
 	VerbDumpStacktraceRequest int = 39
 	// Disconnect Request verbs
@@ -114,6 +117,15 @@ var PreDefinedVerbs = map[int]CommandVerbs{
 	VerbGetEntityResponse:           {id: VerbGetEntityResponse, name: "VerbGetEntityResponse", implementor: "pdu.VerbGetEntityResponse"}, //Represented in ms. Default Value is 10sec
 	VerbGetLargeObjectRequest:       {id: VerbGetLargeObjectRequest, name: "VerbGetLargeObjectRequest", implementor: "pdu.VerbGetLargeObjectRequest"},
 	VerbGetLargeObjectResponse:      {id: VerbGetLargeObjectResponse, name: "VerbGetLargeObjectResponse", implementor: "pdu.VerbGetLargeObjectResponse"},
+	VerbBeginExportRequest:          {id: VerbBeginExportRequest, name: "VerbBeginExportRequest", implementor: "pdu.VerbBeginExportRequest"},
+	VerbBeginExportResponse:         {id: VerbBeginExportResponse, name: "VerbBeginExportResponse", implementor: "pdu.VerbBeginExportResponse"},
+	VerbPartialExportRequest:        {id: VerbPartialExportRequest, name: "VerbPartialExportRequest", implementor: "pdu.VerbPartialExportRequest"},
+	VerbPartialExportResponse:       {id: VerbPartialExportResponse, name: "VerbPartialExportResponse", implementor: "pdu.VerbPartialExportResponse"},
+	VerbCancelExportRequest:         {id: VerbCancelExportRequest, name: "VerbCancelExportRequest", implementor: "pdu.VerbCancelExportRequest"},
+	VerbBeginImportRequest:          {id: VerbBeginImportRequest, name: "VerbBeginImportRequest", implementor: "pdu.VerbBeginImportRequest"},
+	VerbBeginImportResponse:         {id: VerbBeginImportResponse, name: "VerbBeginImportResponse", implementor: "pdu.VerbBeginImportResponse"},
+	VerbPartialImportRequest:        {id: VerbPartialImportRequest, name: "VerbPartialImportRequest", implementor: "pdu.VerbPartialImportRequest"},
+	VerbPartialImportResponse:       {id: VerbPartialImportResponse, name: "VerbPartialImportResponse", implementor: "pdu.VerbPartialImportResponse"},
 	VerbDumpStacktraceRequest:       {id: VerbDumpStacktraceRequest, name: "VerbDumpStacktraceRequest", implementor: "pdu.VerbDumpStacktraceRequest"},
 	VerbDisconnectChannelRequest:    {id: VerbDisconnectChannelRequest, name: "VerbDisconnectChannelRequest", implementor: "pdu.VerbDisconnectChannelRequest"},
 	VerbSessionForcefullyTerminated: {id: VerbSessionForcefullyTerminated, name: "VerbSessionForcefullyTerminated", implementor: "pdu.VerbSessionForcefullyTerminated"},
@@ -0,0 +1,340 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: Filter.go
+ * SVN id: $id: $
+ *
+ */
+
+package query
+
+import (
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The filters below compile into the query expression syntax understood by the server, e.g.
+//
+//	query.And(query.IsNodeType("Member"), query.Or(query.Gt("age", 30), query.In("name", "Alice", "Bob")))
+//
+// compiles into "@nodetype = 'Member' and (age > 30 or (name = 'Alice' or name = 'Bob'))". Ranges and IN lists
+// are expanded into comparisons, and a value created w/ Param is left as a named parameter of a prepared query.
+
+// Operator compares an attribute, or a traversal keyword, w/ a value
+type Operator string
+
+const (
+	OperatorEqual          Operator = "="
+	OperatorNotEqual       Operator = "<>"
+	OperatorLessThan       Operator = "<"
+	OperatorLessOrEqual    Operator = "<="
+	OperatorGreaterThan    Operator = ">"
+	OperatorGreaterOrEqual Operator = ">="
+)
+
+const (
+	connectiveAnd = "and"
+	connectiveOr  = "or"
+
+	nodeTypeKeyword = "@nodetype"
+	edgeTypeKeyword = "@edgetype"
+)
+
+// identifierPattern matches the attribute names that can be used in a comparison
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Parameter is a filter value bound later through the setters of TGQuery, rather than compiled into the expression
+type Parameter string
+
+// Param refers to the named query parameter '$name'
+func Param(name string) Parameter {
+	return Parameter(name)
+}
+
+// comparisonFilter compares an attribute w/ a value. An invalid attribute name is recorded in err when the filter
+// is built, and returned by GetExpression.
+type comparisonFilter struct {
+	attrName string
+	operator Operator
+	value    interface{}
+	err      types.TGError
+}
+
+// typeFilter tests whether an entity is of one of the node or edge types
+type typeFilter struct {
+	keyword   string
+	typeNames []string
+}
+
+// compositeFilter joins the filters w/ 'and' or 'or'
+type compositeFilter struct {
+	connective string
+	filters    []types.TGFilter
+}
+
+// negatedFilter inverts a filter
+type negatedFilter struct {
+	filter types.TGFilter
+}
+
+// Compare matches entities whose attribute compares to the value as per the operator
+func Compare(attrName string, operator Operator, value interface{}) types.TGFilter {
+	return &comparisonFilter{attrName: attrName, operator: operator, value: value, err: checkAttrName(attrName)}
+}
+
+// Eq matches entities whose attribute is equal to the value
+func Eq(attrName string, value interface{}) types.TGFilter {
+	return Compare(attrName, OperatorEqual, value)
+}
+
+// Ne matches entities whose attribute is not equal to the value
+func Ne(attrName string, value interface{}) types.TGFilter {
+	return Compare(attrName, OperatorNotEqual, value)
+}
+
+// Lt matches entities whose attribute is less than the value
+func Lt(attrName string, value interface{}) types.TGFilter {
+	return Compare(attrName, OperatorLessThan, value)
+}
+
+// Le matches entities whose attribute is less than or equal to the value
+func Le(attrName string, value interface{}) types.TGFilter {
+	return Compare(attrName, OperatorLessOrEqual, value)
+}
+
+// Gt matches entities whose attribute is greater than the value
+func Gt(attrName string, value interface{}) types.TGFilter {
+	return Compare(attrName, OperatorGreaterThan, value)
+}
+
+// Ge matches entities whose attribute is greater than or equal to the value
+func Ge(attrName string, value interface{}) types.TGFilter {
+	return Compare(attrName, OperatorGreaterOrEqual, value)
+}
+
+// Between matches entities whose attribute lies within the inclusive range
+func Between(attrName string, lower interface{}, upper interface{}) types.TGFilter {
+	return And(Ge(attrName, lower), Le(attrName, upper))
+}
+
+// In matches entities whose attribute is equal to one of the values
+func In(attrName string, values ...interface{}) types.TGFilter {
+	filters := make([]types.TGFilter, 0, len(values))
+	for _, value := range values {
+		filters = append(filters, Eq(attrName, value))
+	}
+	return &compositeFilter{connective: connectiveOr, filters: filters}
+}
+
+// IsNodeType matches nodes of any of the node types
+func IsNodeType(typeNames ...string) types.TGFilter {
+	return &typeFilter{keyword: nodeTypeKeyword, typeNames: typeNames}
+}
+
+// IsEdgeType matches edges of any of the edge types
+func IsEdgeType(typeNames ...string) types.TGFilter {
+	return &typeFilter{keyword: edgeTypeKeyword, typeNames: typeNames}
+}
+
+// And matches entities that match all the filters
+func And(filters ...types.TGFilter) types.TGFilter {
+	return &compositeFilter{connective: connectiveAnd, filters: filters}
+}
+
+// Or matches entities that match any of the filters
+func Or(filters ...types.TGFilter) types.TGFilter {
+	return &compositeFilter{connective: connectiveOr, filters: filters}
+}
+
+// Not matches entities that do not match the filter
+func Not(filter types.TGFilter) types.TGFilter {
+	return &negatedFilter{filter: filter}
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for Filter
+/////////////////////////////////////////////////////////////////
+
+func invalidFilterError(errMsg string) types.TGError {
+	logger.Error(fmt.Sprintf("ERROR: Filter - %s", errMsg))
+	return exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+}
+
+// checkAttrName verifies that the attribute name is an identifier - optionally scoped to the edge or one of the
+// nodes of a traversal - or one of the keywords of the traversal conditions, so that it cannot alter the expression
+func checkAttrName(attrName string) types.TGError {
+	if attrName == "" {
+		return invalidFilterError("Attribute name of a comparison cannot be empty")
+	}
+	if attrName == degreeKeyword || attrName == isFromEdgeKeyword {
+		return nil
+	}
+	name := attrName
+	for _, prefix := range []string{edgeAttrPrefix, fromNodeAttrPrefix, toNodeAttrPrefix} {
+		if strings.HasPrefix(name, prefix) {
+			name = name[len(prefix):]
+			break
+		}
+	}
+	if !identifierPattern.MatchString(name) {
+		return invalidFilterError(fmt.Sprintf("Attribute name '%s' of a comparison is not a valid identifier", attrName))
+	}
+	return nil
+}
+
+// isValidOperator checks for one of the comparison operators
+func isValidOperator(operator Operator) bool {
+	switch operator {
+	case OperatorEqual, OperatorNotEqual, OperatorLessThan, OperatorLessOrEqual, OperatorGreaterThan, OperatorGreaterOrEqual:
+		return true
+	}
+	return false
+}
+
+// quoteString encloses the string in single quotes, doubling any embedded quote. Backslashes are doubled too, as a
+// backslash escapes the next character of a quoted literal - see scanQueryParameters - so that a value ending w/ a
+// backslash cannot leave the literal open. Every literal of a query expression built on the client is quoted here.
+func quoteString(value string) string {
+	return "'" + strings.Replace(strings.Replace(value, "\\", "\\\\", -1), "'", "''", -1) + "'"
+}
+
+// formatValue renders a value as a literal of the query expression
+func formatValue(value interface{}) (string, types.TGError) {
+	switch v := value.(type) {
+	case Parameter:
+		if v == "" {
+			return "", invalidFilterError("Query parameter name cannot be empty")
+		}
+		return string(QueryParameterPrefix) + string(v), nil
+	case string:
+		return quoteString(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	return "", invalidFilterError(fmt.Sprintf("Value '%+v' of type '%T' is not supported in a filter", value, value))
+}
+
+// nestedExpression compiles a filter used as the operand of another one, enclosing it in parentheses if needed
+func nestedExpression(filter types.TGFilter) (string, types.TGError) {
+	if filter == nil {
+		return "", invalidFilterError("Filter cannot be nil")
+	}
+	expr, err := filter.GetExpression()
+	if err != nil {
+		return "", err
+	}
+	if composite, ok := filter.(*compositeFilter); ok && len(composite.filters) > 1 {
+		return "(" + expr + ")", nil
+	}
+	if tf, ok := filter.(*typeFilter); ok && len(tf.typeNames) > 1 {
+		return "(" + expr + ")", nil
+	}
+	return expr, nil
+}
+
+func filterToString(filter types.TGFilter) string {
+	expr, err := filter.GetExpression()
+	if err != nil {
+		return fmt.Sprintf("Filter:{Error: %s}", err.Error())
+	}
+	return fmt.Sprintf("Filter:{Expression: %s}", expr)
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> TGFilter
+/////////////////////////////////////////////////////////////////
+
+// GetExpression compiles the filter into a query expression - without the terminating ';'
+func (obj *comparisonFilter) GetExpression() (string, types.TGError) {
+	if obj.err != nil {
+		return "", obj.err
+	}
+	if !isValidOperator(obj.operator) {
+		return "", invalidFilterError(fmt.Sprintf("Operator '%s' is not supported in a filter", obj.operator))
+	}
+	if obj.value == nil {
+		return "", invalidFilterError(fmt.Sprintf("Attribute '%s' cannot be compared w/ a null value", obj.attrName))
+	}
+	value, err := formatValue(obj.value)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s %s", obj.attrName, obj.operator, value), nil
+}
+
+func (obj *comparisonFilter) String() string {
+	return filterToString(obj)
+}
+
+// GetExpression compiles the filter into a query expression - without the terminating ';'
+func (obj *typeFilter) GetExpression() (string, types.TGError) {
+	if len(obj.typeNames) == 0 {
+		return "", invalidFilterError(fmt.Sprintf("Filter on '%s' needs at least one type name", obj.keyword))
+	}
+	terms := make([]string, 0, len(obj.typeNames))
+	for _, typeName := range obj.typeNames {
+		terms = append(terms, fmt.Sprintf("%s = %s", obj.keyword, quoteString(typeName)))
+	}
+	return strings.Join(terms, " "+connectiveOr+" "), nil
+}
+
+func (obj *typeFilter) String() string {
+	return filterToString(obj)
+}
+
+// GetExpression compiles the filter into a query expression - without the terminating ';'
+func (obj *compositeFilter) GetExpression() (string, types.TGError) {
+	if len(obj.filters) == 0 {
+		return "", invalidFilterError(fmt.Sprintf("Filter '%s' needs at least one operand", obj.connective))
+	}
+	terms := make([]string, 0, len(obj.filters))
+	for _, filter := range obj.filters {
+		expr, err := nestedExpression(filter)
+		if err != nil {
+			return "", err
+		}
+		terms = append(terms, expr)
+	}
+	return strings.Join(terms, " "+obj.connective+" "), nil
+}
+
+func (obj *compositeFilter) String() string {
+	return filterToString(obj)
+}
+
+// GetExpression compiles the filter into a query expression - without the terminating ';'
+func (obj *negatedFilter) GetExpression() (string, types.TGError) {
+	if obj.filter == nil {
+		return "", invalidFilterError("Filter cannot be nil")
+	}
+	expr, err := obj.filter.GetExpression()
+	if err != nil {
+		return "", err
+	}
+	return "not (" + expr + ")", nil
+}
+
+func (obj *negatedFilter) String() string {
+	return filterToString(obj)
+}
@@ -0,0 +1,79 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: Filter_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package query
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"testing"
+)
+
+func TestFilterGetExpression(t *testing.T) {
+	testCases := []struct {
+		filter types.TGFilter
+		expr   string
+	}{
+		{Eq("name", "O'Brien"), "name = 'O''Brien'"},
+		{And(Eq("path", `C:\dir\`), Eq("name", Param("name"))), `path = 'C:\\dir\\' and name = $name`},
+		{And(IsNodeType("Member"), Gt("age", 30), Le("level", 4.5)), "@nodetype = 'Member' and age > 30 and level <= 4.5"},
+		{And(IsNodeType("Member", "Guest"), Or(Lt("age", 20), Ne("active", true))), "(@nodetype = 'Member' or @nodetype = 'Guest') and (age < 20 or active <> true)"},
+		{And(IsEdgeType("knows"), Between("since", int64(2000), int64(2010))), "@edgetype = 'knows' and (since >= 2000 and since <= 2010)"},
+		{Or(In("name", "Alice", "Bob"), Not(In("name", "Carol"))), "(name = 'Alice' or name = 'Bob') or not (name = 'Carol')"},
+		{And(IsNodeType("Member"), Ge("age", Param("minAge"))), "@nodetype = 'Member' and age >= $minAge"},
+		{And(Gt("@edge.since", 2000), Lt("@degree", 3)), "@edge.since > 2000 and @degree < 3"},
+	}
+	for _, testCase := range testCases {
+		expr, err := testCase.filter.GetExpression()
+		if err != nil {
+			t.Errorf("TestFilterGetExpression unable to compile '%s' w/ error '%s'", testCase.expr, err.Error())
+		} else if expr != testCase.expr {
+			t.Errorf("TestFilterGetExpression expected '%s' and not '%s'", testCase.expr, expr)
+		}
+	}
+}
+
+func TestFilterParameterAfterBackslash(t *testing.T) {
+	expr, err := And(Eq("a", `x\`), Eq("b", Param("p"))).GetExpression()
+	if err != nil {
+		t.Fatalf("TestFilterParameterAfterBackslash unable to compile w/ error '%s'", err.Error())
+	}
+	if names := ParseQueryParameterNames(expr); len(names) != 1 || names[0] != "p" {
+		t.Errorf("TestFilterParameterAfterBackslash expected parameter 'p' in '%s' and not '%+v'", expr, names)
+	}
+}
+
+func TestFilterGetExpressionErrors(t *testing.T) {
+	invalidFilters := []types.TGFilter{
+		Eq("", 1),
+		Eq("age = 1 or 1", 1),
+		Gt("@edge.since; drop", 1),
+		Eq("@nodetype", "Member"),
+		Eq("name", nil),
+		Eq("name", []int{1}),
+		In("name"),
+		IsNodeType(),
+		And(),
+		Not(Or(Eq("age", 1), nil)),
+	}
+	for _, filter := range invalidFilters {
+		if expr, err := filter.GetExpression(); err == nil {
+			t.Errorf("TestFilterGetExpressionErrors expected an error and not '%s'", expr)
+		}
+	}
+}
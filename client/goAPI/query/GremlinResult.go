@@ -26,8 +26,10 @@ import (
 	"fmt"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/iostream"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/mapper"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"reflect"
 )
 
 // ======= Various Element Types for Gremlin Results =======
@@ -44,34 +46,31 @@ const (
 )
 
 func (elementType ElementType) String() string {
-	// Use a buffer for efficient string concatenation
-	var buffer bytes.Buffer
-
-	if elementType&ElementTypeInvalid == ElementTypeInvalid {
-		buffer.WriteString("ElementTypeInvalid")
-	} else if elementType&ElementTypeList == ElementTypeList {
-		buffer.WriteString("ElementTypeList")
-	} else if elementType&ElementTypeAttr == ElementTypeAttr {
-		buffer.WriteString("ElementTypeAttr")
-	} else if elementType&ElementTypeAttrValue == ElementTypeAttrValue {
-		buffer.WriteString("ElementTypeAttrValue")
-	} else if elementType&ElementTypeAttrValueTransient == ElementTypeAttrValueTransient {
-		buffer.WriteString("ElementTypeAttrValueTransient")
-	} else if elementType&ElementTypeEntity == ElementTypeEntity {
-		buffer.WriteString("ElementTypeEntity")
-	} else if elementType&ElementTypeMap == ElementTypeMap {
-		buffer.WriteString("ElementTypeMap")
-	}
-	if buffer.Len() == 0 {
-		return ""
+	switch elementType {
+	case ElementTypeInvalid:
+		return "ElementTypeInvalid"
+	case ElementTypeList:
+		return "ElementTypeList"
+	case ElementTypeAttr:
+		return "ElementTypeAttr"
+	case ElementTypeAttrValue:
+		return "ElementTypeAttrValue"
+	case ElementTypeAttrValueTransient:
+		return "ElementTypeAttrValueTransient"
+	case ElementTypeEntity:
+		return "ElementTypeEntity"
+	case ElementTypeMap:
+		return "ElementTypeMap"
 	}
-	return buffer.String()
+	return ""
 }
 
 /////////////////////////////////////////////////////////////////
 // Helper functions for Gremlin Result
 /////////////////////////////////////////////////////////////////
 
+// FillCollection reads the Gremlin response collection from the entity stream into col.
+// Deprecated: elements appended to col are not visible to the caller - use ReadGremlinResult instead.
 func FillCollection(entityStream types.TGInputStream, gof types.TGGraphObjectFactory, col []interface{}) types.TGError {
 	//logger.Log(fmt.Sprint("Entering GremlinResult:FillCollection"))
 	eleType, err := entityStream.(*iostream.ProtocolDataInputStream).ReadByte()
@@ -92,6 +91,8 @@ func FillCollection(entityStream types.TGInputStream, gof types.TGGraphObjectFac
 	return nil
 }
 
+// ConstructList reads a Gremlin response list from the entity stream into col.
+// Deprecated: elements appended to col are not visible to the caller - use ReadGremlinResult instead.
 func ConstructList(entityStream types.TGInputStream, gof types.TGGraphObjectFactory, col []interface{}) types.TGError {
 	logger.Log(fmt.Sprint("Entering GremlinResult:ConstructList"))
 	size, err := entityStream.(*iostream.ProtocolDataInputStream).ReadInt()
@@ -176,7 +177,7 @@ func ConstructList(entityStream types.TGInputStream, gof types.TGGraphObjectFact
 			_ = ConstructMap(entityStream, gof, mapElem)
 			col = append(col, mapElem)
 		} else if ElementType(eleType) == ElementTypeAttr || ElementType(eleType) == ElementTypeAttrValue || ElementType(eleType) == ElementTypeAttrValueTransient {
-			attr, err := model.ReadExternalForEntity(dummyNode, entityStream)
+			attr, err := model.ReadExternalForEntity(dummyNode.(*model.Node).AbstractEntity, entityStream)
 			if err != nil {
 				logger.Error(fmt.Sprintf("ERROR: Returning AbstractEntity:AbstractEntityReadExternal - unable to read attr w/ Error: '%+v'", err.Error()))
 				return err
@@ -195,6 +196,8 @@ func ConstructList(entityStream types.TGInputStream, gof types.TGGraphObjectFact
 	return nil
 }
 
+// ConstructMap reads a Gremlin response map from the entity stream into colMap.
+// Deprecated: use ReadGremlinResult instead.
 func ConstructMap(entityStream types.TGInputStream, gof types.TGGraphObjectFactory, colMap map[string]interface{}) types.TGError {
 	logger.Log(fmt.Sprint("Entering GremlinResult:ConstructMap"))
 	size, err := entityStream.(*iostream.ProtocolDataInputStream).ReadInt()
@@ -280,7 +283,7 @@ func ConstructMap(entityStream types.TGInputStream, gof types.TGGraphObjectFacto
 				break
 			}
 		} else if ElementType(eleType) == ElementTypeAttr || ElementType(eleType) == ElementTypeAttrValue || ElementType(eleType) == ElementTypeAttrValueTransient {
-			attr, err := model.ReadExternalForEntity(dummyNode, entityStream)
+			attr, err := model.ReadExternalForEntity(dummyNode.(*model.Node).AbstractEntity, entityStream)
 			if err != nil {
 				logger.Error(fmt.Sprintf("ERROR: Returning AbstractEntity:AbstractEntityReadExternal - unable to read attr w/ Error: '%+v'", err.Error()))
 				return err
@@ -306,3 +309,504 @@ func ConstructMap(entityStream types.TGInputStream, gof types.TGGraphObjectFacto
 	return nil
 }
 
+
+/////////////////////////////////////////////////////////////////
+// Typed Gremlin Result
+/////////////////////////////////////////////////////////////////
+
+// GremlinResult is a single element of a Gremlin response. Lists and maps hold nested GremlinResult elements,
+// so that callers can walk the response using typed accessors instead of type-asserting nested interface slices.
+type GremlinResult struct {
+	elementType ElementType
+	value       interface{}
+	attrType    int
+}
+
+func DefaultGremlinResult() *GremlinResult {
+	newResult := GremlinResult{
+		elementType: ElementTypeInvalid,
+		attrType:    types.AttributeTypeInvalid,
+	}
+	return &newResult
+}
+
+func NewGremlinResult(elementType ElementType, value interface{}) *GremlinResult {
+	newResult := DefaultGremlinResult()
+	newResult.elementType = elementType
+	newResult.value = value
+	return newResult
+}
+
+// newGremlinAttrValue creates an attribute value element that remembers the type of the attribute it was read from
+func newGremlinAttrValue(elementType ElementType, attr types.TGAttribute) *GremlinResult {
+	newResult := NewGremlinResult(elementType, attr.GetValue())
+	if attr.GetAttributeDescriptor() != nil {
+		newResult.attrType = attr.GetAttributeDescriptor().GetAttrType()
+	}
+	return newResult
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for GremlinResult
+/////////////////////////////////////////////////////////////////
+
+func (obj *GremlinResult) elementTypeError(expected ElementType) types.TGError {
+	errMsg := fmt.Sprintf("GremlinResult element is of type '%s' and not '%s'", obj.elementType.String(), expected.String())
+	return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, "")
+}
+
+func (obj *GremlinResult) valueTypeError() types.TGError {
+	errMsg := fmt.Sprintf("GremlinResult element of type '%s' holds a value of type '%T'", obj.elementType.String(), obj.value)
+	return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, "")
+}
+
+// attributeType returns the type of the attribute the value was read from, or the type inferred from the value
+func (obj *GremlinResult) attributeType() int {
+	if obj.attrType != types.AttributeTypeInvalid {
+		return obj.attrType
+	}
+	return mapper.AttributeTypeOfValue(obj.value)
+}
+
+/////////////////////////////////////////////////////////////////
+// Helper functions for GremlinResult
+/////////////////////////////////////////////////////////////////
+
+// GetElementType returns the element type of this result
+func (obj *GremlinResult) GetElementType() ElementType {
+	return obj.elementType
+}
+
+// IsNull checks whether the element does not carry any value
+func (obj *GremlinResult) IsNull() bool {
+	return obj.value == nil
+}
+
+// GetAttribute returns the attribute held by an ElementTypeAttr element
+func (obj *GremlinResult) GetAttribute() (types.TGAttribute, types.TGError) {
+	if obj.elementType != ElementTypeAttr {
+		return nil, obj.elementTypeError(ElementTypeAttr)
+	}
+	if obj.value == nil {
+		return nil, nil
+	}
+	attr, ok := obj.value.(types.TGAttribute)
+	if !ok {
+		return nil, obj.valueTypeError()
+	}
+	return attr, nil
+}
+
+// GetAttributeValue returns the value held by an ElementTypeAttrValue or ElementTypeAttrValueTransient element.
+// For an ElementTypeAttr element, the value of the attribute is returned.
+func (obj *GremlinResult) GetAttributeValue() (interface{}, types.TGError) {
+	switch obj.elementType {
+	case ElementTypeAttrValue, ElementTypeAttrValueTransient:
+		return obj.value, nil
+	case ElementTypeAttr:
+		attr, err := obj.GetAttribute()
+		if err != nil || attr == nil {
+			return nil, err
+		}
+		return attr.GetValue(), nil
+	}
+	return nil, obj.elementTypeError(ElementTypeAttrValue)
+}
+
+// GetEntity returns the node or edge held by an ElementTypeEntity element
+func (obj *GremlinResult) GetEntity() (types.TGEntity, types.TGError) {
+	if obj.elementType != ElementTypeEntity {
+		return nil, obj.elementTypeError(ElementTypeEntity)
+	}
+	if obj.value == nil {
+		return nil, nil
+	}
+	entity, ok := obj.value.(types.TGEntity)
+	if !ok {
+		return nil, obj.valueTypeError()
+	}
+	return entity, nil
+}
+
+// GetNode returns the node held by an ElementTypeEntity element
+func (obj *GremlinResult) GetNode() (types.TGNode, types.TGError) {
+	entity, err := obj.GetEntity()
+	if err != nil || entity == nil {
+		return nil, err
+	}
+	node, ok := entity.(types.TGNode)
+	if !ok {
+		errMsg := fmt.Sprintf("GremlinResult entity of kind '%s' is not a node", entity.GetEntityKind().String())
+		return nil, exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, "")
+	}
+	return node, nil
+}
+
+// GetEdge returns the edge held by an ElementTypeEntity element
+func (obj *GremlinResult) GetEdge() (types.TGEdge, types.TGError) {
+	entity, err := obj.GetEntity()
+	if err != nil || entity == nil {
+		return nil, err
+	}
+	edge, ok := entity.(types.TGEdge)
+	if !ok {
+		errMsg := fmt.Sprintf("GremlinResult entity of kind '%s' is not an edge", entity.GetEntityKind().String())
+		return nil, exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, "")
+	}
+	return edge, nil
+}
+
+// GetList returns the elements of an ElementTypeList element
+func (obj *GremlinResult) GetList() ([]*GremlinResult, types.TGError) {
+	if obj.elementType != ElementTypeList {
+		return nil, obj.elementTypeError(ElementTypeList)
+	}
+	if obj.value == nil {
+		return nil, nil
+	}
+	list, ok := obj.value.([]*GremlinResult)
+	if !ok {
+		return nil, obj.valueTypeError()
+	}
+	return list, nil
+}
+
+// GetMap returns the entries of an ElementTypeMap element
+func (obj *GremlinResult) GetMap() (map[string]*GremlinResult, types.TGError) {
+	if obj.elementType != ElementTypeMap {
+		return nil, obj.elementTypeError(ElementTypeMap)
+	}
+	if obj.value == nil {
+		return nil, nil
+	}
+	entries, ok := obj.value.(map[string]*GremlinResult)
+	if !ok {
+		return nil, obj.valueTypeError()
+	}
+	return entries, nil
+}
+
+// GetValue returns the element as plain Go values - lists become []interface{}, maps become map[string]interface{},
+// attribute values are returned as is, and attributes and entities are returned as TGAttribute and TGEntity
+func (obj *GremlinResult) GetValue() interface{} {
+	switch value := obj.value.(type) {
+	case []*GremlinResult:
+		col := make([]interface{}, 0, len(value))
+		for _, element := range value {
+			col = append(col, element.GetValue())
+		}
+		return col
+	case map[string]*GremlinResult:
+		colMap := make(map[string]interface{}, len(value))
+		for key, element := range value {
+			colMap[key] = element.GetValue()
+		}
+		return colMap
+	}
+	return obj.value
+}
+
+// Size returns the number of elements of a list or a map, 1 for any other non-null element
+func (obj *GremlinResult) Size() int {
+	switch value := obj.value.(type) {
+	case []*GremlinResult:
+		return len(value)
+	case map[string]*GremlinResult:
+		return len(value)
+	}
+	if obj.value == nil {
+		return 0
+	}
+	return 1
+}
+
+// ToCollection returns the elements of a list as plain Go values, or a single element collection for any other type
+func (obj *GremlinResult) ToCollection() []interface{} {
+	if col, ok := obj.GetValue().([]interface{}); ok && obj.elementType == ElementTypeList {
+		return col
+	}
+	if obj.value == nil {
+		return make([]interface{}, 0)
+	}
+	return []interface{}{obj.GetValue()}
+}
+
+// Decode populates the Go value pointed to by target from this element. Lists decode into slices and arrays,
+// maps decode into Go maps with string keys or into structs, and entities decode into structs as mapper.Scan
+// does. Struct fields are matched against map keys using the 'tgdb' tags of the mapper package, and values are
+// coerced following its rules - see mapper.AssignAttributeValue.
+func (obj *GremlinResult) Decode(target interface{}) types.TGError {
+	ptr := reflect.ValueOf(target)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() {
+		errMsg := fmt.Sprintf("GremlinResult:Decode requires a non-nil pointer and not '%T'", target)
+		return exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+	}
+	return decodeGremlinElement(obj, ptr.Elem())
+}
+
+func (obj *GremlinResult) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString("GremlinResult:{")
+	buffer.WriteString(fmt.Sprintf("ElementType: %s", obj.elementType.String()))
+	buffer.WriteString(fmt.Sprintf(", Value: %+v", obj.value))
+	buffer.WriteString("}")
+	return buffer.String()
+}
+
+/////////////////////////////////////////////////////////////////
+// Helper functions to read a typed Gremlin Result
+/////////////////////////////////////////////////////////////////
+
+// ReadGremlinResult reads the complete Gremlin response collection from the entity stream
+func ReadGremlinResult(entityStream types.TGInputStream, gof types.TGGraphObjectFactory) (*GremlinResult, types.TGError) {
+	logger.Log(fmt.Sprint("Entering GremlinResult:ReadGremlinResult"))
+	eleType, err := entityStream.(*iostream.ProtocolDataInputStream).ReadByte()
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning GremlinResult:ReadGremlinResult - unable to read eleType in the response stream w/ error: '%s'", err.Error()))
+		errMsg := "GremlinResult:ReadGremlinResult - unable to read element type in the response stream"
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+	}
+	if ElementType(eleType) != ElementTypeList {
+		logger.Error(fmt.Sprintf("ERROR: Returning GremlinResult:ReadGremlinResult - Invalid gremlin response collection type : %+v", ElementType(eleType)))
+		errMsg := fmt.Sprintf("GremlinResult:ReadGremlinResult - Invalid gremlin response collection type : %+v", ElementType(eleType))
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+	}
+	result, gErr := readGremlinList(entityStream, gof)
+	if gErr != nil {
+		return nil, gErr
+	}
+	logger.Log(fmt.Sprint("Returning GremlinResult:ReadGremlinResult"))
+	return result, nil
+}
+
+func readGremlinList(entityStream types.TGInputStream, gof types.TGGraphObjectFactory) (*GremlinResult, types.TGError) {
+	size, err := entityStream.(*iostream.ProtocolDataInputStream).ReadInt()
+	if err != nil {
+		errMsg := "GremlinResult:readGremlinList unable to read size in the response stream"
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+	}
+	eleType, err := entityStream.(*iostream.ProtocolDataInputStream).ReadByte()
+	if err != nil {
+		errMsg := "GremlinResult:readGremlinList unable to read element type in the response stream"
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+	}
+	logger.Debug(fmt.Sprintf("Inside GremlinResult:readGremlinList extracted size: '%d' eleType: '%+v'", size, ElementType(eleType)))
+
+	list := make([]*GremlinResult, 0, size)
+	for i := 0; i < size; i++ {
+		element, gErr := readGremlinElement(entityStream, gof, ElementType(eleType))
+		if gErr != nil {
+			return nil, gErr
+		}
+		list = append(list, element)
+	}
+	return NewGremlinResult(ElementTypeList, list), nil
+}
+
+func readGremlinMap(entityStream types.TGInputStream, gof types.TGGraphObjectFactory) (*GremlinResult, types.TGError) {
+	size, err := entityStream.(*iostream.ProtocolDataInputStream).ReadInt()
+	if err != nil {
+		errMsg := "GremlinResult:readGremlinMap unable to read size in the response stream"
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+	}
+	logger.Debug(fmt.Sprintf("Inside GremlinResult:readGremlinMap extracted size: '%d'", size))
+
+	entries := make(map[string]*GremlinResult, size)
+	for i := 0; i < size; i++ {
+		key, err := entityStream.(*iostream.ProtocolDataInputStream).ReadUTF()
+		if err != nil {
+			errMsg := "GremlinResult:readGremlinMap unable to read key in the response stream"
+			return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+		}
+		eleType, err := entityStream.(*iostream.ProtocolDataInputStream).ReadByte()
+		if err != nil {
+			errMsg := "GremlinResult:readGremlinMap unable to read element type in the response stream"
+			return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+		}
+		element, gErr := readGremlinElement(entityStream, gof, ElementType(eleType))
+		if gErr != nil {
+			return nil, gErr
+		}
+		entries[key] = element
+	}
+	return NewGremlinResult(ElementTypeMap, entries), nil
+}
+
+func readGremlinElement(entityStream types.TGInputStream, gof types.TGGraphObjectFactory, eleType ElementType) (*GremlinResult, types.TGError) {
+	switch eleType {
+	case ElementTypeList:
+		return readGremlinList(entityStream, gof)
+	case ElementTypeMap:
+		return readGremlinMap(entityStream, gof)
+	case ElementTypeEntity:
+		entity, err := readGremlinEntity(entityStream, gof)
+		if err != nil {
+			return nil, err
+		}
+		return NewGremlinResult(eleType, entity), nil
+	case ElementTypeAttr, ElementTypeAttrValue, ElementTypeAttrValueTransient:
+		// Attributes in a Gremlin response are not owned by any entity - use a transient node to resolve descriptors
+		dummyNode, err := gof.CreateNode()
+		if err != nil {
+			errMsg := fmt.Sprintf("GremlinResult:readGremlinElement unable to create node for element type: '%+v'", eleType)
+			return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+		}
+		attr, err := model.ReadExternalForEntity(dummyNode.(*model.Node).AbstractEntity, entityStream)
+		if err != nil {
+			logger.Error(fmt.Sprintf("ERROR: Returning GremlinResult:readGremlinElement - unable to read attr w/ Error: '%+v'", err.Error()))
+			return nil, err
+		}
+		if eleType == ElementTypeAttr {
+			return NewGremlinResult(eleType, attr), nil
+		}
+		return newGremlinAttrValue(eleType, attr), nil
+	}
+	logger.Error(fmt.Sprintf("ERROR: Returning GremlinResult:readGremlinElement - Invalid element type '%+v' from Gremlin response stream", eleType))
+	errMsg := fmt.Sprintf("GremlinResult:readGremlinElement - Invalid element type '%+v' in the response stream", eleType)
+	return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+}
+
+func readGremlinEntity(entityStream types.TGInputStream, gof types.TGGraphObjectFactory) (types.TGEntity, types.TGError) {
+	entityType, err := entityStream.(*iostream.ProtocolDataInputStream).ReadByte()
+	if err != nil {
+		errMsg := "GremlinResult:readGremlinEntity - unable to read entity type in the entity stream"
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+	}
+	kindId := types.TGEntityKind(entityType)
+	var entity types.TGEntity
+	switch kindId {
+	case types.EntityKindNode:
+		entity, err = gof.CreateNode()
+	case types.EntityKindEdge:
+		entity, err = gof.CreateEntity(types.EntityKindEdge)
+	default:
+		errMsg := fmt.Sprintf("GremlinResult:readGremlinEntity - Invalid entity kind '%s' from gremlin response stream", kindId.String())
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, "")
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("GremlinResult:readGremlinEntity unable to create entity of kind: '%s'", kindId.String())
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+	}
+	err = entity.ReadExternal(entityStream)
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning GremlinResult:readGremlinEntity - unable to entity.ReadExternal w/ error: '%s'", err.Error()))
+		errMsg := "GremlinResult:readGremlinEntity - unable to entity.ReadExternal in the entity stream"
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, "", errMsg, err.Error())
+	}
+	return entity, nil
+}
+
+/////////////////////////////////////////////////////////////////
+// Helper functions to decode a Gremlin Result into Go values
+/////////////////////////////////////////////////////////////////
+
+func decodeGremlinElement(element *GremlinResult, target reflect.Value) types.TGError {
+	if element == nil || element.value == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+	if target.Kind() == reflect.Ptr {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return decodeGremlinElement(element, target.Elem())
+	}
+	if target.Kind() == reflect.Interface && target.NumMethod() == 0 {
+		target.Set(reflect.ValueOf(element.GetValue()))
+		return nil
+	}
+
+	switch value := element.value.(type) {
+	case []*GremlinResult:
+		return decodeGremlinList(value, target)
+	case map[string]*GremlinResult:
+		return decodeGremlinMap(value, target)
+	case types.TGEntity:
+		if target.Kind() == reflect.Struct && !reflect.TypeOf(value).AssignableTo(target.Type()) {
+			return mapper.Scan(value, target.Addr().Interface())
+		}
+	case types.TGAttribute:
+		if !reflect.TypeOf(value).AssignableTo(target.Type()) {
+			attrType := types.AttributeTypeInvalid
+			if value.GetAttributeDescriptor() != nil {
+				attrType = value.GetAttributeDescriptor().GetAttrType()
+			}
+			return mapper.AssignAttributeValue(attrType, value.GetValue(), target)
+		}
+	}
+	return mapper.AssignAttributeValue(element.attributeType(), element.value, target)
+}
+
+func decodeGremlinList(list []*GremlinResult, target reflect.Value) types.TGError {
+	switch target.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(target.Type(), len(list), len(list))
+		for i, element := range list {
+			err := decodeGremlinElement(element, slice.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+		return nil
+	case reflect.Array:
+		if len(list) > target.Len() {
+			errMsg := fmt.Sprintf("GremlinResult list of size '%d' does not fit into '%s'", len(list), target.Type().String())
+			return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, "")
+		}
+		// The elements past the end of the list are zeroed, the way the elements of a slice would be
+		array := reflect.New(target.Type()).Elem()
+		for i, element := range list {
+			err := decodeGremlinElement(element, array.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+		target.Set(array)
+		return nil
+	}
+	errMsg := fmt.Sprintf("GremlinResult list cannot be decoded into '%s'", target.Type().String())
+	return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, "")
+}
+
+func decodeGremlinMap(entries map[string]*GremlinResult, target reflect.Value) types.TGError {
+	switch target.Kind() {
+	case reflect.Map:
+		if target.Type().Key().Kind() != reflect.String {
+			errMsg := fmt.Sprintf("GremlinResult map cannot be decoded into '%s' - keys must be strings", target.Type().String())
+			return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, "")
+		}
+		newMap := reflect.MakeMapWithSize(target.Type(), len(entries))
+		for key, element := range entries {
+			value := reflect.New(target.Type().Elem()).Elem()
+			err := decodeGremlinElement(element, value)
+			if err != nil {
+				return err
+			}
+			newMap.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), value)
+		}
+		target.Set(newMap)
+		return nil
+	case reflect.Struct:
+		// The keys of the map are matched like attribute names, so that a tagged struct decodes the same way
+		// from a Gremlin map as from an entity
+		mapping, err := mapper.GetStructMapping(target.Type())
+		if err != nil {
+			return err
+		}
+		for key, element := range entries {
+			field := mapping.GetField(key)
+			if field == nil {
+				continue
+			}
+			err := decodeGremlinElement(element, field.GetValue(target))
+			if err != nil {
+				errMsg := fmt.Sprintf("GremlinResult unable to decode '%s' into field '%s'", key, field.GetFieldName())
+				return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, err.Error())
+			}
+		}
+		return nil
+	}
+	errMsg := fmt.Sprintf("GremlinResult map cannot be decoded into '%s'", target.Type().String())
+	return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, "")
+}
@@ -0,0 +1,229 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: GremlinResult_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package query
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/iostream"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"testing"
+)
+
+type testGremlinPerson struct {
+	Name    string
+	Age     int64 `tgdb:"yearsOld"`
+	Tags    []string
+	Ignored string `tgdb:"-"`
+}
+
+func CreateTestGremlinResult() *GremlinResult {
+	tags := []*GremlinResult{
+		NewGremlinResult(ElementTypeAttrValue, "a"),
+		NewGremlinResult(ElementTypeAttrValue, "b"),
+	}
+	person := map[string]*GremlinResult{
+		"name":     NewGremlinResult(ElementTypeAttrValue, "John"),
+		"yearsOld": NewGremlinResult(ElementTypeAttrValue, 42),
+		"tags":     NewGremlinResult(ElementTypeList, tags),
+		"Ignored":  NewGremlinResult(ElementTypeAttrValue, "x"),
+	}
+	return NewGremlinResult(ElementTypeList, []*GremlinResult{NewGremlinResult(ElementTypeMap, person)})
+}
+
+func TestGremlinResultAccessors(t *testing.T) {
+	result := CreateTestGremlinResult()
+	if result.GetElementType() != ElementTypeList || result.Size() != 1 {
+		t.Errorf("TestGremlinResultAccessors expected a list of size 1 and not '%+v'", result)
+	}
+	list, err := result.GetList()
+	if err != nil {
+		t.Errorf("TestGremlinResultAccessors returned error message %s", err.Error())
+		return
+	}
+	entries, err := list[0].GetMap()
+	if err != nil {
+		t.Errorf("TestGremlinResultAccessors returned error message %s", err.Error())
+		return
+	}
+	value, err := entries["name"].GetAttributeValue()
+	if err != nil || value != "John" {
+		t.Errorf("TestGremlinResultAccessors expected 'John' and not '%+v'", value)
+	}
+	_, err = entries["name"].GetEntity()
+	if err == nil {
+		t.Errorf("TestGremlinResultAccessors expected an error reading an attribute value as an entity")
+	}
+	t.Logf("TestGremlinResultAccessors has the collection '%+v'", result.ToCollection())
+}
+
+func TestGremlinResultDecodeEntity(t *testing.T) {
+	node := CreateTestNodeEntity()
+	result := NewGremlinResult(ElementTypeEntity, node)
+	if _, err := result.GetNode(); err != nil {
+		t.Errorf("TestGremlinResultDecodeEntity returned error message %s", err.Error())
+	}
+	if _, err := result.GetEdge(); err == nil {
+		t.Errorf("TestGremlinResultDecodeEntity expected an error reading a node as an edge")
+	}
+	var target struct {
+		Bool    bool
+		Integer int
+		Label   string `tgdb:"String"`
+	}
+	err := result.Decode(&target)
+	if err != nil {
+		t.Errorf("TestGremlinResultDecodeEntity returned error message %s", err.Error())
+		return
+	}
+	if !target.Bool || target.Integer != 33333 || target.Label != "InsideNodeEntity" {
+		t.Errorf("TestGremlinResultDecodeEntity decoded unexpected values '%+v'", target)
+	}
+	var entity types.TGNode
+	err = result.Decode(&entity)
+	if err != nil || entity != node {
+		t.Errorf("TestGremlinResultDecodeEntity expected to decode the node itself and not '%+v'", entity)
+	}
+}
+
+func TestGremlinResultDecodeStruct(t *testing.T) {
+	var people []testGremlinPerson
+	err := CreateTestGremlinResult().Decode(&people)
+	if err != nil {
+		t.Errorf("TestGremlinResultDecodeStruct returned error message %s", err.Error())
+		return
+	}
+	if len(people) != 1 || people[0].Name != "John" || people[0].Age != 42 || len(people[0].Tags) != 2 || people[0].Ignored != "" {
+		t.Errorf("TestGremlinResultDecodeStruct decoded unexpected values '%+v'", people)
+	}
+}
+
+func TestGremlinResultDecodeMap(t *testing.T) {
+	var maps []map[string]interface{}
+	err := CreateTestGremlinResult().Decode(&maps)
+	if err != nil {
+		t.Errorf("TestGremlinResultDecodeMap returned error message %s", err.Error())
+		return
+	}
+	if len(maps) != 1 || maps[0]["name"] != "John" {
+		t.Errorf("TestGremlinResultDecodeMap decoded unexpected values '%+v'", maps)
+	}
+	var names []int
+	err = NewGremlinResult(ElementTypeList, []*GremlinResult{NewGremlinResult(ElementTypeAttrValue, "John")}).Decode(&names)
+	if err == nil {
+		t.Errorf("TestGremlinResultDecodeMap expected an error decoding a string into an int")
+	}
+}
+
+func TestGremlinResultDecodeArray(t *testing.T) {
+	names := [3]string{"Anne", "Bob", "Carl"}
+	list := []*GremlinResult{NewGremlinResult(ElementTypeAttrValue, "John"), NewGremlinResult(ElementTypeAttrValue, "Mary")}
+	err := NewGremlinResult(ElementTypeList, list).Decode(&names)
+	if err != nil {
+		t.Fatalf("TestGremlinResultDecodeArray returned error message %s", err.Error())
+	}
+	if names != [3]string{"John", "Mary", ""} {
+		t.Errorf("TestGremlinResultDecodeArray expected the element past the list to be zeroed and not '%+v'", names)
+	}
+	var short [1]string
+	if err := NewGremlinResult(ElementTypeList, list).Decode(&short); err == nil {
+		t.Errorf("TestGremlinResultDecodeArray expected an error decoding '%d' elements into '%+v'", len(list), short)
+	}
+}
+
+func TestReadGremlinResult(t *testing.T) {
+	os := iostream.NewProtocolDataOutputStream(0)
+	os.WriteByte(int(ElementTypeList))
+	os.WriteInt(1)
+	os.WriteByte(int(ElementTypeMap))
+	os.WriteInt(1)
+	_ = os.WriteUTF("empty")
+	os.WriteByte(int(ElementTypeList))
+	os.WriteInt(0)
+	os.WriteByte(int(ElementTypeAttrValue))
+
+	gof := model.NewGraphObjectFactory(nil)
+	result, err := ReadGremlinResult(iostream.NewProtocolDataInputStream(os.GetBuffer()), gof)
+	if err != nil {
+		t.Errorf("TestReadGremlinResult returned error message %s", err.Error())
+		return
+	}
+	var decoded []map[string][]string
+	err = result.Decode(&decoded)
+	if err != nil {
+		t.Errorf("TestReadGremlinResult returned error message %s", err.Error())
+		return
+	}
+	if len(decoded) != 1 || decoded[0]["empty"] == nil || len(decoded[0]["empty"]) != 0 {
+		t.Errorf("TestReadGremlinResult decoded unexpected values '%+v'", decoded)
+	}
+	t.Logf("TestReadGremlinResult read '%+v'", result)
+}
+
+func TestGremlinResultDecodeFollowsMapperRules(t *testing.T) {
+	var person testGremlinPerson
+	entries := map[string]*GremlinResult{"YEARSOLD": NewGremlinResult(ElementTypeAttrValue, int64(42))}
+	err := NewGremlinResult(ElementTypeMap, entries).Decode(&person)
+	if err != nil || person.Age != 42 {
+		t.Errorf("TestGremlinResultDecodeFollowsMapperRules expected the tag to match ignoring case and not '%+v' w/ error '%+v'", person, err)
+	}
+	var small int8
+	if err := NewGremlinResult(ElementTypeAttrValue, 300).Decode(&small); err == nil {
+		t.Errorf("TestGremlinResultDecodeFollowsMapperRules expected an overflow error for int8 and not '%d'", small)
+	}
+	var whole int
+	if err := NewGremlinResult(ElementTypeAttrValue, 1.5).Decode(&whole); err == nil {
+		t.Errorf("TestGremlinResultDecodeFollowsMapperRules expected a coercion error for a float into an int and not '%d'", whole)
+	}
+	var ratio float64
+	if err := NewGremlinResult(ElementTypeAttrValue, 3).Decode(&ratio); err != nil || ratio != 3 {
+		t.Errorf("TestGremlinResultDecodeFollowsMapperRules expected 3 and not '%f' w/ error '%+v'", ratio, err)
+	}
+}
+
+func TestGremlinResultMismatchedValue(t *testing.T) {
+	mismatched := []*GremlinResult{
+		NewGremlinResult(ElementTypeAttr, "not an attribute"),
+		NewGremlinResult(ElementTypeEntity, 42),
+		NewGremlinResult(ElementTypeList, "not a list"),
+		NewGremlinResult(ElementTypeMap, []string{"not a map"}),
+	}
+	if _, err := mismatched[0].GetAttribute(); err == nil {
+		t.Errorf("TestGremlinResultMismatchedValue expected an error reading '%+v' as an attribute", mismatched[0])
+	}
+	if _, err := mismatched[0].GetAttributeValue(); err == nil {
+		t.Errorf("TestGremlinResultMismatchedValue expected an error reading '%+v' as an attribute value", mismatched[0])
+	}
+	if _, err := mismatched[1].GetEntity(); err == nil {
+		t.Errorf("TestGremlinResultMismatchedValue expected an error reading '%+v' as an entity", mismatched[1])
+	}
+	if _, err := mismatched[1].GetNode(); err == nil {
+		t.Errorf("TestGremlinResultMismatchedValue expected an error reading '%+v' as a node", mismatched[1])
+	}
+	if _, err := mismatched[2].GetList(); err == nil {
+		t.Errorf("TestGremlinResultMismatchedValue expected an error reading '%+v' as a list", mismatched[2])
+	}
+	if _, err := mismatched[3].GetMap(); err == nil {
+		t.Errorf("TestGremlinResultMismatchedValue expected an error reading '%+v' as a map", mismatched[3])
+	}
+	for _, result := range mismatched {
+		_ = result.Size()
+		_ = result.ToCollection()
+	}
+}
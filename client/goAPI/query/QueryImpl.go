@@ -25,19 +25,42 @@ import (
 	"bytes"
 	"encoding/gob"
 	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/iostream"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/logging"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"sort"
 	"time"
+	"unicode/utf8"
 )
 
 var logger = logging.DefaultTGLogManager().GetLogger()
 
+// QueryParameterPrefix marks a named parameter inside a query expression, e.g. "@name = $name"
+const QueryParameterPrefix = '$'
+
+// TGQueryExecutor is implemented by the connections that execute a server side query w/ the values bound to its
+// named parameters - see TGQueryImpl.WriteParameters
+type TGQueryExecutor interface {
+	ExecuteQueryWithParameters(qry *TGQueryImpl, options types.TGQueryOption) (types.TGResultSet, types.TGError)
+}
+
 type TGQueryImpl struct {
 	qryConnection types.TGConnection
 	qryHashId     int64
 	qryOption     *TGQueryOptionImpl
-	// These parameters are for tokenized query parameters specified as '?, ?, ...'
+	qryExpr       string
+	// These parameters are for named query parameters specified as '$name' in the query expression
 	qryParameters map[string]interface{}
+	// Attribute type of each parameter - fixed by the first value bound to that parameter
+	qryParamTypes map[string]int
+	// Parameter names declared in the query expression - nil when the expression is not known
+	qryDeclared map[string]bool
+	// First error raised by a setter, reported when the query is executed
+	qryBindError types.TGError
+	// Called instead of closing the server side query when the handle is closed - see SetCloseHandler
+	qryCloseHandler func(queryHashId int64) types.TGError
 }
 
 func DefaultQuery() *TGQueryImpl {
@@ -49,6 +72,7 @@ func DefaultQuery() *TGQueryImpl {
 	newQuery := TGQueryImpl{
 		qryHashId:     -1,
 		qryParameters: make(map[string]interface{}, 0),
+		qryParamTypes: make(map[string]int, 0),
 	}
 	newQuery.qryOption = DefaultQueryOption()
 	return &newQuery
@@ -64,6 +88,13 @@ func NewQuery(conn types.TGConnection, queryHashId int64) *TGQueryImpl {
 	return newQuery
 }
 
+// NewPreparedQuery creates a query handle for a server side query whose named parameters are declared in expr
+func NewPreparedQuery(conn types.TGConnection, queryHashId int64, expr string) *TGQueryImpl {
+	newQuery := NewQuery(conn, queryHashId)
+	newQuery.SetQueryExpression(expr)
+	return newQuery
+}
+
 /////////////////////////////////////////////////////////////////
 // Helper functions from Interface ==> TGQuery
 /////////////////////////////////////////////////////////////////
@@ -72,6 +103,10 @@ func (obj *TGQueryImpl) GetQueryConnection() types.TGConnection {
 	return obj.qryConnection
 }
 
+func (obj *TGQueryImpl) GetQueryExpression() string {
+	return obj.qryExpr
+}
+
 func (obj *TGQueryImpl) GetQueryId() int64 {
 	return obj.qryHashId
 }
@@ -84,6 +119,46 @@ func (obj *TGQueryImpl) GetQueryParameters() map[string]interface{} {
 	return obj.qryParameters
 }
 
+// GetParameterNames returns the sorted names of the parameters declared in the query expression
+func (obj *TGQueryImpl) GetParameterNames() []string {
+	names := make([]string, 0, len(obj.qryDeclared))
+	for name := range obj.qryDeclared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetParameterType returns the attribute type bound to the named parameter, or AttributeTypeInvalid
+func (obj *TGQueryImpl) GetParameterType(name string) int {
+	if attrType, ok := obj.qryParamTypes[name]; ok {
+		return attrType
+	}
+	return types.AttributeTypeInvalid
+}
+
+// ClearParameters removes all the values bound to this query, so that it can be re-executed with new values
+func (obj *TGQueryImpl) ClearParameters() {
+	obj.qryParameters = make(map[string]interface{}, 0)
+	obj.qryParamTypes = make(map[string]int, 0)
+	obj.qryBindError = nil
+}
+
+// SetQueryExpression sets the query expression and declares the named parameters referenced in it
+func (obj *TGQueryImpl) SetQueryExpression(expr string) {
+	obj.qryExpr = expr
+	obj.qryDeclared = make(map[string]bool, 0)
+	for _, name := range ParseQueryParameterNames(expr) {
+		obj.qryDeclared[name] = true
+	}
+}
+
+// SetCloseHandler makes Close and CloseQuery call the handler, once, instead of closing the server side query.
+// Query handles sharing a cached server side query use it to leave the query open for the other handles.
+func (obj *TGQueryImpl) SetCloseHandler(handler func(queryHashId int64) types.TGError) {
+	obj.qryCloseHandler = handler
+}
+
 func (obj *TGQueryImpl) SetQueryId(qId int64) {
 	obj.qryHashId = qId
 }
@@ -96,72 +171,301 @@ func (obj *TGQueryImpl) SetQueryParameters(params map[string]interface{}) {
 	obj.qryParameters = params
 }
 
+// ParseQueryParameterNames returns the names of the '$name' parameters referenced in the query expression,
+// in the order of their first appearance. Placeholders inside quoted literals are ignored.
+func ParseQueryParameterNames(expr string) []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool, 0)
+	scanQueryParameters(expr, func(start, end int, name string) bool {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+		return true
+	})
+	return names
+}
+
+// WriteParameters writes the values bound to the declared parameters after the query hash id of an EXECUTEID
+// request: the number of parameters, then for each of them, in the order of GetParameterNames, its name, attribute
+// type and null flag, followed by a non-null value in the wire format of an attribute of that type.
+// Experimental - this layout needs a server that accepts named parameters w/ EXECUTEID.
+func (obj *TGQueryImpl) WriteParameters(os types.TGOutputStream) types.TGError {
+	err := obj.validateParameters()
+	if err != nil {
+		return err
+	}
+	names := obj.GetParameterNames()
+	os.(*iostream.ProtocolDataOutputStream).WriteInt(len(names))
+	for _, name := range names {
+		err = os.(*iostream.ProtocolDataOutputStream).WriteUTF(name)
+		if err != nil {
+			logger.Error(fmt.Sprintf("ERROR: Returning TGQueryImpl:WriteParameters w/ Error in writing parameter '%s' to message buffer", name))
+			return err
+		}
+		attrType := obj.GetParameterType(name)
+		value := obj.qryParameters[name]
+		os.(*iostream.ProtocolDataOutputStream).WriteByte(attrType)
+		os.(*iostream.ProtocolDataOutputStream).WriteBoolean(value == nil)
+		if value == nil {
+			continue
+		}
+		if attrType == types.AttributeTypeChar {
+			// Char attributes are written as their code point
+			r, _ := utf8.DecodeRuneInString(value.(string))
+			value = int(r)
+		}
+		attr, err := model.CreateAttributeWithDesc(nil, model.NewAttributeDescriptorWithType(name, attrType), value)
+		if err != nil {
+			logger.Error(fmt.Sprintf("ERROR: Returning TGQueryImpl:WriteParameters - unable to create attribute for parameter '%s' w/ error: '%s'", name, err.Error()))
+			return err
+		}
+		err = attr.WriteValue(os)
+		if err != nil {
+			logger.Error(fmt.Sprintf("ERROR: Returning TGQueryImpl:WriteParameters w/ Error in writing value of parameter '%s' to message buffer", name))
+			return err
+		}
+	}
+	return nil
+}
+
 /////////////////////////////////////////////////////////////////
 // Private functions from Interface ==> TGQuery
 /////////////////////////////////////////////////////////////////
 
-func (obj *TGQueryImpl) setQueryParameter(name string, value interface{}) {
+func isQueryParameterRune(r rune, first bool) bool {
+	if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+		return true
+	}
+	return !first && r >= '0' && r <= '9'
+}
+
+// scanQueryParameters calls visit w/ the rune offsets and the name of every '$name' placeholder outside quoted
+// literals, until visit returns false
+func scanQueryParameters(expr string, visit func(start, end int, name string) bool) {
+	var quote rune
+	escaped := false
+	runes := []rune(expr)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if quote != 0 {
+			if escaped {
+				escaped = false
+			} else if r == '\\' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+			continue
+		}
+		if r == '\'' || r == '"' {
+			quote = r
+			continue
+		}
+		if r != QueryParameterPrefix {
+			continue
+		}
+		j := i + 1
+		for j < len(runes) && isQueryParameterRune(runes[j], j == i+1) {
+			j++
+		}
+		if j > i+1 {
+			if !visit(i, j, string(runes[i+1:j])) {
+				return
+			}
+			i = j - 1
+		}
+	}
+}
+
+// setParameter binds the value through setQueryParameter, and keeps the first error for the query execution
+func (obj *TGQueryImpl) setParameter(name string, attrType int, value interface{}) {
+	err := obj.setQueryParameter(name, attrType, value)
+	if err != nil && obj.qryBindError == nil {
+		obj.qryBindError = err
+	}
+}
+
+func (obj *TGQueryImpl) setQueryParameter(name string, attrType int, value interface{}) types.TGError {
+	if obj.qryDeclared != nil && !obj.qryDeclared[name] {
+		errMsg := fmt.Sprintf("Parameter '%s' is not declared in the query expression '%s'", name, obj.qryExpr)
+		logger.Error(fmt.Sprintf("ERROR: Returning TGQueryImpl:setQueryParameter - %s", errMsg))
+		return exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	if boundType, ok := obj.qryParamTypes[name]; ok && attrType != types.AttributeTypeInvalid && boundType != attrType {
+		errMsg := fmt.Sprintf("Parameter '%s' is already bound as '%s' and cannot be set as '%s'", name,
+			types.GetAttributeTypeFromId(boundType).GetTypeName(), types.GetAttributeTypeFromId(attrType).GetTypeName())
+		logger.Error(fmt.Sprintf("ERROR: Returning TGQueryImpl:setQueryParameter - %s", errMsg))
+		return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	if attrType != types.AttributeTypeInvalid {
+		obj.qryParamTypes[name] = attrType
+	}
 	obj.qryParameters[name] = value
+	return nil
+}
+
+// validateParameters makes sure that every parameter declared in the query expression has been bound
+func (obj *TGQueryImpl) validateParameters() types.TGError {
+	for _, name := range obj.GetParameterNames() {
+		if _, ok := obj.qryParameters[name]; !ok {
+			errMsg := fmt.Sprintf("Parameter '%s' of query '%s' has not been set", name, obj.qryExpr)
+			logger.Error(fmt.Sprintf("ERROR: Returning TGQueryImpl:validateParameters - %s", errMsg))
+			return exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+		}
+	}
+	return nil
 }
 
 /////////////////////////////////////////////////////////////////
 // Implement functions from Interface ==> TGQuery
 /////////////////////////////////////////////////////////////////
 
+// Bind binds the value to the named parameter w/ the setter matching its Go type
+func (obj *TGQueryImpl) Bind(name string, value interface{}) types.TGError {
+	switch v := value.(type) {
+	case nil:
+		return obj.setQueryParameter(name, types.AttributeTypeInvalid, nil)
+	case bool:
+		return obj.setQueryParameter(name, types.AttributeTypeBoolean, v)
+	case []byte:
+		if v == nil {
+			return obj.setQueryParameter(name, types.AttributeTypeInvalid, nil)
+		}
+		return obj.setQueryParameter(name, types.AttributeTypeBlob, v)
+	case int16:
+		return obj.setQueryParameter(name, types.AttributeTypeShort, v)
+	case int:
+		return obj.setQueryParameter(name, types.AttributeTypeInteger, v)
+	case int32:
+		return obj.setQueryParameter(name, types.AttributeTypeInteger, int(v))
+	case int64:
+		return obj.setQueryParameter(name, types.AttributeTypeLong, v)
+	case float32:
+		return obj.setQueryParameter(name, types.AttributeTypeFloat, v)
+	case float64:
+		return obj.setQueryParameter(name, types.AttributeTypeDouble, v)
+	case string:
+		return obj.setQueryParameter(name, types.AttributeTypeString, v)
+	case time.Time:
+		return obj.setQueryParameter(name, types.AttributeTypeTimeStamp, v)
+	}
+	errMsg := fmt.Sprintf("Value '%+v' of type '%T' cannot be bound to query parameter '%s'", value, value, name)
+	return exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, types.INTERNAL_SERVER_ERROR, errMsg, "")
+}
+
 // Close closes the Query
 func (obj *TGQueryImpl) Close() {
-	_, _ = obj.qryConnection.CloseQuery(obj.qryHashId)
+	err := obj.CloseQuery()
+	if err != nil {
+		logger.Warning(fmt.Sprintf("WARNING: TGQueryImpl:Close - unable to close query '%d' w/ error: '%s'", obj.qryHashId, err.Error()))
+	}
+}
+
+// CloseQuery closes the Query and reports the error returned by the server, if any
+func (obj *TGQueryImpl) CloseQuery() types.TGError {
+	if obj.qryCloseHandler != nil {
+		handler := obj.qryCloseHandler
+		obj.qryCloseHandler = nil
+		return handler(obj.qryHashId)
+	}
+	if obj.qryConnection == nil || obj.qryHashId <= 0 {
+		return nil
+	}
+	_, err := obj.qryConnection.CloseQuery(obj.qryHashId)
+	return err
 }
 
 // Execute executes the Query
 func (obj *TGQueryImpl) Execute() types.TGResultSet {
-	rSet, _ := obj.qryConnection.ExecuteQueryWithId(obj.qryHashId, obj.qryOption)
+	rSet, err := obj.ExecuteQuery()
+	if err != nil {
+		logger.Error(fmt.Sprintf("ERROR: Returning TGQueryImpl:Execute w/ error: '%s'", err.Error()))
+	}
 	return rSet
 }
 
+// ExecuteQuery executes the Query and reports an invalid binding, or the error returned by the server. A query
+// w/ parameters is executed by its hash id together w/ the values bound to them - see WriteParameters.
+func (obj *TGQueryImpl) ExecuteQuery() (types.TGResultSet, types.TGError) {
+	logger.Log(fmt.Sprintf("Entering TGQueryImpl:ExecuteQuery for QueryHashId: '%+v'", obj.qryHashId))
+	if obj.qryBindError != nil {
+		return nil, obj.qryBindError
+	}
+	if obj.qryConnection == nil {
+		errMsg := fmt.Sprintf("Query '%d' is not associated with any connection", obj.qryHashId)
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	if len(obj.qryDeclared) == 0 {
+		return obj.qryConnection.ExecuteQueryWithId(obj.qryHashId, obj.qryOption)
+	}
+	err := obj.validateParameters()
+	if err != nil {
+		return nil, err
+	}
+	executor, ok := obj.qryConnection.(TGQueryExecutor)
+	if !ok {
+		errMsg := fmt.Sprintf("Connection of query '%s' does not support query parameters", obj.qryExpr)
+		logger.Error(fmt.Sprintf("ERROR: Returning TGQueryImpl:ExecuteQuery - %s", errMsg))
+		return nil, exception.GetErrorByType(types.TGErrorTypeNotSupported, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	return executor.ExecuteQueryWithParameters(obj, obj.qryOption)
+}
+
 // SetBoolean sets Boolean parameter
 func (obj *TGQueryImpl) SetBoolean(name string, value bool) {
-	obj.setQueryParameter(name, value)
+	obj.setParameter(name, types.AttributeTypeBoolean, value)
 }
 
 // SetBytes sets Byte Parameter
 func (obj *TGQueryImpl) SetBytes(name string, value []byte) {
-	obj.setQueryParameter(name, value)
+	if value == nil {
+		obj.SetNull(name)
+		return
+	}
+	obj.setParameter(name, types.AttributeTypeBlob, value)
 }
 
 // SetChar sets Character Parameter
 func (obj *TGQueryImpl) SetChar(name string, value string) {
-	obj.setQueryParameter(name, value)
+	if utf8.RuneCountInString(value) != 1 {
+		errMsg := fmt.Sprintf("Parameter '%s' expects a single character and not '%s'", name, value)
+		if obj.qryBindError == nil {
+			obj.qryBindError = exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, types.INTERNAL_SERVER_ERROR, errMsg, "")
+		}
+		return
+	}
+	obj.setParameter(name, types.AttributeTypeChar, value)
 }
 
 // SetDate sets Date Parameter
 func (obj *TGQueryImpl) SetDate(name string, value time.Time) {
-	obj.setQueryParameter(name, value)
+	obj.setParameter(name, types.AttributeTypeTimeStamp, value)
 }
 
 // SetDouble sets Double Parameter
 func (obj *TGQueryImpl) SetDouble(name string, value float64) {
-	obj.setQueryParameter(name, value)
+	obj.setParameter(name, types.AttributeTypeDouble, value)
 }
 
 // SetFloat sets Float Parameter
 func (obj *TGQueryImpl) SetFloat(name string, value float32) {
-	obj.setQueryParameter(name, value)
+	obj.setParameter(name, types.AttributeTypeFloat, value)
 }
 
 // SetInt sets Integer Parameter
 func (obj *TGQueryImpl) SetInt(name string, value int) {
-	obj.setQueryParameter(name, value)
+	obj.setParameter(name, types.AttributeTypeInteger, value)
 }
 
 // SetLong sets Long Parameter
 func (obj *TGQueryImpl) SetLong(name string, value int64) {
-	obj.setQueryParameter(name, value)
+	obj.setParameter(name, types.AttributeTypeLong, value)
 }
 
 // SetNull sets the parameter to null
 func (obj *TGQueryImpl) SetNull(name string) {
-	obj.setQueryParameter(name, nil)
+	obj.setParameter(name, types.AttributeTypeInvalid, nil)
 }
 
 // SetOption sets the Query Option
@@ -171,12 +475,12 @@ func (obj *TGQueryImpl) SetOption(options types.TGQueryOption) {
 
 // SetShort sets Short Parameter
 func (obj *TGQueryImpl) SetShort(name string, value int16) {
-	obj.setQueryParameter(name, value)
+	obj.setParameter(name, types.AttributeTypeShort, value)
 }
 
 // SetString sets String Parameter
 func (obj *TGQueryImpl) SetString(name string, value string) {
-	obj.setQueryParameter(name, value)
+	obj.setParameter(name, types.AttributeTypeString, value)
 }
 
 func (obj *TGQueryImpl) String() string {
@@ -184,8 +488,10 @@ func (obj *TGQueryImpl) String() string {
 	buffer.WriteString("TGQueryImpl:{")
 	buffer.WriteString(fmt.Sprintf("QryConnection: %+v", obj.qryConnection))
 	buffer.WriteString(fmt.Sprintf(", QryHashId: %+v", obj.qryHashId))
+	buffer.WriteString(fmt.Sprintf(", QryExpr: %+v", obj.qryExpr))
 	buffer.WriteString(fmt.Sprintf(", QryOption: %+v", obj.qryOption.String()))
 	buffer.WriteString(fmt.Sprintf(", QryParameters: %+v", obj.qryParameters))
+	buffer.WriteString(fmt.Sprintf(", QryParamTypes: %+v", obj.qryParamTypes))
 	buffer.WriteString("}")
 	return buffer.String()
 }
@@ -197,7 +503,7 @@ func (obj *TGQueryImpl) String() string {
 func (obj *TGQueryImpl) MarshalBinary() ([]byte, error) {
 	// A simple encoding: plain text.
 	var b bytes.Buffer
-	_, err := fmt.Fprintln(&b, obj.qryConnection, obj.qryHashId, obj.qryOption, obj.qryParameters)
+	_, err := fmt.Fprintln(&b, obj.qryConnection, obj.qryHashId, obj.qryOption, obj.qryExpr, obj.qryParameters, obj.qryParamTypes)
 	if err != nil {
 		logger.Error(fmt.Sprintf("ERROR: Returning TGQueryImpl:MarshalBinary w/ Error: '%+v'", err.Error()))
 		return nil, err
@@ -212,7 +518,7 @@ func (obj *TGQueryImpl) MarshalBinary() ([]byte, error) {
 func (obj *TGQueryImpl) UnmarshalBinary(data []byte) error {
 	// A simple encoding: plain text.
 	b := bytes.NewBuffer(data)
-	_, err := fmt.Fscanln(b, &obj.qryConnection, &obj.qryHashId, &obj.qryOption, &obj.qryParameters)
+	_, err := fmt.Fscanln(b, &obj.qryConnection, &obj.qryHashId, &obj.qryOption, &obj.qryExpr, &obj.qryParameters, &obj.qryParamTypes)
 	if err != nil {
 		logger.Error(fmt.Sprintf("ERROR: Returning TGQueryImpl:UnmarshalBinary w/ Error: '%+v'", err.Error()))
 		return err
@@ -21,6 +21,14 @@
 
 package query
 
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/iostream"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/pdu"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"testing"
+	"time"
+)
+
 // TODO: Revisit later - once connection is implemented - to create proper test queries against default meta data
 //func TestQueryClose(t *testing.T) {
 //	testQry := DefaultQuery()
@@ -46,3 +54,178 @@ package query
 //	testQry.Close()
 //}
 //
+
+func TestParseQueryParameterNames(t *testing.T) {
+	names := ParseQueryParameterNames("@nodetype = 'House' and name = $name and yearBuilt > $year and note = '$notAParam' or name = $name")
+	if len(names) != 2 || names[0] != "name" || names[1] != "year" {
+		t.Errorf("TestParseQueryParameterNames expected [name year] and not '%+v'", names)
+	}
+	names = ParseQueryParameterNames("g.V().has('House', 'name', \"it's $x\")")
+	if len(names) != 0 {
+		t.Errorf("TestParseQueryParameterNames expected no parameters inside literals and not '%+v'", names)
+	}
+}
+
+func TestQuerySetParameters(t *testing.T) {
+	testQry := NewPreparedQuery(nil, 1234567890, "@nodetype = 'House' and name = $name and yearBuilt > $year")
+	if err := testQry.Bind("name", "Gamble House"); err != nil {
+		t.Errorf("TestQuerySetParameters returned error message %s", err.Error())
+	}
+	if err := testQry.Bind("year", 1900); err != nil {
+		t.Errorf("TestQuerySetParameters returned error message %s", err.Error())
+	}
+	if err := testQry.Bind("year", int64(1900)); err == nil {
+		t.Errorf("TestQuerySetParameters expected an error re-binding an integer parameter as a long")
+	}
+	if err := testQry.Bind("owner", "x' or 1=1"); err == nil {
+		t.Errorf("TestQuerySetParameters expected an error binding an undeclared parameter")
+	}
+	if err := testQry.Bind("name", struct{}{}); err == nil {
+		t.Errorf("TestQuerySetParameters expected an error binding a value of an unsupported type")
+	}
+	if err := testQry.Bind("year", nil); err != nil || testQry.GetParameterType("year") != types.AttributeTypeInteger {
+		t.Errorf("TestQuerySetParameters expected null to keep the integer type of parameter 'year'")
+	}
+}
+
+func TestQuerySetterErrorReportedOnExecute(t *testing.T) {
+	conn := &boundQueryConnection{}
+	testQry := NewPreparedQuery(conn, 1234567890, "name = $name")
+	testQry.SetChar("name", "ab")
+	testQry.SetString("name", "Gamble House")
+	if _, err := testQry.ExecuteQuery(); err == nil || len(conn.queries) != 0 {
+		t.Errorf("TestQuerySetterErrorReportedOnExecute expected the error of SetChar and not queries '%+v'", conn.queries)
+	}
+	if testQry.Execute() != nil {
+		t.Errorf("TestQuerySetterErrorReportedOnExecute expected no result set")
+	}
+	testQry.ClearParameters()
+	testQry.SetString("name", "Gamble House")
+	if _, err := testQry.ExecuteQuery(); err != nil {
+		t.Errorf("TestQuerySetterErrorReportedOnExecute returned error message %s", err.Error())
+	}
+}
+
+func TestQueryExecuteUnboundParameter(t *testing.T) {
+	testQry := NewPreparedQuery(nil, 1234567890, "@nodetype = 'House' and name = $name")
+	_, err := testQry.ExecuteQuery()
+	if err == nil {
+		t.Errorf("TestQueryExecuteUnboundParameter expected an error executing a query w/o binding 'name'")
+	}
+}
+
+// boundQueryConnection records the queries executed through it
+type boundQueryConnection struct {
+	types.TGConnection
+	queries  []*TGQueryImpl
+	queryIds []int64
+}
+
+func (obj *boundQueryConnection) ExecuteQueryWithId(queryHashId int64, options types.TGQueryOption) (types.TGResultSet, types.TGError) {
+	obj.queryIds = append(obj.queryIds, queryHashId)
+	return DefaultResultSet(), nil
+}
+
+func (obj *boundQueryConnection) ExecuteQueryWithParameters(qry *TGQueryImpl, options types.TGQueryOption) (types.TGResultSet, types.TGError) {
+	obj.queries = append(obj.queries, qry)
+	obj.queryIds = append(obj.queryIds, qry.GetQueryId())
+	return DefaultResultSet(), nil
+}
+
+func TestQueryExecuteWithParameters(t *testing.T) {
+	conn := &boundQueryConnection{}
+	testQry := NewPreparedQuery(conn, 1234567890, "@nodetype = 'House' and name = $name and built = $date and image = $image;")
+	testQry.SetString("name", "x\\' or 1=1 or name = '://")
+	testQry.SetDate("date", time.Date(1908, time.June, 1, 0, 0, 0, 0, time.UTC))
+	testQry.SetNull("image")
+	if _, err := testQry.ExecuteQuery(); err != nil {
+		t.Errorf("TestQueryExecuteWithParameters returned error message %s", err.Error())
+		return
+	}
+	if len(conn.queries) != 1 || conn.queries[0] != testQry || conn.queryIds[0] != 1234567890 {
+		t.Errorf("TestQueryExecuteWithParameters expected the query to be executed by id w/ its parameters and not '%+v'", conn.queryIds)
+	}
+
+	testQry = NewPreparedQuery(conn, 1234567891, "@nodetype = 'House';")
+	if _, err := testQry.ExecuteQuery(); err != nil || len(conn.queries) != 1 || conn.queryIds[1] != 1234567891 {
+		t.Errorf("TestQueryExecuteWithParameters expected a query w/o parameters to be executed by id only and not '%+v'", conn.queryIds)
+	}
+}
+
+func TestQueryWriteParameters(t *testing.T) {
+	testQry := NewPreparedQuery(nil, 1234567890, "name = $name and year = $year and image = $image")
+	testQry.SetString("name", "x\\' or name = '://")
+	testQry.SetInt("year", 1908)
+	testQry.SetBytes("image", nil)
+	os := iostream.DefaultProtocolDataOutputStream()
+	if err := testQry.WriteParameters(os); err != nil {
+		t.Errorf("TestQueryWriteParameters returned error message %s", err.Error())
+		return
+	}
+	is := iostream.NewProtocolDataInputStream(os.GetBuffer())
+	if count, _ := is.ReadInt(); count != 3 {
+		t.Errorf("TestQueryWriteParameters expected 3 parameters and not '%d'", count)
+		return
+	}
+	// Parameters are written in the order of their names
+	if name, _ := is.ReadUTF(); name != "image" {
+		t.Errorf("TestQueryWriteParameters expected parameter 'image' and not '%s'", name)
+	}
+	is.ReadByte()
+	if isNull, _ := is.ReadBoolean(); !isNull {
+		t.Errorf("TestQueryWriteParameters expected parameter 'image' to be null")
+	}
+	if name, _ := is.ReadUTF(); name != "name" {
+		t.Errorf("TestQueryWriteParameters expected parameter 'name' and not '%s'", name)
+	}
+	if attrType, _ := is.ReadByte(); int(attrType) != types.AttributeTypeString {
+		t.Errorf("TestQueryWriteParameters expected parameter 'name' to be a string and not '%d'", attrType)
+	}
+	is.ReadBoolean()
+	if value, _ := is.ReadUTF(); value != "x\\' or name = '://" {
+		t.Errorf("TestQueryWriteParameters expected the value of 'name' unchanged and not '%s'", value)
+	}
+	if name, _ := is.ReadUTF(); name != "year" {
+		t.Errorf("TestQueryWriteParameters expected parameter 'year' and not '%s'", name)
+	}
+	is.ReadByte()
+	is.ReadBoolean()
+	if value, _ := is.ReadInt(); value != 1908 {
+		t.Errorf("TestQueryWriteParameters expected the value of 'year' to be 1908 and not '%d'", value)
+	}
+}
+
+func TestQueryRequestExecuteById(t *testing.T) {
+	// EXECUTEID of a query w/o parameters carries the query hash id only, like the Java client does
+	msg := pdu.NewQueryRequestMessage(0, 0)
+	msg.SetCommand(5)
+	msg.SetQueryHashId(1234567890)
+	_, executeLen, err := msg.ToBytes()
+	if err != nil {
+		t.Errorf("TestQueryRequestExecuteById returned error message %s", err.Error())
+		return
+	}
+	msg.SetCommand(6)
+	_, closeLen, err := msg.ToBytes()
+	if err != nil {
+		t.Errorf("TestQueryRequestExecuteById returned error message %s", err.Error())
+		return
+	}
+	if executeLen != closeLen {
+		t.Errorf("TestQueryRequestExecuteById expected the payload of CLOSE, '%d' bytes, and not '%d' bytes", closeLen, executeLen)
+	}
+
+	// The values bound to the parameters of the query follow the hash id
+	testQry := NewPreparedQuery(nil, 1234567890, "name = $name")
+	testQry.SetString("name", "Gamble House")
+	msg.SetCommand(5)
+	msg.SetQueryObject(testQry)
+	_, paramLen, err := msg.ToBytes()
+	if err != nil {
+		t.Errorf("TestQueryRequestExecuteById returned error message %s", err.Error())
+		return
+	}
+	if paramLen <= executeLen {
+		t.Errorf("TestQueryRequestExecuteById expected the parameters to follow the hash id, and not '%d' bytes", paramLen)
+	}
+}
@@ -82,7 +82,7 @@ func NewQueryOption() *TGQueryOptionImpl {
 
 func (obj *TGQueryOptionImpl) preloadQueryOptions() {
 	// Add property will either insert or update it using underlying TGProperties functionality
-	//obj.AddProperty(OptionQueryBatchSize, -1)
+	obj.AddProperty(OptionQueryBatchSize, strconv.Itoa(DefaultBatchSize))
 	obj.AddProperty(OptionQueryFetchSize, strconv.Itoa(DefaultPrefetchSize))
 	obj.AddProperty(OptionQueryTraversalDepth, strconv.Itoa(DefaultTraversalDepth))
 	obj.AddProperty(OptionQueryEdgeLimit, strconv.Itoa(DefaultEdgeLimit))
@@ -120,7 +120,7 @@ func (obj TGQueryOptionImpl) GetBatchSize() int {
 			return int(v)
 		}
 	}
-	return DefaultPrefetchSize
+	return DefaultBatchSize
 }
 
 // SetBatchSize sets a limit on the batch. Default is 50
@@ -25,15 +25,26 @@ import (
 	"bytes"
 	"encoding/gob"
 	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"iter"
 )
 
+// resultException is an error raised while populating a result set. It is reported by the iterators just before
+// the entry at its position, i.e. after all the entries that were read successfully before the error occurred.
+type resultException struct {
+	position int
+	err      types.TGError
+}
+
 type ResultSet struct {
-	conn       types.TGConnection
-	currPos    int
-	isOpen     bool
-	resultId   int
-	resultList []interface{}
+	conn          types.TGConnection
+	currPos       int
+	isOpen        bool
+	resultId      int
+	resultList    []interface{}
+	gremlinResult *GremlinResult
+	exceptions    []resultException
 }
 
 func DefaultResultSet() *ResultSet {
@@ -47,6 +58,7 @@ func DefaultResultSet() *ResultSet {
 		isOpen:     true,
 		resultId:   -1,
 		resultList: make([]interface{}, 0),
+		exceptions: make([]resultException, 0),
 	}
 	return &newResults
 }
@@ -61,6 +73,16 @@ func NewResultSet(conn types.TGConnection, resultId int) *ResultSet {
 	return newResults
 }
 
+// NewGremlinResultSet creates a result set whose entries are the elements of the Gremlin response collection
+func NewGremlinResultSet(conn types.TGConnection, result *GremlinResult) *ResultSet {
+	newResults := NewResultSet(conn, 0)
+	newResults.gremlinResult = result
+	if result != nil {
+		newResults.resultList = result.ToCollection()
+	}
+	return newResults
+}
+
 /////////////////////////////////////////////////////////////////
 // Helper functions from Interface ==> TGResultSet
 /////////////////////////////////////////////////////////////////
@@ -73,6 +95,11 @@ func (obj *ResultSet) GetCurrentPos() int {
 	return obj.currPos
 }
 
+// GetGremlinResult returns the typed Gremlin response this result set was created from, if any
+func (obj *ResultSet) GetGremlinResult() *GremlinResult {
+	return obj.gremlinResult
+}
+
 func (obj *ResultSet) GetIsOpen() bool {
 	return obj.isOpen
 }
@@ -85,6 +112,31 @@ func (obj *ResultSet) GetResults() []interface{} {
 	return obj.resultList
 }
 
+// entityIterator narrows an iterator over raw result entries down to entities
+func entityIterator(values iter.Seq2[interface{}, error]) iter.Seq2[types.TGEntity, error] {
+	return func(yield func(types.TGEntity, error) bool) {
+		for value, err := range values {
+			if err != nil {
+				if !yield(nil, err) {
+					return
+				}
+				continue
+			}
+			entity, ok := value.(types.TGEntity)
+			if !ok {
+				errMsg := fmt.Sprintf("Result entry '%+v' of type '%T' is not an entity", value, value)
+				if !yield(nil, exception.GetErrorByType(types.TGErrorTypeCoercionNotSupported, "", errMsg, "")) {
+					return
+				}
+				continue
+			}
+			if !yield(entity, nil) {
+				return
+			}
+		}
+	}
+}
+
 /////////////////////////////////////////////////////////////////
 // Implement functions from Interface ==> TGResultSet
 /////////////////////////////////////////////////////////////////
@@ -96,6 +148,20 @@ func (obj *ResultSet) AddEntityToResultSet(entity types.TGEntity) types.TGResult
 	return obj
 }
 
+// AddException records an error raised while populating the result set, after the entries added so far
+func (obj *ResultSet) AddException(err types.TGError) types.TGResultSet {
+	if err != nil {
+		obj.exceptions = append(obj.exceptions, resultException{position: len(obj.resultList), err: err})
+	}
+	return obj
+}
+
+// All returns an iterator over the entities of the result set, from the first one onwards. It does not move the
+// cursor. Errors raised while populating the result set are yielded w/ a nil entity where they occurred.
+func (obj *ResultSet) All() iter.Seq2[types.TGEntity, error] {
+	return entityIterator(obj.Values())
+}
+
 // Close closes the result set
 func (obj *ResultSet) Close() types.TGResultSet {
 	obj.isOpen = false
@@ -145,10 +211,13 @@ func (obj *ResultSet) GetAt(position int) interface{} {
 	return nil
 }
 
-// GetExceptions gets the Exceptions in the result set
+// GetExceptions gets the errors raised while populating the result set
 func (obj *ResultSet) GetExceptions() []types.TGError {
-	// TODO: Revisit later - No-Op for Now
-	return nil
+	errs := make([]types.TGError, 0, len(obj.exceptions))
+	for _, ex := range obj.exceptions {
+		errs = append(errs, ex.err)
+	}
+	return errs
 }
 
 // GetPosition gets the Current cursor position. A result set upon creation is set to the position 0.
@@ -159,10 +228,9 @@ func (obj *ResultSet) GetPosition() int {
 	return obj.currPos
 }
 
-// HasExceptions checks whether the result set has any exceptions
+// HasExceptions checks whether populating the result set raised any errors
 func (obj *ResultSet) HasExceptions() bool {
-	// TODO: Revisit later - No-Op for Now
-	return false
+	return len(obj.exceptions) > 0
 }
 
 // HasNext Check whether there is next entry in result set
@@ -221,6 +289,32 @@ func (obj *ResultSet) ToCollection() []interface{} {
 	return obj.resultList
 }
 
+// Values returns an iterator over the raw entries of the result set, from the first one onwards. It does not
+// move the cursor.
+func (obj *ResultSet) Values() iter.Seq2[interface{}, error] {
+	return func(yield func(interface{}, error) bool) {
+		if obj.isOpen == false {
+			return
+		}
+		next := 0
+		for i, value := range obj.resultList {
+			for ; next < len(obj.exceptions) && obj.exceptions[next].position <= i; next++ {
+				if !yield(nil, obj.exceptions[next].err) {
+					return
+				}
+			}
+			if !yield(value, nil) {
+				return
+			}
+		}
+		for ; next < len(obj.exceptions); next++ {
+			if !yield(nil, obj.exceptions[next].err) {
+				return
+			}
+		}
+	}
+}
+
 func (obj *ResultSet) String() string {
 	var buffer bytes.Buffer
 	buffer.WriteString("ResultSet:{")
@@ -229,6 +323,7 @@ func (obj *ResultSet) String() string {
 	buffer.WriteString(fmt.Sprintf(", isOpen: %+v", obj.isOpen))
 	buffer.WriteString(fmt.Sprintf(", ResultId: %+v", obj.resultId))
 	buffer.WriteString(fmt.Sprintf(", ResultList: %+v", obj.resultList))
+	buffer.WriteString(fmt.Sprintf(", Exceptions: %+v", obj.GetExceptions()))
 	buffer.WriteString("}")
 	return buffer.String()
 }
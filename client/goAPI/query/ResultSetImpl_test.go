@@ -22,6 +22,7 @@
 package query
 
 import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
 	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
 	"testing"
@@ -266,3 +267,45 @@ func TestResultSetNext(t *testing.T) {
 	nextEntry := rs3.Next()
 	t.Logf("The next entry in the result set is '%+v' and current position is '%+v'", nextEntry, rs3.GetPosition())
 }
+
+func TestResultSetAllWithExceptions(t *testing.T) {
+	testRs := DefaultResultSet()
+	testNode := CreateTestNodeEntity()
+	testRs.AddEntityToResultSet(testNode)
+	testRs.AddEntityToResultSet(testNode)
+	testRs.AddException(exception.GetErrorByType(types.TGErrorGeneralException, "", "Test decoding failure", ""))
+	if !testRs.HasExceptions() || len(testRs.GetExceptions()) != 1 {
+		t.Errorf("TestResultSetAllWithExceptions expected one exception - '%s'", testRs.String())
+	}
+	entities, errs := 0, 0
+	for entity, err := range testRs.All() {
+		if err != nil {
+			if entities != 2 {
+				t.Errorf("TestResultSetAllWithExceptions expected the error after 2 entities and not after %d", entities)
+			}
+			errs++
+			continue
+		}
+		if entity != testNode {
+			t.Errorf("TestResultSetAllWithExceptions unexpected entity '%+v'", entity)
+		}
+		entities++
+	}
+	if entities != 2 || errs != 1 || testRs.GetPosition() != -1 {
+		t.Errorf("TestResultSetAllWithExceptions iterated over %d entities and %d errors - '%s'", entities, errs, testRs.String())
+	}
+}
+
+func TestResultSetAllNonEntity(t *testing.T) {
+	testRs := NewGremlinResultSet(nil, NewGremlinResult(ElementTypeList, []*GremlinResult{NewGremlinResult(ElementTypeAttrValue, 42)}))
+	for value, err := range testRs.Values() {
+		if err != nil || value != 42 {
+			t.Errorf("TestResultSetAllNonEntity expected the value 42 and not '%+v' w/ error '%+v'", value, err)
+		}
+	}
+	for entity, err := range testRs.All() {
+		if entity != nil || err == nil {
+			t.Errorf("TestResultSetAllNonEntity expected a coercion error and not '%+v'", entity)
+		}
+	}
+}
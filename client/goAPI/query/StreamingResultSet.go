@@ -0,0 +1,366 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: StreamingResultSet.go
+ * SVN id: $id: $
+ *
+ */
+
+package query
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"iter"
+)
+
+// TGResultFetcher fetches the remaining batches of a result that is still open on the server
+type TGResultFetcher interface {
+	// FetchResultBatch fetches the next batch of the result. hasMore is false once the server has sent the last batch.
+	FetchResultBatch(resultId int, batchSize int) (entities []interface{}, hasMore bool, err types.TGError)
+	// CancelResult releases the remaining, unfetched part of the result on the server
+	CancelResult(resultId int) types.TGError
+}
+
+// StreamingResultSet is a cursor over a server side result. Only the current batch is kept in memory:
+// the next batch is fetched when Next() moves past the current one, and the entities of the previous
+// batch are released at that time - Count() is the exception, as it fetches the whole result. Positions are absolute, so GetAt() and Prev() only reach entities
+// of the current batch.
+type StreamingResultSet struct {
+	conn       types.TGConnection
+	fetcher    TGResultFetcher
+	resultId   int
+	batchSize  int
+	fetchLimit int           // Maximum number of entities to fetch - 0 ==> Unlimited
+	batch      []interface{} // Entities of the current batch
+	batchStart int           // Absolute position of the first entity of the current batch
+	currPos    int
+	hasMore    bool
+	isOpen     bool
+	exceptions []resultException
+}
+
+func DefaultStreamingResultSet() *StreamingResultSet {
+	// We must register the concrete type for the encoder and decoder (which would
+	// normally be on a separate machine from the encoder). On each end, this tells the
+	// engine which concrete type is being sent that implements the interface.
+	gob.Register(StreamingResultSet{})
+
+	newResults := StreamingResultSet{
+		resultId:   -1,
+		batchSize:  DefaultBatchSize,
+		fetchLimit: DefaultPrefetchSize,
+		batch:      make([]interface{}, 0),
+		currPos:    -1,
+		isOpen:     true,
+		exceptions: make([]resultException, 0),
+	}
+	return &newResults
+}
+
+// Make sure that the StreamingResultSet implements the TGResultSet interface
+var _ types.TGResultSet = (*StreamingResultSet)(nil)
+
+// NewStreamingResultSet creates a cursor starting w/ the first batch received from the server. The batch and
+// pre-fetch sizes of the query option control the size of subsequent batches and the total number of entities fetched.
+func NewStreamingResultSet(conn types.TGConnection, fetcher TGResultFetcher, resultId int, firstBatch []interface{}, hasMore bool, options types.TGQueryOption) *StreamingResultSet {
+	newResults := DefaultStreamingResultSet()
+	newResults.conn = conn
+	newResults.fetcher = fetcher
+	newResults.resultId = resultId
+	newResults.hasMore = hasMore
+	if firstBatch != nil {
+		newResults.batch = firstBatch
+	}
+	if options != nil {
+		if options.GetBatchSize() > 0 {
+			newResults.batchSize = options.GetBatchSize()
+		}
+		// A pre-fetch size of -1 ==> Unlimited
+		newResults.fetchLimit = options.GetPreFetchSize()
+	}
+	return newResults
+}
+
+/////////////////////////////////////////////////////////////////
+// Helper functions from Interface ==> TGResultSet
+/////////////////////////////////////////////////////////////////
+
+func (obj *StreamingResultSet) GetBatchSize() int {
+	return obj.batchSize
+}
+
+func (obj *StreamingResultSet) GetConnection() types.TGConnection {
+	return obj.conn
+}
+
+func (obj *StreamingResultSet) GetIsOpen() bool {
+	return obj.isOpen
+}
+
+func (obj *StreamingResultSet) GetResultId() int {
+	return obj.resultId
+}
+
+// GetFetchedCount returns the number of entities fetched from the server so far
+func (obj *StreamingResultSet) GetFetchedCount() int {
+	return obj.batchStart + len(obj.batch)
+}
+
+// HasMoreBatches checks whether the server still has batches that have not been fetched
+func (obj *StreamingResultSet) HasMoreBatches() bool {
+	return obj.isOpen && obj.hasMore && !obj.isFetchLimitReached()
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions from Interface ==> TGResultSet
+/////////////////////////////////////////////////////////////////
+
+func (obj *StreamingResultSet) isFetchLimitReached() bool {
+	return obj.fetchLimit > 0 && obj.GetFetchedCount() >= obj.fetchLimit
+}
+
+// fetchNextBatch replaces the current batch by the next one from the server
+func (obj *StreamingResultSet) fetchNextBatch() bool {
+	if !obj.HasMoreBatches() || obj.fetcher == nil {
+		return false
+	}
+	logger.Debug(fmt.Sprintf("Inside StreamingResultSet:fetchNextBatch about to fetch next batch of result '%d' after '%d' entities", obj.resultId, obj.GetFetchedCount()))
+	entities, hasMore, err := obj.fetcher.FetchResultBatch(obj.resultId, obj.batchSize)
+	if err != nil {
+		// The entities decoded before the error, if any, are still part of the result
+		logger.Error(fmt.Sprintf("ERROR: StreamingResultSet:fetchNextBatch - unable to fetch next batch of result '%d' w/ error: '%s'", obj.resultId, err.Error()))
+	}
+	if obj.fetchLimit > 0 && obj.GetFetchedCount()+len(entities) > obj.fetchLimit {
+		entities = entities[:obj.fetchLimit-obj.GetFetchedCount()]
+	}
+	// Release the consumed batch
+	obj.batchStart += len(obj.batch)
+	obj.batch = entities
+	if entities == nil {
+		obj.batch = make([]interface{}, 0)
+	}
+	obj.hasMore = hasMore
+	if err != nil {
+		obj.AddException(err)
+		obj.cancel()
+	} else if !obj.HasMoreBatches() && obj.hasMore {
+		obj.cancel()
+	}
+	return len(entities) > 0
+}
+
+// cancel releases the unfetched part of the result on the server
+func (obj *StreamingResultSet) cancel() {
+	if !obj.hasMore || obj.fetcher == nil {
+		return
+	}
+	obj.hasMore = false
+	err := obj.fetcher.CancelResult(obj.resultId)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("WARNING: StreamingResultSet:cancel - unable to cancel result '%d' w/ error: '%s'", obj.resultId, err.Error()))
+		obj.AddException(err)
+	}
+}
+
+/////////////////////////////////////////////////////////////////
+// Implement functions from Interface ==> TGResultSet
+/////////////////////////////////////////////////////////////////
+
+// AddEntityToResultSet adds another entity to the current batch of the result set
+func (obj *StreamingResultSet) AddEntityToResultSet(entity types.TGEntity) types.TGResultSet {
+	obj.batch = append(obj.batch, entity)
+	return obj
+}
+
+// AddException records an error raised while populating or fetching the result set, after the entities fetched so far
+func (obj *StreamingResultSet) AddException(err types.TGError) types.TGResultSet {
+	if err != nil {
+		obj.exceptions = append(obj.exceptions, resultException{position: obj.GetFetchedCount(), err: err})
+	}
+	return obj
+}
+
+// All returns an iterator over the remaining entities - from the current cursor position onwards - fetching the
+// remaining batches as needed. Errors raised while fetching are yielded w/ a nil entity. This consumes the result set.
+func (obj *StreamingResultSet) All() iter.Seq2[types.TGEntity, error] {
+	return entityIterator(obj.Values())
+}
+
+// Close closes the result set and cancels the remaining stream on the server
+func (obj *StreamingResultSet) Close() types.TGResultSet {
+	if obj.isOpen {
+		obj.cancel()
+	}
+	obj.isOpen = false
+	obj.batch = make([]interface{}, 0)
+	return obj
+}
+
+// Count returns the number of entities in the result set, like ResultSet.Count. The server does not report the
+// size of a result up front, so counting fetches all the remaining batches and keeps them in memory w/ the current one.
+func (obj *StreamingResultSet) Count() int {
+	if obj.isOpen == false {
+		return 0
+	}
+	for obj.HasMoreBatches() {
+		batch, batchStart := obj.batch, obj.batchStart
+		fetched := obj.fetchNextBatch()
+		obj.batch, obj.batchStart = append(batch, obj.batch...), batchStart
+		if !fetched {
+			break
+		}
+	}
+	return obj.GetFetchedCount()
+}
+
+// First returns the first entity in the result set, as long as its batch has not been released
+func (obj *StreamingResultSet) First() interface{} {
+	return obj.GetAt(0)
+}
+
+// Last returns the last entity fetched so far
+func (obj *StreamingResultSet) Last() interface{} {
+	return obj.GetAt(obj.GetFetchedCount() - 1)
+}
+
+// GetAt gets the entity at the position, if it belongs to the current batch
+func (obj *StreamingResultSet) GetAt(position int) interface{} {
+	if obj.isOpen == false {
+		return nil
+	}
+	if position >= obj.batchStart && position < obj.GetFetchedCount() {
+		return obj.batch[position-obj.batchStart]
+	}
+	return nil
+}
+
+// GetExceptions gets the errors raised while fetching batches of the result set
+func (obj *StreamingResultSet) GetExceptions() []types.TGError {
+	errs := make([]types.TGError, 0, len(obj.exceptions))
+	for _, ex := range obj.exceptions {
+		errs = append(errs, ex.err)
+	}
+	return errs
+}
+
+// GetPosition gets the Current cursor position
+func (obj *StreamingResultSet) GetPosition() int {
+	if obj.isOpen == false {
+		return 0
+	}
+	return obj.currPos
+}
+
+// HasExceptions checks whether fetching the result set raised any errors
+func (obj *StreamingResultSet) HasExceptions() bool {
+	return len(obj.exceptions) > 0
+}
+
+// HasNext checks whether there is a next entry in the result set, fetching the next batch if needed
+func (obj *StreamingResultSet) HasNext() bool {
+	if obj.isOpen == false {
+		return false
+	}
+	if obj.currPos+1 < obj.GetFetchedCount() {
+		return true
+	}
+	return obj.fetchNextBatch()
+}
+
+// Next returns the next entity w.r.t to the current cursor position in the result set
+func (obj *StreamingResultSet) Next() interface{} {
+	if !obj.HasNext() {
+		return nil
+	}
+	obj.currPos++
+	return obj.batch[obj.currPos-obj.batchStart]
+}
+
+// Prev returns the previous entity, if it belongs to the current batch
+func (obj *StreamingResultSet) Prev() interface{} {
+	if obj.isOpen == false {
+		return nil
+	}
+	if obj.currPos > obj.batchStart {
+		obj.currPos--
+		return obj.batch[obj.currPos-obj.batchStart]
+	}
+	return nil
+}
+
+// Skip moves the cursor forward by a number of positions, fetching batches as needed
+func (obj *StreamingResultSet) Skip(position int) types.TGResultSet {
+	if obj.isOpen == false || position < 0 {
+		return obj
+	}
+	for i := 0; i < position && obj.HasNext(); i++ {
+		obj.currPos++
+	}
+	return obj
+}
+
+// ToCollection returns the remaining entities - from the current cursor position onwards - fetching all the
+// remaining batches. This consumes the result set.
+func (obj *StreamingResultSet) ToCollection() []interface{} {
+	collection := make([]interface{}, 0)
+	for obj.HasNext() {
+		collection = append(collection, obj.Next())
+	}
+	return collection
+}
+
+// Values returns an iterator over the remaining raw entries - from the current cursor position onwards - fetching
+// the remaining batches as needed. This consumes the result set.
+func (obj *StreamingResultSet) Values() iter.Seq2[interface{}, error] {
+	return func(yield func(interface{}, error) bool) {
+		next := 0
+		for next < len(obj.exceptions) && obj.exceptions[next].position <= obj.currPos {
+			next++
+		}
+		for obj.HasNext() {
+			for ; next < len(obj.exceptions) && obj.exceptions[next].position <= obj.currPos+1; next++ {
+				if !yield(nil, obj.exceptions[next].err) {
+					return
+				}
+			}
+			if !yield(obj.Next(), nil) {
+				return
+			}
+		}
+		for ; next < len(obj.exceptions); next++ {
+			if !yield(nil, obj.exceptions[next].err) {
+				return
+			}
+		}
+	}
+}
+
+func (obj *StreamingResultSet) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString("StreamingResultSet:{")
+	buffer.WriteString(fmt.Sprintf("ResultId: %+v", obj.resultId))
+	buffer.WriteString(fmt.Sprintf(", BatchSize: %+v", obj.batchSize))
+	buffer.WriteString(fmt.Sprintf(", FetchLimit: %+v", obj.fetchLimit))
+	buffer.WriteString(fmt.Sprintf(", BatchStart: %+v", obj.batchStart))
+	buffer.WriteString(fmt.Sprintf(", BatchCount: %+v", len(obj.batch)))
+	buffer.WriteString(fmt.Sprintf(", CurrPos: %+v", obj.currPos))
+	buffer.WriteString(fmt.Sprintf(", HasMore: %+v", obj.hasMore))
+	buffer.WriteString(fmt.Sprintf(", IsOpen: %+v", obj.isOpen))
+	buffer.WriteString(fmt.Sprintf(", Exceptions: %+v", obj.GetExceptions()))
+	buffer.WriteString("}")
+	return buffer.String()
+}
@@ -0,0 +1,148 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: StreamingResultSet_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package query
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"testing"
+)
+
+// testResultFetcher serves a result of 'total' integers in batches, like the server would
+type testResultFetcher struct {
+	total     int
+	served    int
+	fetches   int
+	cancelled bool
+	failAt    int
+}
+
+func (obj *testResultFetcher) nextBatch(batchSize int) ([]interface{}, bool) {
+	batch := make([]interface{}, 0)
+	for len(batch) < batchSize && obj.served < obj.total {
+		batch = append(batch, obj.served)
+		obj.served++
+	}
+	return batch, obj.served < obj.total
+}
+
+func (obj *testResultFetcher) FetchResultBatch(resultId int, batchSize int) ([]interface{}, bool, types.TGError) {
+	obj.fetches++
+	if obj.failAt > 0 && obj.fetches == obj.failAt {
+		return nil, false, exception.GetErrorByType(types.TGErrorIOException, types.INTERNAL_SERVER_ERROR, "Test fetch failure", "")
+	}
+	batch, hasMore := obj.nextBatch(batchSize)
+	return batch, hasMore, nil
+}
+
+func (obj *testResultFetcher) CancelResult(resultId int) types.TGError {
+	obj.cancelled = true
+	return nil
+}
+
+func createTestStreamingResultSet(fetcher *testResultFetcher, batchSize, fetchSize int) *StreamingResultSet {
+	option := NewQueryOption()
+	_ = option.SetBatchSize(batchSize)
+	_ = option.SetPreFetchSize(fetchSize)
+	firstBatch, hasMore := fetcher.nextBatch(batchSize)
+	return NewStreamingResultSet(nil, fetcher, 7, firstBatch, hasMore, option)
+}
+
+func TestStreamingResultSetFetchesBatches(t *testing.T) {
+	fetcher := &testResultFetcher{total: 25}
+	rs := createTestStreamingResultSet(fetcher, 10, 0)
+	count := 0
+	for rs.HasNext() {
+		value := rs.Next()
+		if value != count {
+			t.Errorf("TestStreamingResultSetFetchesBatches expected '%d' and not '%+v'", count, value)
+		}
+		count++
+		// Entities of released batches are no longer reachable
+		if count == 15 && rs.GetAt(5) != nil {
+			t.Errorf("TestStreamingResultSetFetchesBatches expected the first batch to be released")
+		}
+	}
+	if count != 25 || fetcher.fetches != 2 || fetcher.cancelled {
+		t.Errorf("TestStreamingResultSetFetchesBatches read %d entities in %d fetches - '%s'", count, fetcher.fetches, rs.String())
+	}
+}
+
+func TestStreamingResultSetCount(t *testing.T) {
+	fetcher := &testResultFetcher{total: 25}
+	rs := createTestStreamingResultSet(fetcher, 10, 0)
+	if count := rs.Count(); count != 25 || fetcher.fetches != 2 {
+		t.Errorf("TestStreamingResultSetCount expected 25 entities in 2 fetches and not %d in %d", count, fetcher.fetches)
+	}
+	if collection := rs.ToCollection(); len(collection) != 25 || collection[0] != 0 || collection[24] != 24 {
+		t.Errorf("TestStreamingResultSetCount expected to read all 25 entities after counting and not '%+v'", collection)
+	}
+}
+
+func TestStreamingResultSetEarlyClose(t *testing.T) {
+	fetcher := &testResultFetcher{total: 1000}
+	rs := createTestStreamingResultSet(fetcher, 10, 0)
+	rs.Skip(15)
+	if rs.GetPosition() != 14 || rs.Next() != 15 {
+		t.Errorf("TestStreamingResultSetEarlyClose expected to skip to position 14 - '%s'", rs.String())
+	}
+	rs.Close()
+	if !fetcher.cancelled || rs.HasNext() || fetcher.fetches != 1 {
+		t.Errorf("TestStreamingResultSetEarlyClose expected the stream to be cancelled - '%s'", rs.String())
+	}
+}
+
+func TestStreamingResultSetFetchLimit(t *testing.T) {
+	fetcher := &testResultFetcher{total: 1000}
+	rs := createTestStreamingResultSet(fetcher, 10, 25)
+	collection := rs.ToCollection()
+	if len(collection) != 25 || !fetcher.cancelled {
+		t.Errorf("TestStreamingResultSetFetchLimit expected 25 entities and a cancelled stream and not %d entities - '%s'", len(collection), rs.String())
+	}
+}
+
+func TestStreamingResultSetFetchError(t *testing.T) {
+	fetcher := &testResultFetcher{total: 30, failAt: 1}
+	rs := createTestStreamingResultSet(fetcher, 10, 0)
+	collection := rs.ToCollection()
+	if len(collection) != 10 || !rs.HasExceptions() || len(rs.GetExceptions()) != 1 {
+		t.Errorf("TestStreamingResultSetFetchError expected 10 entities and an exception - '%s'", rs.String())
+	}
+}
+
+func TestStreamingResultSetAllYieldsFetchError(t *testing.T) {
+	fetcher := &testResultFetcher{total: 30, failAt: 2}
+	rs := createTestStreamingResultSet(fetcher, 10, 0)
+	count := 0
+	var lastErr error
+	for value, err := range rs.Values() {
+		if err != nil {
+			lastErr = err
+			break
+		}
+		if value != count {
+			t.Errorf("TestStreamingResultSetAllYieldsFetchError expected '%d' and not '%+v'", count, value)
+		}
+		count++
+	}
+	if count != 20 || lastErr == nil || rs.HasNext() {
+		t.Errorf("TestStreamingResultSetAllYieldsFetchError read %d entities before error '%+v' - '%s'", count, lastErr, rs.String())
+	}
+}
@@ -0,0 +1,389 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: TGQLBuilder.go
+ * SVN id: $id: $
+ *
+ */
+
+package query
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/exception"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"reflect"
+	"strings"
+)
+
+// Reserved keywords of the traversal conditions
+const (
+	fromNodeTypeKeyword = "@fromnodetype"
+	toNodeTypeKeyword   = "@tonodetype"
+	isFromEdgeKeyword   = "@isfromedge"
+	degreeKeyword       = "@degree"
+	edgeAttrPrefix      = "@edge."
+	fromNodeAttrPrefix  = "@fromnode."
+	toNodeAttrPrefix    = "@tonode."
+)
+
+// TGQLBuilder builds the four parts of a TGQL query - the query expression, the edge filter, the traversal
+// condition and the end condition - from node types, edge types and attribute descriptors. The types and
+// attributes are validated against the cached graph metadata, and the values against the attribute types,
+// so that a typo is reported by Build rather than by the server. Once a node or edge type is named for a scope,
+// e.g. by ToNodeType for the attributes compared by ToNodeAttr, the attributes of that scope must belong to
+// one of its types. The first validation error is kept and
+// returned by Build, which lets the conditions be chained w/o checking each of them, e.g.
+//
+//	b := query.NewTGQLBuilder(gmd)
+//	b.Expr(b.NodeType(house), b.AttrByName("yearBuilt", query.OperatorGreaterThan, 1900))
+//	b.TraversalCondition(b.EdgeType(offspring), b.Degree(query.OperatorLessThan, 3))
+//	tgql, err := b.Build()
+type TGQLBuilder struct {
+	graphMetadata      types.TGGraphMetadata
+	expr               []types.TGFilter
+	edgeFilter         []types.TGFilter
+	traversalCondition []types.TGFilter
+	endCondition       []types.TGFilter
+	scopeTypes         map[string][]types.TGEntityType // Types named for each scope, keyed by the attribute prefix
+	attrUses           []attrUse
+	err                types.TGError
+}
+
+// attrUse records an attribute compared in the query, w/ the prefix of its scope
+type attrUse struct {
+	prefix string
+	name   string
+}
+
+// TGQLQuery holds the parts of a TGQL query built by TGQLBuilder. A part w/o any condition is empty.
+type TGQLQuery struct {
+	Expr               string
+	EdgeFilter         string
+	TraversalCondition string
+	EndCondition       string
+}
+
+func NewTGQLBuilder(gmd types.TGGraphMetadata) *TGQLBuilder {
+	return &TGQLBuilder{graphMetadata: gmd, scopeTypes: make(map[string][]types.TGEntityType)}
+}
+
+/////////////////////////////////////////////////////////////////
+// Private functions for TGQLBuilder
+/////////////////////////////////////////////////////////////////
+
+// fail records the first validation error, and returns the filter to use in its place
+func (obj *TGQLBuilder) fail(errMsg string) types.TGFilter {
+	logger.Error(fmt.Sprintf("ERROR: TGQLBuilder - %s", errMsg))
+	if obj.err == nil {
+		obj.err = exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	return And()
+}
+
+// isNil checks for a nil interface, as well as an interface holding a nil pointer
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// checkAttribute verifies that the attribute is known to the graph metadata, and that the value can be compared w/ it
+func (obj *TGQLBuilder) checkAttribute(attrDesc types.TGAttributeDescriptor, value interface{}) string {
+	if isNil(attrDesc) {
+		return "Attribute descriptor cannot be nil"
+	}
+	desc, err := obj.graphMetadata.GetAttributeDescriptor(attrDesc.GetName())
+	if err != nil || isNil(desc) {
+		return fmt.Sprintf("Attribute '%s' is not defined in the graph metadata", attrDesc.GetName())
+	}
+	if desc.IsAttributeArray() {
+		return fmt.Sprintf("Array attribute '%s' cannot be compared in a query", desc.GetName())
+	}
+	if value == nil {
+		return fmt.Sprintf("Attribute '%s' cannot be compared w/ a null value", desc.GetName())
+	}
+	if _, ok := value.(Parameter); ok {
+		return ""
+	}
+	kind := reflect.TypeOf(value).Kind()
+	isInteger := kind >= reflect.Int && kind <= reflect.Uint64
+	isFloat := kind == reflect.Float32 || kind == reflect.Float64
+	compatible := false
+	switch desc.GetAttrType() {
+	case types.AttributeTypeBoolean:
+		compatible = kind == reflect.Bool
+	case types.AttributeTypeByte, types.AttributeTypeShort, types.AttributeTypeInteger, types.AttributeTypeLong:
+		compatible = isInteger
+	case types.AttributeTypeFloat, types.AttributeTypeDouble, types.AttributeTypeNumber:
+		compatible = isInteger || isFloat
+	case types.AttributeTypeChar, types.AttributeTypeString:
+		compatible = kind == reflect.String
+	default:
+		return fmt.Sprintf("Attribute '%s' of type '%s' can only be compared w/ a query parameter", desc.GetName(),
+			types.GetAttributeTypeFromId(desc.GetAttrType()).GetTypeName())
+	}
+	if !compatible {
+		return fmt.Sprintf("Value '%+v' of type '%T' cannot be compared w/ attribute '%s' of type '%s'", value, value,
+			desc.GetName(), types.GetAttributeTypeFromId(desc.GetAttrType()).GetTypeName())
+	}
+	return ""
+}
+
+// scopedAttr compares the attribute, prefixed w/ the scope keyword if any, w/ the value
+func (obj *TGQLBuilder) scopedAttr(prefix string, attrDesc types.TGAttributeDescriptor, operator Operator, value interface{}) types.TGFilter {
+	if !isValidOperator(operator) {
+		return obj.fail(fmt.Sprintf("Operator '%s' is not supported in a query", operator))
+	}
+	if errMsg := obj.checkAttribute(attrDesc, value); errMsg != "" {
+		return obj.fail(errMsg)
+	}
+	obj.attrUses = append(obj.attrUses, attrUse{prefix: prefix, name: attrDesc.GetName()})
+	return Compare(prefix+attrDesc.GetName(), operator, value)
+}
+
+// checkTypeMembership verifies that each attribute compared in the query belongs to one of the types named for its scope
+func (obj *TGQLBuilder) checkTypeMembership() string {
+	for _, use := range obj.attrUses {
+		entityTypes := obj.scopeTypes[use.prefix]
+		if len(entityTypes) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(entityTypes))
+		found := false
+		for _, entityType := range entityTypes {
+			found = found || hasAttribute(entityType, use.name)
+			names = append(names, entityType.GetName())
+		}
+		if !found {
+			return fmt.Sprintf("Attribute '%s' does not belong to any of the types '%s'", use.prefix+use.name,
+				strings.Join(names, "', '"))
+		}
+	}
+	return ""
+}
+
+// hasAttribute checks the attributes of the entity type, and of the types it is derived from
+func hasAttribute(entityType types.TGEntityType, attrName string) bool {
+	for eType := entityType; !isNil(eType); eType = eType.DerivedFrom() {
+		if !isNil(eType.GetAttributeDescriptor(attrName)) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeTypes matches the keyword against the names of the node types, after verifying them against the graph metadata,
+// and records the types for the attributes of the scope
+func (obj *TGQLBuilder) nodeTypes(keyword, prefix string, nodeTypes []types.TGNodeType) types.TGFilter {
+	names := make([]string, 0, len(nodeTypes))
+	for _, nodeType := range nodeTypes {
+		if isNil(nodeType) {
+			return obj.fail("Node type cannot be nil")
+		}
+		known, err := obj.graphMetadata.GetNodeType(nodeType.GetName())
+		if err != nil || isNil(known) {
+			return obj.fail(fmt.Sprintf("Node type '%s' is not defined in the graph metadata", nodeType.GetName()))
+		}
+		obj.scopeTypes[prefix] = append(obj.scopeTypes[prefix], known)
+		names = append(names, nodeType.GetName())
+	}
+	return &typeFilter{keyword: keyword, typeNames: names}
+}
+
+// compile joins the conditions of a query part, and terminates the expression w/ ';'
+func compile(filters []types.TGFilter) (string, types.TGError) {
+	if len(filters) == 0 {
+		return "", nil
+	}
+	expr, err := And(filters...).GetExpression()
+	if err != nil {
+		return "", err
+	}
+	return expr + ";", nil
+}
+
+/////////////////////////////////////////////////////////////////
+// Conditions of TGQLBuilder
+/////////////////////////////////////////////////////////////////
+
+// Attr compares the attribute of the node w/ the value
+func (obj *TGQLBuilder) Attr(attrDesc types.TGAttributeDescriptor, operator Operator, value interface{}) types.TGFilter {
+	return obj.scopedAttr("", attrDesc, operator, value)
+}
+
+// AttrByName compares the attribute of the node, looked up by name in the graph metadata, w/ the value
+func (obj *TGQLBuilder) AttrByName(attrName string, operator Operator, value interface{}) types.TGFilter {
+	attrDesc, err := obj.graphMetadata.GetAttributeDescriptor(attrName)
+	if err != nil || isNil(attrDesc) {
+		return obj.fail(fmt.Sprintf("Attribute '%s' is not defined in the graph metadata", attrName))
+	}
+	return obj.scopedAttr("", attrDesc, operator, value)
+}
+
+// EdgeAttr compares the attribute of the edge being traversed w/ the value
+func (obj *TGQLBuilder) EdgeAttr(attrDesc types.TGAttributeDescriptor, operator Operator, value interface{}) types.TGFilter {
+	return obj.scopedAttr(edgeAttrPrefix, attrDesc, operator, value)
+}
+
+// FromNodeAttr compares the attribute of the node the traversal comes from w/ the value
+func (obj *TGQLBuilder) FromNodeAttr(attrDesc types.TGAttributeDescriptor, operator Operator, value interface{}) types.TGFilter {
+	return obj.scopedAttr(fromNodeAttrPrefix, attrDesc, operator, value)
+}
+
+// ToNodeAttr compares the attribute of the node the traversal goes to w/ the value
+func (obj *TGQLBuilder) ToNodeAttr(attrDesc types.TGAttributeDescriptor, operator Operator, value interface{}) types.TGFilter {
+	return obj.scopedAttr(toNodeAttrPrefix, attrDesc, operator, value)
+}
+
+// NodeType matches nodes of any of the node types
+func (obj *TGQLBuilder) NodeType(nodeTypes ...types.TGNodeType) types.TGFilter {
+	return obj.nodeTypes(nodeTypeKeyword, "", nodeTypes)
+}
+
+// FromNodeType matches traversals coming from a node of any of the node types
+func (obj *TGQLBuilder) FromNodeType(nodeTypes ...types.TGNodeType) types.TGFilter {
+	return obj.nodeTypes(fromNodeTypeKeyword, fromNodeAttrPrefix, nodeTypes)
+}
+
+// ToNodeType matches traversals going to a node of any of the node types
+func (obj *TGQLBuilder) ToNodeType(nodeTypes ...types.TGNodeType) types.TGFilter {
+	return obj.nodeTypes(toNodeTypeKeyword, toNodeAttrPrefix, nodeTypes)
+}
+
+// EdgeType matches edges of any of the edge types
+func (obj *TGQLBuilder) EdgeType(edgeTypes ...types.TGEdgeType) types.TGFilter {
+	names := make([]string, 0, len(edgeTypes))
+	for _, edgeType := range edgeTypes {
+		if isNil(edgeType) {
+			return obj.fail("Edge type cannot be nil")
+		}
+		known, err := obj.graphMetadata.GetEdgeType(edgeType.GetName())
+		if err != nil || isNil(known) {
+			return obj.fail(fmt.Sprintf("Edge type '%s' is not defined in the graph metadata", edgeType.GetName()))
+		}
+		obj.scopeTypes[edgeAttrPrefix] = append(obj.scopeTypes[edgeAttrPrefix], known)
+		names = append(names, edgeType.GetName())
+	}
+	return &typeFilter{keyword: edgeTypeKeyword, typeNames: names}
+}
+
+// IsFromEdge matches traversals whose starting node is - or is not - on the from side of the edge
+func (obj *TGQLBuilder) IsFromEdge(isFrom bool) types.TGFilter {
+	if isFrom {
+		return Eq(isFromEdgeKeyword, 1)
+	}
+	return Eq(isFromEdgeKeyword, 0)
+}
+
+// Degree compares the degree of separation - i.e. the depth - of the traversal w/ the value
+func (obj *TGQLBuilder) Degree(operator Operator, degree int) types.TGFilter {
+	if !isValidOperator(operator) {
+		return obj.fail(fmt.Sprintf("Operator '%s' is not supported in a query", operator))
+	}
+	if degree < 0 {
+		return obj.fail(fmt.Sprintf("Degree of separation '%d' cannot be negative", degree))
+	}
+	return Compare(degreeKeyword, operator, degree)
+}
+
+/////////////////////////////////////////////////////////////////
+// Query parts of TGQLBuilder
+/////////////////////////////////////////////////////////////////
+
+// Expr adds conditions, all of which the nodes selected by the query must match
+func (obj *TGQLBuilder) Expr(filters ...types.TGFilter) *TGQLBuilder {
+	obj.expr = append(obj.expr, filters...)
+	return obj
+}
+
+// EdgeFilter adds conditions, all of which the edges returned w/ the nodes must match
+func (obj *TGQLBuilder) EdgeFilter(filters ...types.TGFilter) *TGQLBuilder {
+	obj.edgeFilter = append(obj.edgeFilter, filters...)
+	return obj
+}
+
+// TraversalCondition adds conditions, all of which the edges must match to be traversed
+func (obj *TGQLBuilder) TraversalCondition(filters ...types.TGFilter) *TGQLBuilder {
+	obj.traversalCondition = append(obj.traversalCondition, filters...)
+	return obj
+}
+
+// EndCondition adds conditions, all of which stop the traversal when matched
+func (obj *TGQLBuilder) EndCondition(filters ...types.TGFilter) *TGQLBuilder {
+	obj.endCondition = append(obj.endCondition, filters...)
+	return obj
+}
+
+// Build compiles the query parts, or returns the first error found while adding the conditions. The attributes are
+// checked against the types of their scope here, since the types may be named after the attributes.
+func (obj *TGQLBuilder) Build() (*TGQLQuery, types.TGError) {
+	if obj.err == nil {
+		if errMsg := obj.checkTypeMembership(); errMsg != "" {
+			obj.fail(errMsg)
+		}
+	}
+	if obj.err != nil {
+		return nil, obj.err
+	}
+	if len(obj.expr) == 0 {
+		errMsg := "TGQL query needs at least one condition in its query expression"
+		return nil, exception.GetErrorByType(types.TGErrorGeneralException, types.INTERNAL_SERVER_ERROR, errMsg, "")
+	}
+	tgql := &TGQLQuery{}
+	parts := []struct {
+		filters []types.TGFilter
+		expr    *string
+	}{
+		{obj.expr, &tgql.Expr},
+		{obj.edgeFilter, &tgql.EdgeFilter},
+		{obj.traversalCondition, &tgql.TraversalCondition},
+		{obj.endCondition, &tgql.EndCondition},
+	}
+	for _, part := range parts {
+		expr, err := compile(part.filters)
+		if err != nil {
+			return nil, err
+		}
+		*part.expr = expr
+	}
+	logger.Debug(fmt.Sprintf("Inside TGQLBuilder:Build built query '%s'", tgql.String()))
+	return tgql, nil
+}
+
+/////////////////////////////////////////////////////////////////
+// Helper functions for TGQLQuery
+/////////////////////////////////////////////////////////////////
+
+// Execute executes the query on the connection
+func (obj *TGQLQuery) Execute(conn types.TGConnection, options types.TGQueryOption) (types.TGResultSet, types.TGError) {
+	if obj.EdgeFilter == "" && obj.TraversalCondition == "" && obj.EndCondition == "" {
+		return conn.ExecuteQuery("tgql://"+obj.Expr, options)
+	}
+	return conn.ExecuteQueryWithFilter(obj.Expr, obj.EdgeFilter, obj.TraversalCondition, obj.EndCondition, options)
+}
+
+func (obj *TGQLQuery) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString("TGQLQuery:{")
+	buffer.WriteString(fmt.Sprintf("Expr: %s", obj.Expr))
+	buffer.WriteString(fmt.Sprintf(", EdgeFilter: %s", obj.EdgeFilter))
+	buffer.WriteString(fmt.Sprintf(", TraversalCondition: %s", obj.TraversalCondition))
+	buffer.WriteString(fmt.Sprintf(", EndCondition: %s", obj.EndCondition))
+	buffer.WriteString("}")
+	return buffer.String()
+}
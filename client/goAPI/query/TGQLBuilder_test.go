@@ -0,0 +1,157 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: TGQLBuilder_test.go
+ * SVN id: $id: $
+ *
+ */
+
+package query
+
+import (
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/model"
+	"github.com/TIBCOSoftware/tgdb-client/client/goAPI/types"
+	"testing"
+)
+
+func createTestBuilderMetadata() *model.GraphMetadata {
+	gmd := model.NewGraphObjectFactory(nil).GetGraphMetaData()
+	name := model.NewAttributeDescriptorWithType("memberName", types.AttributeTypeString)
+	yearBorn := model.NewAttributeDescriptorWithType("yearBorn", types.AttributeTypeInteger)
+	birthOrder := model.NewAttributeDescriptorWithType("birthOrder", types.AttributeTypeInteger)
+	gmd.SetAttributeDescriptors(map[string]types.TGAttributeDescriptor{"memberName": name, "yearBorn": yearBorn, "birthOrder": birthOrder})
+	member := model.NewNodeType("houseMemberType", nil)
+	member.AddAttributeDescriptor("memberName", name)
+	member.AddAttributeDescriptor("yearBorn", yearBorn)
+	offspring := model.NewEdgeType("offspringEdge", types.DirectionTypeDirected, nil)
+	offspring.AddAttributeDescriptor("birthOrder", birthOrder)
+	gmd.SetNodeTypes(map[string]types.TGNodeType{"houseMemberType": member})
+	gmd.SetEdgeTypes(map[string]types.TGEdgeType{"offspringEdge": offspring})
+	return gmd
+}
+
+func TestTGQLBuilderBuild(t *testing.T) {
+	gmd := createTestBuilderMetadata()
+	member, _ := gmd.GetNodeType("houseMemberType")
+	offspring, _ := gmd.GetEdgeType("offspringEdge")
+	birthOrder, _ := gmd.GetAttributeDescriptor("birthOrder")
+	memberName, _ := gmd.GetAttributeDescriptor("memberName")
+
+	b := NewTGQLBuilder(gmd)
+	b.Expr(b.NodeType(member), b.AttrByName("memberName", OperatorEqual, "Napoleon's"))
+	b.TraversalCondition(b.EdgeType(offspring), b.IsFromEdge(true), b.EdgeAttr(birthOrder, OperatorEqual, 1), b.Degree(OperatorLessThan, 3))
+	b.EndCondition(b.ToNodeType(member), b.ToNodeAttr(memberName, OperatorEqual, Param("name")))
+	tgql, err := b.Build()
+	if err != nil {
+		t.Fatalf("TestTGQLBuilderBuild unable to build w/ error '%s'", err.Error())
+	}
+	expected := TGQLQuery{
+		Expr:               "@nodetype = 'houseMemberType' and memberName = 'Napoleon''s';",
+		TraversalCondition: "@edgetype = 'offspringEdge' and @isfromedge = 1 and @edge.birthOrder = 1 and @degree < 3;",
+		EndCondition:       "@tonodetype = 'houseMemberType' and @tonode.memberName = $name;",
+	}
+	if *tgql != expected {
+		t.Errorf("TestTGQLBuilderBuild expected '%s' and not '%s'", expected.String(), tgql.String())
+	}
+}
+
+func TestTGQLBuilderValidation(t *testing.T) {
+	gmd := createTestBuilderMetadata()
+	yearBorn, _ := gmd.GetAttributeDescriptor("yearBorn")
+	invalid := []func(b *TGQLBuilder) types.TGFilter{
+		func(b *TGQLBuilder) types.TGFilter { return b.AttrByName("yearBron", OperatorEqual, 1900) },
+		func(b *TGQLBuilder) types.TGFilter { return b.Attr(yearBorn, OperatorGreaterThan, "1900") },
+		func(b *TGQLBuilder) types.TGFilter { return b.NodeType(model.NewNodeType("unknownType", nil)) },
+		func(b *TGQLBuilder) types.TGFilter {
+			return b.EdgeType(model.NewEdgeType("unknownEdge", types.DirectionTypeDirected, nil))
+		},
+		func(b *TGQLBuilder) types.TGFilter { return b.Degree(OperatorEqual, -1) },
+	}
+	for i, condition := range invalid {
+		b := NewTGQLBuilder(gmd)
+		b.Expr(condition(b))
+		if _, err := b.Build(); err == nil {
+			t.Errorf("TestTGQLBuilderValidation expected an error for condition #%d", i)
+		}
+	}
+	if _, err := NewTGQLBuilder(gmd).Build(); err == nil {
+		t.Errorf("TestTGQLBuilderValidation expected an error for a query w/o expression")
+	}
+}
+
+func TestTGQLBuilderOperatorCheckedAtCallTime(t *testing.T) {
+	gmd := createTestBuilderMetadata()
+	yearBorn, _ := gmd.GetAttributeDescriptor("yearBorn")
+	invalid := []func(b *TGQLBuilder) types.TGFilter{
+		func(b *TGQLBuilder) types.TGFilter { return b.Degree(Operator("like"), 2) },
+		func(b *TGQLBuilder) types.TGFilter { return b.Attr(yearBorn, Operator("; drop"), 1900) },
+		func(b *TGQLBuilder) types.TGFilter { return b.EdgeAttr(yearBorn, Operator(""), 1900) },
+	}
+	for i, condition := range invalid {
+		b := NewTGQLBuilder(gmd)
+		// The error is recorded by the call, even if the condition is never added to a query part
+		condition(b)
+		if b.err == nil {
+			t.Errorf("TestTGQLBuilderOperatorCheckedAtCallTime expected an error for condition #%d", i)
+		}
+	}
+}
+
+func TestTGQLBuilderAttributeOfType(t *testing.T) {
+	gmd := createTestBuilderMetadata()
+	member, _ := gmd.GetNodeType("houseMemberType")
+	offspring, _ := gmd.GetEdgeType("offspringEdge")
+	birthOrder, _ := gmd.GetAttributeDescriptor("birthOrder")
+	yearBorn, _ := gmd.GetAttributeDescriptor("yearBorn")
+	invalid := []func(b *TGQLBuilder){
+		func(b *TGQLBuilder) { b.Expr(b.NodeType(member), b.AttrByName("birthOrder", OperatorEqual, 1)) },
+		// The type may be named after the attribute
+		func(b *TGQLBuilder) { b.Expr(b.Attr(birthOrder, OperatorEqual, 1), b.NodeType(member)) },
+		func(b *TGQLBuilder) {
+			b.Expr(b.NodeType(member)).TraversalCondition(b.EdgeType(offspring), b.EdgeAttr(yearBorn, OperatorEqual, 1900))
+		},
+		func(b *TGQLBuilder) {
+			b.Expr(b.NodeType(member)).EndCondition(b.FromNodeType(member), b.FromNodeAttr(birthOrder, OperatorEqual, 1))
+		},
+	}
+	for i, query := range invalid {
+		b := NewTGQLBuilder(gmd)
+		query(b)
+		if _, err := b.Build(); err == nil {
+			t.Errorf("TestTGQLBuilderAttributeOfType expected an error for query #%d", i)
+		}
+	}
+
+	// W/o a type named for its scope, the attribute only has to be defined in the graph metadata
+	b := NewTGQLBuilder(gmd)
+	b.Expr(b.NodeType(member), b.Attr(yearBorn, OperatorGreaterThan, 1900))
+	b.TraversalCondition(b.EdgeAttr(yearBorn, OperatorEqual, 1900))
+	if _, err := b.Build(); err != nil {
+		t.Errorf("TestTGQLBuilderAttributeOfType unable to build w/ error '%s'", err.Error())
+	}
+}
+
+func TestTGQLBuilderQuotesBackslash(t *testing.T) {
+	gmd := createTestBuilderMetadata()
+	b := NewTGQLBuilder(gmd)
+	b.Expr(b.AttrByName("memberName", OperatorEqual, `C:\houses\'Bonaparte`))
+	tgql, err := b.Build()
+	if err != nil {
+		t.Fatalf("TestTGQLBuilderQuotesBackslash unable to build w/ error '%s'", err.Error())
+	}
+	expected := `memberName = 'C:\\houses\\''Bonaparte';`
+	if tgql.Expr != expected {
+		t.Errorf("TestTGQLBuilderQuotesBackslash expected '%s' and not '%s'", expected, tgql.Expr)
+	}
+}
@@ -72,6 +72,9 @@ type TGConnection interface {
 	GetEntities(key TGKey, properties TGProperties) (TGResultSet, TGError)
 	// GetEntity gets an Entity given an UniqueKey for the Object
 	GetEntity(key TGKey, options TGQueryOption) (TGEntity, TGError)
+	// GetEntitiesByKeys gets the entities of many unique keys, possibly of different types, in as few requests as
+	// possible. The result holds an entry for each of the keys, reporting the entity or the error of that key.
+	GetEntitiesByKeys(keys []TGKey, options TGQueryOption) (map[TGKey]TGKeyResult, TGError)
 	// GetGraphMetadata gets the Graph Metadata
 	GetGraphMetadata(refresh bool) (TGGraphMetadata, TGError)
 	// GetGraphObjectFactory gets the Graph Object Factory for Object creation
@@ -82,8 +85,20 @@ type TGConnection interface {
 	GetRemovedList() map[int64]TGEntity
 	// InsertEntity marks an ENTITY for insert operation. Upon commit, the entity will be inserted in the database
 	InsertEntity(entity TGEntity) TGError
+	// Prepare returns a reusable query for the expression, sharing the server side query with earlier
+	// calls for the same expression on this connection. Closing the returned query only releases it - the cached
+	// server side query is closed once it has been evicted and no other returned query uses it.
+	Prepare(expr string) (TGQuery, TGError)
 	// Rollback rolls back the current transaction on this connection
 	Rollback() TGError
+	// UpsertNode resolves the node of the node type w/ the primary key values - among the pending changes of this
+	// connection first, then on the server - and merges the attributes into it, creating the node if there is none.
+	// A nil attribute value clears the attribute. The node is inserted or updated in the database upon commit.
+	UpsertNode(nodeType TGNodeType, keyValues map[string]interface{}, attrs map[string]interface{}) (TGNode, TGError)
+	// SaveObject creates or updates the node for a Go struct tagged w/ its node type, along w/ the edges of its
+	// tagged relationship fields, and commits the changes. As the commit would include any other pending change,
+	// the connection must not have any. The changes are rolled back if the commit fails.
+	SaveObject(object interface{}) (TGNode, TGError)
 	// SetConnectionPool sets connection pool
 	SetConnectionPool(connPool TGConnectionPool)
 	// SetConnectionProperties sets connection properties
@@ -21,5 +21,12 @@
 
 package types
 
+// TGFilter is a predicate over the nodes and edges of the graph, such as an attribute comparison or an entity type
+// membership test. Filters are composable, and compile into a server query expression, which is the subset of the
+// SQL-92 where clause used by TGConnection.ExecuteQuery - e.g. "@nodetype = 'Member' and (age > 30 or age < 20)".
 type TGFilter interface {
+	// GetExpression compiles the filter into a query expression - without the terminating ';'
+	GetExpression() (string, TGError)
+	// Additional Method to help debugging
+	String() string
 }
@@ -27,16 +27,17 @@ type TGGraph interface {
 	AddNode(node TGNode) (TGGraph, TGError)
 	// AddEdges adds a collection of edges for this node
 	AddEdges(edges []TGEdge) (TGGraph, TGError)
-	// GetNode gets a unique node which matches the unique constraint
+	// GetNode gets a unique node which matches the unique constraint, or nil if there is none
 	GetNode(filter TGFilter) (TGNode, TGError)
 	// ListNodes lists all the nodes that match the filter and recurse All sub graphs
-	ListNodes(filter TGFilter, recurseAllSubGraphs bool) (TGNode, TGError)
+	ListNodes(filter TGFilter, recurseAllSubGraphs bool) ([]TGNode, TGError)
 	// CreateGraph creates a sub graph within this graph
 	CreateGraph(name string) (TGGraph, TGError)
 	// RemoveGraph removes the graph
 	RemoveGraph(name string) (TGGraph, TGError)
 	// RemoveNode removes this node from the graph
 	RemoveNode(node TGNode) (TGGraph, TGError)
-	// RemoveNodes removes a set of nodes from this graph that match the filter
+	// RemoveNodes marks the nodes from this graph that match the filter for delete operation, and returns their
+	// count - or -1 if the nodes cannot be queried
 	RemoveNodes(filter TGFilter) int
 }
@@ -32,13 +32,15 @@ type TGGraphManager interface {
 	CreateEdgeWithDirection(fromNode TGNode, toNode TGNode, directionType TGDirectionType) (TGEdge, TGError)
 	// CreateGraph creates a SubGraph at the Root level.
 	CreateGraph(name string) (TGGraph, TGError)
-	// DeleteNode removes this node from the graph
+	// DeleteNode marks the unique node matching the filter for delete operation, which happens upon commit.
+	// It is an error for the filter to match more than one node.
 	DeleteNode(filter TGFilter) (TGGraphManager, TGError)
-	// DeleteNodes removes the nodes from this graph that match the filter
+	// DeleteNodes marks the nodes that match the filter for delete operation, which happens upon commit
 	DeleteNodes(filter TGFilter) (TGGraphManager, TGError)
-	// CreateQuery creates a Reusable Query
+	// CreateQuery creates a Reusable Query, or returns nil if the filter does not compile
 	CreateQuery(filter TGFilter) TGQuery
-	// QueryNodes gets Nodes based on the Filter condition with a set of Arguments
+	// QueryNodes gets Nodes based on the Filter condition with a set of Arguments. The arguments are pairs of a
+	// parameter name and its value, bound to the parameters of the filter. It returns nil if the query fails.
 	QueryNodes(filter TGFilter, args ...interface{}) TGResultSet
 	// Traverse follows the graph using the traversal descriptor
 	Traverse(descriptor TGTraversalDescriptor, startingPoints []TGNode) TGResultSet
@@ -28,6 +28,8 @@ type TGGraphObjectFactory interface {
 	CreateEdgeWithEdgeType(fromNode TGNode, toNode TGNode, edgeType TGEdgeType) (TGEdge, TGError)
 	// CreateEdgeWithDirection creates an Edge with a direction
 	CreateEdgeWithDirection(fromNode TGNode, toNode TGNode, directionType TGDirectionType) (TGEdge, TGError)
+	// CreateHyperEdge creates a HyperEdge connecting the nodes, of the edge type if one is specified
+	CreateHyperEdge(nodes []TGNode, edgeType TGEdgeType) (TGHyperEdge, TGError)
 	// CreateEntity creates entity based on the entity kind specified
 	CreateEntity(entityKind TGEntityKind) (TGEntity, TGError)
 	// CreateEntityId creates entity id from input buffer
@@ -0,0 +1,30 @@
+/**
+ * Copyright 2018-19 TIBCO Software Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); You may not use this file except
+ * in compliance with the License.
+ * A copy of the License is included in the distribution package with this file.
+ * You also may obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * File name: TGHyperEdge.go
+ * SVN id: $id: $
+ *
+ */
+
+package types
+
+// A HyperEdge connects an arbitrary set of nodes. GetVertices returns all the connected nodes in the order
+// they were added, and a directed hyperedge leads from the first vertex to the others.
+type TGHyperEdge interface {
+	TGEdge
+	// AddVertex connects another node to this hyperedge
+	AddVertex(node TGNode)
+	// RemoveVertex disconnects the node from this hyperedge, and reports whether it was connected
+	RemoveVertex(node TGNode) bool
+}
@@ -26,3 +26,15 @@ type TGKey interface {
 	// Dynamically set the attribute to this entity. If the AttributeDescriptor doesn't exist in the database, create a new one.
 	SetOrCreateAttribute(name string, value interface{}) TGError
 }
+
+// TGKeyResult is the outcome of fetching the entity of one of the keys of a batch. Entity is nil if no entity has
+// the key, and Error is set if the entity of the key could not be fetched.
+type TGKeyResult struct {
+	Entity TGEntity
+	Error  TGError
+}
+
+// IsFound checks whether an entity was found for the key
+func (obj TGKeyResult) IsFound() bool {
+	return obj.Entity != nil
+}
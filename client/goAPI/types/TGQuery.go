@@ -25,11 +25,22 @@ import (
 	"time"
 )
 
+// TGQuery is a reusable query handle created by TGConnection.CreateQuery. Named parameters are
+// referenced in the query expression as '$name' and bound using the typed setters or Bind. A parameter
+// keeps the type of its first binding. The bound values are sent as typed values together w/ the hash id
+// of the server side query when it is executed, and never become part of the expression.
 type TGQuery interface {
+	// Bind binds the value to the named parameter w/ the setter matching its Go type, and reports a
+	// parameter that is not declared in the expression, already bound w/ another type, or of an unsupported type
+	Bind(name string, value interface{}) TGError
 	// Close closes the Query
 	Close()
+	// CloseQuery closes the Query and reports the error returned by the server, if any
+	CloseQuery() TGError
 	// Execute executes the Query
 	Execute() TGResultSet
+	// ExecuteQuery executes the Query and reports an invalid binding, or the error returned by the server
+	ExecuteQuery() (TGResultSet, TGError)
 	// SetBoolean sets Boolean parameter
 	SetBoolean(name string, value bool)
 	// SetBytes sets Byte Parameter
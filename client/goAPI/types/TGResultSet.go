@@ -21,9 +21,16 @@
 
 package types
 
+import "iter"
+
 type TGResultSet interface {
 	// AddEntityToResultSet adds another entity to the result set
 	AddEntityToResultSet(entity TGEntity) TGResultSet
+	// AddException records an error raised while populating or fetching the result set
+	AddException(err TGError) TGResultSet
+	// All returns an iterator over the entities of the result set. An entry that could not be decoded, or that is
+	// not an entity, is yielded as a nil entity w/ the corresponding error.
+	All() iter.Seq2[TGEntity, error]
 	// Close closes the result set
 	Close() TGResultSet
 	// Count returns nos of entities returned by the query. The result set has a cursor which prefetches
@@ -54,4 +61,6 @@ type TGResultSet interface {
 	String() string
 	// ToCollection converts the result set into a collection
 	ToCollection() []interface{}
+	// Values returns an iterator over the raw entries of the result set, e.g. scalar values of a Gremlin result
+	Values() iter.Seq2[interface{}, error]
 }
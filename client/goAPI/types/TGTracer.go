@@ -28,5 +28,9 @@ type TGTracer interface {
 	Stop()
 	// Trace traces the path the message has taken
 	Trace(msg TGMessage)
+	// TraceReceived traces the bytes of a message as read from the wire
+	TraceReceived(buf []byte)
+	// TraceSent traces the bytes of a message as written on the wire
+	TraceSent(buf []byte)
 }
 
@@ -51,6 +51,7 @@ const (
 	ConnectionTimeStampFormat
 	ConnectionLocale
 	ConnectionDefaultQueryLanguage
+	ConnectionQueryCacheSize
 	TlsProviderName
 	TlsProviderClassName
 	TlsProviderConfigFile
@@ -62,6 +63,9 @@ const (
 	KeyStorePassword
 	EnableConnectionTrace
 	ConnectionTraceDir
+	ConnectionTraceMaxFileSize
+	ConnectionTraceMaxFileAge
+	ConnectionTraceMaxFiles
 	InvalidName
 )
 
@@ -99,6 +103,7 @@ var PreDefinedConfigurations = map[int]ConfigName{
 	ConnectionTimeStampFormat:         {configPropName: "tgdb.connection.timeStampFormat", aliasName: "timeStampFormat", defaultValue: "YYYY-MM-DD HH:mm:ss.zzz", description: "Timestamp format for this connection"},
 	ConnectionLocale:                  {configPropName: "tgdb.connection.locale", aliasName: "locale", defaultValue: "en_US", description: "Locale for this connection"},
 	ConnectionDefaultQueryLanguage:    {configPropName: "tgdb.connection.defaultQueryLanguage", aliasName: "queryLanguage", defaultValue: "tgql", description: "Default query lanaguge format for this connection"},
+	ConnectionQueryCacheSize:          {configPropName: "tgdb.connection.queryCacheSize", aliasName: "queryCacheSize", defaultValue: "32", description: "Number of prepared queries cached per connection. 0 disables the cache"},
 	// TODO: Ask TGDB Engineering Team
 	TlsProviderName: {configPropName: "tgdb.tls.provider.name", aliasName: "tlsProviderName", defaultValue: "SunJSSE", description: "Transport level Security provider. Work with your InfoSec team to change this value"},
 	// TODO: Ask TGDB Engineering Team - The default is the Sun JSSE. One can specify the tibco wrapper class for FIPS
@@ -113,6 +118,9 @@ var PreDefinedConfigurations = map[int]ConfigName{
 	KeyStorePassword:       {configPropName: "tgdb.security.keyStorePassword", aliasName: "keyStorePassword", defaultValue: "", description: "The Keystore for the password"},
 	EnableConnectionTrace:  {configPropName: "tgdb.connection.enableTrace", aliasName: "enableTrace", defaultValue: "false", description: "The flag for debugging purpose, to enable the commit trace"},
 	ConnectionTraceDir:     {configPropName: "tgdb.connection.enableTraceDir", aliasName: "enableTraceDir", defaultValue: ".", description: "The base directory to hold commit trace log"},
+	ConnectionTraceMaxFileSize: {configPropName: "tgdb.connection.traceMaxFileSize", aliasName: "traceMaxFileSize", defaultValue: "1048576", description: "The size in bytes after which the trace file is rolled over"},
+	ConnectionTraceMaxFileAge:  {configPropName: "tgdb.connection.traceMaxFileAgeSeconds", aliasName: "traceMaxFileAgeSeconds", defaultValue: "3600", description: "The age in seconds after which the trace file is rolled over. 0 disables it"},
+	ConnectionTraceMaxFiles:    {configPropName: "tgdb.connection.traceMaxFiles", aliasName: "traceMaxFiles", defaultValue: "10", description: "Number of trace files kept per channel. 0 keeps all of them"},
 	InvalidName:            {configPropName: "", aliasName: "", defaultValue: "", description: ""},
 }
 
@@ -137,16 +137,12 @@ func (q *SimpleQueue) Enqueue(x interface{}) {
 
 // Dequeue removes and returns an entry from the queue in first to last order.
 func (q *SimpleQueue) Dequeue() interface{} {
-	for {
-		if len(q.values) > 0 {
-			q.qLock.Lock()
-			x := q.values[0]
-			q.values = q.values[1:]
-			q.qLock.Unlock()
-			return x
-		}
-		break
-		//return nil
+	q.qLock.Lock()
+	defer q.qLock.Unlock()
+	if len(q.values) > 0 {
+		x := q.values[0]
+		q.values = q.values[1:]
+		return x
 	}
 	return nil
 }
@@ -156,5 +152,7 @@ func (q *SimpleQueue) Items() []interface{} {
 }
 
 func (q *SimpleQueue) Len() int {
+	q.qLock.Lock()
+	defer q.qLock.Unlock()
 	return len(q.values)
 }